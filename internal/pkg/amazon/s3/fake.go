@@ -0,0 +1,176 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Fake 記憶體中的 S3 實作,供單元測試與本機開發使用,不需連線
+type Fake struct {
+	buckets string
+	mu      sync.RWMutex
+	objects map[string]*fakeObject
+}
+
+type fakeObject struct {
+	body         []byte
+	contentType  *string
+	lastModified time.Time
+	etag         string
+}
+
+var _ AmazonStorageInterface = (*Fake)(nil)
+
+// NewFake 建立空的記憶體 S3
+func NewFake(bucket string) *Fake {
+	return &Fake{
+		buckets: bucket,
+		objects: make(map[string]*fakeObject),
+	}
+}
+
+func (fake *Fake) Upload(ctx context.Context, input *s3.PutObjectInput) (outPut *manager.UploadOutput, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	body := []byte{}
+	if input.Body != nil {
+		body, err = io.ReadAll(input.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sum := md5.Sum(body)
+	key := aws.ToString(input.Key)
+
+	fake.mu.Lock()
+	fake.objects[key] = &fakeObject{
+		body:         body,
+		contentType:  input.ContentType,
+		lastModified: time.Now(),
+		etag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+	}
+	fake.mu.Unlock()
+
+	return &manager.UploadOutput{
+		Location: fmt.Sprintf("fake://%s/%s", fake.buckets, key),
+		Key:      aws.String(key),
+	}, nil
+}
+
+func (fake *Fake) Get(ctx context.Context, input *s3.GetObjectInput) (outPut *s3.GetObjectOutput, err error) {
+	object, err := fake.object(ctx, aws.ToString(input.Key))
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3.GetObjectOutput{
+		Body:          io.NopCloser(bytes.NewReader(object.body)),
+		ContentLength: aws.Int64(int64(len(object.body))),
+		ContentType:   object.contentType,
+		ETag:          aws.String(object.etag),
+		LastModified:  aws.Time(object.lastModified),
+	}, nil
+}
+
+func (fake *Fake) Head(ctx context.Context, input *s3.HeadObjectInput) (outPut *s3.HeadObjectOutput, err error) {
+	object, err := fake.object(ctx, aws.ToString(input.Key))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, &types.NotFound{Message: aws.String("Not Found")}
+	}
+
+	return &s3.HeadObjectOutput{
+		ContentLength: aws.Int64(int64(len(object.body))),
+		ContentType:   object.contentType,
+		ETag:          aws.String(object.etag),
+		LastModified:  aws.Time(object.lastModified),
+	}, nil
+}
+
+func (fake *Fake) List(ctx context.Context, input *s3.ListObjectsV2Input) (outPut []types.Object, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	prefix := aws.ToString(input.Prefix)
+
+	fake.mu.RLock()
+	for key, object := range fake.objects {
+		if strings.HasPrefix(key, prefix) {
+			outPut = append(outPut, types.Object{
+				Key:          aws.String(key),
+				Size:         aws.Int64(int64(len(object.body))),
+				ETag:         aws.String(object.etag),
+				LastModified: aws.Time(object.lastModified),
+			})
+		}
+	}
+	fake.mu.RUnlock()
+
+	sort.Slice(outPut, func(i, j int) bool {
+		return aws.ToString(outPut[i].Key) < aws.ToString(outPut[j].Key)
+	})
+
+	return outPut, nil
+}
+
+// Delete 與 S3 相同,刪除不存在的物件不會回傳錯誤
+func (fake *Fake) Delete(ctx context.Context, input *s3.DeleteObjectInput) (outPut *s3.DeleteObjectOutput, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fake.mu.Lock()
+	delete(fake.objects, aws.ToString(input.Key))
+	fake.mu.Unlock()
+
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (fake *Fake) PresignGet(ctx context.Context, input *s3.GetObjectInput, expires time.Duration) (url string, err error) {
+	return fake.presign(ctx, "GET", aws.ToString(input.Key), expires)
+}
+
+func (fake *Fake) PresignPut(ctx context.Context, input *s3.PutObjectInput, expires time.Duration) (url string, err error) {
+	return fake.presign(ctx, "PUT", aws.ToString(input.Key), expires)
+}
+
+func (fake *Fake) presign(ctx context.Context, method, key string, expires time.Duration) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("fake://%s/%s?method=%s&expires=%d", fake.buckets, key, method, time.Now().Add(expires).Unix()), nil
+}
+
+func (fake *Fake) object(ctx context.Context, key string) (*fakeObject, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fake.mu.RLock()
+	defer fake.mu.RUnlock()
+
+	object, ok := fake.objects[key]
+	if !ok {
+		return nil, &types.NoSuchKey{Message: aws.String("The specified key does not exist.")}
+	}
+	return object, nil
+}
@@ -0,0 +1,69 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestFakeList(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake("bucket")
+	for _, key := range []string{"projects/p2/b.pdf", "stocks/s1/c.pdf", "projects/p1/a.pdf"} {
+		_, err := fake.Upload(ctx, &s3.PutObjectInput{Key: aws.String(key), Body: strings.NewReader(key)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"projects/", []string{"projects/p1/a.pdf", "projects/p2/b.pdf"}},
+		{"stocks/", []string{"stocks/s1/c.pdf"}},
+		{"", []string{"projects/p1/a.pdf", "projects/p2/b.pdf", "stocks/s1/c.pdf"}},
+		{"none/", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			objects, err := fake.List(ctx, &s3.ListObjectsV2Input{Prefix: aws.String(tt.prefix)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var keys []string
+			for _, object := range objects {
+				keys = append(keys, aws.ToString(object.Key))
+			}
+			if strings.Join(keys, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("List(%q) = %v, want %v", tt.prefix, keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeMissingObject(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake("bucket")
+
+	_, err := fake.Get(ctx, &s3.GetObjectInput{Key: aws.String("missing")})
+	var noSuchKey *types.NoSuchKey
+	if !errors.As(err, &noSuchKey) {
+		t.Errorf("Get() error = %v, want NoSuchKey", err)
+	}
+
+	_, err = fake.Head(ctx, &s3.HeadObjectInput{Key: aws.String("missing")})
+	var notFound *types.NotFound
+	if !errors.As(err, &notFound) {
+		t.Errorf("Head() error = %v, want NotFound", err)
+	}
+
+	if _, err := fake.Delete(ctx, &s3.DeleteObjectInput{Key: aws.String("missing")}); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+}
@@ -2,61 +2,147 @@ package s3
 
 import (
 	"context"
-
 	"errors"
+	"time"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
+// AmazonStorageInterface S3 操作介面,bucket 由建立時的設定決定,輸入中的 Bucket 會被覆寫
 type AmazonStorageInterface interface {
-	Upload(input *s3.PutObjectInput) (outPut *manager.UploadOutput, err error)
-	Delete(input *s3.DeleteObjectInput) (outPut *s3.DeleteObjectOutput, err error)
+	// Upload 以分段上傳串流寫入,大檔案不需事先讀入記憶體
+	Upload(ctx context.Context, input *s3.PutObjectInput) (outPut *manager.UploadOutput, err error)
+	Get(ctx context.Context, input *s3.GetObjectInput) (outPut *s3.GetObjectOutput, err error)
+	Head(ctx context.Context, input *s3.HeadObjectInput) (outPut *s3.HeadObjectOutput, err error)
+	// List 依前綴列出物件,會自動翻頁
+	List(ctx context.Context, input *s3.ListObjectsV2Input) (outPut []types.Object, err error)
+	Delete(ctx context.Context, input *s3.DeleteObjectInput) (outPut *s3.DeleteObjectOutput, err error)
+	PresignGet(ctx context.Context, input *s3.GetObjectInput, expires time.Duration) (url string, err error)
+	PresignPut(ctx context.Context, input *s3.PutObjectInput, expires time.Duration) (url string, err error)
 }
 
-type amazonStorage struct {
-	buckets string
+// Options 連線設定
+type Options struct {
+	Bucket string
+	// S3 相容服務(例如 MinIO)的端點,空值表示 AWS
+	Endpoint string
+	Region   string
+	// MinIO 需使用 path-style 網址
+	UsePathStyle bool
+	// 分段上傳的每段大小(bytes),0 使用預設 5MB
+	PartSize int64
+	// 分段上傳的同時上傳數,0 使用預設值
+	Concurrency int
 }
 
-func NewAmazonStorage(bucket string) AmazonStorageInterface {
-	return &amazonStorage{
-		buckets: bucket,
-	}
+type amazonStorage struct {
+	buckets  string
+	client   *s3.Client
+	presign  *s3.PresignClient
+	uploader *manager.Uploader
 }
 
-func (storage *amazonStorage) Upload(input *s3.PutObjectInput) (outPut *manager.UploadOutput, err error) {
-	if len(storage.buckets) == 0 {
+var ErrBucketEmpty = errors.New("S3 Bucket Name Can Not Be Empty")
 
-		//log.Error("S3 Bucket Name Can Not Be Empty")
-		return nil, errors.New("S3 Bucket Name Can Not Be Empty")
+// NewAmazonStorage 建立長期使用的 S3 用戶端,應在啟動時建立一次並重複使用
+func NewAmazonStorage(ctx context.Context, options Options) (AmazonStorageInterface, error) {
+	if len(options.Bucket) == 0 {
+		return nil, ErrBucketEmpty
 	}
 
-	input.Bucket = aws.String(storage.buckets)
-	cfg, err := config.LoadDefaultConfig(context.TODO())
-	if err != nil {
+	loadOptions := make([]func(*config.LoadOptions) error, 0)
+	if options.Region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(options.Region))
+	}
 
-		//log.Error(err)
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
 		return nil, err
 	}
-	client := s3.NewFromConfig(cfg)
-	uploader := manager.NewUploader(client)
-	return uploader.Upload(context.TODO(), input)
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if options.Endpoint != "" {
+			o.BaseEndpoint = aws.String(options.Endpoint)
+		}
+		o.UsePathStyle = options.UsePathStyle
+	})
+
+	uploader := manager.NewUploader(client, func(u *manager.Uploader) {
+		if options.PartSize > 0 {
+			u.PartSize = options.PartSize
+		}
+		if options.Concurrency > 0 {
+			u.Concurrency = options.Concurrency
+		}
+	})
+
+	return &amazonStorage{
+		buckets:  options.Bucket,
+		client:   client,
+		presign:  s3.NewPresignClient(client),
+		uploader: uploader,
+	}, nil
+}
+
+func (storage *amazonStorage) Upload(ctx context.Context, input *s3.PutObjectInput) (outPut *manager.UploadOutput, err error) {
+	input.Bucket = aws.String(storage.buckets)
+	return storage.uploader.Upload(ctx, input)
+}
+
+func (storage *amazonStorage) Get(ctx context.Context, input *s3.GetObjectInput) (outPut *s3.GetObjectOutput, err error) {
+	input.Bucket = aws.String(storage.buckets)
+	return storage.client.GetObject(ctx, input)
 }
 
-func (storage *amazonStorage) Delete(input *s3.DeleteObjectInput) (outPut *s3.DeleteObjectOutput, err error) {
-	if len(storage.buckets) == 0 {
-		return nil, errors.New("S3 Bucket Name Can Not Be Empty")
+func (storage *amazonStorage) Head(ctx context.Context, input *s3.HeadObjectInput) (outPut *s3.HeadObjectOutput, err error) {
+	input.Bucket = aws.String(storage.buckets)
+	return storage.client.HeadObject(ctx, input)
+}
+
+func (storage *amazonStorage) List(ctx context.Context, input *s3.ListObjectsV2Input) (outPut []types.Object, err error) {
+	input.Bucket = aws.String(storage.buckets)
+
+	paginator := s3.NewListObjectsV2Paginator(storage.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		outPut = append(outPut, page.Contents...)
 	}
 
+	return outPut, nil
+}
+
+func (storage *amazonStorage) Delete(ctx context.Context, input *s3.DeleteObjectInput) (outPut *s3.DeleteObjectOutput, err error) {
 	input.Bucket = aws.String(storage.buckets)
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+	return storage.client.DeleteObject(ctx, input)
+}
+
+// PresignGet 產生限時下載網址
+func (storage *amazonStorage) PresignGet(ctx context.Context, input *s3.GetObjectInput, expires time.Duration) (url string, err error) {
+	input.Bucket = aws.String(storage.buckets)
+
+	request, err := storage.presign.PresignGetObject(ctx, input, s3.WithPresignExpires(expires))
 	if err != nil {
+		return "", err
+	}
 
-		//log.Error(err)
-		return nil, err
+	return request.URL, nil
+}
+
+// PresignPut 產生限時上傳網址,讓前端直接上傳到 S3
+func (storage *amazonStorage) PresignPut(ctx context.Context, input *s3.PutObjectInput, expires time.Duration) (url string, err error) {
+	input.Bucket = aws.String(storage.buckets)
+
+	request, err := storage.presign.PresignPutObject(ctx, input, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", err
 	}
-	client := s3.NewFromConfig(cfg)
 
-	return client.DeleteObject(context.TODO(), input)
+	return request.URL, nil
 }
@@ -2,47 +2,51 @@ package auth
 
 import (
 	"errors"
-	"os"
-	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var (
-	jwtKey     []byte
-	jwtKeyOnce sync.Once
+// token 類型,區分登入後的存取 token 與一次性操作 token(兩者以相同金鑰簽章)
+const (
+	TokenTypeAccess = "access"
+	TokenTypeAction = "action"
 )
 
-// 初始化 JWT 密鑰
-func initJWTKey() {
-	jwtKeyOnce.Do(func() {
-		secret := os.Getenv("JWT_SECRET")
-		if secret == "" {
-			panic("JWT_SECRET environment variable is required")
-		}
-		jwtKey = []byte(secret)
-	})
-}
+var ErrInvalidToken = errors.New("invalid token")
 
 // Claims JWT 聲明結構
 type Claims struct {
+	// token 類型,只接受 access
+	Type     string `json:"typ"`
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// 是否需先變更密碼(預設管理員或管理員建立的帳號首次登入)
+	MustChangePassword bool `json:"must_change_password,omitempty"`
+	// 一次性操作 token 的用途,存取 token 必須為空
+	Purpose string `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成 JWT token
-func GenerateToken(userID, username, role string) (string, error) {
-	initJWTKey() // 確保初始化
+// ActionClaims 一次性操作 token 聲明結構(例如登入票證、重設密碼連結)
+type ActionClaims struct {
+	Type    string `json:"typ"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
 
+// GenerateToken 生成 JWT token
+func GenerateToken(userID, username, role string, mustChangePassword bool) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
 
 	claims := &Claims{
+		Type:     TokenTypeAccess,
 		UserID:   userID,
 		Username: username,
 		Role:     role,
+
+		MustChangePassword: mustChangePassword,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -50,28 +54,22 @@ func GenerateToken(userID, username, role string) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)
-
-	return tokenString, err
+	return signToken(claims)
 }
 
-// ValidateToken 驗證 JWT token
+// ValidateToken 驗證 JWT 存取 token
+// 登入票證(尚未完成第二因子)與重設密碼等一次性操作 token 使用相同金鑰簽章,必須拒絕
 func ValidateToken(tokenString string) (*Claims, error) {
-	initJWTKey() // 確保初始化
-
 	claims := &Claims{}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
+	token, err := parseToken(tokenString, claims)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if !token.Valid {
-		return nil, errors.New("invalid token")
+	if !token.Valid || claims.Type != TokenTypeAccess || claims.Purpose != "" {
+		return nil, ErrInvalidToken
 	}
 
 	return claims, nil
@@ -86,8 +84,43 @@ func RefreshToken(tokenString string) (string, error) {
 
 	// 如果 token 接近過期（剩餘時間少於 12 小時），則刷新它
 	if time.Until(claims.ExpiresAt.Time) < 12*time.Hour {
-		return GenerateToken(claims.UserID, claims.Username, claims.Role)
+		return GenerateToken(claims.UserID, claims.Username, claims.Role, claims.MustChangePassword)
 	}
 
 	return tokenString, nil
 }
+
+// GenerateActionToken 生成一次性操作 token,subject 為使用者編號,id 對應資料庫中的紀錄編號
+func GenerateActionToken(subject, id, purpose string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &ActionClaims{
+		Type:    TokenTypeAction,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "esst-sendEmail",
+		},
+	}
+
+	return signToken(claims)
+}
+
+// ValidateActionToken 驗證一次性操作 token 的簽章、期限與用途
+func ValidateActionToken(tokenString, purpose string) (*ActionClaims, error) {
+	claims := &ActionClaims{}
+
+	token, err := parseToken(tokenString, claims)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid || claims.Type != TokenTypeAction || purpose == "" || claims.Purpose != purpose {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
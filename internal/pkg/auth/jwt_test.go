@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("JWT_ALGORITHM", AlgorithmHS256)
+	os.Setenv("JWT_SECRET", "test-secret")
+	os.Exit(m.Run())
+}
+
+func TestValidateTokenAcceptsAccessToken(t *testing.T) {
+	token, err := GenerateToken("user-1", "alice", "admin", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if claims.UserID != "user-1" || claims.Type != TokenTypeAccess {
+		t.Errorf("ValidateToken() claims = %+v", claims)
+	}
+}
+
+func TestValidateTokenRejectsOtherTokens(t *testing.T) {
+	loginTicket, err := GenerateActionToken("user-1", "ticket-1", "login", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resetToken, err := GenerateActionToken("user-1", "reset-1", "password_reset", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 沒有類型的舊格式 token
+	untyped, err := signToken(&Claims{
+		UserID:           "user-1",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 類型為 access 但帶有用途
+	withPurpose, err := signToken(&Claims{
+		Type:             TokenTypeAccess,
+		UserID:           "user-1",
+		Purpose:          "login",
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"login ticket", loginTicket},
+		{"password reset token", resetToken},
+		{"untyped token", untyped},
+		{"access type with purpose", withPurpose},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ValidateToken(tt.token); err == nil {
+				t.Error("ValidateToken() accepted a non-access token")
+			}
+		})
+	}
+}
+
+func TestValidateActionTokenChecksPurpose(t *testing.T) {
+	ticket, err := GenerateActionToken("user-1", "ticket-1", "login", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	access, err := GenerateToken("user-1", "alice", "admin", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ValidateActionToken(ticket, "login"); err != nil {
+		t.Errorf("ValidateActionToken(login) error = %v", err)
+	}
+	if _, err := ValidateActionToken(ticket, "password_reset"); err == nil {
+		t.Error("ValidateActionToken() accepted a token with another purpose")
+	}
+	if _, err := ValidateActionToken(access, ""); err == nil {
+		t.Error("ValidateActionToken() accepted an access token")
+	}
+}
@@ -0,0 +1,330 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 簽章演算法
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// keysetReloadInterval 檢查金鑰設定檔是否更新的間隔,新增金鑰不需重新啟動服務
+const keysetReloadInterval = time.Minute
+
+// signingKey 一把簽章金鑰
+// ActiveFrom 之後開始用於簽章;RetireAt 之前仍可驗證(與下一把金鑰重疊,讓已核發的 token 在到期前持續有效)
+type signingKey struct {
+	ID         string
+	ActiveFrom time.Time
+	RetireAt   *time.Time
+	private    crypto.Signer
+	public     crypto.PublicKey
+}
+
+// keysetFile JWT_KEYSET_FILE 的格式
+type keysetFile struct {
+	Keys []struct {
+		KID            string     `json:"kid"`
+		PrivateKeyFile string     `json:"private_key_file"`
+		ActiveFrom     time.Time  `json:"active_from"`
+		RetireAt       *time.Time `json:"retire_at,omitempty"`
+	} `json:"keys"`
+}
+
+// JWK 公開金鑰 (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKSet 公開金鑰集合
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type keyset struct {
+	mu         sync.RWMutex
+	algorithm  string
+	secret     []byte
+	keys       []*signingKey
+	path       string
+	modTime    time.Time
+	lastLoaded time.Time
+}
+
+var (
+	keys     *keyset
+	keysOnce sync.Once
+)
+
+// Algorithm 目前使用的簽章演算法,由 JWT_ALGORITHM 設定,預設 HS256
+func Algorithm() string {
+	switch algorithm := os.Getenv("JWT_ALGORITHM"); algorithm {
+	case AlgorithmRS256, AlgorithmEdDSA:
+		return algorithm
+	default:
+		return AlgorithmHS256
+	}
+}
+
+// getKeyset 初始化金鑰;HS256 使用 JWT_SECRET,RS256/EdDSA 使用 JWT_KEYSET_FILE
+func getKeyset() *keyset {
+	keysOnce.Do(func() {
+		keys = &keyset{algorithm: Algorithm()}
+
+		if keys.algorithm == AlgorithmHS256 {
+			secret := os.Getenv("JWT_SECRET")
+			if secret == "" {
+				panic("JWT_SECRET environment variable is required")
+			}
+			keys.secret = []byte(secret)
+			return
+		}
+
+		keys.path = os.Getenv("JWT_KEYSET_FILE")
+		if keys.path == "" {
+			panic("JWT_KEYSET_FILE environment variable is required for " + keys.algorithm)
+		}
+		if err := keys.load(); err != nil {
+			panic(fmt.Sprintf("failed to load JWT keyset: %v", err))
+		}
+	})
+
+	keys.reloadIfChanged()
+	return keys
+}
+
+// load 讀取金鑰設定檔與私鑰
+func (k *keyset) load() error {
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		return err
+	}
+
+	file := &keysetFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return err
+	}
+
+	loaded := make([]*signingKey, 0, len(file.Keys))
+	for _, entry := range file.Keys {
+		if entry.KID == "" {
+			return errors.New("kid is required")
+		}
+
+		// 相對路徑以設定檔所在目錄為準
+		keyPath := entry.PrivateKeyFile
+		if !filepath.IsAbs(keyPath) {
+			keyPath = filepath.Join(filepath.Dir(k.path), keyPath)
+		}
+
+		pemData, err := os.ReadFile(keyPath)
+		if err != nil {
+			return fmt.Errorf("kid %s: %w", entry.KID, err)
+		}
+
+		key := &signingKey{ID: entry.KID, ActiveFrom: entry.ActiveFrom, RetireAt: entry.RetireAt}
+		switch k.algorithm {
+		case AlgorithmRS256:
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(pemData)
+			if err != nil {
+				return fmt.Errorf("kid %s: %w", entry.KID, err)
+			}
+			key.private, key.public = private, &private.PublicKey
+		case AlgorithmEdDSA:
+			private, err := jwt.ParseEdPrivateKeyFromPEM(pemData)
+			if err != nil {
+				return fmt.Errorf("kid %s: %w", entry.KID, err)
+			}
+			signer, ok := private.(ed25519.PrivateKey)
+			if !ok {
+				return fmt.Errorf("kid %s: not an Ed25519 key", entry.KID)
+			}
+			key.private, key.public = signer, signer.Public()
+		}
+
+		loaded = append(loaded, key)
+	}
+
+	// 依啟用時間排序,最新的在最後
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].ActiveFrom.Before(loaded[j].ActiveFrom) })
+
+	k.mu.Lock()
+	k.keys = loaded
+	k.modTime = info.ModTime()
+	k.lastLoaded = time.Now()
+	k.mu.Unlock()
+
+	log.Info("JWT keyset loaded:", len(loaded), "keys")
+	return nil
+}
+
+// reloadIfChanged 設定檔更新時重新載入,載入失敗時沿用舊的金鑰
+func (k *keyset) reloadIfChanged() {
+	if k.path == "" {
+		return
+	}
+
+	k.mu.RLock()
+	due := time.Since(k.lastLoaded) > keysetReloadInterval
+	modTime := k.modTime
+	k.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	info, err := os.Stat(k.path)
+	if err != nil || info.ModTime().Equal(modTime) {
+		k.mu.Lock()
+		k.lastLoaded = time.Now()
+		k.mu.Unlock()
+		return
+	}
+
+	if err := k.load(); err != nil {
+		log.Error("Failed to reload JWT keyset:", err)
+	}
+}
+
+// current 目前用於簽章的金鑰:已啟用且未退役的金鑰中最新的一把
+func (k *keyset) current(now time.Time) (*signingKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		key := k.keys[i]
+		if !now.Before(key.ActiveFrom) && (key.RetireAt == nil || now.Before(*key.RetireAt)) {
+			return key, nil
+		}
+	}
+
+	return nil, errors.New("no active JWT signing key")
+}
+
+// lookup 依 kid 取得仍可驗證的金鑰(未退役,包含尚未啟用但已預先發布的金鑰)
+func (k *keyset) lookup(kid string, now time.Time) (*signingKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.keys {
+		if key.ID == kid && (key.RetireAt == nil || now.Before(*key.RetireAt)) {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown or retired kid %q", kid)
+}
+
+func (k *keyset) method() jwt.SigningMethod {
+	switch k.algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+// signToken 以目前的金鑰簽章,非對稱金鑰會在 header 中帶入 kid
+func signToken(claims jwt.Claims) (string, error) {
+	k := getKeyset()
+	token := jwt.NewWithClaims(k.method(), claims)
+
+	if k.algorithm == AlgorithmHS256 {
+		return token.SignedString(k.secret)
+	}
+
+	key, err := k.current(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+// parseToken 驗證簽章,只接受設定的演算法,並依 kid 選擇驗證金鑰
+func parseToken(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	k := getKeyset()
+
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if k.algorithm == AlgorithmHS256 {
+			return k.secret, nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, err := k.lookup(kid, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return key.public, nil
+	}, jwt.WithValidMethods([]string{k.method().Alg()}))
+}
+
+// JWKS 回傳仍可驗證的公開金鑰,供其他內部服務驗證本服務核發的 token
+// HS256 為對稱金鑰,不對外公開
+func JWKS() *JWKSet {
+	k := getKeyset()
+	set := &JWKSet{Keys: []JWK{}}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := time.Now()
+	for _, key := range k.keys {
+		if key.RetireAt != nil && !now.Before(*key.RetireAt) {
+			continue
+		}
+
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: k.algorithm}
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
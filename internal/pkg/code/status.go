@@ -3,14 +3,19 @@ package code
 import "time"
 
 const (
-	Successful          = 200
-	Sync                = 202
-	JWTRejected         = 401
-	PermissionDenied    = 403
-	DoesNotExist        = 404
-	FormatError         = 415
-	InternalServerError = 500
-	ServerDown          = 503
+	Successful           = 200
+	Sync                 = 202
+	JWTRejected          = 401
+	PermissionDenied     = 403
+	DoesNotExist         = 404
+	Conflict             = 409
+	PreconditionFailed   = 412
+	FormatError          = 415
+	UnprocessableEntity  = 422
+	PreconditionRequired = 428
+	TooManyRequests      = 429
+	InternalServerError  = 500
+	ServerDown           = 503
 )
 
 var (
@@ -20,7 +25,12 @@ var (
 		401: "JWT rejected.",
 		403: "Permission denied.",
 		404: "Item does not exist.",
+		409: "Conflict.",
+		412: "Precondition failed.",
 		415: "Data format error.",
+		422: "Unprocessable entity.",
+		428: "Precondition required.",
+		429: "Too many requests.",
 		500: "Unexpected server error.",
 		503: "Server down.",
 	}
@@ -37,6 +47,8 @@ type SuccessfulMessage struct {
 	codeTime
 	//正確回傳內容
 	Body interface{} `json:"body"`
+	//警告(例如可能重複的報備),沒有警告時不回傳
+	Warnings interface{} `json:"warnings,omitempty"`
 }
 
 type ErrorMessage struct {
@@ -67,6 +79,7 @@ func setSuccessfulMessage(code int, body interface{}) *SuccessfulMessage {
 			time.Now().Format(time.RFC3339),
 		},
 		body,
+		nil,
 	}
 }
 
@@ -0,0 +1,156 @@
+package directory
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/util"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+var (
+	ErrDisabled           = errors.New("未設定 LDAP 目錄服務")
+	ErrUnavailable        = errors.New("目錄服務暫時無法連線，請稍後再試")
+	ErrInvalidCredentials = errors.New("LDAP 帳號或密碼錯誤")
+	ErrUserNotFound       = errors.New("LDAP 查無此帳號")
+	ErrNoRole             = errors.New("您的帳號不屬於任何可登入的群組")
+)
+
+// Entry 目錄中的使用者
+type Entry struct {
+	DN       string
+	Username string
+	Email    string
+	// 所屬群組名稱(取自 memberOf 的 CN)
+	Groups []string
+}
+
+// Enabled 是否已設定 LDAP
+func Enabled() bool {
+	return os.Getenv("LDAP_URL") != "" && os.Getenv("LDAP_BASE_DN") != ""
+}
+
+// Authenticate 以服務帳號搜尋使用者後,以使用者 DN 與密碼進行 bind 驗證
+func Authenticate(username, password string) (*Entry, error) {
+	if !Enabled() {
+		return nil, ErrDisabled
+	}
+
+	// 空密碼會被視為匿名 bind 而成功,必須先擋下
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := dial()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer conn.Close()
+
+	if bindDN := os.Getenv("LDAP_BIND_DN"); bindDN != "" {
+		err = conn.Bind(bindDN, os.Getenv("LDAP_BIND_PASSWORD"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: service account bind failed: %v", ErrUnavailable, err)
+		}
+	}
+
+	emailAttribute := env("LDAP_EMAIL_ATTRIBUTE", "mail")
+	groupAttribute := env("LDAP_GROUP_ATTRIBUTE", "memberOf")
+
+	request := ldap.NewSearchRequest(
+		os.Getenv("LDAP_BASE_DN"),
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 10, false,
+		fmt.Sprintf(env("LDAP_USER_FILTER", "(sAMAccountName=%s)"), ldap.EscapeFilter(username)),
+		[]string{"dn", emailAttribute, groupAttribute},
+		nil,
+	)
+
+	result, err := conn.Search(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: search failed: %v", ErrUnavailable, err)
+	}
+
+	if len(result.Entries) != 1 {
+		return nil, ErrUserNotFound
+	}
+
+	entry := result.Entries[0]
+	err = conn.Bind(entry.DN, password)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("%w: user bind failed: %v", ErrUnavailable, err)
+	}
+
+	return &Entry{
+		DN:       entry.DN,
+		Username: username,
+		Email:    entry.GetAttributeValue(emailAttribute),
+		Groups:   groupNames(entry.GetAttributeValues(groupAttribute)),
+	}, nil
+}
+
+// RoleForGroups 依 LDAP_ROLE_MAPPING 將群組轉換為角色,沒有符合的群組時使用 LDAP_DEFAULT_ROLE
+func RoleForGroups(groups []string) (string, error) {
+	role, ok := util.RoleForGroups(os.Getenv("LDAP_ROLE_MAPPING"), os.Getenv("LDAP_DEFAULT_ROLE"), groups)
+	if !ok {
+		return "", ErrNoRole
+	}
+
+	return role, nil
+}
+
+func dial() (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: os.Getenv("LDAP_INSECURE_SKIP_VERIFY") == "true"}
+
+	conn, err := ldap.DialURL(os.Getenv("LDAP_URL"),
+		ldap.DialWithTLSConfig(tlsConfig),
+		ldap.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(10 * time.Second)
+
+	if os.Getenv("LDAP_START_TLS") == "true" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// groupNames 取出群組 DN 中的 CN,例如 CN=ESST-Admins,OU=Groups,DC=corp,DC=local → ESST-Admins
+func groupNames(dns []string) []string {
+	names := make([]string, 0, len(dns))
+	for _, dn := range dns {
+		parsed, err := ldap.ParseDN(dn)
+		if err != nil || len(parsed.RDNs) == 0 {
+			names = append(names, dn)
+			continue
+		}
+
+		for _, attribute := range parsed.RDNs[0].Attributes {
+			if strings.EqualFold(attribute.Type, "CN") {
+				names = append(names, attribute.Value)
+			}
+		}
+	}
+	return names
+}
+
+func env(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,99 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"sync"
+)
+
+var (
+	encryptionKey     []byte
+	encryptionKeyErr  error
+	encryptionKeyOnce sync.Once
+)
+
+// 初始化加密金鑰 (ENCRYPTION_KEY 為 base64 編碼的 32 bytes 金鑰)
+func initKey() {
+	encryptionKeyOnce.Do(func() {
+		secret := os.Getenv("ENCRYPTION_KEY")
+		if secret == "" {
+			encryptionKeyErr = errors.New("ENCRYPTION_KEY environment variable is required")
+			return
+		}
+
+		key, err := base64.StdEncoding.DecodeString(secret)
+		if err != nil {
+			encryptionKeyErr = err
+			return
+		}
+		if len(key) != 32 {
+			encryptionKeyErr = errors.New("ENCRYPTION_KEY must decode to 32 bytes")
+			return
+		}
+
+		encryptionKey = key
+	})
+}
+
+// Encrypt 使用 AES-256-GCM 加密字串
+func Encrypt(plaintext string) (string, error) {
+	initKey()
+	if encryptionKeyErr != nil {
+		return "", encryptionKeyErr
+	}
+
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密 Encrypt 產生的字串
+func Decrypt(ciphertext string) (string, error) {
+	initKey()
+	if encryptionKeyErr != nil {
+		return "", encryptionKeyErr
+	}
+
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func newGCM() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
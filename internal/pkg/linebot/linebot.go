@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/pricing"
 )
 
 // LineBotService LINE Bot 服務介面
@@ -16,11 +18,17 @@ type LineBotService interface {
 	SendProjectStep1Notification(data *ProjectStep1Data) error
 	SendProjectStep2Notification(data *ProjectStep2Data) error
 	SendStockNotification(data *StockData) error
+	SendProjectExpiryNotification(data *ProjectExpiryData) error
+	SendProjectApprovalNotification(data *ProjectApprovalData) error
+	SendCommentMentionNotification(data *CommentMentionData) error
+	SendQuotationNotification(data *QuotationData) error
+	SendEquipmentChangeNotification(data *EquipmentChangeData) error
 }
 
 type lineBotService struct {
 	channelAccessToken string
 	groupID            string
+	managerGroupID     string
 	httpClient         *http.Client
 }
 
@@ -35,6 +43,10 @@ type ProjectStep1Data struct {
 	Remark       string
 	Equipments   []Equipment
 	CreatedTime  time.Time
+	// ApprovalStatus 審核狀態(pending 時顯示待審核)
+	ApprovalStatus string
+	// Pricing 報價金額(只發送給主管群組,尚未報價時為 nil)
+	Pricing *Pricing
 }
 
 // ProjectStep2Data 第二階段專案資料
@@ -51,6 +63,10 @@ type ProjectStep2Data struct {
 	SpecialRequirements    string
 	Equipments             []Equipment
 	UpdatedTime            time.Time
+	// Coverage 現貨支應摘要(沒有分配現貨時為 nil)
+	Coverage *StockCoverage
+	// Pricing 報價金額(只發送給主管群組,尚未報價時為 nil)
+	Pricing *Pricing
 }
 
 // StockData 現貨報備資料
@@ -73,6 +89,47 @@ type StockData struct {
 	CreatedTime            time.Time
 }
 
+// ProjectExpiryData 專案保護期到期資料
+type ProjectExpiryData struct {
+	ProjectID   string
+	ProjectName string
+	ContactName string
+	Owner       string
+	ExpiresAt   time.Time
+	// Expired 已到期(false 表示到期前提醒)
+	Expired bool
+}
+
+// ProjectApprovalData 專案審核資料
+type ProjectApprovalData struct {
+	ProjectID   string
+	ProjectName string
+	ContactName string
+	Owner       string
+	// Level 審核關卡
+	Level int
+	// ApproverRoles 可審核的角色
+	ApproverRoles []string
+	// Status 審核結果(pending 表示待審核通知)
+	Status string
+	// Reason 核准說明或駁回原因
+	Reason string
+	// DecidedBy 審核者名稱
+	DecidedBy string
+}
+
+// CommentMentionData 留言提及資料
+type CommentMentionData struct {
+	// TargetType 留言對象類型(project / stock)
+	TargetType string
+	TargetID   string
+	TargetName string
+	Author     string
+	Body       string
+	// Mentioned 被提及的使用者帳號
+	Mentioned []string
+}
+
 // Equipment 設備資料
 type Equipment struct {
 	PartNumber  string
@@ -80,6 +137,79 @@ type Equipment struct {
 	Description string
 }
 
+// StockCoverage 專案設備由現貨支應與需另行採購的數量
+type StockCoverage struct {
+	FromStock int64
+	ToOrder   int64
+	Lines     []CoverageLine
+}
+
+// CoverageLine 單筆設備的現貨支應情況
+type CoverageLine struct {
+	PartNumber string
+	Quantity   int64
+	FromStock  int64
+	ToOrder    int64
+}
+
+// Pricing 專案設備的報價金額
+type Pricing struct {
+	Lines  []PricedLine
+	Totals []Total
+	// Unpriced 尚未填寫單價的設備數
+	Unpriced int
+}
+
+// PricedLine 單筆設備的報價金額
+type PricedLine struct {
+	PartNumber string
+	Quantity   int64
+	Currency   string
+	UnitPrice  pricing.Money
+	Total      pricing.Money
+}
+
+// Total 單一幣別的總計
+type Total struct {
+	Currency string
+	Subtotal pricing.Money
+	Discount pricing.Money
+	Tax      pricing.Money
+	Total    pricing.Money
+}
+
+// QuotationData 報價單資料
+type QuotationData struct {
+	ProjectID   string
+	ProjectName string
+	Version     int64
+	// Status 報價單狀態(sent / accepted)
+	Status  string
+	SentTo  string
+	Total   Total
+	Updated time.Time
+}
+
+// EquipmentChangeData 設備清單異動資料
+type EquipmentChangeData struct {
+	// TargetType 對象類型(project / stock)
+	TargetType string
+	TargetID   string
+	TargetName string
+	// ChangedBy 異動者名稱
+	ChangedBy string
+	Added     []Equipment
+	Updated   []EquipmentChange
+	Deleted   []Equipment
+	ChangedAt time.Time
+}
+
+// EquipmentChange 單筆設備異動前後的內容
+type EquipmentChange struct {
+	Before Equipment
+	After  Equipment
+}
+
 // LINE Messaging API 的訊息結構
 type lineMessage struct {
 	To       string        `json:"to"`
@@ -96,6 +226,7 @@ func New() LineBotService {
 	return &lineBotService{
 		channelAccessToken: os.Getenv("LINE_CHANNEL_ACCESS_TOKEN"),
 		groupID:            os.Getenv("LINE_GROUP_ID"),
+		managerGroupID:     os.Getenv("LINE_MANAGER_GROUP_ID"),
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -105,13 +236,13 @@ func New() LineBotService {
 // SendProjectStep1Notification 發送第一階段專案報備通知
 func (s *lineBotService) SendProjectStep1Notification(data *ProjectStep1Data) error {
 	message := s.buildStep1Message(data)
-	return s.sendMessage(message)
+	return s.sendWithPricing(message, data.Pricing)
 }
 
 // SendProjectStep2Notification 發送第二階段專案報備通知
 func (s *lineBotService) SendProjectStep2Notification(data *ProjectStep2Data) error {
 	message := s.buildStep2Message(data)
-	return s.sendMessage(message)
+	return s.sendWithPricing(message, data.Pricing)
 }
 
 // SendStockNotification 發送現貨報備通知
@@ -120,6 +251,54 @@ func (s *lineBotService) SendStockNotification(data *StockData) error {
 	return s.sendMessage(message)
 }
 
+// SendProjectExpiryNotification 發送專案保護期到期提醒或已到期通知
+func (s *lineBotService) SendProjectExpiryNotification(data *ProjectExpiryData) error {
+	message := s.buildExpiryMessage(data)
+	return s.sendMessage(message)
+}
+
+// SendProjectApprovalNotification 發送專案待審核或審核結果通知
+func (s *lineBotService) SendProjectApprovalNotification(data *ProjectApprovalData) error {
+	message := s.buildApprovalMessage(data)
+	return s.sendMessage(message)
+}
+
+// SendCommentMentionNotification 發送留言提及通知
+func (s *lineBotService) SendCommentMentionNotification(data *CommentMentionData) error {
+	message := s.buildMentionMessage(data)
+	return s.sendMessage(message)
+}
+
+// SendQuotationNotification 發送報價單寄出或接受通知,含金額因此只發送給主管群組
+func (s *lineBotService) SendQuotationNotification(data *QuotationData) error {
+	if s.managerGroupID == "" {
+		log.Info("LINE_MANAGER_GROUP_ID is not set, skip quotation notification")
+		return nil
+	}
+
+	message := s.buildQuotationMessage(data)
+	return s.sendMessageTo(s.managerGroupID, message)
+}
+
+// SendEquipmentChangeNotification 發送設備清單異動通知
+func (s *lineBotService) SendEquipmentChangeNotification(data *EquipmentChangeData) error {
+	message := s.buildEquipmentChangeMessage(data)
+	return s.sendMessage(message)
+}
+
+// sendWithPricing 一般群組收到不含金額的訊息,設定主管群組時另發一份附上金額的訊息
+func (s *lineBotService) sendWithPricing(message string, data *Pricing) error {
+	if err := s.sendMessage(message); err != nil {
+		return err
+	}
+
+	if data == nil || s.managerGroupID == "" {
+		return nil
+	}
+
+	return s.sendMessageTo(s.managerGroupID, message+"\n\n"+buildPricingSection(data))
+}
+
 // buildStep1Message 建立第一階段訊息
 func (s *lineBotService) buildStep1Message(data *ProjectStep1Data) string {
 	var msg bytes.Buffer
@@ -132,7 +311,11 @@ func (s *lineBotService) buildStep1Message(data *ProjectStep1Data) string {
 	msg.WriteString(fmt.Sprintf("• 專案編號: %s\n", data.ProjectID))
 	msg.WriteString(fmt.Sprintf("• 專案名稱: %s\n", data.ProjectName))
 	msg.WriteString(fmt.Sprintf("• 建立時間: %s\n", data.CreatedTime.Format("2006-01-02 15:04:05")))
-	msg.WriteString(fmt.Sprintf("• 專案報備: 第一階段\n\n"))
+	msg.WriteString(fmt.Sprintf("• 專案報備: 第一階段\n"))
+	if data.ApprovalStatus == "pending" {
+		msg.WriteString("• 審核狀態: 待審核\n")
+	}
+	msg.WriteString("\n")
 
 	// 聯絡人資訊
 	msg.WriteString("👤 聯絡人資訊\n")
@@ -164,7 +347,11 @@ func (s *lineBotService) buildStep1Message(data *ProjectStep1Data) string {
 		msg.WriteString(fmt.Sprintf("%s\n\n", data.Remark))
 	}
 
-	msg.WriteString("⚠️ 提醒:專案得標後,請記得填寫第二階段交貨資訊")
+	if data.ApprovalStatus == "pending" {
+		msg.WriteString("⚠️ 提醒:報備待主管審核,核准後才能填寫第二階段交貨資訊")
+	} else {
+		msg.WriteString("⚠️ 提醒:專案得標後,請記得填寫第二階段交貨資訊")
+	}
 
 	return msg.String()
 }
@@ -211,6 +398,17 @@ func (s *lineBotService) buildStep2Message(data *ProjectStep2Data) string {
 		msg.WriteString("\n")
 	}
 
+	// 現貨支應
+	if data.Coverage != nil {
+		msg.WriteString("🏬 現貨支應\n")
+		msg.WriteString(fmt.Sprintf("• 現貨支應: %d / 需採購: %d\n", data.Coverage.FromStock, data.Coverage.ToOrder))
+		for i, line := range data.Coverage.Lines {
+			msg.WriteString(fmt.Sprintf("%d. 料號: %s\n", i+1, line.PartNumber))
+			msg.WriteString(fmt.Sprintf("   需求 %d / 現貨 %d / 需採購 %d\n", line.Quantity, line.FromStock, line.ToOrder))
+		}
+		msg.WriteString("\n")
+	}
+
 	// 交貨地址
 	if data.DeliveryAddress != "" {
 		msg.WriteString("📍 交貨地址\n")
@@ -305,23 +503,220 @@ func (s *lineBotService) buildStockMessage(data *StockData) string {
 	return msg.String()
 }
 
+// buildExpiryMessage 建立保護期到期訊息
+func (s *lineBotService) buildExpiryMessage(data *ProjectExpiryData) string {
+	var msg bytes.Buffer
+
+	if data.Expired {
+		msg.WriteString("⌛ 【專案報備已過期】\n")
+	} else {
+		msg.WriteString("⏰ 【專案報備即將到期】\n")
+	}
+	msg.WriteString("━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	msg.WriteString("📌 專案基本資訊\n")
+	msg.WriteString(fmt.Sprintf("• 專案編號: %s\n", data.ProjectID))
+	msg.WriteString(fmt.Sprintf("• 專案名稱: %s\n", data.ProjectName))
+	msg.WriteString(fmt.Sprintf("• 聯絡人: %s\n", data.ContactName))
+	msg.WriteString(fmt.Sprintf("• 雙欣負責人: %s\n", data.Owner))
+	msg.WriteString(fmt.Sprintf("• 保護期到期: %s\n\n", data.ExpiresAt.Format("2006-01-02 15:04")))
+
+	if data.Expired {
+		msg.WriteString("⚠️ 保護期已過,如需繼續保護請申請延長並由管理員核准")
+	} else {
+		msg.WriteString("⚠️ 提醒:請於到期前填寫第二階段交貨資訊,或申請延長保護期")
+	}
+
+	return msg.String()
+}
+
+// buildApprovalMessage 建立審核訊息
+func (s *lineBotService) buildApprovalMessage(data *ProjectApprovalData) string {
+	var msg bytes.Buffer
+
+	switch data.Status {
+	case "approved":
+		msg.WriteString("✅ 【專案報備已核准】\n")
+	case "rejected":
+		msg.WriteString("❌ 【專案報備已駁回】\n")
+	default:
+		msg.WriteString("📝 【專案報備待審核】\n")
+	}
+	msg.WriteString("━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	msg.WriteString("📌 專案基本資訊\n")
+	msg.WriteString(fmt.Sprintf("• 專案編號: %s\n", data.ProjectID))
+	msg.WriteString(fmt.Sprintf("• 專案名稱: %s\n", data.ProjectName))
+	msg.WriteString(fmt.Sprintf("• 聯絡人: %s\n", data.ContactName))
+	msg.WriteString(fmt.Sprintf("• 雙欣負責人: %s\n", data.Owner))
+	msg.WriteString(fmt.Sprintf("• 審核關卡: 第 %d 關\n\n", data.Level))
+
+	switch data.Status {
+	case "approved", "rejected":
+		if data.DecidedBy != "" {
+			msg.WriteString(fmt.Sprintf("👤 審核者: %s\n", data.DecidedBy))
+		}
+		if data.Reason != "" {
+			msg.WriteString(fmt.Sprintf("📝 說明: %s\n", data.Reason))
+		}
+	default:
+		msg.WriteString(fmt.Sprintf("⚠️ 請 %s 至系統審核此報備", strings.Join(data.ApproverRoles, " / ")))
+	}
+
+	return strings.TrimRight(msg.String(), "\n")
+}
+
+// buildMentionMessage 建立留言提及訊息
+func (s *lineBotService) buildMentionMessage(data *CommentMentionData) string {
+	var msg bytes.Buffer
+
+	msg.WriteString("💬 【留言提及通知】\n")
+	msg.WriteString("━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if data.TargetType == "stock" {
+		msg.WriteString(fmt.Sprintf("• 現貨編號: %s\n", data.TargetID))
+		msg.WriteString(fmt.Sprintf("• 現貨名稱: %s\n", data.TargetName))
+	} else {
+		msg.WriteString(fmt.Sprintf("• 專案編號: %s\n", data.TargetID))
+		msg.WriteString(fmt.Sprintf("• 專案名稱: %s\n", data.TargetName))
+	}
+	msg.WriteString(fmt.Sprintf("• 留言者: %s\n", data.Author))
+	msg.WriteString(fmt.Sprintf("• 提及: @%s\n\n", strings.Join(data.Mentioned, " @")))
+
+	msg.WriteString("📝 留言內容\n")
+	msg.WriteString(data.Body)
+
+	return msg.String()
+}
+
+// buildQuotationMessage 建立報價單通知訊息
+func (s *lineBotService) buildQuotationMessage(data *QuotationData) string {
+	var msg bytes.Buffer
+
+	if data.Status == "accepted" {
+		msg.WriteString("🤝 【報價單已接受】\n")
+	} else {
+		msg.WriteString("📄 【報價單已寄出】\n")
+	}
+	msg.WriteString("━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	msg.WriteString(fmt.Sprintf("• 專案編號: %s\n", data.ProjectID))
+	msg.WriteString(fmt.Sprintf("• 專案名稱: %s\n", data.ProjectName))
+	msg.WriteString(fmt.Sprintf("• 報價版本: 第 %d 版\n", data.Version))
+	if data.SentTo != "" {
+		msg.WriteString(fmt.Sprintf("• 寄送信箱: %s\n", data.SentTo))
+	}
+	msg.WriteString(fmt.Sprintf("• 時間: %s\n\n", data.Updated.Format("2006-01-02 15:04:05")))
+
+	msg.WriteString("💰 報價金額\n")
+	writeTotal(&msg, data.Total)
+
+	return msg.String()
+}
+
+// buildEquipmentChangeMessage 建立設備清單異動訊息
+func (s *lineBotService) buildEquipmentChangeMessage(data *EquipmentChangeData) string {
+	var msg bytes.Buffer
+
+	msg.WriteString("🔄 【設備清單異動】\n")
+	msg.WriteString("━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if data.TargetType == "stock" {
+		msg.WriteString(fmt.Sprintf("• 現貨編號: %s\n", data.TargetID))
+		msg.WriteString(fmt.Sprintf("• 現貨名稱: %s\n", data.TargetName))
+	} else {
+		msg.WriteString(fmt.Sprintf("• 專案編號: %s\n", data.TargetID))
+		msg.WriteString(fmt.Sprintf("• 專案名稱: %s\n", data.TargetName))
+	}
+	if data.ChangedBy != "" {
+		msg.WriteString(fmt.Sprintf("• 異動者: %s\n", data.ChangedBy))
+	}
+	msg.WriteString(fmt.Sprintf("• 異動時間: %s\n", data.ChangedAt.Format("2006-01-02 15:04:05")))
+
+	if len(data.Added) > 0 {
+		msg.WriteString(fmt.Sprintf("\n➕ 新增設備 (%d 項)\n", len(data.Added)))
+		for i, eq := range data.Added {
+			msg.WriteString(fmt.Sprintf("%d. %s × %d\n", i+1, eq.PartNumber, eq.Quantity))
+			if eq.Description != "" {
+				msg.WriteString(fmt.Sprintf("   %s\n", eq.Description))
+			}
+		}
+	}
+
+	if len(data.Updated) > 0 {
+		msg.WriteString(fmt.Sprintf("\n✏️ 修改設備 (%d 項)\n", len(data.Updated)))
+		for i, change := range data.Updated {
+			if change.Before.PartNumber == change.After.PartNumber {
+				msg.WriteString(fmt.Sprintf("%d. %s × %d → %d\n", i+1, change.After.PartNumber, change.Before.Quantity, change.After.Quantity))
+			} else {
+				msg.WriteString(fmt.Sprintf("%d. %s × %d → %s × %d\n", i+1, change.Before.PartNumber, change.Before.Quantity, change.After.PartNumber, change.After.Quantity))
+			}
+		}
+	}
+
+	if len(data.Deleted) > 0 {
+		msg.WriteString(fmt.Sprintf("\n➖ 刪除設備 (%d 項)\n", len(data.Deleted)))
+		for i, eq := range data.Deleted {
+			msg.WriteString(fmt.Sprintf("%d. %s × %d\n", i+1, eq.PartNumber, eq.Quantity))
+		}
+	}
+
+	return msg.String()
+}
+
+// buildPricingSection 建立報價金額區塊(只附在主管群組的訊息)
+func buildPricingSection(data *Pricing) string {
+	var msg bytes.Buffer
+
+	msg.WriteString("💰 報價金額(主管限定)\n")
+	for i, line := range data.Lines {
+		msg.WriteString(fmt.Sprintf("%d. %s × %d\n", i+1, line.PartNumber, line.Quantity))
+		msg.WriteString(fmt.Sprintf("   單價 %s %s / 含稅 %s\n", line.Currency, pricing.Format(line.UnitPrice), pricing.Format(line.Total)))
+	}
+	for _, total := range data.Totals {
+		msg.WriteString("\n")
+		writeTotal(&msg, total)
+	}
+	if data.Unpriced > 0 {
+		msg.WriteString(fmt.Sprintf("\n⚠️ 尚有 %d 項設備未報價\n", data.Unpriced))
+	}
+
+	return msg.String()
+}
+
+// writeTotal 寫入單一幣別的總計
+func writeTotal(msg *bytes.Buffer, total Total) {
+	msg.WriteString(fmt.Sprintf("• 小計: %s %s\n", total.Currency, pricing.Format(total.Subtotal)))
+	if total.Discount > 0 {
+		msg.WriteString(fmt.Sprintf("• 折扣: -%s %s\n", total.Currency, pricing.Format(total.Discount)))
+	}
+	msg.WriteString(fmt.Sprintf("• 稅額: %s %s\n", total.Currency, pricing.Format(total.Tax)))
+	msg.WriteString(fmt.Sprintf("• 總計: %s %s\n", total.Currency, pricing.Format(total.Total)))
+}
+
 // sendMessage 發送訊息到 LINE 群組
 func (s *lineBotService) sendMessage(text string) error {
-	if s.channelAccessToken == "" {
-		return fmt.Errorf("LINE_CHANNEL_ACCESS_TOKEN is not set")
-	}
 	if s.groupID == "" {
 		return fmt.Errorf("LINE_GROUP_ID is not set")
 	}
 
+	return s.sendMessageTo(s.groupID, text)
+}
+
+// sendMessageTo 發送訊息到指定的 LINE 群組
+func (s *lineBotService) sendMessageTo(to, text string) error {
+	if s.channelAccessToken == "" {
+		return fmt.Errorf("LINE_CHANNEL_ACCESS_TOKEN is not set")
+	}
+
 	// 檢查訊息長度 (LINE 限制 5000 字元)
 	if len(text) > 5000 {
 		log.Info("Message too long, splitting into multiple messages")
-		return s.sendLongMessage(text)
+		return s.sendLongMessage(to, text)
 	}
 
 	message := lineMessage{
-		To: s.groupID,
+		To: to,
 		Messages: []interface{}{
 			textMessage{
 				Type: "text",
@@ -359,12 +754,12 @@ func (s *lineBotService) sendMessage(text string) error {
 		return fmt.Errorf("LINE API returned status %d: %v", resp.StatusCode, errorResponse)
 	}
 
-	log.Info("LINE notification sent successfully to group:", s.groupID)
+	log.Info("LINE notification sent successfully to group:", to)
 	return nil
 }
 
 // sendLongMessage 發送長訊息(分割成多則)
-func (s *lineBotService) sendLongMessage(text string) error {
+func (s *lineBotService) sendLongMessage(to, text string) error {
 	const maxLength = 4500 // 留一些緩衝空間
 
 	for len(text) > 0 {
@@ -377,7 +772,7 @@ func (s *lineBotService) sendLongMessage(text string) error {
 			}
 		}
 
-		if err := s.sendMessage(text[:end]); err != nil {
+		if err := s.sendMessageTo(to, text[:end]); err != nil {
 			return err
 		}
 
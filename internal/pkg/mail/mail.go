@@ -5,10 +5,12 @@ import (
 	"crypto/tls"
 	"fmt"
 	"html/template"
+	"io"
 	"os"
 	"time"
 
 	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/pricing"
 
 	"gopkg.in/gomail.v2"
 )
@@ -17,6 +19,14 @@ type EmailService interface {
 	SendProjectStep1Email(data *ProjectStep1Data) error
 	SendProjectStep2Email(data *ProjectStep2Data) error
 	SendVerificationCode(email, code, username string) error // 新增
+	SendPasswordResetEmail(email, username, resetURL string, expiresAt time.Time) error
+	SendPasswordChangedEmail(email, username string, changedAt time.Time) error
+	SendAccountLockedEmail(email, username string, lockedUntil time.Time) error
+	SendProjectExpiryWarningEmail(email, username string, data *ProjectExpiryData) error
+	SendApprovalRequestEmail(email, username string, data *ProjectApprovalData) error
+	SendApprovalDecisionEmail(email, username string, data *ProjectApprovalData) error
+	SendCommentMentionEmail(email, username string, data *CommentMentionData) error
+	SendQuotationEmail(email string, data *QuotationData, attachment []byte) error
 }
 
 type emailService struct {
@@ -57,6 +67,60 @@ type ProjectStep2Data struct {
 	UpdatedTime            time.Time
 }
 
+// ProjectExpiryData 專案保護期到期資料
+type ProjectExpiryData struct {
+	ProjectID   string
+	ProjectName string
+	Owner       string
+	ExpiresAt   time.Time
+}
+
+// ProjectApprovalData 專案審核資料
+type ProjectApprovalData struct {
+	ProjectID   string
+	ProjectName string
+	Owner       string
+	Level       int
+	Status      string
+	Reason      string
+	DecidedBy   string
+}
+
+// CommentMentionData 留言提及資料
+type CommentMentionData struct {
+	TargetType string
+	TargetID   string
+	TargetName string
+	Author     string
+	Body       string
+}
+
+// QuotationData 報價單資料
+type QuotationData struct {
+	ProjectID   string
+	ProjectName string
+	ContactName string
+	Version     int64
+	Currency    string
+	Lines       []QuotationLine
+	Subtotal    pricing.Money
+	Discount    pricing.Money
+	Tax         pricing.Money
+	Total       pricing.Money
+	ValidUntil  time.Time
+	Note        string
+}
+
+// QuotationLine 報價單明細
+type QuotationLine struct {
+	PartNumber  string
+	Description string
+	Quantity    int64
+	UnitPrice   pricing.Money
+	// Amount 折扣後未稅金額
+	Amount pricing.Money
+}
+
 // Equipment 設備資料
 type Equipment struct {
 	PartNumber  string
@@ -398,3 +462,480 @@ func (s *emailService) renderVerificationCodeTemplate(email, code, username stri
 </html>
 `
 }
+
+// SendPasswordResetEmail 發送重設密碼連結
+func (s *emailService) SendPasswordResetEmail(email, username, resetURL string, expiresAt time.Time) error {
+	subject := "【重設密碼】專案報備系統"
+
+	htmlBody, err := s.renderTemplate(passwordResetTemplate, map[string]interface{}{
+		"Username":  username,
+		"ResetURL":  resetURL,
+		"ExpiresAt": expiresAt.Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		log.Error("Failed to render password reset template:", err)
+		return err
+	}
+
+	return s.sendEmailTo(email, subject, htmlBody)
+}
+
+// SendPasswordChangedEmail 發送密碼已變更通知
+func (s *emailService) SendPasswordChangedEmail(email, username string, changedAt time.Time) error {
+	subject := "【密碼已變更】專案報備系統"
+
+	htmlBody, err := s.renderTemplate(passwordChangedTemplate, map[string]interface{}{
+		"Username":  username,
+		"ChangedAt": changedAt.Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		log.Error("Failed to render password changed template:", err)
+		return err
+	}
+
+	return s.sendEmailTo(email, subject, htmlBody)
+}
+
+// SendAccountLockedEmail 發送帳號因多次登入失敗被暫時鎖定的通知
+func (s *emailService) SendAccountLockedEmail(email, username string, lockedUntil time.Time) error {
+	subject := "【帳號已鎖定】專案報備系統"
+
+	htmlBody, err := s.renderTemplate(accountLockedTemplate, map[string]interface{}{
+		"Username":    username,
+		"LockedUntil": lockedUntil.Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		log.Error("Failed to render account locked template:", err)
+		return err
+	}
+
+	return s.sendEmailTo(email, subject, htmlBody)
+}
+
+// SendProjectExpiryWarningEmail 發送專案保護期即將到期提醒
+func (s *emailService) SendProjectExpiryWarningEmail(email, username string, data *ProjectExpiryData) error {
+	subject := fmt.Sprintf("【保護期即將到期】%s", data.ProjectName)
+
+	htmlBody, err := s.renderTemplate(projectExpiryWarningTemplate, map[string]interface{}{
+		"Username":    username,
+		"ProjectID":   data.ProjectID,
+		"ProjectName": data.ProjectName,
+		"Owner":       data.Owner,
+		"ExpiresAt":   data.ExpiresAt.Format("2006-01-02 15:04"),
+	})
+	if err != nil {
+		log.Error("Failed to render project expiry warning template:", err)
+		return err
+	}
+
+	return s.sendEmailTo(email, subject, htmlBody)
+}
+
+// SendApprovalRequestEmail 通知審核者有待審核的專案報備
+func (s *emailService) SendApprovalRequestEmail(email, username string, data *ProjectApprovalData) error {
+	subject := fmt.Sprintf("【待審核】%s - 第 %d 關", data.ProjectName, data.Level)
+
+	htmlBody, err := s.renderTemplate(approvalRequestTemplate, map[string]interface{}{
+		"Username":    username,
+		"ProjectID":   data.ProjectID,
+		"ProjectName": data.ProjectName,
+		"Owner":       data.Owner,
+		"Level":       data.Level,
+	})
+	if err != nil {
+		log.Error("Failed to render approval request template:", err)
+		return err
+	}
+
+	return s.sendEmailTo(email, subject, htmlBody)
+}
+
+// SendApprovalDecisionEmail 通知建立者審核結果
+func (s *emailService) SendApprovalDecisionEmail(email, username string, data *ProjectApprovalData) error {
+	result := "已核准"
+	if data.Status == "rejected" {
+		result = "已駁回"
+	}
+	subject := fmt.Sprintf("【審核結果】%s - %s", data.ProjectName, result)
+
+	htmlBody, err := s.renderTemplate(approvalDecisionTemplate, map[string]interface{}{
+		"Username":    username,
+		"ProjectID":   data.ProjectID,
+		"ProjectName": data.ProjectName,
+		"Owner":       data.Owner,
+		"Result":      result,
+		"Reason":      data.Reason,
+		"DecidedBy":   data.DecidedBy,
+	})
+	if err != nil {
+		log.Error("Failed to render approval decision template:", err)
+		return err
+	}
+
+	return s.sendEmailTo(email, subject, htmlBody)
+}
+
+// SendCommentMentionEmail 通知被留言提及的使用者
+func (s *emailService) SendCommentMentionEmail(email, username string, data *CommentMentionData) error {
+	targetLabel := "專案"
+	if data.TargetType == "stock" {
+		targetLabel = "現貨"
+	}
+	subject := fmt.Sprintf("【留言提及】%s 在%s「%s」提到了您", data.Author, targetLabel, data.TargetName)
+
+	htmlBody, err := s.renderTemplate(commentMentionTemplate, map[string]interface{}{
+		"Username":    username,
+		"TargetLabel": targetLabel,
+		"TargetID":    data.TargetID,
+		"TargetName":  data.TargetName,
+		"Author":      data.Author,
+		"Body":        data.Body,
+	})
+	if err != nil {
+		log.Error("Failed to render comment mention template:", err)
+		return err
+	}
+
+	return s.sendEmailTo(email, subject, htmlBody)
+}
+
+// SendQuotationEmail 寄送報價單給專案聯絡人,附上 PDF 檔
+func (s *emailService) SendQuotationEmail(email string, data *QuotationData, attachment []byte) error {
+	subject := fmt.Sprintf("【報價單】%s - 第 %d 版", data.ProjectName, data.Version)
+
+	lines := make([]map[string]interface{}, 0, len(data.Lines))
+	for i, line := range data.Lines {
+		lines = append(lines, map[string]interface{}{
+			"No":          i + 1,
+			"PartNumber":  line.PartNumber,
+			"Description": line.Description,
+			"Quantity":    line.Quantity,
+			"UnitPrice":   pricing.Format(line.UnitPrice),
+			"Amount":      pricing.Format(line.Amount),
+		})
+	}
+
+	htmlBody, err := s.renderTemplate(quotationTemplate, map[string]interface{}{
+		"ContactName": data.ContactName,
+		"ProjectName": data.ProjectName,
+		"Version":     data.Version,
+		"Currency":    data.Currency,
+		"Lines":       lines,
+		"Subtotal":    pricing.Format(data.Subtotal),
+		"Discount":    pricing.Format(data.Discount),
+		"HasDiscount": data.Discount > 0,
+		"Tax":         pricing.Format(data.Tax),
+		"Total":       pricing.Format(data.Total),
+		"ValidUntil":  data.ValidUntil.Format("2006-01-02"),
+		"Note":        data.Note,
+	})
+	if err != nil {
+		log.Error("Failed to render quotation template:", err)
+		return err
+	}
+
+	filename := fmt.Sprintf("quotation-%s-v%d.pdf", data.ProjectID, data.Version)
+	return s.sendEmailWithAttachment(email, subject, htmlBody, filename, attachment)
+}
+
+// sendEmailWithAttachment 發送附檔的 Email 到指定收件者
+func (s *emailService) sendEmailWithAttachment(to, subject, htmlBody, filename string, attachment []byte) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", s.fromEmail)
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/html", htmlBody)
+	m.Attach(filename, gomail.SetCopyFunc(func(w io.Writer) error {
+		_, err := w.Write(attachment)
+		return err
+	}))
+
+	d := gomail.NewDialer(s.smtpHost, s.smtpPort, s.smtpUser, s.smtpPassword)
+	d.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+
+	if err := d.DialAndSend(m); err != nil {
+		log.Error("Failed to send email:", err)
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	log.Info("Email sent successfully to:", to)
+	return nil
+}
+
+// sendEmailTo 發送 Email 到指定收件者
+func (s *emailService) sendEmailTo(to, subject, htmlBody string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", s.fromEmail)
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/html", htmlBody)
+
+	d := gomail.NewDialer(s.smtpHost, s.smtpPort, s.smtpUser, s.smtpPassword)
+	d.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+
+	if err := d.DialAndSend(m); err != nil {
+		log.Error("Failed to send email:", err)
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	log.Info("Email sent successfully to:", to)
+	return nil
+}
+
+// renderTemplate 渲染 Email 範本
+func (s *emailService) renderTemplate(tmpl string, data interface{}) (string, error) {
+	t, err := template.New("email").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// 通知類郵件共用樣式
+const noticeStyle = `
+        body { font-family: 'Microsoft JhengHei', Arial, sans-serif; line-height: 1.8; color: #333; background: #f5f5f5; margin: 0; padding: 20px; }
+        .container { max-width: 600px; margin: 0 auto; background: white; border: 1px solid #e0e0e0; border-radius: 8px; overflow: hidden; }
+        .header { background: #2c3e50; color: white; padding: 25px 30px; }
+        .header h1 { margin: 0 0 8px 0; font-size: 22px; font-weight: 600; }
+        .content { padding: 30px; }
+        .button { display: inline-block; background: #2c3e50; color: white !important; padding: 12px 28px; border-radius: 4px; text-decoration: none; margin: 20px 0; }
+        .notice { background: #fff3cd; border-left: 4px solid #ffc107; padding: 15px 20px; margin: 20px 0; border-radius: 4px; color: #856404; font-size: 14px; }
+        .footer { background: #f8f9fa; padding: 20px 30px; text-align: center; font-size: 12px; color: #999; border-top: 1px solid #e8e8e8; }
+`
+
+const passwordResetTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>` + noticeStyle + `</style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔑 重設密碼</h1>
+            <p>專案報備系統</p>
+        </div>
+        <div class="content">
+            <p><strong>{{.Username}}</strong>，您好！</p>
+            <p>我們收到了您的重設密碼申請，請點擊下方按鈕設定新密碼：</p>
+            <p style="text-align: center;"><a class="button" href="{{.ResetURL}}">重設密碼</a></p>
+            <div class="notice">
+                • 此連結將於 <strong>{{.ExpiresAt}}</strong> 失效<br>
+                • 此連結僅可使用一次<br>
+                • 如非本人操作，請忽略此郵件，您的密碼不會被變更
+            </div>
+        </div>
+        <div class="footer"><p>此為系統自動發送的通知郵件，請勿直接回覆</p></div>
+    </div>
+</body>
+</html>
+`
+
+const passwordChangedTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>` + noticeStyle + `</style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔐 密碼已變更</h1>
+            <p>專案報備系統</p>
+        </div>
+        <div class="content">
+            <p><strong>{{.Username}}</strong>，您好！</p>
+            <p>您的帳號密碼已於 <strong>{{.ChangedAt}}</strong> 變更。</p>
+            <div class="notice">
+                如非本人操作，請立即聯絡系統管理員。
+            </div>
+        </div>
+        <div class="footer"><p>此為系統自動發送的通知郵件，請勿直接回覆</p></div>
+    </div>
+</body>
+</html>
+`
+
+const accountLockedTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>` + noticeStyle + `</style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔒 帳號已暫時鎖定</h1>
+            <p>專案報備系統</p>
+        </div>
+        <div class="content">
+            <p><strong>{{.Username}}</strong>，您好！</p>
+            <p>您的帳號因連續多次登入失敗，已暫時鎖定至 <strong>{{.LockedUntil}}</strong>。</p>
+            <div class="notice">
+                如非本人操作，建議於解鎖後立即變更密碼，或聯絡系統管理員協助處理。
+            </div>
+        </div>
+        <div class="footer"><p>此為系統自動發送的通知郵件，請勿直接回覆</p></div>
+    </div>
+</body>
+</html>
+`
+
+const projectExpiryWarningTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>` + noticeStyle + `</style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>⏰ 專案報備保護期即將到期</h1>
+            <p>專案報備系統</p>
+        </div>
+        <div class="content">
+            <p><strong>{{.Username}}</strong>，您好！</p>
+            <p>您建立的專案 <strong>{{.ProjectName}}</strong>（負責人：{{.Owner}}）保護期將於 <strong>{{.ExpiresAt}}</strong> 到期。</p>
+            <p>專案編號：{{.ProjectID}}</p>
+            <div class="notice">
+                到期前未填寫第二階段交貨資訊，報備將自動轉為「已過期」。如需繼續保護，請申請延長並由管理員核准。
+            </div>
+        </div>
+        <div class="footer"><p>此為系統自動發送的通知郵件，請勿直接回覆</p></div>
+    </div>
+</body>
+</html>
+`
+
+const approvalRequestTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>` + noticeStyle + `</style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>📝 專案報備待審核</h1>
+            <p>專案報備系統</p>
+        </div>
+        <div class="content">
+            <p><strong>{{.Username}}</strong>，您好！</p>
+            <p>專案 <strong>{{.ProjectName}}</strong>（負責人：{{.Owner}}）已進入第 <strong>{{.Level}}</strong> 關審核，請至系統核准或駁回。</p>
+            <p>專案編號：{{.ProjectID}}</p>
+            <div class="notice">
+                核准前此報備無法填寫第二階段交貨資訊。
+            </div>
+        </div>
+        <div class="footer"><p>此為系統自動發送的通知郵件，請勿直接回覆</p></div>
+    </div>
+</body>
+</html>
+`
+
+const approvalDecisionTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>` + noticeStyle + `</style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>專案報備{{.Result}}</h1>
+            <p>專案報備系統</p>
+        </div>
+        <div class="content">
+            <p><strong>{{.Username}}</strong>，您好！</p>
+            <p>您建立的專案 <strong>{{.ProjectName}}</strong>（負責人：{{.Owner}}）審核結果為「<strong>{{.Result}}</strong>」。</p>
+            <p>專案編號：{{.ProjectID}}</p>
+            {{if .DecidedBy}}<p>審核者：{{.DecidedBy}}</p>{{end}}
+            {{if .Reason}}<div class="notice">說明：{{.Reason}}</div>{{end}}
+        </div>
+        <div class="footer"><p>此為系統自動發送的通知郵件，請勿直接回覆</p></div>
+    </div>
+</body>
+</html>
+`
+
+const commentMentionTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>` + noticeStyle + `</style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>💬 有人在留言中提到您</h1>
+            <p>專案報備系統</p>
+        </div>
+        <div class="content">
+            <p><strong>{{.Username}}</strong>，您好！</p>
+            <p><strong>{{.Author}}</strong> 在{{.TargetLabel}} <strong>{{.TargetName}}</strong> 的留言中提到了您。</p>
+            <p>{{.TargetLabel}}編號：{{.TargetID}}</p>
+            <div class="notice" style="white-space: pre-wrap;">{{.Body}}</div>
+        </div>
+        <div class="footer"><p>此為系統自動發送的通知郵件，請勿直接回覆</p></div>
+    </div>
+</body>
+</html>
+`
+
+const quotationTemplate = `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>` + noticeStyle + `
+        table { width: 100%; border-collapse: collapse; font-size: 14px; }
+        th, td { border-bottom: 1px solid #e8e8e8; padding: 8px 6px; text-align: left; }
+        th { background: #f8f9fa; }
+        .amount { text-align: right; white-space: nowrap; }
+        .total td { font-weight: 600; border-bottom: none; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>📄 報價單</h1>
+            <p>{{.ProjectName}}(第 {{.Version}} 版)</p>
+        </div>
+        <div class="content">
+            <p><strong>{{.ContactName}}</strong>，您好！</p>
+            <p>感謝您的詢價，以下為本次報價內容，完整報價單請參考附件 PDF。</p>
+            <table>
+                <tr><th>#</th><th>料號</th><th>說明</th><th class="amount">數量</th><th class="amount">單價</th><th class="amount">未稅金額</th></tr>
+                {{range .Lines}}
+                <tr><td>{{.No}}</td><td>{{.PartNumber}}</td><td>{{.Description}}</td><td class="amount">{{.Quantity}}</td><td class="amount">{{.UnitPrice}}</td><td class="amount">{{.Amount}}</td></tr>
+                {{end}}
+            </table>
+            <table style="margin-top: 16px;">
+                <tr><td>小計(折扣前)</td><td class="amount">{{.Currency}} {{.Subtotal}}</td></tr>
+                {{if .HasDiscount}}<tr><td>折扣</td><td class="amount">-{{.Currency}} {{.Discount}}</td></tr>{{end}}
+                <tr><td>稅額</td><td class="amount">{{.Currency}} {{.Tax}}</td></tr>
+                <tr class="total"><td>總計</td><td class="amount">{{.Currency}} {{.Total}}</td></tr>
+            </table>
+            <div class="notice">
+                • 報價有效期限至 <strong>{{.ValidUntil}}</strong><br>
+                {{if .Note}}• {{.Note}}{{end}}
+            </div>
+        </div>
+        <div class="footer"><p>此為系統自動發送的通知郵件，如有任何問題請與業務負責人聯繫</p></div>
+    </div>
+</body>
+</html>
+`
@@ -0,0 +1,152 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"esst_sendEmail/internal/pkg/log"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile []byte
+
+// Policy 密碼規則
+type Policy struct {
+	MinLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSymbol  bool
+	commonPassword map[string]struct{}
+}
+
+// PolicyError 密碼不符合規則時的錯誤,Violations 列出所有未通過的規則
+type PolicyError struct {
+	Violations []string
+}
+
+func (e *PolicyError) Error() string {
+	return "密碼不符合規則: " + strings.Join(e.Violations, "; ")
+}
+
+var (
+	policy     *Policy
+	policyOnce sync.Once
+)
+
+// GetPolicy 取得密碼規則(由環境變數設定)
+func GetPolicy() *Policy {
+	policyOnce.Do(func() {
+		policy = &Policy{
+			MinLength:      envInt("PASSWORD_MIN_LENGTH", 8),
+			RequireUpper:   envBool("PASSWORD_REQUIRE_UPPER", true),
+			RequireLower:   envBool("PASSWORD_REQUIRE_LOWER", true),
+			RequireDigit:   envBool("PASSWORD_REQUIRE_DIGIT", true),
+			RequireSymbol:  envBool("PASSWORD_REQUIRE_SYMBOL", false),
+			commonPassword: make(map[string]struct{}),
+		}
+
+		policy.loadList(commonPasswordsFile)
+
+		// 可另外指定本地的外洩密碼清單檔案(每行一組)
+		if path := os.Getenv("PASSWORD_BLOCKLIST_FILE"); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				log.Error("Failed to load password blocklist:", err)
+			} else {
+				policy.loadList(data)
+			}
+		}
+	})
+	return policy
+}
+
+// Validate 以目前設定的規則驗證密碼
+func Validate(password, username string) error {
+	return GetPolicy().Validate(password, username)
+}
+
+// Validate 驗證密碼是否符合規則
+func (p *Policy) Validate(password, username string) error {
+	var violations []string
+
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("長度至少 %d 個字元", p.MinLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, "需包含大寫英文字母")
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, "需包含小寫英文字母")
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, "需包含數字")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, "需包含特殊符號")
+	}
+
+	if username != "" && strings.EqualFold(password, username) {
+		violations = append(violations, "不可與帳號相同")
+	}
+
+	if _, ok := p.commonPassword[strings.ToLower(password)]; ok {
+		violations = append(violations, "此密碼過於常見或已外洩,請更換")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// loadList 載入密碼清單,忽略空行與 # 開頭的註解
+func (p *Policy) loadList(data []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.commonPassword[strings.ToLower(line)] = struct{}{}
+	}
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+func envBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
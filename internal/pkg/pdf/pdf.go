@@ -0,0 +1,149 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 紙張尺寸(單位 pt)
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document 簡易 PDF 文件,只支援文字與線條
+// 文字使用 Adobe 標準繁體中文字型 MSung-Light(UniCNS-UCS2-H 編碼),不內嵌字型,由閱讀器提供
+type Document struct {
+	pages []*bytes.Buffer
+}
+
+// New 建立空白文件
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage 新增一頁,之後的繪製都在此頁
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	return d.pages[len(d.pages)-1]
+}
+
+// Text 在 (x, y) 繪製文字,座標原點在左上角
+func (d *Document) Text(x, y, size float64, text string) {
+	fmt.Fprintf(d.current(), "BT /F1 %.2f Tf %.2f %.2f Td <%s> Tj ET\n", size, x, PageHeight-y, encode(text))
+}
+
+// TextRight 繪製靠右對齊的文字,x 為右邊界
+func (d *Document) TextRight(x, y, size float64, text string) {
+	d.Text(x-Width(text, size), y, size, text)
+}
+
+// Line 繪製線段
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// Rect 繪製填滿的灰階矩形(gray 0 為黑、1 為白)
+func (d *Document) Rect(x, y, w, h, gray float64) {
+	fmt.Fprintf(d.current(), "q %.2f g %.2f %.2f %.2f %.2f re f Q\n", gray, x, PageHeight-y-h, w, h)
+}
+
+// Width 估算文字寬度,半形字元為字級的一半,其餘為全形
+func Width(text string, size float64) float64 {
+	width := 0.0
+	for _, r := range text {
+		if r < 0x80 {
+			width += size / 2
+		} else {
+			width += size
+		}
+	}
+
+	return width
+}
+
+// Truncate 截斷超過寬度的文字並加上省略符號
+func Truncate(text string, size, width float64) string {
+	if Width(text, size) <= width {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 && Width(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + "..."
+}
+
+// Bytes 輸出 PDF 檔案內容
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	// 物件編號:1 Catalog、2 Pages、3~5 字型,之後每頁兩個物件(頁面、內容)
+	objects := []string{
+		"", // Catalog,頁面編號確定後填入
+		"", // Pages
+		"<< /Type /Font /Subtype /Type0 /BaseFont /MSung-Light /Encoding /UniCNS-UCS2-H /DescendantFonts [4 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /MSung-Light " +
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (CNS1) /Supplement 0 >> " +
+			"/FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>",
+		"<< /Type /FontDescriptor /FontName /MSung-Light /Flags 6 /FontBBox [0 -200 1000 900] " +
+			"/ItalicAngle 0 /Ascent 800 /Descent -200 /CapHeight 800 /StemV 50 >>",
+	}
+
+	kids := make([]string, 0, len(d.pages))
+	for _, page := range d.pages {
+		pageID := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				PageWidth, PageHeight, pageID+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()),
+		)
+	}
+	objects[0] = "<< /Type /Catalog /Pages 2 0 R >>"
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, 0, len(objects))
+	for i, object := range objects {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes()
+}
+
+// encode 轉為 UCS-2 十六進位字串,超出基本多文種平面的字元以問號取代
+func encode(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r > 0xFFFF || utf16.IsSurrogate(r) {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	reader "rsc.io/pdf"
+)
+
+func sample(pages int) []byte {
+	doc := New()
+	for i := 0; i < pages; i++ {
+		doc.AddPage()
+		doc.Text(40, 60, 18, fmt.Sprintf("報價單 Quotation %d", i+1))
+		doc.TextRight(PageWidth-40, 60, 10, "NT$ 1,234.50")
+		doc.Line(40, 70, PageWidth-40, 70, 0.5)
+		doc.Rect(40, 80, 100, 20, 0.9)
+		doc.Text(40, 120, 10, "𠀀 超出基本平面")
+	}
+	return doc.Bytes()
+}
+
+// TestBytesOpens 以 PDF 解析器開啟產生的檔案,確認頁數、頁面尺寸與字型資源
+func TestBytesOpens(t *testing.T) {
+	for _, pages := range []int{0, 1, 3} {
+		t.Run(strconv.Itoa(pages), func(t *testing.T) {
+			data := sample(pages)
+			file, err := reader.NewReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+
+			want := max(pages, 1)
+			if file.NumPage() != want {
+				t.Fatalf("NumPage() = %d, want %d", file.NumPage(), want)
+			}
+
+			for i := 1; i <= want; i++ {
+				page := file.Page(i)
+				box := page.V.Key("MediaBox")
+				if box.Len() != 4 || box.Index(2).Float64() != PageWidth || box.Index(3).Float64() != PageHeight {
+					t.Errorf("page %d MediaBox = %v", i, box)
+				}
+				if font := page.Font("F1"); font.BaseFont() != "MSung-Light" {
+					t.Errorf("page %d font = %q", i, font.BaseFont())
+				}
+
+				content := page.V.Key("Contents")
+				stream, err := readAll(content)
+				if err != nil {
+					t.Fatalf("page %d contents: %v", i, err)
+				}
+				if int64(len(stream)) != content.Key("Length").Int64() {
+					t.Errorf("page %d stream length = %d, /Length = %d", i, len(stream), content.Key("Length").Int64())
+				}
+			}
+		})
+	}
+}
+
+// TestXref 交叉參照表的位移必須指向對應的物件
+func TestXref(t *testing.T) {
+	data := sample(2)
+
+	match := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+	if match == nil {
+		t.Fatal("missing startxref trailer")
+	}
+	start, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(data[start:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point to xref", start)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllSubmatch(data[start:], -1)
+	if len(entries) != 5+2*2 {
+		t.Fatalf("xref entries = %d, want %d", len(entries), 9)
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		prefix := fmt.Sprintf("%d 0 obj\n", i+1)
+		if !bytes.HasPrefix(data[offset:], []byte(prefix)) {
+			t.Errorf("object %d offset %d points to %q", i+1, offset, data[offset:offset+len(prefix)])
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"A", "0041"},
+		{"報價", "583150F9"},
+		{"𠀀", "003F"},
+	}
+	for _, tt := range tests {
+		if got := encode(tt.text); got != tt.want {
+			t.Errorf("encode(%q) = %s, want %s", tt.text, got, tt.want)
+		}
+	}
+}
+
+func readAll(v reader.Value) ([]byte, error) {
+	var buf bytes.Buffer
+	_, err := buf.ReadFrom(v.Reader())
+	return buf.Bytes(), err
+}
@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money 以最小單位(0.01)儲存的金額,對應資料庫的 NUMERIC(14,2),加總與四捨五入皆以整數計算
+type Money int64
+
+// FromFloat 將輸入的金額(例如單價)四捨五入到小數第二位
+func FromFloat(x float64) Money {
+	return Money(math.Round(x * 100))
+}
+
+// Parse 解析十進位金額字串(例如 "1234.5"),超過兩位的小數四捨五入
+func Parse(text string) (Money, error) {
+	text = strings.TrimSpace(text)
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimLeft(text, "+-")
+
+	integer, fraction, _ := strings.Cut(text, ".")
+	if integer == "" {
+		integer = "0"
+	}
+	units, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", text)
+	}
+	for _, r := range fraction {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("invalid amount %q", text)
+		}
+	}
+
+	fraction += "000"
+	cents, _ := strconv.ParseInt(fraction[:2], 10, 64)
+	output := units*100 + cents
+	if fraction[2] >= '5' {
+		output++
+	}
+	if negative {
+		output = -output
+	}
+	return Money(output), nil
+}
+
+// Float 轉為浮點數,僅供顯示或與其他系統交換
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+// String 固定兩位小數,例如 1234.50
+func (m Money) String() string {
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+// MarshalJSON 以數字輸出,例如 1234.5
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON 接受數字或字串
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "null" {
+		return nil
+	}
+
+	value, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*m = value
+	return nil
+}
+
+// Scan 讀取資料庫的 NUMERIC 欄位
+func (m *Money) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		return m.UnmarshalJSON(value)
+	case string:
+		return m.UnmarshalJSON([]byte(value))
+	case int64:
+		*m = Money(value * 100)
+		return nil
+	case float64:
+		*m = FromFloat(value)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into Money", src)
+}
+
+// Value 以十進位字串寫入資料庫,避免浮點誤差
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// percentOf 金額乘上百分比(可有兩位小數)後四捨五入到最小單位
+func percentOf(m Money, percent float64) Money {
+	basisPoints := int64(math.Round(percent * 100))
+	product := int64(m) * basisPoints
+	if product < 0 {
+		return Money(-((-product + 5000) / 10000))
+	}
+	return Money((product + 5000) / 10000)
+}
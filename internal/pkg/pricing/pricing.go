@@ -0,0 +1,113 @@
+package pricing
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Amount 金額計算結果
+type Amount struct {
+	// 幣別
+	Currency string `json:"currency"`
+	// 未稅小計(單價 × 數量,折扣前)
+	Subtotal Money `json:"subtotal"`
+	// 折扣金額
+	Discount Money `json:"discount"`
+	// 稅額(以折扣後金額計算)
+	Tax Money `json:"tax"`
+	// 含稅總額
+	Total Money `json:"total"`
+}
+
+// DefaultCurrency 設備未指定幣別時使用,由 PRICING_CURRENCY 設定,預設 TWD
+func DefaultCurrency() string {
+	if currency := os.Getenv("PRICING_CURRENCY"); currency != "" {
+		return strings.ToUpper(currency)
+	}
+
+	return "TWD"
+}
+
+// DefaultTaxRate 設備未指定稅率時使用的百分比,由 PRICING_TAX_RATE 設定,預設 5(營業稅)
+func DefaultTaxRate() float64 {
+	if rate, err := strconv.ParseFloat(os.Getenv("PRICING_TAX_RATE"), 64); err == nil && rate >= 0 {
+		return rate
+	}
+
+	return 5
+}
+
+// Of 計算單筆設備金額,尚未填寫單價時回傳 nil
+// 折扣與稅率皆為百分比,未填寫時分別視為不打折與預設稅率
+func Of(quantity int64, unitPrice, discount *float64, currency *string, taxRate *float64) *Amount {
+	if unitPrice == nil {
+		return nil
+	}
+
+	output := &Amount{Currency: DefaultCurrency()}
+	if currency != nil && *currency != "" {
+		output.Currency = strings.ToUpper(*currency)
+	}
+
+	rate := DefaultTaxRate()
+	if taxRate != nil {
+		rate = *taxRate
+	}
+
+	// 單價先取到最小單位,之後皆以整數計算
+	output.Subtotal = FromFloat(*unitPrice) * Money(quantity)
+	if discount != nil {
+		output.Discount = percentOf(output.Subtotal, *discount)
+	}
+	output.Tax = percentOf(output.Subtotal-output.Discount, rate)
+	output.Total = output.Subtotal - output.Discount + output.Tax
+
+	return output
+}
+
+// Sum 依幣別分別加總,順序依各幣別第一次出現的位置
+func Sum(amounts []*Amount) []*Amount {
+	output := make([]*Amount, 0)
+	totals := make(map[string]*Amount)
+	for _, amount := range amounts {
+		if amount == nil {
+			continue
+		}
+
+		total, ok := totals[amount.Currency]
+		if !ok {
+			total = &Amount{Currency: amount.Currency}
+			totals[amount.Currency] = total
+			output = append(output, total)
+		}
+		total.Subtotal += amount.Subtotal
+		total.Discount += amount.Discount
+		total.Tax += amount.Tax
+		total.Total += amount.Total
+	}
+
+	return output
+}
+
+// Format 格式化金額為千分位並保留兩位小數,例如 12,345.60
+func Format(m Money) string {
+	text := m.String()
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	integer, decimal := text[:len(text)-3], text[len(text)-3:]
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString(decimal)
+
+	return b.String()
+}
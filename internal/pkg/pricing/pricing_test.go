@@ -0,0 +1,167 @@
+package pricing
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestOf(t *testing.T) {
+	t.Setenv("PRICING_CURRENCY", "")
+	t.Setenv("PRICING_TAX_RATE", "")
+
+	tests := []struct {
+		name      string
+		quantity  int64
+		unitPrice *float64
+		discount  *float64
+		currency  *string
+		taxRate   *float64
+		want      *Amount
+	}{
+		{"no unit price", 3, nil, nil, nil, nil, nil},
+		{"default currency and tax", 3, ptr(100.0), nil, nil, nil,
+			&Amount{Currency: "TWD", Subtotal: 30000, Tax: 1500, Total: 31500}},
+		{"discount rounds to the cent", 3, ptr(19.99), ptr(10.0), ptr("usd"), nil,
+			&Amount{Currency: "USD", Subtotal: 5997, Discount: 600, Tax: 270, Total: 5667}},
+		{"half cent rounds up", 1, ptr(0.1), nil, nil, ptr(5.0),
+			&Amount{Currency: "TWD", Subtotal: 10, Tax: 1, Total: 11}},
+		{"fractional rates", 7, ptr(333.33), ptr(12.5), nil, ptr(7.25),
+			&Amount{Currency: "TWD", Subtotal: 233331, Discount: 29166, Tax: 14802, Total: 218967}},
+		{"zero tax", 2, ptr(0.07), nil, nil, ptr(0.0),
+			&Amount{Currency: "TWD", Subtotal: 14, Total: 14}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Of(tt.quantity, tt.unitPrice, tt.discount, tt.currency, tt.taxRate)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("Of() = %+v, want %+v", got, tt.want)
+			}
+			if got != nil && *got != *tt.want {
+				t.Errorf("Of() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []*Amount
+		want    []Amount
+	}{
+		{"empty", nil, []Amount{}},
+		{"skips lines without price", []*Amount{nil, {Currency: "TWD", Subtotal: 100, Total: 105, Tax: 5}, nil},
+			[]Amount{{Currency: "TWD", Subtotal: 100, Tax: 5, Total: 105}}},
+		{
+			// 以浮點數累加 0.1 + 0.2 會產生誤差,以整數加總必須剛好等於各行合計
+			name: "sums in minor units per currency",
+			amounts: []*Amount{
+				{Currency: "TWD", Subtotal: 10, Tax: 1, Total: 11},
+				{Currency: "USD", Subtotal: 20, Total: 20},
+				{Currency: "TWD", Subtotal: 20, Discount: 5, Tax: 1, Total: 16},
+			},
+			want: []Amount{
+				{Currency: "TWD", Subtotal: 30, Discount: 5, Tax: 2, Total: 27},
+				{Currency: "USD", Subtotal: 20, Total: 20},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sum(tt.amounts)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Sum() = %d currencies, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if *got[i] != tt.want[i] {
+					t.Errorf("Sum()[%d] = %+v, want %+v", i, *got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSumMatchesLines(t *testing.T) {
+	amounts := make([]*Amount, 0)
+	var subtotal, total Money
+	for i := 0; i < 1000; i++ {
+		amount := Of(1, ptr(0.35), ptr(3.0), nil, ptr(5.0))
+		amounts = append(amounts, amount)
+		subtotal += amount.Subtotal
+		total += amount.Total
+	}
+
+	sum := Sum(amounts)[0]
+	if sum.Subtotal != subtotal || sum.Total != total || sum.Subtotal != 35000 {
+		t.Errorf("Sum() = %+v, want subtotal %s total %s", *sum, subtotal, total)
+	}
+}
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   Money
+		format string
+	}{
+		{"0", 0, "0.00"},
+		{"1234.5", 123450, "1,234.50"},
+		{"1234567.89", 123456789, "1,234,567.89"},
+		{"0.005", 1, "0.01"},
+		{"0.004", 0, "0.00"},
+		{"-12.345", -1235, "-12.35"},
+		{".5", 50, "0.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := Parse(tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %d, want %d", tt.text, got, tt.want)
+			}
+			if Format(got) != tt.format {
+				t.Errorf("Format(%d) = %q, want %q", got, Format(got), tt.format)
+			}
+
+			var scanned Money
+			if err := scanned.Scan([]byte(tt.text)); err != nil || scanned != tt.want {
+				t.Errorf("Scan(%q) = %d, %v", tt.text, scanned, err)
+			}
+		})
+	}
+
+	for _, text := range []string{"abc", "1.2x", "1e3"} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Parse(%q) error = nil", text)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	input := Amount{Currency: "TWD", Subtotal: 123450, Discount: 5, Tax: 0, Total: -1}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"currency":"TWD","subtotal":1234.50,"discount":0.05,"tax":0.00,"total":-0.01}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var output Amount
+	if err := json.Unmarshal(data, &output); err != nil {
+		t.Fatal(err)
+	}
+	if output != input {
+		t.Errorf("Unmarshal() = %+v, want %+v", output, input)
+	}
+}
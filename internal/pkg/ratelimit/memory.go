@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryStore 單一程序內的 bucket,服務重新啟動後歸零,多台部署時請改用 Postgres
+type memoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+type memoryBucket struct {
+	bucket
+	// FullAt 補滿的時間,之後與不存在的 bucket 相同,可以清除
+	FullAt time.Time
+}
+
+// NewMemoryStore 建立記憶體 store,並定期清除已補滿的 bucket
+func NewMemoryStore() Store {
+	store := &memoryStore{buckets: make(map[string]*memoryBucket)}
+	go store.startCleanupRoutine()
+	return store
+}
+
+func (s *memoryStore) Take(_ context.Context, key string, limit Limit) (*Result, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, exists := s.buckets[key]
+	if !exists {
+		b = &memoryBucket{bucket: bucket{Tokens: float64(limit.Requests), UpdatedAt: now}}
+		s.buckets[key] = b
+	}
+
+	result, fullAt := b.take(limit, now)
+	b.FullAt = fullAt
+
+	return result, nil
+}
+
+// cleanup 清除已補滿的 bucket
+func (s *memoryStore) cleanup() {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if now.After(b.FullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func (s *memoryStore) startCleanupRoutine() {
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.cleanup()
+	}
+}
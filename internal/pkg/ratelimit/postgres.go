@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+
+	"gorm.io/gorm"
+)
+
+// postgresStore 以資料表 rate_limit_buckets 儲存 bucket,多台服務共用同一份限制
+type postgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore 建立 Postgres store,並定期清除已補滿的 bucket
+func NewPostgresStore(db *gorm.DB) Store {
+	store := &postgresStore{db: db}
+	go store.startCleanupRoutine()
+	return store
+}
+
+func (s *postgresStore) Take(ctx context.Context, key string, limit Limit) (*Result, error) {
+	var result *Result
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// 新的 key 以滿的 bucket 開始
+		err := tx.Exec(`INSERT INTO rate_limit_buckets (bucket_key, tokens, updated_at, full_at)
+			VALUES (?, ?, ?, ?) ON CONFLICT (bucket_key) DO NOTHING`,
+			key, float64(limit.Requests), now, now).Error
+		if err != nil {
+			return err
+		}
+
+		// 鎖定該列,避免同時的請求重複取用
+		b := &bucket{}
+		err = tx.Raw(`SELECT tokens, updated_at FROM rate_limit_buckets WHERE bucket_key = ? FOR UPDATE`, key).
+			Row().Scan(&b.Tokens, &b.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		var fullAt time.Time
+		result, fullAt = b.take(limit, now)
+
+		return tx.Exec(`UPDATE rate_limit_buckets SET tokens = ?, updated_at = ?, full_at = ? WHERE bucket_key = ?`,
+			b.Tokens, b.UpdatedAt, fullAt, key).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// cleanup 清除已補滿的 bucket
+func (s *postgresStore) cleanup() {
+	err := s.db.Exec(`DELETE FROM rate_limit_buckets WHERE full_at < ?`, time.Now()).Error
+	if err != nil {
+		log.Error("Failed to clean rate limit buckets:", err)
+	}
+}
+
+func (s *postgresStore) startCleanupRoutine() {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.cleanup()
+	}
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit 每個 Period 最多 Requests 次請求(token bucket 容量為 Requests,並以固定速率補充)
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit 解析 "次數/期間" 格式,例如 10/1m、5/15m、1000/1h
+func ParseLimit(value string) (Limit, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected <requests>/<period>", value)
+	}
+
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit requests %q", parts[0])
+	}
+
+	period, err := time.ParseDuration(parts[1])
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit period %q", parts[1])
+	}
+
+	return Limit{Requests: requests, Period: period}, nil
+}
+
+// rate 每秒補充的 token 數
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result 一次請求的判斷結果
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter bucket 補滿所需時間
+	ResetAfter time.Duration
+	// RetryAfter 被拒絕時,下一次可通過的等待時間
+	RetryAfter time.Duration
+}
+
+// Store 儲存各 key 的 bucket 狀態
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// bucket token bucket 狀態
+type bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// take 依經過時間補充 token 後嘗試取用一個,回傳結果與 bucket 補滿的時間
+func (b *bucket) take(limit Limit, now time.Time) (*Result, time.Time) {
+	capacity := float64(limit.Requests)
+	rate := limit.rate()
+
+	elapsed := now.Sub(b.UpdatedAt).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	b.Tokens = math.Min(capacity, b.Tokens+elapsed*rate)
+	b.UpdatedAt = now
+
+	result := &Result{Limit: limit.Requests}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.Tokens) / rate)
+	}
+
+	result.Remaining = int(math.Floor(b.Tokens))
+	result.ResetAfter = seconds((capacity - b.Tokens) / rate)
+
+	return result, now.Add(result.ResetAfter)
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(math.Ceil(value)) * time.Second
+}
@@ -0,0 +1,112 @@
+package similarity
+
+import (
+	"strings"
+	"unicode"
+)
+
+// companyTypes 比對名稱時忽略的公司型態字樣(出現在名稱任何位置)
+var companyTypes = []string{"股份有限公司", "有限公司", "企業社", "公司"}
+
+// nameSuffixes 比對名稱時忽略的英文公司型態結尾
+var nameSuffixes = []string{"coltd", "corp", "inc", "ltd", "llc"}
+
+// NormalizeName 正規化客戶 / 專案名稱:轉小寫、全形轉半形、移除空白與符號及公司型態字樣
+func NormalizeName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		// 全形英數字轉半形
+		if r >= '！' && r <= '～' {
+			r -= 0xFEE0
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+
+	name := b.String()
+	for _, companyType := range companyTypes {
+		if trimmed := strings.ReplaceAll(name, companyType, ""); trimmed != "" {
+			name = trimmed
+		}
+	}
+	for _, suffix := range nameSuffixes {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != "" {
+			name = trimmed
+		}
+	}
+	return name
+}
+
+// NormalizeEmail 正規化電子郵件
+func NormalizeEmail(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// NormalizePhone 正規化電話號碼,只保留數字,+886 開頭轉為 0
+func NormalizePhone(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+
+	phone := b.String()
+	if strings.HasPrefix(phone, "886") && len(phone) > 9 {
+		phone = "0" + phone[3:]
+	}
+	return phone
+}
+
+// NormalizePartNumber 正規化料號:轉大寫並移除空白
+func NormalizePartNumber(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), ""))
+}
+
+// Ratio 以編輯距離計算兩個字串的相似度(0~1),一方包含另一方時以長度比例計算
+func Ratio(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longer := len(ra)
+	if len(rb) > longer {
+		longer = len(rb)
+	}
+
+	ratio := 1 - float64(levenshtein(ra, rb))/float64(longer)
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		shorter := len(ra) + len(rb) - longer
+		if contained := 0.5 + 0.5*float64(shorter)/float64(longer); contained > ratio {
+			ratio = contained
+		}
+	}
+	return ratio
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,262 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"esst_sendEmail/internal/pkg/encryption"
+	"esst_sendEmail/internal/pkg/util"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// FlowTTL 從導向身分提供者到回呼的有效時間
+const FlowTTL = 10 * time.Minute
+
+var (
+	ErrDisabled     = errors.New("未設定 OIDC 單一登入")
+	ErrInvalidFlow  = errors.New("登入流程無效或已過期，請重新登入")
+	ErrInvalidNonce = errors.New("ID token nonce 不符")
+	ErrNoRole       = errors.New("您的帳號不屬於任何可登入的群組")
+)
+
+// Flow 一次授權碼流程的暫存資料(加密後存放於 cookie)
+type Flow struct {
+	State     string    `json:"state"`
+	Nonce     string    `json:"nonce"`
+	Verifier  string    `json:"verifier"` // PKCE code verifier
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Identity 從 ID token 取得的使用者資訊
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	Name          string
+	Groups        []string
+}
+
+type provider struct {
+	config   *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+var (
+	instance *provider
+	mu       sync.Mutex
+)
+
+// Enabled 是否已設定 OIDC
+func Enabled() bool {
+	return os.Getenv("OIDC_ISSUER_URL") != "" && os.Getenv("OIDC_CLIENT_ID") != ""
+}
+
+// getProvider 透過 discovery 取得身分提供者設定,失敗時下次呼叫會重試
+func getProvider(ctx context.Context) (*provider, error) {
+	if !Enabled() {
+		return nil, ErrDisabled
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if instance != nil {
+		return instance, nil
+	}
+
+	oidcProvider, err := oidc.NewProvider(ctx, os.Getenv("OIDC_ISSUER_URL"))
+	if err != nil {
+		return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+
+	scopes := strings.Fields(os.Getenv("OIDC_SCOPES"))
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+	}
+
+	clientID := os.Getenv("OIDC_CLIENT_ID")
+	instance = &provider{
+		config: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+			Endpoint:     oidcProvider.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier: oidcProvider.Verifier(&oidc.Config{ClientID: clientID}),
+	}
+
+	return instance, nil
+}
+
+// NewFlow 產生 state、nonce 與 PKCE verifier
+func NewFlow() (*Flow, error) {
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Flow{
+		State:     state,
+		Nonce:     nonce,
+		Verifier:  oauth2.GenerateVerifier(),
+		ExpiresAt: time.Now().Add(FlowTTL),
+	}, nil
+}
+
+// EncodeFlow 加密流程資料,供寫入 cookie
+func EncodeFlow(flow *Flow) (string, error) {
+	data, err := json.Marshal(flow)
+	if err != nil {
+		return "", err
+	}
+
+	return encryption.Encrypt(string(data))
+}
+
+// DecodeFlow 解密 cookie 中的流程資料並檢查期限
+func DecodeFlow(value string) (*Flow, error) {
+	plaintext, err := encryption.Decrypt(value)
+	if err != nil {
+		return nil, ErrInvalidFlow
+	}
+
+	flow := &Flow{}
+	if err := json.Unmarshal([]byte(plaintext), flow); err != nil {
+		return nil, ErrInvalidFlow
+	}
+
+	if time.Now().After(flow.ExpiresAt) {
+		return nil, ErrInvalidFlow
+	}
+
+	return flow, nil
+}
+
+// AuthCodeURL 產生導向身分提供者的授權網址
+func AuthCodeURL(ctx context.Context, flow *Flow) (string, error) {
+	p, err := getProvider(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return p.config.AuthCodeURL(flow.State, oidc.Nonce(flow.Nonce), oauth2.S256ChallengeOption(flow.Verifier)), nil
+}
+
+// Exchange 以授權碼換取 token,驗證 ID token 後回傳使用者資訊
+func Exchange(ctx context.Context, flow *Flow, code string) (*Identity, error) {
+	p, err := getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(flow.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("OIDC code exchange failed: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("OIDC token response has no id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC id_token verification failed: %w", err)
+	}
+
+	if idToken.Nonce != flow.Nonce {
+		return nil, ErrInvalidNonce
+	}
+
+	claims := map[string]interface{}{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Subject:       idToken.Subject,
+		Email:         stringClaim(claims, "email"),
+		EmailVerified: boolClaim(claims, "email_verified"),
+		Username:      stringClaim(claims, "preferred_username"),
+		Name:          stringClaim(claims, "name"),
+		Groups:        listClaim(claims, groupsClaim()),
+	}
+
+	return identity, nil
+}
+
+// RoleForGroups 依 OIDC_ROLE_MAPPING 將群組轉換為角色,沒有符合的群組時使用 OIDC_DEFAULT_ROLE
+func RoleForGroups(groups []string) (string, error) {
+	role, ok := util.RoleForGroups(os.Getenv("OIDC_ROLE_MAPPING"), os.Getenv("OIDC_DEFAULT_ROLE"), groups)
+	if !ok {
+		return "", ErrNoRole
+	}
+
+	return role, nil
+}
+
+func groupsClaim() string {
+	if claim := os.Getenv("OIDC_GROUPS_CLAIM"); claim != "" {
+		return claim
+	}
+	return "groups"
+}
+
+func stringClaim(claims map[string]interface{}, key string) string {
+	value, _ := claims[key].(string)
+	return value
+}
+
+func boolClaim(claims map[string]interface{}, key string) bool {
+	switch value := claims[key].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	default:
+		return false
+	}
+}
+
+// listClaim 群組 claim 可能是字串陣列或以空白分隔的字串
+func listClaim(claims map[string]interface{}, key string) []string {
+	switch value := claims[key].(type) {
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	case string:
+		return strings.Fields(value)
+	default:
+		return nil
+	}
+}
+
+func randomString() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,161 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"esst_sendEmail/internal/pkg/sso/ssotest"
+)
+
+// newTestProvider 啟動模擬的身分提供者並指向它
+func newTestProvider(t *testing.T) *ssotest.Provider {
+	t.Helper()
+
+	provider, err := ssotest.NewProvider("esst-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(provider.Close)
+
+	t.Setenv("OIDC_ISSUER_URL", provider.Issuer())
+	t.Setenv("OIDC_CLIENT_ID", provider.ClientID)
+	t.Setenv("OIDC_CLIENT_SECRET", "secret")
+	t.Setenv("OIDC_REDIRECT_URL", "http://localhost/callback")
+
+	mu.Lock()
+	instance = nil
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		instance = nil
+		mu.Unlock()
+	})
+
+	return provider
+}
+
+// authorize 走完導向身分提供者的步驟,回傳授權碼
+func authorize(t *testing.T, flow *Flow) string {
+	t.Helper()
+
+	authURL, err := AuthCodeURL(context.Background(), flow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.Query().Get("state") != flow.State {
+		t.Fatalf("state = %q, want %q", location.Query().Get("state"), flow.State)
+	}
+
+	return location.Query().Get("code")
+}
+
+func TestExchange(t *testing.T) {
+	provider := newTestProvider(t)
+	provider.Claims = map[string]interface{}{
+		"sub":                "subject-1",
+		"email":              "alice@example.com",
+		"email_verified":     true,
+		"preferred_username": "alice",
+		"groups":             []string{"sales", "admins"},
+	}
+
+	flow, err := NewFlow()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity, err := Exchange(context.Background(), flow, authorize(t, flow))
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+
+	if identity.Subject != "subject-1" || identity.Email != "alice@example.com" || !identity.EmailVerified || identity.Username != "alice" {
+		t.Errorf("Exchange() identity = %+v", identity)
+	}
+	if len(identity.Groups) != 2 {
+		t.Errorf("Exchange() groups = %v", identity.Groups)
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	provider := newTestProvider(t)
+	provider.Claims = map[string]interface{}{"sub": "subject-1"}
+	provider.Nonce = "another-nonce"
+
+	flow, err := NewFlow()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Exchange(context.Background(), flow, authorize(t, flow))
+	if !errors.Is(err, ErrInvalidNonce) {
+		t.Errorf("Exchange() error = %v, want ErrInvalidNonce", err)
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	provider := newTestProvider(t)
+	provider.Claims = map[string]interface{}{"sub": "subject-1"}
+
+	flow, err := NewFlow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := authorize(t, flow)
+
+	// 換取 token 時帶入不同的 PKCE verifier
+	other, err := NewFlow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Nonce = flow.Nonce
+
+	if _, err := Exchange(context.Background(), other, code); err == nil {
+		t.Error("Exchange() accepted a code with the wrong PKCE verifier")
+	}
+}
+
+func TestRoleForGroups(t *testing.T) {
+	tests := []struct {
+		name        string
+		mapping     string
+		defaultRole string
+		groups      []string
+		role        string
+		err         error
+	}{
+		{"mapped group", "admins=admin,sales=user", "", []string{"Sales"}, "user", nil},
+		{"first mapping wins", "admins=admin,sales=user", "", []string{"sales", "admins"}, "admin", nil},
+		{"default role", "admins=admin", "user", []string{"others"}, "user", nil},
+		{"no role", "admins=admin", "", []string{"others"}, "", ErrNoRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OIDC_ROLE_MAPPING", tt.mapping)
+			t.Setenv("OIDC_DEFAULT_ROLE", tt.defaultRole)
+
+			role, err := RoleForGroups(tt.groups)
+			if role != tt.role || !errors.Is(err, tt.err) {
+				t.Errorf("RoleForGroups() = (%q, %v), want (%q, %v)", role, err, tt.role, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalPath 本機儲存的下載路由
+const LocalPath = "/authority/v1.0/files"
+
+// Local 本機檔案系統儲存,供開發與測試使用;下載網址以 HMAC 簽章限制時效
+type Local struct {
+	dir     string
+	baseURL string
+	secret  []byte
+}
+
+// ErrNoSigningKey 未設定下載網址的簽章金鑰
+var ErrNoSigningKey = errors.New("STORAGE_SIGNING_KEY is not set")
+
+// NewLocal 建立本機儲存,dir 預設 ./uploads;secret 為空時無法產生或驗證下載網址
+func NewLocal(dir, baseURL, secret string) *Local {
+	if dir == "" {
+		dir = "./uploads"
+	}
+	return &Local{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  []byte(secret),
+	}
+}
+
+// LocalFromEnv 以環境變數建立本機儲存(下載路由使用)
+func LocalFromEnv() *Local {
+	return NewLocal(os.Getenv("STORAGE_LOCAL_DIR"), os.Getenv("STORAGE_PUBLIC_URL"), os.Getenv("STORAGE_SIGNING_KEY"))
+}
+
+func (l *Local) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) PresignGet(ctx context.Context, key, filename string, expires time.Duration) (string, error) {
+	if len(l.secret) == 0 {
+		return "", ErrNoSigningKey
+	}
+
+	expiresAt := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("key", key)
+	query.Set("filename", filename)
+	query.Set("expires", expiresAt)
+	query.Set("signature", l.sign(key, filename, expiresAt))
+
+	return fmt.Sprintf("%s%s?%s", l.baseURL, LocalPath, query.Encode()), nil
+}
+
+// Verify 驗證下載網址的簽章與時效,回傳檔案路徑
+func (l *Local) Verify(key, filename, expires, signature string) (string, error) {
+	if len(l.secret) == 0 || !hmac.Equal([]byte(l.sign(key, filename, expires)), []byte(signature)) {
+		return "", ErrNotFound
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return "", ErrNotFound
+	}
+
+	path, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return "", ErrNotFound
+	}
+	return path, nil
+}
+
+func (l *Local) sign(key, filename, expires string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(key + "\n" + filename + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path 將 key 轉為儲存目錄下的路徑,不允許跳出儲存目錄
+func (l *Local) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" {
+		return "", ErrNotFound
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalSignature(t *testing.T) {
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, "projects"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "projects", "a.pdf"), []byte("%PDF"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local := NewLocal(dir, "http://localhost", "secret")
+	link, err := local.PresignGet(context.Background(), "projects/a.pdf", "a.pdf", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+
+	tests := []struct {
+		name      string
+		local     *Local
+		signature string
+		ok        bool
+	}{
+		{"valid", local, query.Get("signature"), true},
+		{"tampered", local, strings.Repeat("0", 64), false},
+		{"other key", NewLocal(dir, "", "other"), query.Get("signature"), false},
+		{"empty key", NewLocal(dir, "", ""), query.Get("signature"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.local.Verify(query.Get("key"), query.Get("filename"), query.Get("expires"), tt.signature)
+			if (err == nil) != tt.ok {
+				t.Errorf("Verify() error = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLocalPresignWithoutKey(t *testing.T) {
+	_, err := NewLocal(t.TempDir(), "", "").PresignGet(context.Background(), "projects/a.pdf", "a.pdf", time.Minute)
+	if !errors.Is(err, ErrNoSigningKey) {
+		t.Errorf("PresignGet() error = %v, want %v", err, ErrNoSigningKey)
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"time"
+
+	amazonS3 "esst_sendEmail/internal/pkg/amazon/s3"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Options S3 儲存設定
+type S3Options = amazonS3.Options
+
+// S3 Amazon S3 或相容服務(MinIO)儲存
+type S3 struct {
+	client amazonS3.AmazonStorageInterface
+}
+
+// NewS3 建立 S3 儲存,Endpoint 為空時連線 AWS
+func NewS3(ctx context.Context, options S3Options) (*S3, error) {
+	client, err := amazonS3.NewAmazonStorage(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return NewS3WithClient(client), nil
+}
+
+// NewS3WithClient 以既有的 S3 用戶端(例如 amazonS3.NewFake)建立儲存
+func NewS3WithClient(client amazonS3.AmazonStorageInterface) *S3 {
+	return &S3{client: client}
+}
+
+// Put 以分段上傳串流寫入,不需事先知道檔案大小
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	input := &s3.PutObjectInput{
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	}
+	if size >= 0 {
+		input.ContentLength = aws.Int64(size)
+	}
+
+	_, err := s.client.Upload(ctx, input)
+	return err
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	_, err := s.client.Delete(ctx, &s3.DeleteObjectInput{
+		Key: aws.String(key),
+	})
+
+	var notFound *types.NoSuchKey
+	if errors.As(err, &notFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *S3) PresignGet(ctx context.Context, key, filename string, expires time.Duration) (string, error) {
+	input := &s3.GetObjectInput{
+		Key: aws.String(key),
+	}
+	if disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename}); disposition != "" {
+		input.ResponseContentDisposition = aws.String(disposition)
+	}
+
+	return s.client.PresignGet(ctx, input, expires)
+}
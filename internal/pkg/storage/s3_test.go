@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	amazonS3 "esst_sendEmail/internal/pkg/amazon/s3"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestS3PutAndDelete(t *testing.T) {
+	ctx := context.Background()
+	fake := amazonS3.NewFake("bucket")
+	storage := NewS3WithClient(fake)
+
+	err := storage.Put(ctx, "projects/p1/a.pdf", strings.NewReader("%PDF-1.4"), -1, "application/pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := fake.Get(ctx, &s3.GetObjectInput{Key: aws.String("projects/p1/a.pdf")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(output.Body)
+	if string(body) != "%PDF-1.4" || aws.ToString(output.ContentType) != "application/pdf" {
+		t.Errorf("Get() = %q, %q", body, aws.ToString(output.ContentType))
+	}
+
+	err = storage.Delete(ctx, "projects/p1/a.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = fake.Head(ctx, &s3.HeadObjectInput{Key: aws.String("projects/p1/a.pdf")})
+	var notFound *types.NotFound
+	if !errors.As(err, &notFound) {
+		t.Errorf("Head() after Delete error = %v, want NotFound", err)
+	}
+
+	// 刪除不存在的檔案不視為錯誤
+	if err := storage.Delete(ctx, "projects/p1/a.pdf"); err != nil {
+		t.Errorf("Delete() missing object error = %v", err)
+	}
+}
+
+func TestS3PresignGet(t *testing.T) {
+	storage := NewS3WithClient(amazonS3.NewFake("bucket"))
+
+	url, err := storage.PresignGet(context.Background(), "stocks/s1/b.xlsx", "報價.xlsx", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(url, "fake://bucket/stocks/s1/b.xlsx?method=GET") {
+		t.Errorf("PresignGet() = %q", url)
+	}
+}
+
+func TestS3CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	storage := NewS3WithClient(amazonS3.NewFake("bucket"))
+	if err := storage.Put(ctx, "a", strings.NewReader("a"), 1, "text/plain"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Put() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+)
+
+// ErrNotFound 檔案不存在
+var ErrNotFound = errors.New("file not found")
+
+// Storage 附件檔案儲存介面
+type Storage interface {
+	// Put 儲存檔案,size 未知時傳 -1
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Delete 刪除檔案,檔案不存在時不視為錯誤
+	Delete(ctx context.Context, key string) error
+	// PresignGet 產生限時下載網址,filename 為下載時的檔名
+	PresignGet(ctx context.Context, key, filename string, expires time.Duration) (string, error)
+}
+
+// 儲存服務類型
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Driver 由 STORAGE_DRIVER 設定儲存服務類型,minio 視為 s3,預設 local
+func Driver() string {
+	switch strings.ToLower(os.Getenv("STORAGE_DRIVER")) {
+	case "s3", "minio":
+		return DriverS3
+	default:
+		return DriverLocal
+	}
+}
+
+// New 依 Driver 建立儲存服務;S3 設定錯誤時回傳的儲存服務每次操作都會回傳該錯誤
+func New() Storage {
+	switch Driver() {
+	case DriverS3:
+		partSizeMB, _ := strconv.ParseInt(os.Getenv("STORAGE_S3_PART_SIZE_MB"), 10, 64)
+		s3, err := NewS3(context.Background(), S3Options{
+			Bucket:       os.Getenv("STORAGE_S3_BUCKET"),
+			Endpoint:     os.Getenv("STORAGE_S3_ENDPOINT"),
+			Region:       os.Getenv("STORAGE_S3_REGION"),
+			UsePathStyle: os.Getenv("STORAGE_S3_PATH_STYLE") == "true",
+			PartSize:     partSizeMB << 20,
+		})
+		if err != nil {
+			log.Error("Failed to initialize S3 storage:", err)
+			return &unavailable{err: err}
+		}
+		return s3
+	default:
+		return LocalFromEnv()
+	}
+}
+
+// unavailable 初始化失敗的儲存服務
+type unavailable struct {
+	err error
+}
+
+func (u *unavailable) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	return u.err
+}
+
+func (u *unavailable) Delete(ctx context.Context, key string) error {
+	return u.err
+}
+
+func (u *unavailable) PresignGet(ctx context.Context, key, filename string, expires time.Duration) (string, error) {
+	return "", u.err
+}
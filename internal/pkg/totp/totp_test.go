@@ -0,0 +1,109 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 附錄 B 的 SHA1 測試金鑰 "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := GenerateCode(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("GenerateCode(%d): %v", tt.unix, err)
+		}
+		if code != tt.code {
+			t.Errorf("GenerateCode(%d) = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestValidateStep(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / Period
+
+	codeAt := func(offset int64) string {
+		code, err := GenerateCode(rfcSecret, now.Add(time.Duration(offset*Period)*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name string
+		code string
+		step int64
+		ok   bool
+	}{
+		{"current step", codeAt(0), current, true},
+		{"previous step within skew", codeAt(-1), current - 1, true},
+		{"next step within skew", codeAt(1), current + 1, true},
+		{"outside skew", codeAt(-2), 0, false},
+		{"surrounding spaces", " " + codeAt(0) + " ", current, true},
+		{"wrong length", "12345", 0, false},
+		{"wrong code", "000000", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateStep(rfcSecret, tt.code, now)
+			if ok != tt.ok || step != tt.step {
+				t.Errorf("ValidateStep() = (%d, %v), want (%d, %v)", step, ok, tt.step, tt.ok)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsReplay(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / Period
+
+	code, err := GenerateCode(rfcSecret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := GenerateCode(rfcSecret, now.Add(-Period*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		ok       bool
+	}{
+		{"never used", code, 0, true},
+		{"earlier step used", code, current - 1, true},
+		{"same step used", code, current, false},
+		{"later step used", code, current + 1, false},
+		{"older code after newer one", previous, current, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Verify(rfcSecret, tt.code, now, tt.lastStep)
+			if ok != tt.ok {
+				t.Fatalf("Verify() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && step <= tt.lastStep {
+				t.Errorf("Verify() step = %d, must be after last step %d", step, tt.lastStep)
+			}
+		})
+	}
+}
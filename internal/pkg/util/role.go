@@ -0,0 +1,28 @@
+package util
+
+import "strings"
+
+// RoleForGroups 依群組對應設定(格式: 群組=角色,群組=角色)取得角色,依設定順序第一個符合的群組決定角色
+// 群組名稱不分大小寫;沒有符合的群組時回傳 defaultRole,defaultRole 為空則 ok 為 false
+func RoleForGroups(mapping, defaultRole string, groups []string) (string, bool) {
+	joined := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		joined[strings.ToLower(group)] = struct{}{}
+	}
+
+	for _, pair := range strings.Split(mapping, ",") {
+		group, role, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		if _, exists := joined[strings.ToLower(strings.TrimSpace(group))]; exists {
+			return strings.TrimSpace(role), true
+		}
+	}
+
+	if defaultRole != "" {
+		return defaultRole, true
+	}
+
+	return "", false
+}
@@ -20,3 +20,11 @@ func GenerateRangeNum(min, max int) int {
 func Round(x float64) int64 {
 	return int64(math.Floor(x + 0.5))
 }
+
+// Equal 比較兩個指標指向的值,皆為 nil 時視為相同
+func Equal[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,67 @@
+package api_key
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/api_keys"
+)
+
+func (e *entity) Create(input *model.Table) error {
+	return e.db.Create(input).Error
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Table, error) {
+	var output model.Table
+	db := e.db.Where("ak_id = ?", input.APIKeyID)
+	if input.UserID != nil {
+		db = db.Where("user_id = ?", *input.UserID)
+	}
+	err := db.First(&output).Error
+	return &output, err
+}
+
+func (e *entity) GetByPrefix(prefix string) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("prefix = ?", prefix).First(&output).Error
+	return &output, err
+}
+
+func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Model(&model.Table{})
+
+	if input.UserID != nil {
+		db = db.Where("user_id = ?", *input.UserID)
+	}
+	if !input.IncludeRevoked {
+		db = db.Where("revoked_at IS NULL")
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("created_at DESC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+// Revoke 撤銷金鑰,已撤銷的金鑰不會被覆寫撤銷時間
+func (e *entity) Revoke(id string, revokedAt time.Time) error {
+	return e.db.Model(&model.Table{}).
+		Where("ak_id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt).Error
+}
+
+// Touch 記錄最後使用時間與 IP
+func (e *entity) Touch(id, ip string, usedAt time.Time) error {
+	return e.db.Model(&model.Table{}).
+		Where("ak_id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": usedAt, "last_used_ip": ip}).Error
+}
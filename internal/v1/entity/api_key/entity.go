@@ -0,0 +1,31 @@
+package api_key
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/api_keys"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Create(input *model.Table) error
+	GetByID(input *model.Field) (*model.Table, error)
+	GetByPrefix(prefix string) (*model.Table, error)
+	List(input *model.Fields) (int64, []*model.Table, error)
+	Revoke(id string, revokedAt time.Time) error
+	Touch(id, ip string, usedAt time.Time) error
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,79 @@
+package approval_rule
+
+import (
+	model "esst_sendEmail/internal/v1/structure/approval_rules"
+
+	"gorm.io/gorm"
+)
+
+func (e *entity) Create(input *model.Table) error {
+	return e.db.Create(input).Error
+}
+
+func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Model(&model.Table{})
+
+	if input.Enabled != nil {
+		db = db.Where("enabled = ?", *input.Enabled)
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("level ASC, created_at ASC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+// ListEnabled 取得所有啟用中的規則
+func (e *entity) ListEnabled() ([]*model.Table, error) {
+	var records []*model.Table
+	err := e.db.Where("enabled = ?", true).Order("level ASC, created_at ASC").Find(&records).Error
+	return records, err
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("ar_id = ?", input.RuleID).First(&output).Error
+	return &output, err
+}
+
+func (e *entity) Update(input *model.Table) error {
+	result := e.db.Model(&model.Table{}).
+		Where("ar_id = ?", input.RuleID).
+		Updates(map[string]interface{}{
+			"name":          input.Name,
+			"level":         input.Level,
+			"min_quantity":  input.MinQuantity,
+			"team":          input.Team,
+			"approver_role": input.ApproverRole,
+			"enabled":       input.Enabled,
+			"updated_at":    input.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (e *entity) Delete(input *model.Field) error {
+	result := e.db.Where("ar_id = ?", input.RuleID).Delete(&model.Table{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package attachment
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/attachments"
+
+	"gorm.io/gorm"
+)
+
+func (e *entity) Create(input *model.Table) error {
+	return e.db.Create(input).Error
+}
+
+func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Model(&model.Table{}).
+		Where("target_type = ? AND target_id = ?", input.TargetType, input.TargetID)
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("created_at DESC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("a_id = ? AND target_type = ? AND target_id = ?", input.AttachmentID, input.TargetType, input.TargetID).
+		First(&output).Error
+	return &output, err
+}
+
+func (e *entity) Delete(input *model.Field) error {
+	result := e.db.Where("a_id = ?", input.AttachmentID).Delete(&model.Table{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListPurgeable 查詢對象已在 before 之前刪除或已不存在的附件
+func (e *entity) ListPurgeable(before time.Time) ([]*model.Table, error) {
+	var records []*model.Table
+	err := e.db.Table("attachments AS a").
+		Joins("LEFT JOIN projects AS p ON a.target_type = ? AND p.p_id = a.target_id", model.TargetProject).
+		Joins("LEFT JOIN stocks AS s ON a.target_type = ? AND s.stock_id = a.target_id", model.TargetStock).
+		Where("(a.target_type = ? AND (p.p_id IS NULL OR p.deleted_at < ?)) OR (a.target_type = ? AND (s.stock_id IS NULL OR s.deleted_at < ?))",
+			model.TargetProject, before, model.TargetStock, before).
+		Select("a.*").
+		Find(&records).Error
+	return records, err
+}
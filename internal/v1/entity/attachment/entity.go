@@ -0,0 +1,30 @@
+package attachment
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/attachments"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Create(input *model.Table) error
+	List(input *model.Fields) (int64, []*model.Table, error)
+	GetByID(input *model.Field) (*model.Table, error)
+	Delete(input *model.Field) error
+	ListPurgeable(before time.Time) ([]*model.Table, error)
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
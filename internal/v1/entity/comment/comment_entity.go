@@ -0,0 +1,90 @@
+package comment
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/comments"
+
+	"gorm.io/gorm"
+)
+
+func (e *entity) Create(input *model.Table) error {
+	return e.db.Create(input).Error
+}
+
+// rowColumns 留言與作者帳號
+const rowColumns = "c.*, u.username AS author_name"
+
+func (e *entity) query() *gorm.DB {
+	return e.db.Table("comments AS c").
+		Joins("LEFT JOIN users AS u ON u.id = c.author_id").
+		Where("c.deleted_at IS NULL")
+}
+
+// List 依建立時間排序列出對象的留言
+func (e *entity) List(input *model.Fields) (int64, []*model.Row, error) {
+	var total int64
+	var records []*model.Row
+
+	db := e.query().Where("c.target_type = ? AND c.target_id = ?", input.TargetType, input.TargetID)
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Select(rowColumns).
+		Order("c.created_at ASC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Row, error) {
+	var output model.Row
+	err := e.query().Select(rowColumns).
+		Where("c.c_id = ? AND c.target_type = ? AND c.target_id = ?", input.CommentID, input.TargetType, input.TargetID).
+		Take(&output).Error
+	return &output, err
+}
+
+func (e *entity) Update(input *model.Table) error {
+	result := e.db.Model(&model.Table{}).
+		Where("c_id = ?", input.CommentID).
+		Updates(map[string]interface{}{
+			"body":       input.Body,
+			"updated_at": input.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete 軟刪除留言
+func (e *entity) Delete(input *model.Field) error {
+	result := e.db.Model(&model.Table{}).
+		Where("c_id = ?", input.CommentID).
+		Update("deleted_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge 永久刪除對象已在 before 之前刪除或已不存在的留言(target_id 沒有外鍵,需由排程清除)
+func (e *entity) Purge(before time.Time) (int64, error) {
+	result := e.db.
+		Where("target_type = ? AND NOT EXISTS (SELECT 1 FROM projects AS p WHERE p.p_id = comments.target_id AND (p.deleted_at IS NULL OR p.deleted_at >= ?))", model.TargetProject, before).
+		Or("target_type = ? AND NOT EXISTS (SELECT 1 FROM stocks AS s WHERE s.stock_id = comments.target_id AND (s.deleted_at IS NULL OR s.deleted_at >= ?))", model.TargetStock, before).
+		Delete(&model.Table{})
+	return result.RowsAffected, result.Error
+}
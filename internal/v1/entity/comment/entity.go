@@ -0,0 +1,31 @@
+package comment
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/comments"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Create(input *model.Table) error
+	List(input *model.Fields) (int64, []*model.Row, error)
+	GetByID(input *model.Field) (*model.Row, error)
+	Update(input *model.Table) error
+	Delete(input *model.Field) error
+	Purge(before time.Time) (int64, error)
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -1,6 +1,8 @@
 package equipment
 
 import (
+	"time"
+
 	model "esst_sendEmail/internal/v1/structure/equipments"
 
 	"gorm.io/gorm"
@@ -14,8 +16,13 @@ type Entity interface {
 	ListByProjectID(projectID string) ([]*model.Table, error)
 	GetByID(input *model.Field) (*model.Table, error)
 	Update(input *model.Table) (err error)
-	Delete(input *model.Field) (err error)
-	DeleteByProjectID(projectID string) (err error)
+	Delete(input *model.Field, version int64, deletedBy string, deletedAt time.Time) (err error)
+	DeleteByProjectID(projectID, deletedBy string, deletedAt time.Time) (err error)
+	ListDeleted(input *model.Fields) (int64, []*model.Table, error)
+	GetDeletedByID(input *model.Field) (*model.Table, error)
+	Restore(input *model.Field) (err error)
+	RestoreByProjectID(projectID string, deletedAt time.Time) (err error)
+	Purge(before time.Time) (int64, error)
 }
 
 type entity struct {
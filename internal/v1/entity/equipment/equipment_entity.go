@@ -3,9 +3,12 @@ package equipment
 import (
 	"esst_sendEmail/internal/pkg/log"
 	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/structure"
 	model "esst_sendEmail/internal/v1/structure/equipments"
 	"fmt"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // 批次建立設備
@@ -28,8 +31,13 @@ func (e *entity) CreateBatch(input *model.BatchCreated) error {
 			EquipmentID: util.GenerateUUID(),
 			ProjectID:   input.ProjectID,
 			PartNumber:  eq.PartNumber,
+			PartID:      eq.PartID,
 			Quantity:    eq.Quantity,
 			Description: eq.Description,
+			UnitPrice:   eq.UnitPrice,
+			Discount:    eq.Discount,
+			Currency:    eq.Currency,
+			TaxRate:     eq.TaxRate,
 			CreatedTime: time.Now(),
 		}
 		equipmentTables = append(equipmentTables, equipment)
@@ -103,16 +111,96 @@ func (e *entity) GetByID(input *model.Field) (output *model.Table, err error) {
 	return output, err
 }
 
+// Update 以版本號更新,版本不符(已被其他人修改)時回傳 ErrVersionConflict
 func (e *entity) Update(input *model.Table) (err error) {
-	err = e.db.Model(&model.Table{}).Save(&input).Error
-	return err
+	expected := input.Version
+	input.Version = expected + 1
+
+	result := e.db.Model(&model.Table{}).Where("eq_id = ? AND version = ?", input.EquipmentID, expected).
+		Select("p_id", "part_number", "part_id", "quantity", "description", "unit_price", "discount", "currency", "tax_rate", "version").
+		Updates(input)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return structure.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// Delete 以版本號軟刪除,保留於資源回收筒,版本不符時回傳 ErrVersionConflict
+func (e *entity) Delete(input *model.Field, version int64, deletedBy string, deletedAt time.Time) error {
+	result := e.db.Model(&model.Table{}).Where("eq_id = ? AND version = ?", input.EquipmentID, version).Updates(map[string]interface{}{
+		"deleted_at": deletedAt,
+		"deleted_by": deletedBy,
+		"version":    gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return structure.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// ListDeleted 資源回收筒列表,依刪除時間降序排列
+func (e *entity) ListDeleted(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Unscoped().Model(&model.Table{}).Where("deleted_at IS NOT NULL")
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("deleted_at DESC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+func (e *entity) GetDeletedByID(input *model.Field) (output *model.Table, err error) {
+	db := e.db.Unscoped().Model(&model.Table{}).Where("eq_id = ? AND deleted_at IS NOT NULL", input.EquipmentID)
+	err = db.First(&output).Error
+	return output, err
+}
+
+// Restore 從資源回收筒還原
+func (e *entity) Restore(input *model.Field) error {
+	return e.db.Unscoped().Model(&model.Table{}).Where("eq_id = ?", input.EquipmentID).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": nil,
+		"version":    gorm.Expr("version + 1"),
+	}).Error
+}
+
+// 根據專案 ID 軟刪除所有相關設備
+func (e *entity) DeleteByProjectID(projectID, deletedBy string, deletedAt time.Time) error {
+	return e.db.Model(&model.Table{}).Where("p_id = ?", projectID).Updates(map[string]interface{}{
+		"deleted_at": deletedAt,
+		"deleted_by": deletedBy,
+		"version":    gorm.Expr("version + 1"),
+	}).Error
 }
 
-func (e *entity) Delete(input *model.Field) error {
-	return e.db.Where("eq_id = ?", input.EquipmentID).Delete(&model.Table{}).Error
+// RestoreByProjectID 還原與專案同時刪除的設備(刪除時間相同),不影響先前個別刪除的設備
+func (e *entity) RestoreByProjectID(projectID string, deletedAt time.Time) error {
+	return e.db.Unscoped().Model(&model.Table{}).Where("p_id = ? AND deleted_at = ?", projectID, deletedAt).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": nil,
+		"version":    gorm.Expr("version + 1"),
+	}).Error
 }
 
-// 根據專案 ID 刪除所有相關設備
-func (e *entity) DeleteByProjectID(projectID string) error {
-	return e.db.Where("p_id = ?", projectID).Delete(&model.Table{}).Error
+// Purge 永久刪除在 before 之前刪除的資料
+func (e *entity) Purge(before time.Time) (int64, error) {
+	result := e.db.Unscoped().Where("deleted_at < ?", before).Delete(&model.Table{})
+	return result.RowsAffected, result.Error
 }
@@ -0,0 +1,25 @@
+package equipment_change
+
+import (
+	model "esst_sendEmail/internal/v1/structure/equipment_changes"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	CreateBatch(input []*model.Table) error
+	List(input *model.Fields) (int64, []*model.Table, error)
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,45 @@
+package equipment_change
+
+import (
+	model "esst_sendEmail/internal/v1/structure/equipment_changes"
+)
+
+func (e *entity) CreateBatch(input []*model.Table) error {
+	if len(input) == 0 {
+		return nil
+	}
+
+	return e.db.Create(&input).Error
+}
+
+// List 依異動時間降序列出專案或現貨報備的設備異動紀錄
+func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Model(&model.Table{}).Where("target_type = ? AND target_id = ?", input.TargetType, input.TargetID)
+
+	if input.LineID != nil {
+		db = db.Where("line_id = ?", *input.LineID)
+	}
+
+	if input.BatchID != nil {
+		db = db.Where("batch_id = ?", *input.BatchID)
+	}
+
+	if input.Action != nil {
+		db = db.Where("action = ?", *input.Action)
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("changed_at DESC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
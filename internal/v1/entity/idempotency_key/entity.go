@@ -0,0 +1,30 @@
+package idempotency_key
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/idempotency_keys"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Create(input *model.Table) (bool, error)
+	Get(userID, key string) (*model.Table, error)
+	Complete(id string, statusCode int, responseBody string, completedAt time.Time) error
+	Delete(id string) error
+	Purge(before time.Time) (int64, error)
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
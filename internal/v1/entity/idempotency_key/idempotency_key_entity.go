@@ -0,0 +1,45 @@
+package idempotency_key
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/idempotency_keys"
+
+	"gorm.io/gorm/clause"
+)
+
+// Create 新增紀錄,同一使用者的金鑰已存在時不寫入並回傳 false
+func (e *entity) Create(input *model.Table) (bool, error) {
+	result := e.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "idempotency_key"}},
+		DoNothing: true,
+	}).Create(input)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (e *entity) Get(userID, key string) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("user_id = ? AND idempotency_key = ?", userID, key).First(&output).Error
+	return &output, err
+}
+
+// Complete 保存回應內容
+func (e *entity) Complete(id string, statusCode int, responseBody string, completedAt time.Time) error {
+	return e.db.Model(&model.Table{}).
+		Where("ik_id = ?", id).
+		Updates(map[string]interface{}{
+			"status_code":   statusCode,
+			"response_body": responseBody,
+			"completed_at":  completedAt,
+		}).Error
+}
+
+func (e *entity) Delete(id string) error {
+	return e.db.Where("ik_id = ?", id).Delete(&model.Table{}).Error
+}
+
+// Purge 刪除已過期的紀錄
+func (e *entity) Purge(before time.Time) (int64, error) {
+	result := e.db.Where("expires_at < ?", before).Delete(&model.Table{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,28 @@
+package inventory
+
+import (
+	model "esst_sendEmail/internal/v1/structure/inventories"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Ensure(input []*model.Table) error
+	List(input *model.Fields) (int64, []*model.Table, error)
+	GetByID(input *model.Field) (*model.Table, error)
+	ListForUpdate(normalized []string, ids []string) ([]*model.Table, error)
+	Update(input *model.Table) error
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,89 @@
+package inventory
+
+import (
+	"esst_sendEmail/internal/pkg/similarity"
+	model "esst_sendEmail/internal/v1/structure/inventories"
+
+	"gorm.io/gorm/clause"
+)
+
+// Ensure 建立尚未存在的料號庫存(在庫與保留皆為 0)
+func (e *entity) Ensure(input []*model.Table) error {
+	if len(input) == 0 {
+		return nil
+	}
+
+	return e.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "normalized"}},
+		DoNothing: true,
+	}).Create(&input).Error
+}
+
+func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Model(&model.Table{})
+
+	if input.Query != "" {
+		db = db.Where("normalized LIKE ?", "%"+similarity.NormalizePartNumber(input.Query)+"%")
+	}
+
+	if input.Available != nil {
+		if *input.Available {
+			db = db.Where("on_hand > reserved")
+		} else {
+			db = db.Where("on_hand <= reserved")
+		}
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("part_number ASC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("inv_id = ?", input.InventoryID).First(&output).Error
+	return &output, err
+}
+
+// ListForUpdate 依正規化料號或庫存編號取得庫存並鎖定,依編號排序以避免交易互相等待
+func (e *entity) ListForUpdate(normalized []string, ids []string) ([]*model.Table, error) {
+	var records []*model.Table
+	if len(normalized) == 0 && len(ids) == 0 {
+		return records, nil
+	}
+
+	db := e.db.Clauses(clause.Locking{Strength: "UPDATE"})
+	switch {
+	case len(normalized) > 0 && len(ids) > 0:
+		db = db.Where("normalized IN ? OR inv_id IN ?", normalized, ids)
+	case len(normalized) > 0:
+		db = db.Where("normalized IN ?", normalized)
+	default:
+		db = db.Where("inv_id IN ?", ids)
+	}
+
+	err := db.Order("inv_id ASC").Find(&records).Error
+	return records, err
+}
+
+func (e *entity) Update(input *model.Table) error {
+	return e.db.Model(&model.Table{}).
+		Where("inv_id = ?", input.InventoryID).
+		Updates(map[string]interface{}{
+			"part_id":    input.PartID,
+			"on_hand":    input.OnHand,
+			"reserved":   input.Reserved,
+			"updated_at": input.UpdatedAt,
+		}).Error
+}
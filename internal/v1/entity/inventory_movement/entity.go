@@ -0,0 +1,26 @@
+package inventory_movement
+
+import (
+	model "esst_sendEmail/internal/v1/structure/inventory_movements"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	CreateBatch(input []*model.Table) error
+	List(input *model.Fields) (int64, []*model.Table, error)
+	Outstanding(stockID string, stockEquipmentID string) ([]*model.Outstanding, error)
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,67 @@
+package inventory_movement
+
+import (
+	model "esst_sendEmail/internal/v1/structure/inventory_movements"
+)
+
+func (e *entity) CreateBatch(input []*model.Table) error {
+	if len(input) == 0 {
+		return nil
+	}
+
+	return e.db.Create(&input).Error
+}
+
+func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Model(&model.Table{})
+
+	if input.InventoryID != "" {
+		db = db.Where("inv_id = ?", input.InventoryID)
+	}
+
+	if input.Type != nil {
+		db = db.Where("type = ?", *input.Type)
+	}
+
+	if input.StockID != nil {
+		db = db.Where("stock_id = ?", *input.StockID)
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("created_at DESC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+// Outstanding 彙整現貨報備(或單一現貨設備)各料號尚未出貨的保留數量與已出貨數量
+func (e *entity) Outstanding(stockID string, stockEquipmentID string) ([]*model.Outstanding, error) {
+	var records []*model.Outstanding
+
+	db := e.db.Model(&model.Table{}).
+		Select(`seq_id, inv_id,
+			COALESCE(SUM(CASE type WHEN ? THEN quantity WHEN ? THEN -quantity WHEN ? THEN -quantity ELSE 0 END), 0) AS reserved,
+			COALESCE(SUM(CASE type WHEN ? THEN quantity ELSE 0 END), 0) AS shipped`,
+			model.TypeReserve, model.TypeRelease, model.TypeShip, model.TypeShip).
+		Where("seq_id IS NOT NULL")
+
+	if stockID != "" {
+		db = db.Where("stock_id = ?", stockID)
+	}
+
+	if stockEquipmentID != "" {
+		db = db.Where("seq_id = ?", stockEquipmentID)
+	}
+
+	err := db.Group("seq_id, inv_id").Scan(&records).Error
+	return records, err
+}
@@ -0,0 +1,27 @@
+package login_attempt
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/login_attempts"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Create(input *model.Table) error
+	CountFailedByIP(ip string, since time.Time) (int64, error)
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,20 @@
+package login_attempt
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/login_attempts"
+)
+
+func (e *entity) Create(input *model.Table) error {
+	return e.db.Create(input).Error
+}
+
+// CountFailedByIP 計算指定 IP 在某時間後的登入失敗次數
+func (e *entity) CountFailedByIP(ip string, since time.Time) (int64, error) {
+	var total int64
+	err := e.db.Model(&model.Table{}).
+		Where("ip = ? AND success = false AND created_at >= ?", ip, since).
+		Count(&total).Error
+	return total, err
+}
@@ -0,0 +1,33 @@
+package part
+
+import (
+	model "esst_sendEmail/internal/v1/structure/parts"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Create(input *model.Table) error
+	List(input *model.Fields) (int64, []*model.Table, error)
+	GetByID(input *model.Field) (*model.Table, error)
+	GetByNormalized(normalized string) (*model.Table, error)
+	ListByIDs(ids []string) ([]*model.Table, error)
+	ListByNormalized(normalized []string) ([]*model.Table, error)
+	ListByPrefix(prefix string, limit int) ([]*model.Table, error)
+	Suggest(normalized string, limit int) ([]*model.Suggested, error)
+	Update(input *model.Table) error
+	Delete(input *model.Field) error
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,167 @@
+package part
+
+import (
+	"strings"
+
+	"esst_sendEmail/internal/pkg/similarity"
+	model "esst_sendEmail/internal/v1/structure/parts"
+
+	"gorm.io/gorm"
+)
+
+func (e *entity) Create(input *model.Table) error {
+	return e.db.Create(input).Error
+}
+
+func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Model(&model.Table{})
+
+	if input.Query != "" {
+		keyword := "%" + input.Query + "%"
+		normalized := "%" + similarity.NormalizePartNumber(input.Query) + "%"
+		db = db.Where("part_number ILIKE ? OR name ILIKE ? OR normalized LIKE ?", keyword, keyword, normalized)
+	}
+
+	if input.Category != nil {
+		db = db.Where("category = ?", *input.Category)
+	}
+
+	if input.Brand != nil {
+		db = db.Where("brand = ?", *input.Brand)
+	}
+
+	if input.Active != nil {
+		db = db.Where("active = ?", *input.Active)
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("part_number ASC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("part_id = ?", input.PartID).First(&output).Error
+	return &output, err
+}
+
+// GetByNormalized 依正規化料號取得料號(含停用)
+func (e *entity) GetByNormalized(normalized string) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("normalized = ?", normalized).First(&output).Error
+	return &output, err
+}
+
+// ListByIDs 依編號取得啟用中的料號
+func (e *entity) ListByIDs(ids []string) ([]*model.Table, error) {
+	var records []*model.Table
+	if len(ids) == 0 {
+		return records, nil
+	}
+
+	err := e.db.Where("part_id IN ? AND active = ?", ids, true).Find(&records).Error
+	return records, err
+}
+
+// ListByNormalized 依正規化料號取得啟用中的料號
+func (e *entity) ListByNormalized(normalized []string) ([]*model.Table, error) {
+	var records []*model.Table
+	if len(normalized) == 0 {
+		return records, nil
+	}
+
+	err := e.db.Where("normalized IN ? AND active = ?", normalized, true).Find(&records).Error
+	return records, err
+}
+
+// ListByPrefix 取得正規化料號以 prefix 開頭的啟用中料號,作為建議候選
+func (e *entity) ListByPrefix(prefix string, limit int) ([]*model.Table, error) {
+	var records []*model.Table
+	err := e.db.Where("normalized LIKE ? AND active = ?", prefix+"%", true).
+		Order("normalized ASC").
+		Limit(limit).
+		Find(&records).Error
+	return records, err
+}
+
+// normalizedPart 設備料號去除空白並轉大寫,篩選條件需與索引(idx_*_part_number_trgm)使用相同的運算式
+const normalizedPart = `upper(regexp_replace(part_number, '\s', '', 'g'))`
+
+// likeEscaper 跳脫 LIKE 的萬用字元
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Suggest 依歷史專案設備與現貨設備彙整料號,以前綴相符、三連字相似度與使用次數排序
+// 分數 = 前綴相符 0.5 + 三連字相似度(0~1) + 使用次數 0.3(100 次以上給滿分)
+func (e *entity) Suggest(normalized string, limit int) ([]*model.Suggested, error) {
+	var records []*model.Suggested
+	prefix := likeEscaper.Replace(normalized) + "%"
+
+	err := e.db.Raw(`SELECT normalized,
+			mode() WITHIN GROUP (ORDER BY part_number) AS part_number,
+			(array_agg(description ORDER BY created_time DESC) FILTER (WHERE description <> ''))[1] AS description,
+			(array_agg(part_id::text) FILTER (WHERE part_id IS NOT NULL))[1] AS part_id,
+			count(*) AS count,
+			round((CASE WHEN normalized LIKE ? THEN 0.5 ELSE 0 END
+				+ similarity(normalized, ?)
+				+ 0.3 * least(ln(1 + count(*)) / ln(101), 1))::numeric, 3) AS score
+		FROM (
+			SELECT `+normalizedPart+` AS normalized, part_number, description, part_id, created_time
+			FROM equipments
+			WHERE deleted_at IS NULL AND (`+normalizedPart+` LIKE ? OR `+normalizedPart+` % ?)
+			UNION ALL
+			SELECT `+normalizedPart+` AS normalized, part_number, description, part_id, created_time
+			FROM stock_equipments
+			WHERE deleted_at IS NULL AND (`+normalizedPart+` LIKE ? OR `+normalizedPart+` % ?)
+		) lines
+		GROUP BY normalized
+		ORDER BY score DESC, count DESC, normalized ASC
+		LIMIT ?`,
+		prefix, normalized, prefix, normalized, prefix, normalized, limit).
+		Scan(&records).Error
+	return records, err
+}
+
+func (e *entity) Update(input *model.Table) error {
+	result := e.db.Model(&model.Table{}).
+		Where("part_id = ?", input.PartID).
+		Updates(map[string]interface{}{
+			"part_number": input.PartNumber,
+			"normalized":  input.Normalized,
+			"name":        input.Name,
+			"category":    input.Category,
+			"brand":       input.Brand,
+			"unit":        input.Unit,
+			"list_price":  input.ListPrice,
+			"active":      input.Active,
+			"updated_at":  input.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (e *entity) Delete(input *model.Field) error {
+	result := e.db.Where("part_id = ?", input.PartID).Delete(&model.Table{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package password_reset
+
+import (
+	"errors"
+
+	model "esst_sendEmail/internal/v1/structure/password_resets"
+
+	"gorm.io/gorm"
+)
+
+// ErrAlreadyUsed token 已被使用或已失效
+var ErrAlreadyUsed = errors.New("password reset token already used")
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Create(input *model.Table) error
+	GetByID(id string) (*model.Table, error)
+	MarkUsed(input *model.Table) error
+	InvalidateByUserID(userID string) error
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,38 @@
+package password_reset
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/password_resets"
+)
+
+func (e *entity) Create(input *model.Table) error {
+	return e.db.Create(input).Error
+}
+
+func (e *entity) GetByID(id string) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("prt_id = ?", id).First(&output).Error
+	return &output, err
+}
+
+// MarkUsed 標記 token 已使用(條件包含 used_at IS NULL,確保只能使用一次)
+func (e *entity) MarkUsed(input *model.Table) error {
+	result := e.db.Model(&model.Table{}).
+		Where("prt_id = ? AND used_at IS NULL", input.PasswordResetID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAlreadyUsed
+	}
+	return nil
+}
+
+// InvalidateByUserID 讓使用者所有尚未使用的 token 失效
+func (e *entity) InvalidateByUserID(userID string) error {
+	return e.db.Model(&model.Table{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
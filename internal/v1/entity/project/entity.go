@@ -1,6 +1,8 @@
 package project
 
 import (
+	"time"
+
 	model "esst_sendEmail/internal/v1/structure/projects"
 
 	"gorm.io/gorm"
@@ -12,7 +14,17 @@ type Entity interface {
 	List(input *model.Fields) (int64, []*model.Table, error)
 	GetByID(input *model.Field) (*model.Table, error)
 	Update(input *model.Table) (err error)
-	Delete(input *model.Field) (err error)
+	Delete(input *model.Field, version int64, deletedBy string, deletedAt time.Time) (err error)
+	ListDeleted(input *model.Fields) (int64, []*model.Table, error)
+	GetDeletedByID(input *model.Field) (*model.Table, error)
+	Restore(input *model.Field) (err error)
+	Purge(before time.Time) (int64, error)
+	ListExpiring(now, before time.Time) ([]*model.Table, error)
+	ListOverdue(now time.Time) ([]*model.Table, error)
+	MarkExpiryWarned(projectID string, warnedAt time.Time) error
+	Expire(projectID string, expiredAt time.Time) (bool, error)
+	Extend(projectID string, expiresAt time.Time) error
+	UpdateApproval(projectID, approvalStatus string, approvalLevel int) error
 }
 
 type entity struct {
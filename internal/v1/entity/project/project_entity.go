@@ -1,7 +1,12 @@
 package project
 
 import (
+	"time"
+
+	"esst_sendEmail/internal/v1/structure"
 	model "esst_sendEmail/internal/v1/structure/projects"
+
+	"gorm.io/gorm"
 )
 
 func (e *entity) Create(input *model.Table) error {
@@ -35,6 +40,9 @@ func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
 	if input.Status != nil {
 		db = db.Where("status = ?", *input.Status)
 	}
+	if input.ApprovalStatus != nil {
+		db = db.Where("approval_status = ?", *input.ApprovalStatus)
+	}
 
 	err := db.Count(&total).Error
 	if err != nil {
@@ -58,14 +66,148 @@ func (e *entity) GetByID(input *model.Field) (output *model.Table, err error) {
 	return output, err
 }
 
+// Update 以版本號更新,版本不符(已被其他人修改)時回傳 ErrVersionConflict
 func (e *entity) Update(input *model.Table) (err error) {
-	// 使用 Save 會更新所有欄位（包括零值）
-	// 如果只想更新非零值欄位，可以使用 Updates
-	err = e.db.Model(&model.Table{}).Where("p_id = ?", input.ProjectID).Updates(input).Error
+	expected := input.Version
+	input.Version = expected + 1
+
+	// 使用 Updates 只更新非零值欄位
+	result := e.db.Model(&model.Table{}).Where("p_id = ? AND version = ?", input.ProjectID, expected).Updates(input)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return structure.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// Delete 以版本號軟刪除,保留於資源回收筒,版本不符時回傳 ErrVersionConflict
+func (e *entity) Delete(input *model.Field, version int64, deletedBy string, deletedAt time.Time) error {
+	result := e.db.Model(&model.Table{}).Where("p_id = ? AND version = ?", input.ProjectID, version).Updates(map[string]interface{}{
+		"deleted_at": deletedAt,
+		"deleted_by": deletedBy,
+		"version":    gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return structure.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// ListDeleted 資源回收筒列表,依刪除時間降序排列
+func (e *entity) ListDeleted(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Unscoped().Model(&model.Table{}).Where("deleted_at IS NOT NULL")
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("deleted_at DESC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
 
-	return err
+	return total, records, err
+}
+
+func (e *entity) GetDeletedByID(input *model.Field) (output *model.Table, err error) {
+	db := e.db.Unscoped().Model(&model.Table{}).Where("p_id = ? AND deleted_at IS NOT NULL", input.ProjectID)
+	err = db.First(&output).Error
+	return output, err
+}
+
+// Restore 從資源回收筒還原
+func (e *entity) Restore(input *model.Field) error {
+	return e.db.Unscoped().Model(&model.Table{}).Where("p_id = ?", input.ProjectID).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": nil,
+		"version":    gorm.Expr("version + 1"),
+	}).Error
+}
+
+// Purge 永久刪除在 before 之前刪除的資料
+func (e *entity) Purge(before time.Time) (int64, error) {
+	result := e.db.Unscoped().Where("deleted_at < ?", before).Delete(&model.Table{})
+	return result.RowsAffected, result.Error
+}
+
+// ListExpiring 查詢即將到期且尚未提醒的第一階段專案
+func (e *entity) ListExpiring(now, before time.Time) ([]*model.Table, error) {
+	var records []*model.Table
+	err := e.db.Model(&model.Table{}).
+		Where("status = ? AND expires_at > ? AND expires_at <= ? AND expiry_warned_at IS NULL", model.StatusStep1, now, before).
+		Order("expires_at").
+		Find(&records).Error
+	return records, err
+}
+
+// ListOverdue 查詢保護期已過的第一階段專案
+func (e *entity) ListOverdue(now time.Time) ([]*model.Table, error) {
+	var records []*model.Table
+	err := e.db.Model(&model.Table{}).
+		Where("status = ? AND expires_at <= ?", model.StatusStep1, now).
+		Order("expires_at").
+		Find(&records).Error
+	return records, err
+}
+
+// MarkExpiryWarned 記錄到期前提醒已發送
+func (e *entity) MarkExpiryWarned(projectID string, warnedAt time.Time) error {
+	return e.db.Model(&model.Table{}).
+		Where("p_id = ?", projectID).
+		Update("expiry_warned_at", warnedAt).Error
+}
+
+// Expire 將仍在第一階段的專案轉為 expired,回傳是否有更新
+func (e *entity) Expire(projectID string, expiredAt time.Time) (bool, error) {
+	result := e.db.Model(&model.Table{}).
+		Where("p_id = ? AND status = ?", projectID, model.StatusStep1).
+		Updates(map[string]interface{}{
+			"status":       model.StatusExpired,
+			"updated_time": expiredAt,
+			"version":      gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Extend 延長保護期,已過期的專案回到第一階段並重新提醒
+func (e *entity) Extend(projectID string, expiresAt time.Time) error {
+	result := e.db.Model(&model.Table{}).
+		Where("p_id = ? AND status IN ?", projectID, []string{model.StatusStep1, model.StatusExpired}).
+		Updates(map[string]interface{}{
+			"status":           model.StatusStep1,
+			"expires_at":       expiresAt,
+			"expiry_warned_at": nil,
+			"updated_time":     time.Now(),
+			"version":          gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-func (e *entity) Delete(input *model.Field) error {
-	return e.db.Where("p_id = ?", input.ProjectID).Delete(&model.Table{}).Error
+// UpdateApproval 更新審核狀態與目前關卡
+func (e *entity) UpdateApproval(projectID, approvalStatus string, approvalLevel int) error {
+	return e.db.Model(&model.Table{}).
+		Where("p_id = ?", projectID).
+		Updates(map[string]interface{}{
+			"approval_status": approvalStatus,
+			"approval_level":  approvalLevel,
+			"updated_time":    time.Now(),
+			"version":         gorm.Expr("version + 1"),
+		}).Error
 }
@@ -0,0 +1,32 @@
+package project_approval
+
+import (
+	model "esst_sendEmail/internal/v1/structure/project_approvals"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	CreateBatch(input []*model.Table) error
+	ListByProjectID(input *model.Fields) (int64, []*model.Row, error)
+	GetByID(input *model.Field) (*model.Row, error)
+	NextPending(projectID string, afterLevel int) (*model.Table, error)
+	Queue(input *model.Fields, role string) (int64, []*model.Row, error)
+	Decide(input *model.Table) error
+	CancelPending(projectID string) error
+	ListLevels(projectID string) ([]*model.Table, error)
+	UpdateLevel(input *model.Table) error
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
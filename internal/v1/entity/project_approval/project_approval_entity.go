@@ -0,0 +1,141 @@
+package project_approval
+
+import (
+	model "esst_sendEmail/internal/v1/structure/project_approvals"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateBatch 建立審核關卡,已存在的關卡略過
+func (e *entity) CreateBatch(input []*model.Table) error {
+	if len(input) == 0 {
+		return nil
+	}
+	return e.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&input).Error
+}
+
+// rowColumns 紀錄與專案資訊
+const rowColumns = "pa.*, p.p_name, p.owner, p.contact_name, p.created_time"
+
+func (e *entity) query() *gorm.DB {
+	return e.db.Table("project_approvals AS pa").
+		Joins("JOIN projects AS p ON p.p_id = pa.p_id AND p.deleted_at IS NULL")
+}
+
+// ListByProjectID 查詢專案的審核紀錄
+func (e *entity) ListByProjectID(input *model.Fields) (int64, []*model.Row, error) {
+	var total int64
+	var records []*model.Row
+
+	db := e.query()
+
+	if input.ProjectID != nil {
+		db = db.Where("pa.p_id = ?", *input.ProjectID)
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Select(rowColumns).
+		Order("pa.level ASC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Row, error) {
+	var output model.Row
+	err := e.query().Select(rowColumns).Where("pa.pa_id = ?", input.ApprovalID).Take(&output).Error
+	return &output, err
+}
+
+// NextPending 取得專案在指定關卡之後最前面的待審核紀錄
+func (e *entity) NextPending(projectID string, afterLevel int) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("p_id = ? AND level > ? AND status = ?", projectID, afterLevel, model.StatusPending).
+		Order("level ASC").
+		First(&output).Error
+	return &output, err
+}
+
+// Queue 審核佇列:專案目前關卡的待審核紀錄;role 為空時不限角色(管理員)
+func (e *entity) Queue(input *model.Fields, role string) (int64, []*model.Row, error) {
+	var total int64
+	var records []*model.Row
+
+	db := e.query().
+		Where("pa.status = ?", model.StatusPending).
+		Where("p.approval_status = ? AND pa.level = p.approval_level", projectModel.ApprovalPending)
+
+	if role != "" {
+		db = db.Where("? = ANY(string_to_array(pa.approver_roles, ' '))", role)
+	}
+	if input.ProjectID != nil {
+		db = db.Where("pa.p_id = ?", *input.ProjectID)
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Select(rowColumns).
+		Order("pa.created_at ASC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+// Decide 更新審核結果,只會更新待審核的紀錄
+func (e *entity) Decide(input *model.Table) error {
+	result := e.db.Model(&model.Table{}).
+		Where("pa_id = ? AND status = ?", input.ApprovalID, model.StatusPending).
+		Updates(map[string]interface{}{
+			"status":     input.Status,
+			"decided_by": input.DecidedBy,
+			"decided_at": input.DecidedAt,
+			"reason":     input.Reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CancelPending 取消專案其餘待審核的關卡
+func (e *entity) CancelPending(projectID string) error {
+	return e.db.Model(&model.Table{}).
+		Where("p_id = ? AND status = ?", projectID, model.StatusPending).
+		Update("status", model.StatusCancelled).Error
+}
+
+// ListLevels 查詢專案所有關卡的紀錄(依關卡排序)
+func (e *entity) ListLevels(projectID string) ([]*model.Table, error) {
+	var records []*model.Table
+	err := e.db.Where("p_id = ?", projectID).Order("level ASC").Find(&records).Error
+	return records, err
+}
+
+// UpdateLevel 更新關卡的角色與狀態,重新開啟時一併清除審核結果
+func (e *entity) UpdateLevel(input *model.Table) error {
+	return e.db.Model(&model.Table{}).
+		Where("pa_id = ?", input.ApprovalID).
+		Updates(map[string]interface{}{
+			"approver_roles": input.ApproverRoles,
+			"status":         input.Status,
+			"decided_by":     input.DecidedBy,
+			"decided_at":     input.DecidedAt,
+			"reason":         input.Reason,
+		}).Error
+}
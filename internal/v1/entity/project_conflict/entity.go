@@ -0,0 +1,28 @@
+package project_conflict
+
+import (
+	model "esst_sendEmail/internal/v1/structure/project_conflicts"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	CreateBatch(input []*model.Table) error
+	List(input *model.Fields) (int64, []*model.Row, error)
+	GetByID(input *model.Field) (*model.Row, error)
+	Review(input *model.Table) error
+	ListCandidates(input *model.Candidates) ([]*model.Candidate, error)
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,129 @@
+package project_conflict
+
+import (
+	model "esst_sendEmail/internal/v1/structure/project_conflicts"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (e *entity) CreateBatch(input []*model.Table) error {
+	if len(input) == 0 {
+		return nil
+	}
+	return e.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&input).Error
+}
+
+// rowColumns 紀錄與雙方專案名稱、負責人
+const rowColumns = "pc.*, p.p_name, p.owner, c.p_name AS conflict_p_name, c.owner AS conflict_owner"
+
+func (e *entity) query() *gorm.DB {
+	return e.db.Table("project_conflicts AS pc").
+		Joins("JOIN projects AS p ON p.p_id = pc.p_id").
+		Joins("JOIN projects AS c ON c.p_id = pc.conflict_p_id")
+}
+
+func (e *entity) List(input *model.Fields) (int64, []*model.Row, error) {
+	var total int64
+	var records []*model.Row
+
+	db := e.query()
+
+	if input.ProjectID != nil {
+		db = db.Where("pc.p_id = ? OR pc.conflict_p_id = ?", *input.ProjectID, *input.ProjectID)
+	}
+	if input.Status != nil {
+		db = db.Where("pc.status = ?", *input.Status)
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Select(rowColumns).
+		Order("pc.created_at DESC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Row, error) {
+	var output model.Row
+	err := e.query().Select(rowColumns).Where("pc.pc_id = ?", input.ConflictID).Take(&output).Error
+	return &output, err
+}
+
+// Review 更新審核結果
+func (e *entity) Review(input *model.Table) error {
+	result := e.db.Model(&model.Table{}).
+		Where("pc_id = ?", input.ConflictID).
+		Updates(map[string]interface{}{
+			"status":      input.Status,
+			"reviewed_by": input.ReviewedBy,
+			"reviewed_at": input.ReviewedAt,
+			"review_note": input.ReviewNote,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// 與 similarity 套件相近的正規化規則,供資料庫端篩選候選專案
+const (
+	// candidateName 專案名稱轉小寫並移除公司型態字樣、空白與符號
+	candidateName = `regexp_replace(regexp_replace(lower(p.p_name), '股份有限公司|有限公司|企業社|公司', '', 'g'), '[[:space:][:punct:]]', '', 'g')`
+	// candidatePhone 聯絡電話只保留數字,+886 開頭轉為 0
+	candidatePhone = `regexp_replace(regexp_replace(p.contact_phone, '[^0-9]', '', 'g'), '^886([0-9]{7,})$', '0\1')`
+	// candidatePart 料號轉大寫並移除空白
+	candidatePart = `upper(regexp_replace(part_number, '\s', '', 'g'))`
+)
+
+// ListCandidates 查詢檢查期間內名稱相近、聯絡方式相同或有相同料號的專案,並計算相同的料號數
+// 名稱以三連字相似度預先篩選,精確的相似度與料號重疊比例由呼叫端判斷
+// 以巢狀交易(交易中為 SAVEPOINT)執行,查詢失敗時不會中止呼叫端的交易
+func (e *entity) ListCandidates(input *model.Candidates) ([]*model.Candidate, error) {
+	var records []*model.Candidate
+
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		matched := tx.Table("equipments").
+			Select("p_id, count(DISTINCT "+candidatePart+") AS matched_parts").
+			Where("deleted_at IS NULL AND "+candidatePart+" IN ?", input.PartNumbers).
+			Group("p_id")
+		if len(input.PartNumbers) == 0 {
+			matched = matched.Where("false")
+		}
+
+		db := tx.Table("projects AS p").
+			Joins("LEFT JOIN (?) AS m ON m.p_id = p.p_id", matched).
+			Where("p.deleted_at IS NULL AND p.created_time >= ? AND p.status <> ?", input.Since, projectModel.StatusExpired)
+		if input.ExcludeProjectID != "" {
+			db = db.Where("p.p_id <> ?", input.ExcludeProjectID)
+		}
+
+		filters := tx.Where("m.matched_parts > 0")
+		if input.Name != "" {
+			filters = filters.Or("similarity("+candidateName+", @name) >= @min OR word_similarity(@name, "+candidateName+") >= @min OR word_similarity("+candidateName+", @name) >= @min",
+				map[string]interface{}{"name": input.Name, "min": input.NamePrefilter})
+		}
+		if input.Email != "" {
+			filters = filters.Or("lower(trim(p.contact_email)) = ?", input.Email)
+		}
+		if input.Phone != "" {
+			filters = filters.Or(candidatePhone+" = ?", input.Phone)
+		}
+
+		return db.Where(filters).
+			Select("p.p_id, p.p_name, p.contact_name, p.contact_phone, p.contact_email, p.owner, p.created_time, COALESCE(m.matched_parts, 0) AS matched_parts").
+			Order("p.created_time DESC").
+			Find(&records).Error
+	})
+	return records, err
+}
@@ -0,0 +1,28 @@
+package project_extension
+
+import (
+	model "esst_sendEmail/internal/v1/structure/project_extensions"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Create(input *model.Table) error
+	List(input *model.Fields) (int64, []*model.Table, error)
+	GetByID(input *model.Field) (*model.Table, error)
+	HasPending(projectID string) (bool, error)
+	Review(input *model.Table) error
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
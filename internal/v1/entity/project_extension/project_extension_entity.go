@@ -0,0 +1,73 @@
+package project_extension
+
+import (
+	model "esst_sendEmail/internal/v1/structure/project_extensions"
+
+	"gorm.io/gorm"
+)
+
+func (e *entity) Create(input *model.Table) error {
+	return e.db.Create(input).Error
+}
+
+func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Model(&model.Table{})
+
+	if input.ProjectID != nil {
+		db = db.Where("p_id = ?", *input.ProjectID)
+	}
+	if input.Status != nil {
+		db = db.Where("status = ?", *input.Status)
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("created_at DESC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("pe_id = ?", input.ExtensionID).First(&output).Error
+	return &output, err
+}
+
+// HasPending 專案是否已有待審核的申請
+func (e *entity) HasPending(projectID string) (bool, error) {
+	var count int64
+	err := e.db.Model(&model.Table{}).
+		Where("p_id = ? AND status = ?", projectID, model.StatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Review 更新審核結果,只會更新待審核的申請
+func (e *entity) Review(input *model.Table) error {
+	result := e.db.Model(&model.Table{}).
+		Where("pe_id = ? AND status = ?", input.ExtensionID, model.StatusPending).
+		Updates(map[string]interface{}{
+			"status":              input.Status,
+			"reviewed_by":         input.ReviewedBy,
+			"reviewed_at":         input.ReviewedAt,
+			"review_note":         input.ReviewNote,
+			"previous_expires_at": input.PreviousExpiresAt,
+			"new_expires_at":      input.NewExpiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package quotation
+
+import (
+	model "esst_sendEmail/internal/v1/structure/quotations"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Create(input *model.Table, lines []*model.LineTable) error
+	List(input *model.Fields) (int64, []*model.Table, error)
+	GetByID(input *model.Field) (*model.Table, error)
+	GetAccepted(projectID string) (*model.Table, error)
+	ListLines(quotationID string) ([]*model.LineTable, error)
+	NextVersion(projectID string) (int64, error)
+	Transition(quotationID string, from []string, values map[string]interface{}) (bool, error)
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,105 @@
+package quotation
+
+import (
+	"errors"
+
+	model "esst_sendEmail/internal/v1/structure/quotations"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// uniqueViolation PostgreSQL 唯一索引衝突的錯誤代碼
+const uniqueViolation = "23505"
+
+// Create 建立報價單與明細,需在交易中呼叫
+func (e *entity) Create(input *model.Table, lines []*model.LineTable) error {
+	err := e.db.Create(input).Error
+	if err != nil {
+		return err
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	return e.db.Create(&lines).Error
+}
+
+func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Model(&model.Table{}).Where("p_id = ?", input.ProjectID)
+
+	if input.Status != nil {
+		db = db.Where("status = ?", *input.Status)
+	}
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("version DESC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Table, error) {
+	var output model.Table
+	db := e.db.Where("q_id = ?", input.QuotationID)
+	if input.ProjectID != "" {
+		db = db.Where("p_id = ?", input.ProjectID)
+	}
+	err := db.First(&output).Error
+	return &output, err
+}
+
+// GetAccepted 取得專案已接受的報價單
+func (e *entity) GetAccepted(projectID string) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("p_id = ? AND status = ?", projectID, model.StatusAccepted).First(&output).Error
+	return &output, err
+}
+
+func (e *entity) ListLines(quotationID string) ([]*model.LineTable, error) {
+	var records []*model.LineTable
+	err := e.db.Where("q_id = ?", quotationID).Order("position ASC").Find(&records).Error
+	return records, err
+}
+
+// NextVersion 鎖定專案後取得下一個報價版本,避免同時建立時版本重複
+func (e *entity) NextVersion(projectID string) (int64, error) {
+	var ids []string
+	err := e.db.Table("projects").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("p_id = ?", projectID).Pluck("p_id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var version int64
+	err = e.db.Model(&model.Table{}).
+		Select("COALESCE(MAX(version), 0) + 1").
+		Where("p_id = ?", projectID).
+		Scan(&version).Error
+	return version, err
+}
+
+// Transition 只在目前狀態符合 from 時更新,回傳是否有更新
+// 違反唯一索引(例如同一專案同時接受兩張報價單)時回傳 gorm.ErrDuplicatedKey
+func (e *entity) Transition(quotationID string, from []string, values map[string]interface{}) (bool, error) {
+	result := e.db.Model(&model.Table{}).
+		Where("q_id = ? AND status IN ?", quotationID, from).
+		Updates(values)
+
+	var pgErr *pgconn.PgError
+	if errors.As(result.Error, &pgErr) && pgErr.Code == uniqueViolation {
+		return false, gorm.ErrDuplicatedKey
+	}
+	return result.RowsAffected > 0, result.Error
+}
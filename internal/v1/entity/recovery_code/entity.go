@@ -0,0 +1,32 @@
+package recovery_code
+
+import (
+	"errors"
+
+	model "esst_sendEmail/internal/v1/structure/recovery_codes"
+
+	"gorm.io/gorm"
+)
+
+// ErrAlreadyUsed 復原碼已被使用
+var ErrAlreadyUsed = errors.New("recovery code already used")
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	CreateBatch(input []*model.Table) error
+	GetUnused(userID, codeHash string) (*model.Table, error)
+	MarkUsed(input *model.Table) error
+	DeleteByUserID(userID string) error
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,39 @@
+package recovery_code
+
+import (
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/recovery_codes"
+)
+
+func (e *entity) CreateBatch(input []*model.Table) error {
+	if len(input) == 0 {
+		return nil
+	}
+	return e.db.Create(&input).Error
+}
+
+// GetUnused 取得尚未使用的復原碼
+func (e *entity) GetUnused(userID, codeHash string) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).First(&output).Error
+	return &output, err
+}
+
+// MarkUsed 標記復原碼已使用(條件包含 used_at IS NULL,避免同一組復原碼被重複使用)
+func (e *entity) MarkUsed(input *model.Table) error {
+	result := e.db.Model(&model.Table{}).
+		Where("rc_id = ? AND used_at IS NULL", input.RecoveryCodeID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAlreadyUsed
+	}
+	return nil
+}
+
+func (e *entity) DeleteByUserID(userID string) error {
+	return e.db.Where("user_id = ?", userID).Delete(&model.Table{}).Error
+}
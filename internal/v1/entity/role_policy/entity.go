@@ -0,0 +1,26 @@
+package role_policy
+
+import (
+	model "esst_sendEmail/internal/v1/structure/role_policies"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	List() ([]*model.Table, error)
+	GetByRole(input *model.Field) (*model.Table, error)
+	Upsert(input *model.Table) error
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
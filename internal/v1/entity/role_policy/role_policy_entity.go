@@ -0,0 +1,27 @@
+package role_policy
+
+import (
+	model "esst_sendEmail/internal/v1/structure/role_policies"
+
+	"gorm.io/gorm/clause"
+)
+
+func (e *entity) List() ([]*model.Table, error) {
+	var records []*model.Table
+	err := e.db.Order("role ASC").Find(&records).Error
+	return records, err
+}
+
+func (e *entity) GetByRole(input *model.Field) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("role = ?", input.Role).First(&output).Error
+	return &output, err
+}
+
+// Upsert 新增或更新角色政策
+func (e *entity) Upsert(input *model.Table) error {
+	return e.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "role"}},
+		DoUpdates: clause.AssignmentColumns([]string{"require_two_factor", "updated_at"}),
+	}).Create(input).Error
+}
@@ -1,6 +1,8 @@
 package stock
 
 import (
+	"time"
+
 	model "esst_sendEmail/internal/v1/structure/stocks"
 
 	"gorm.io/gorm"
@@ -12,7 +14,11 @@ type Entity interface {
 	List(input *model.Fields) (int64, []*model.Table, error)
 	GetByID(input *model.Field) (*model.Table, error)
 	Update(input *model.Table) (err error)
-	Delete(input *model.Field) (err error)
+	Delete(input *model.Field, version int64, deletedBy string, deletedAt time.Time) (err error)
+	ListDeleted(input *model.Fields) (int64, []*model.Table, error)
+	GetDeletedByID(input *model.Field) (*model.Table, error)
+	Restore(input *model.Field) (err error)
+	Purge(before time.Time) (int64, error)
 }
 
 type entity struct {
@@ -1,7 +1,12 @@
 package stock
 
 import (
+	"time"
+
+	"esst_sendEmail/internal/v1/structure"
 	model "esst_sendEmail/internal/v1/structure/stocks"
+
+	"gorm.io/gorm"
 )
 
 func (e *entity) Create(input *model.Table) error {
@@ -42,11 +47,77 @@ func (e *entity) GetByID(input *model.Field) (output *model.Table, err error) {
 	return output, err
 }
 
+// Update 以版本號更新,版本不符(已被其他人修改)時回傳 ErrVersionConflict
 func (e *entity) Update(input *model.Table) (err error) {
-	err = e.db.Model(&model.Table{}).Where("stock_id = ?", input.StockID).Updates(input).Error
-	return err
+	expected := input.Version
+	input.Version = expected + 1
+
+	// 使用 Updates 只更新非零值欄位
+	result := e.db.Model(&model.Table{}).Where("stock_id = ? AND version = ?", input.StockID, expected).Updates(input)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return structure.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// Delete 以版本號軟刪除,保留於資源回收筒,版本不符時回傳 ErrVersionConflict
+func (e *entity) Delete(input *model.Field, version int64, deletedBy string, deletedAt time.Time) error {
+	result := e.db.Model(&model.Table{}).Where("stock_id = ? AND version = ?", input.StockID, version).Updates(map[string]interface{}{
+		"deleted_at": deletedAt,
+		"deleted_by": deletedBy,
+		"version":    gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return structure.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// ListDeleted 資源回收筒列表,依刪除時間降序排列
+func (e *entity) ListDeleted(input *model.Fields) (int64, []*model.Table, error) {
+	var total int64
+	var records []*model.Table
+
+	db := e.db.Unscoped().Model(&model.Table{}).Where("deleted_at IS NOT NULL")
+
+	err := db.Count(&total).Error
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = db.Order("deleted_at DESC").
+		Offset(int((input.Page - 1) * input.Limit)).
+		Limit(int(input.Limit)).
+		Find(&records).Error
+
+	return total, records, err
+}
+
+func (e *entity) GetDeletedByID(input *model.Field) (output *model.Table, err error) {
+	db := e.db.Unscoped().Model(&model.Table{}).Where("stock_id = ? AND deleted_at IS NOT NULL", input.StockID)
+	err = db.First(&output).Error
+	return output, err
+}
+
+// Restore 從資源回收筒還原
+func (e *entity) Restore(input *model.Field) error {
+	return e.db.Unscoped().Model(&model.Table{}).Where("stock_id = ?", input.StockID).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": nil,
+		"version":    gorm.Expr("version + 1"),
+	}).Error
 }
 
-func (e *entity) Delete(input *model.Field) error {
-	return e.db.Where("stock_id = ?", input.StockID).Delete(&model.Table{}).Error
+// Purge 永久刪除在 before 之前刪除的資料
+func (e *entity) Purge(before time.Time) (int64, error) {
+	result := e.db.Unscoped().Where("deleted_at < ?", before).Delete(&model.Table{})
+	return result.RowsAffected, result.Error
 }
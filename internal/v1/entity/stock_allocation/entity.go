@@ -0,0 +1,36 @@
+package stock_allocation
+
+import (
+	model "esst_sendEmail/internal/v1/structure/stock_allocations"
+
+	"gorm.io/gorm"
+)
+
+type Entity interface {
+	WithTrx(tx *gorm.DB) Entity
+	Upsert(input *model.Table) error
+	ListByProjectID(projectID string) ([]*model.Row, error)
+	GetByID(input *model.Field) (*model.Table, error)
+	Delete(input *model.Field) error
+	DeleteByEquipmentID(equipmentID string) error
+	DeleteByStockEquipmentID(stockEquipmentID string) error
+	DeleteByProjectID(projectID string) error
+	DeleteByStockID(stockID string) error
+	LockLines(equipmentID, stockEquipmentID string) error
+	LockEquipment(equipmentID string) error
+	LockStockEquipment(stockEquipmentID string) error
+	SumByEquipment(equipmentID string) (int64, error)
+	SumByStockEquipment(stockEquipmentID string) (int64, error)
+}
+
+type entity struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Entity {
+	return &entity{db: db}
+}
+
+func (e *entity) WithTrx(tx *gorm.DB) Entity {
+	return &entity{db: tx}
+}
@@ -0,0 +1,124 @@
+package stock_allocation
+
+import (
+	model "esst_sendEmail/internal/v1/structure/stock_allocations"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// active 只計入專案設備與現貨設備都未刪除的分配,設備刪除時其分配會一併移除,此處僅為保險
+func (e *entity) active() *gorm.DB {
+	return e.db.Table("stock_allocations AS sa").
+		Joins("JOIN equipments AS eq ON eq.eq_id = sa.eq_id AND eq.deleted_at IS NULL").
+		Joins("JOIN stock_equipments AS se ON se.seq_id = sa.seq_id AND se.deleted_at IS NULL")
+}
+
+// Upsert 建立分配,同一組專案設備與現貨設備已有分配時累加數量,並回寫累加後的紀錄
+func (e *entity) Upsert(input *model.Table) error {
+	return e.db.Clauses(clause.Returning{}, clause.OnConflict{
+		Columns: []clause.Column{{Name: "eq_id"}, {Name: "seq_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"quantity":   gorm.Expr("stock_allocations.quantity + excluded.quantity"),
+			"updated_at": gorm.Expr("excluded.created_at"),
+		}),
+	}).Create(input).Error
+}
+
+// ListByProjectID 查詢專案的分配紀錄(只含未刪除的專案設備與現貨設備)
+func (e *entity) ListByProjectID(projectID string) ([]*model.Row, error) {
+	var records []*model.Row
+	err := e.active().
+		Select("sa.*, se.stock_id, s.stock_name, se.part_number").
+		Joins("JOIN stocks AS s ON s.stock_id = se.stock_id").
+		Where("sa.p_id = ?", projectID).
+		Order("sa.created_at ASC").
+		Find(&records).Error
+	return records, err
+}
+
+func (e *entity) GetByID(input *model.Field) (*model.Table, error) {
+	var output model.Table
+	db := e.db.Where("sa_id = ?", input.AllocationID)
+	if input.ProjectID != "" {
+		db = db.Where("p_id = ?", input.ProjectID)
+	}
+	err := db.First(&output).Error
+	return &output, err
+}
+
+func (e *entity) Delete(input *model.Field) error {
+	result := e.db.Where("sa_id = ? AND p_id = ?", input.AllocationID, input.ProjectID).Delete(&model.Table{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteByEquipmentID 移除專案設備的全部分配,需與設備刪除在同一交易中執行
+func (e *entity) DeleteByEquipmentID(equipmentID string) error {
+	return e.db.Where("eq_id = ?", equipmentID).Delete(&model.Table{}).Error
+}
+
+// DeleteByStockEquipmentID 移除現貨設備的全部分配,需與設備刪除在同一交易中執行
+func (e *entity) DeleteByStockEquipmentID(stockEquipmentID string) error {
+	return e.db.Where("seq_id = ?", stockEquipmentID).Delete(&model.Table{}).Error
+}
+
+// DeleteByProjectID 移除專案的全部分配,需與專案刪除在同一交易中執行
+func (e *entity) DeleteByProjectID(projectID string) error {
+	return e.db.Where("p_id = ?", projectID).Delete(&model.Table{}).Error
+}
+
+// DeleteByStockID 移除現貨所屬設備的全部分配,需與現貨刪除在同一交易中執行
+func (e *entity) DeleteByStockID(stockID string) error {
+	return e.db.Where("seq_id IN (?)", e.db.Table("stock_equipments").Select("seq_id").Where("stock_id = ?", stockID)).
+		Delete(&model.Table{}).Error
+}
+
+// LockLines 鎖定專案設備與現貨設備,避免同時分配造成超額
+func (e *entity) LockLines(equipmentID, stockEquipmentID string) error {
+	err := e.LockEquipment(equipmentID)
+	if err != nil {
+		return err
+	}
+
+	return e.LockStockEquipment(stockEquipmentID)
+}
+
+// LockEquipment 鎖定專案設備,分配與調整數量不可同時進行
+func (e *entity) LockEquipment(equipmentID string) error {
+	var ids []string
+	return e.db.Table("equipments").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("eq_id = ?", equipmentID).Pluck("eq_id", &ids).Error
+}
+
+// LockStockEquipment 鎖定現貨設備,分配與調整數量不可同時進行
+func (e *entity) LockStockEquipment(stockEquipmentID string) error {
+	var ids []string
+	return e.db.Table("stock_equipments").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("seq_id = ?", stockEquipmentID).Pluck("seq_id", &ids).Error
+}
+
+// SumByEquipment 專案設備已分配的數量
+func (e *entity) SumByEquipment(equipmentID string) (int64, error) {
+	var total int64
+	err := e.active().
+		Select("COALESCE(SUM(sa.quantity), 0)").
+		Where("sa.eq_id = ?", equipmentID).
+		Scan(&total).Error
+	return total, err
+}
+
+// SumByStockEquipment 現貨設備已分配出去的數量
+func (e *entity) SumByStockEquipment(stockEquipmentID string) (int64, error) {
+	var total int64
+	err := e.active().
+		Select("COALESCE(SUM(sa.quantity), 0)").
+		Where("sa.seq_id = ?", stockEquipmentID).
+		Scan(&total).Error
+	return total, err
+}
@@ -1,6 +1,8 @@
 package stock_equipment
 
 import (
+	"time"
+
 	model "esst_sendEmail/internal/v1/structure/stock_equipments"
 
 	"gorm.io/gorm"
@@ -14,8 +16,13 @@ type Entity interface {
 	ListByStockID(stockID string) ([]*model.Table, error)
 	GetByID(input *model.Field) (*model.Table, error)
 	Update(input *model.Table) (err error)
-	Delete(input *model.Field) (err error)
-	DeleteByStockID(stockID string) (err error)
+	Delete(input *model.Field, version int64, deletedBy string, deletedAt time.Time) (err error)
+	DeleteByStockID(stockID, deletedBy string, deletedAt time.Time) (err error)
+	ListDeleted(input *model.Fields) (int64, []*model.Table, error)
+	GetDeletedByID(input *model.Field) (*model.Table, error)
+	Restore(input *model.Field) (err error)
+	RestoreByStockID(stockID string, deletedAt time.Time) (err error)
+	Purge(before time.Time) (int64, error)
 }
 
 type entity struct {
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"time"
+
 	model "esst_sendEmail/internal/v1/structure/users"
 	"gorm.io/gorm"
 )
@@ -10,9 +12,16 @@ type Entity interface {
 	Create(input *model.Table) error
 	GetByUsername(input *model.Field) (*model.Table, error)
 	GetByID(input *model.Field) (*model.Table, error)
+	GetByEmail(input *model.Field) (*model.Table, error)
+	GetByOIDCSubject(subject string) (*model.Table, error)
 	List(input *model.Fields) (int64, []*model.Table, error)
 	Update(input *model.Table) error
+	UpdateColumns(id string, columns map[string]interface{}) error
+	ClaimTOTPStep(id string, step int64) (bool, error)
+	IncrementFailedLogin(id string, max int, now, until time.Time) (*model.FailedLogin, error)
 	Delete(input *model.Field) error
+	ListByRoles(roles []string) ([]*model.Table, error)
+	ListByUsernames(usernames []string) ([]*model.Table, error)
 }
 
 type entity struct {
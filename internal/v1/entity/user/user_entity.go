@@ -2,6 +2,8 @@ package user
 
 import (
 	"errors"
+	"time"
+
 	model "esst_sendEmail/internal/v1/structure/users"
 )
 
@@ -21,6 +23,18 @@ func (e *entity) GetByID(input *model.Field) (*model.Table, error) {
 	return &output, err
 }
 
+func (e *entity) GetByEmail(input *model.Field) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("LOWER(email) = LOWER(?)", *input.Email).Order("created_at ASC").First(&output).Error
+	return &output, err
+}
+
+func (e *entity) GetByOIDCSubject(subject string) (*model.Table, error) {
+	var output model.Table
+	err := e.db.Where("oidc_subject = ?", subject).First(&output).Error
+	return &output, err
+}
+
 func (e *entity) List(input *model.Fields) (int64, []*model.Table, error) {
 	var total int64
 	var records []*model.Table
@@ -52,6 +66,40 @@ func (e *entity) Update(input *model.Table) error {
 	return e.db.Model(&model.Table{}).Where("id = ?", input.ID).Updates(input).Error
 }
 
+// UpdateColumns 更新指定欄位(可寫入零值或 NULL)
+func (e *entity) UpdateColumns(id string, columns map[string]interface{}) error {
+	return e.db.Model(&model.Table{}).Where("id = ?", id).Updates(columns).Error
+}
+
+// ClaimTOTPStep 以條件更新記錄已使用的 TOTP 時間區間,同一區間只有一個請求能成功(回傳 false 表示重複使用)
+func (e *entity) ClaimTOTPStep(id string, step int64) (bool, error) {
+	result := e.db.Model(&model.Table{}).Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// IncrementFailedLogin 以單一 UPDATE 累計登入失敗次數,避免並行的失敗請求互相覆蓋
+// 前一次鎖定已過期時從 1 重新計算;達 max 次時鎖定至 until,仍在鎖定中則維持原鎖定時間
+func (e *entity) IncrementFailedLogin(id string, max int, now, until time.Time) (*model.FailedLogin, error) {
+	var output model.FailedLogin
+
+	err := e.db.Raw(`UPDATE users SET
+			failed_login_count = CASE WHEN locked_until IS NOT NULL AND locked_until <= @now THEN 1 ELSE failed_login_count + 1 END,
+			locked_until = CASE
+				WHEN locked_until > @now THEN locked_until
+				WHEN (CASE WHEN locked_until IS NOT NULL THEN 1 ELSE failed_login_count + 1 END) >= @max THEN @until
+				ELSE NULL END
+		WHERE id = @id
+		RETURNING failed_login_count, locked_until`,
+		map[string]interface{}{"id": id, "max": max, "now": now, "until": until}).Scan(&output).Error
+
+	return &output, err
+}
+
 func (e *entity) Delete(input *model.Field) error {
 	//後端保護:先查詢使用者資料
 	var user model.Table
@@ -68,3 +116,23 @@ func (e *entity) Delete(input *model.Field) error {
 	// 執行刪除
 	return e.db.Where("id = ?", *input.ID).Delete(&model.Table{}).Error
 }
+
+// ListByRoles 查詢指定角色的使用者(審核通知用)
+func (e *entity) ListByRoles(roles []string) ([]*model.Table, error) {
+	var users []*model.Table
+	if len(roles) == 0 {
+		return users, nil
+	}
+	err := e.db.Where("role IN ?", roles).Find(&users).Error
+	return users, err
+}
+
+// ListByUsernames 查詢指定帳號的使用者(留言提及通知用)
+func (e *entity) ListByUsernames(usernames []string) ([]*model.Table, error) {
+	var users []*model.Table
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	err := e.db.Where("username IN ?", usernames).Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,22 @@
+package job
+
+import (
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/v1/service/idempotency_key"
+
+	"gorm.io/gorm"
+)
+
+// purgeIdempotencyKeys 刪除已過期的 Idempotency-Key 紀錄
+func purgeIdempotencyKeys(db *gorm.DB) {
+	count, err := idempotency_key.New(db).Purge(time.Now())
+	if err != nil {
+		log.Error("Failed to purge idempotency keys:", err)
+		return
+	}
+	if count > 0 {
+		log.Info("Purged", count, "expired idempotency keys")
+	}
+}
@@ -0,0 +1,47 @@
+package job
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+
+	"gorm.io/gorm"
+)
+
+// Start 啟動背景排程
+func Start(db *gorm.DB) {
+	go schedule("trash purge", time.Hour, func() { purgeTrash(db) })
+	go schedule("idempotency key purge", time.Hour, func() { purgeIdempotencyKeys(db) })
+	go schedule("project expiry", time.Hour, func() { checkProjectExpiry(db) })
+}
+
+// schedule 啟動時先執行一次,之後每隔 interval 執行;單次執行 panic 不影響後續排程
+func schedule(name string, interval time.Duration, fn func()) {
+	run := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("Job", name, "panicked:", r)
+			}
+		}()
+		fn()
+	}
+
+	run()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		run()
+	}
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,87 @@
+package job
+
+import (
+	"time"
+
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/mail"
+	"esst_sendEmail/internal/v1/service/project"
+	"esst_sendEmail/internal/v1/service/user"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+	userModel "esst_sendEmail/internal/v1/structure/users"
+
+	"gorm.io/gorm"
+)
+
+// checkProjectExpiry 專案保護期排程
+// 到期前 PROJECT_EXPIRY_WARNING_DAYS(預設 7 天,0 表示不提醒)通知建立者與 LINE 群組;保護期已過的第一階段專案轉為 expired
+func checkProjectExpiry(db *gorm.DB) {
+	projectService := project.New(db)
+
+	if days := envInt("PROJECT_EXPIRY_WARNING_DAYS", 7); days > 0 {
+		projects, err := projectService.ListExpiring(time.Duration(days) * 24 * time.Hour)
+		if err != nil {
+			log.Error("Failed to query expiring projects:", err)
+		}
+
+		for _, p := range projects {
+			warnProjectExpiry(db, p)
+			// 不論通知是否成功都記錄,避免每次排程重複發送
+			_ = projectService.MarkExpiryWarned(p.ProjectID)
+		}
+	}
+
+	expired, err := projectService.ExpireOverdue()
+	if err != nil {
+		log.Error("Failed to expire overdue projects:", err)
+		return
+	}
+
+	for _, p := range expired {
+		log.Info("Project protection period expired:", p.ProjectID)
+		notifyProjectExpiry(p, true)
+	}
+}
+
+// warnProjectExpiry 到期前提醒:LINE 群組與建立者 email
+func warnProjectExpiry(db *gorm.DB, p *projectModel.Base) {
+	notifyProjectExpiry(p, false)
+
+	if p.CreatedBy == nil || p.ExpiresAt == nil {
+		return
+	}
+
+	creator, err := user.New(db).GetByID(&userModel.Field{ID: p.CreatedBy})
+	if err != nil || creator.Email == "" {
+		return
+	}
+
+	err = mail.New().SendProjectExpiryWarningEmail(creator.Email, creator.Username, &mail.ProjectExpiryData{
+		ProjectID:   p.ProjectID,
+		ProjectName: p.ProjectName,
+		Owner:       p.Owner,
+		ExpiresAt:   *p.ExpiresAt,
+	})
+	if err != nil {
+		log.Error("Failed to send project expiry warning email:", err)
+	}
+}
+
+func notifyProjectExpiry(p *projectModel.Base, expired bool) {
+	if p.ExpiresAt == nil {
+		return
+	}
+
+	err := linebot.New().SendProjectExpiryNotification(&linebot.ProjectExpiryData{
+		ProjectID:   p.ProjectID,
+		ProjectName: p.ProjectName,
+		ContactName: p.ContactName,
+		Owner:       p.Owner,
+		ExpiresAt:   *p.ExpiresAt,
+		Expired:     expired,
+	})
+	if err != nil {
+		log.Error("Failed to send project expiry LINE notification:", err)
+	}
+}
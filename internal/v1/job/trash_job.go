@@ -0,0 +1,48 @@
+package job
+
+import (
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/v1/service/attachment"
+	"esst_sendEmail/internal/v1/service/comment"
+	"esst_sendEmail/internal/v1/service/equipment"
+	"esst_sendEmail/internal/v1/service/project"
+	"esst_sendEmail/internal/v1/service/stock"
+	"esst_sendEmail/internal/v1/service/stock_equipment"
+
+	"gorm.io/gorm"
+)
+
+// purgeTrash 永久刪除資源回收筒中超過 TRASH_RETENTION_DAYS(預設 30 天)的資料,設為 0 則不清除
+func purgeTrash(db *gorm.DB) {
+	days := envInt("TRASH_RETENTION_DAYS", 30)
+	if days <= 0 {
+		return
+	}
+	before := time.Now().AddDate(0, 0, -days)
+
+	// 先清除對象已過期的附件、檔案與留言,再清除個別刪除的設備,最後清除主資料(其設備由外鍵一併刪除)
+	purges := []struct {
+		name  string
+		purge func(before time.Time) (int64, error)
+	}{
+		{"attachments", attachment.New(db).Purge},
+		{"comments", comment.New(db).Purge},
+		{"equipments", equipment.New(db).Purge},
+		{"projects", project.New(db).Purge},
+		{"stock_equipments", stock_equipment.New(db).Purge},
+		{"stocks", stock.New(db).Purge},
+	}
+
+	for _, p := range purges {
+		count, err := p.purge(before)
+		if err != nil {
+			log.Error("Failed to purge", p.name, "trash:", err)
+			continue
+		}
+		if count > 0 {
+			log.Info("Purged", count, p.name, "deleted before", before.Format("2006-01-02 15:04:05"))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/v1/service/api_key"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// APIKeyMiddleware 接受 Authorization: ApiKey <key>,需放在 JWTMiddleware 之前
+// resource 為路由群組名稱,GET 需要 <resource>:read,其他方法需要 <resource>:write
+// 沒有帶 API 金鑰的請求交由 JWTMiddleware 處理
+func APIKeyMiddleware(db *gorm.DB, resource string) gin.HandlerFunc {
+	apiKeyService := api_key.New(db)
+
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if !(len(parts) == 2 && parts[0] == "ApiKey") {
+			c.Next()
+			return
+		}
+
+		principal, err := apiKeyService.Authenticate(parts[1], c.ClientIP())
+		if err != nil {
+			if !errors.Is(err, api_key.ErrInvalidKey) {
+				log.Error(err)
+			}
+			c.JSON(http.StatusUnauthorized, code.GetCodeMessage(code.JWTRejected, "Invalid, expired or revoked API key"))
+			c.Abort()
+			return
+		}
+
+		scope := resource + ":write"
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = resource + ":read"
+		}
+		if !principal.HasScope(scope) {
+			c.JSON(http.StatusForbidden, code.GetCodeMessage(code.PermissionDenied, "API key scope required: "+scope))
+			c.Abort()
+			return
+		}
+
+		// 與 JWTMiddleware 設定相同的使用者資訊
+		c.Set("userID", principal.UserID)
+		c.Set("username", principal.Username)
+		c.Set("role", principal.Role)
+		c.Set("mustChangePassword", false)
+		c.Set("apiKeyID", principal.APIKeyID)
+
+		c.Next()
+	}
+}
@@ -19,8 +19,8 @@ func CORSMiddleware() gin.HandlerFunc {
 			"http://127.0.0.1:5500",
 			"http://127.0.0.1:8080"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "If-Match", "Idempotency-Key"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Type", "ETag", "RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After", "Idempotent-Replayed"},
 		AllowCredentials: true, // Critical for cookies/sessions
 		MaxAge:           12 * time.Hour,
 	})
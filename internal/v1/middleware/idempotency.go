@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/v1/service/idempotency_key"
+	model "esst_sendEmail/internal/v1/structure/idempotency_keys"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxIdempotencyKeyLength Idempotency-Key 長度上限
+const maxIdempotencyKeyLength = 255
+
+// idempotencyRecorder 複製寫出的回應內容,供保存後重送
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware 支援 Idempotency-Key 標頭,需放在 JWTMiddleware 之後、Transaction 之前
+// 相同使用者以相同金鑰重送相同內容時,直接回傳第一次的回應(標頭 Idempotent-Replayed: true),不再重複建立資料與發送通知
+// 相同金鑰但內容不同回傳 422;第一次請求仍在處理中回傳 409;未帶標頭的請求照常處理
+func IdempotencyMiddleware(db *gorm.DB) gin.HandlerFunc {
+	idempotencyService := idempotency_key.New(db)
+
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, "Idempotency-Key must not exceed 255 characters"))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.Sum256(body)
+		id, replay, err := idempotencyService.Begin(&model.Begun{
+			UserID:      c.GetString("userID"),
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: hex.EncodeToString(hash[:]),
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, idempotency_key.ErrKeyMismatch):
+				c.JSON(http.StatusUnprocessableEntity, code.GetCodeMessage(code.UnprocessableEntity, err.Error()))
+			case errors.Is(err, idempotency_key.ErrInProgress):
+				c.Header("Retry-After", "1")
+				c.JSON(http.StatusConflict, code.GetCodeMessage(code.Conflict, err.Error()))
+			default:
+				c.JSON(http.StatusInternalServerError, code.GetCodeMessage(code.InternalServerError, err.Error()))
+			}
+			c.Abort()
+			return
+		}
+
+		if replay != nil {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(replay.StatusCode, "application/json; charset=utf-8", replay.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// 伺服器錯誤不保存,讓用戶端以相同金鑰重試
+		if recorder.Status() >= http.StatusInternalServerError || responseCode(recorder.body.Bytes()) >= code.InternalServerError {
+			_ = idempotencyService.Release(id)
+			return
+		}
+
+		_ = idempotencyService.Complete(&model.Completed{
+			IdempotencyKeyID: id,
+			StatusCode:       recorder.Status(),
+			ResponseBody:     recorder.body.Bytes(),
+		})
+	}
+}
+
+// responseCode 取出回應內容中的 code 欄位
+func responseCode(body []byte) int {
+	var output struct {
+		Code int `json:"code"`
+	}
+	_ = json.Unmarshal(body, &output)
+	return output.Code
+}
@@ -12,6 +12,12 @@ import (
 // JWTMiddleware JWT 驗證中間件
 func JWTMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// 已由 APIKeyMiddleware 驗證
+		if c.GetString("apiKeyID") != "" {
+			c.Next()
+			return
+		}
+
 		// 從 Authorization header 獲取 token
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -42,15 +48,29 @@ func JWTMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 需先變更密碼的帳號只能存取變更密碼相關路由
+		if claims.MustChangePassword && !passwordChangeAllowed[c.FullPath()] {
+			c.JSON(http.StatusForbidden, code.GetCodeMessage(code.PermissionDenied, "Password change required"))
+			c.Abort()
+			return
+		}
+
 		// 將用戶資訊存儲在 context 中
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("mustChangePassword", claims.MustChangePassword)
 
 		c.Next()
 	}
 }
 
+// passwordChangeAllowed 需先變更密碼時仍可存取的路由
+var passwordChangeAllowed = map[string]bool{
+	"/auth/me":              true,
+	"/auth/password/change": true,
+}
+
 // AdminMiddleware 管理員權限驗證中間件
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"esst_sendEmail/internal/pkg/ratelimit"
+)
+
+func TestPolicyLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  ratelimit.Limit
+	}{
+		{"default", "", ratelimit.Limit{Requests: 5, Period: 15 * time.Minute}},
+		{"override", "20/1h", ratelimit.Limit{Requests: 20, Period: time.Hour}},
+		{"invalid format falls back", "abc", ratelimit.Limit{Requests: 5, Period: 15 * time.Minute}},
+		{"invalid period falls back", "20/soon", ratelimit.Limit{Requests: 5, Period: 15 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RATE_LIMIT_EMAIL", tt.value)
+
+			if got := policyLimit(EmailRateLimit); got != tt.want {
+				t.Errorf("policyLimit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package api_key
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/resolver/api_key"
+	"esst_sendEmail/internal/v1/structure/api_keys"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Create 建立 API 金鑰,完整金鑰僅顯示一次
+func (p *presenter) Create(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &api_keys.Created{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	codeMessage := p.APIKeyResolver.Create(trx, requester(ctx), input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// GetByID 取得單一 API 金鑰
+func (p *presenter) GetByID(ctx *gin.Context) {
+	input := &api_keys.Field{}
+	input.APIKeyID = ctx.Param("apiKeyId")
+
+	codeMessage := p.APIKeyResolver.GetByID(requester(ctx), input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// List 取得 API 金鑰列表
+func (p *presenter) List(ctx *gin.Context) {
+	input := &api_keys.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	codeMessage := p.APIKeyResolver.List(requester(ctx), input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Revoke 撤銷 API 金鑰
+func (p *presenter) Revoke(ctx *gin.Context) {
+	input := &api_keys.Field{}
+	input.APIKeyID = ctx.Param("apiKeyId")
+
+	codeMessage := p.APIKeyResolver.Revoke(requester(ctx), input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+func requester(ctx *gin.Context) *api_key.Requester {
+	return &api_key.Requester{
+		UserID: ctx.GetString("userID"),
+		Role:   ctx.GetString("role"),
+	}
+}
@@ -0,0 +1,25 @@
+package api_key
+
+import (
+	"esst_sendEmail/internal/v1/resolver/api_key"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	Create(ctx *gin.Context)
+	GetByID(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Revoke(ctx *gin.Context)
+}
+
+type presenter struct {
+	APIKeyResolver api_key.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		APIKeyResolver: api_key.New(db),
+	}
+}
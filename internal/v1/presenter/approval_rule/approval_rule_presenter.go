@@ -0,0 +1,71 @@
+package approval_rule
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/approval_rules"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Create 新增審核規則 (僅限管理員)
+func (p *presenter) Create(ctx *gin.Context) {
+	input := &approval_rules.Created{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	codeMessage := p.ApprovalRuleResolver.Create(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// List 查詢審核規則列表 (僅限管理員)
+func (p *presenter) List(ctx *gin.Context) {
+	input := &approval_rules.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	codeMessage := p.ApprovalRuleResolver.List(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// GetByID 取得單一審核規則 (僅限管理員)
+func (p *presenter) GetByID(ctx *gin.Context) {
+	input := &approval_rules.Field{}
+	input.RuleID = ctx.Param("ruleId")
+
+	codeMessage := p.ApprovalRuleResolver.GetByID(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Update 更新審核規則 (僅限管理員)
+func (p *presenter) Update(ctx *gin.Context) {
+	input := &approval_rules.Updated{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.RuleID = ctx.Param("ruleId")
+
+	codeMessage := p.ApprovalRuleResolver.Update(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Delete 刪除審核規則 (僅限管理員)
+func (p *presenter) Delete(ctx *gin.Context) {
+	input := &approval_rules.Field{}
+	input.RuleID = ctx.Param("ruleId")
+
+	codeMessage := p.ApprovalRuleResolver.Delete(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
@@ -0,0 +1,26 @@
+package approval_rule
+
+import (
+	"esst_sendEmail/internal/v1/resolver/approval_rule"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	Create(ctx *gin.Context)
+	List(ctx *gin.Context)
+	GetByID(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type presenter struct {
+	ApprovalRuleResolver approval_rule.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		ApprovalRuleResolver: approval_rule.New(db),
+	}
+}
@@ -0,0 +1,100 @@
+package attachment
+
+import (
+	"errors"
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/storage"
+	preset "esst_sendEmail/internal/v1/presenter"
+	attachmentService "esst_sendEmail/internal/v1/service/attachment"
+	"esst_sendEmail/internal/v1/structure/attachments"
+
+	"github.com/gin-gonic/gin"
+)
+
+// target 由路由參數判斷附件對象(專案或現貨)
+func target(ctx *gin.Context) (string, string) {
+	if projectID := ctx.Param("projectId"); projectID != "" {
+		return attachments.TargetProject, projectID
+	}
+	return attachments.TargetStock, ctx.Param("stockId")
+}
+
+// multipartOverhead multipart 邊界與欄位標頭預留的大小
+const multipartOverhead = 1 << 20
+
+// Create 上傳附件(multipart/form-data,欄位名稱 file)
+// 讀取 body 前先限制大小,超過上限時不會將整個檔案寫入暫存
+func (p *presenter) Create(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, attachmentService.MaxSize()+multipartOverhead)
+
+	input := &attachments.Created{}
+	if err := ctx.ShouldBind(input); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, code.GetCodeMessage(code.FormatError, attachmentService.ErrTooLarge.Error()))
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.TargetType, input.TargetID = target(ctx)
+	input.UploadedBy = ctx.GetString("userID")
+
+	codeMessage := p.AttachmentResolver.Create(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// List 查詢附件列表
+func (p *presenter) List(ctx *gin.Context) {
+	input := &attachments.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	input.TargetType, input.TargetID = target(ctx)
+
+	codeMessage := p.AttachmentResolver.List(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Download 取得限時下載網址
+func (p *presenter) Download(ctx *gin.Context) {
+	input := &attachments.Field{}
+	input.AttachmentID = ctx.Param("attachmentId")
+	input.TargetType, input.TargetID = target(ctx)
+
+	codeMessage := p.AttachmentResolver.Download(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Delete 刪除附件(僅限上傳者或管理員)
+func (p *presenter) Delete(ctx *gin.Context) {
+	input := &attachments.Field{}
+	input.AttachmentID = ctx.Param("attachmentId")
+	input.TargetType, input.TargetID = target(ctx)
+	input.UserID = ctx.GetString("userID")
+	input.Role = ctx.GetString("role")
+
+	codeMessage := p.AttachmentResolver.Delete(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// File 本機儲存的檔案下載,以網址簽章驗證時效(不需 JWT)
+func (p *presenter) File(ctx *gin.Context) {
+	filename := ctx.Query("filename")
+	path, err := storage.LocalFromEnv().Verify(ctx.Query("key"), filename, ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		ctx.JSON(http.StatusOK, code.GetCodeMessage(code.DoesNotExist, "下載網址無效或已過期"))
+		return
+	}
+
+	ctx.FileAttachment(path, filename)
+}
@@ -0,0 +1,26 @@
+package attachment
+
+import (
+	"esst_sendEmail/internal/v1/resolver/attachment"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	Create(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Download(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	File(ctx *gin.Context)
+}
+
+type presenter struct {
+	AttachmentResolver attachment.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		AttachmentResolver: attachment.New(db),
+	}
+}
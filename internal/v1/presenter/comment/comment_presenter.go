@@ -0,0 +1,79 @@
+package comment
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/comments"
+
+	"github.com/gin-gonic/gin"
+)
+
+// target 由路由參數判斷留言對象(專案或現貨)
+func target(ctx *gin.Context) (string, string) {
+	if projectID := ctx.Param("projectId"); projectID != "" {
+		return comments.TargetProject, projectID
+	}
+	return comments.TargetStock, ctx.Param("stockId")
+}
+
+// Create 新增留言
+func (p *presenter) Create(ctx *gin.Context) {
+	input := &comments.Created{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.TargetType, input.TargetID = target(ctx)
+	input.AuthorID = ctx.GetString("userID")
+
+	codeMessage := p.CommentResolver.Create(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// List 查詢留言串
+func (p *presenter) List(ctx *gin.Context) {
+	input := &comments.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	input.TargetType, input.TargetID = target(ctx)
+
+	codeMessage := p.CommentResolver.List(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Update 編輯留言(僅限留言者本人)
+func (p *presenter) Update(ctx *gin.Context) {
+	input := &comments.Updated{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.CommentID = ctx.Param("commentId")
+	input.TargetType, input.TargetID = target(ctx)
+	input.AuthorID = ctx.GetString("userID")
+
+	codeMessage := p.CommentResolver.Update(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Delete 刪除留言(僅限留言者本人)
+func (p *presenter) Delete(ctx *gin.Context) {
+	input := &comments.Field{}
+	input.CommentID = ctx.Param("commentId")
+	input.TargetType, input.TargetID = target(ctx)
+	input.AuthorID = ctx.GetString("userID")
+
+	codeMessage := p.CommentResolver.Delete(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
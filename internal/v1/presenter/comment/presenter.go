@@ -0,0 +1,25 @@
+package comment
+
+import (
+	"esst_sendEmail/internal/v1/resolver/comment"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	Create(ctx *gin.Context)
+	List(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type presenter struct {
+	CommentResolver comment.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		CommentResolver: comment.New(db),
+	}
+}
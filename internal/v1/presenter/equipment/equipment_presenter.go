@@ -6,6 +6,7 @@ import (
 	"esst_sendEmail/internal/pkg/code"
 	"esst_sendEmail/internal/pkg/log"
 	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/equipment_changes"
 	"esst_sendEmail/internal/v1/structure/equipments"
 
 	"github.com/gin-gonic/gin"
@@ -22,7 +23,7 @@ func (p *presenter) Create(ctx *gin.Context) {
 	}
 
 	codeMessage := p.EquipmentResolver.Create(trx, input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	ctx.JSON(preset.Status(codeMessage, code.Conflict), codeMessage)
 }
 
 func (p *presenter) CreateBatch(ctx *gin.Context) {
@@ -35,7 +36,7 @@ func (p *presenter) CreateBatch(ctx *gin.Context) {
 	}
 
 	codeMessage := p.EquipmentResolver.CreateBatch(trx, input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	ctx.JSON(preset.Status(codeMessage, code.Conflict), codeMessage)
 }
 
 func (p *presenter) List(ctx *gin.Context) {
@@ -62,6 +63,12 @@ func (p *presenter) ListByProjectID(ctx *gin.Context) {
 	}
 
 	codeMessage := p.EquipmentResolver.ListByProjectID(projectID)
+	// 以清單雜湊作為 ETag,整批取代時需以 If-Match 帶回
+	if message, ok := codeMessage.(*code.SuccessfulMessage); ok {
+		if list, ok := message.Body.([]*equipments.Base); ok {
+			ctx.Header("ETag", preset.ListETag(equipment_changes.Checksum(list)))
+		}
+	}
 	ctx.JSON(http.StatusOK, codeMessage)
 }
 
@@ -77,6 +84,12 @@ func (p *presenter) GetByID(ctx *gin.Context) {
 	}
 
 	codeMessage := p.EquipmentResolver.GetByID(input)
+	// 以版本號作為 ETag,更新或刪除時需以 If-Match 帶回
+	if message, ok := codeMessage.(*code.SuccessfulMessage); ok {
+		if single, ok := message.Body.(*equipments.Single); ok {
+			ctx.Header("ETag", preset.ETag(single.Version))
+		}
+	}
 	ctx.JSON(http.StatusOK, codeMessage)
 }
 
@@ -92,21 +105,44 @@ func (p *presenter) Update(ctx *gin.Context) {
 
 	input.EquipmentID = equipmentID
 
-	codeMessage := p.EquipmentResolver.Update(input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	version, err := preset.IfMatchVersion(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusPreconditionRequired, code.GetCodeMessage(code.PreconditionRequired, err.Error()))
+		return
+	}
+	input.Version = version
+
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	codeMessage := p.EquipmentResolver.Update(trx, input)
+	if _, ok := codeMessage.(*code.SuccessfulMessage); ok {
+		ctx.Header("ETag", preset.ETag(input.Version+1))
+	}
+	ctx.JSON(preset.Status(codeMessage, code.PreconditionFailed, code.Conflict), codeMessage)
 }
 
 func (p *presenter) Delete(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
 	equipmentID := ctx.Param("equipmentId")
 	input := &equipments.Updated{}
 	input.EquipmentID = equipmentID
 
 	// DELETE 請求通常不需要 body,直接使用 URL 參數
 	if equipmentID == "" {
+		trx.Rollback()
 		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, "Equipment ID is required"))
 		return
 	}
 
-	codeMessage := p.EquipmentResolver.Delete(input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	input.DeletedBy = ctx.GetString("userID")
+
+	version, err := preset.IfMatchVersion(ctx)
+	if err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusPreconditionRequired, code.GetCodeMessage(code.PreconditionRequired, err.Error()))
+		return
+	}
+	input.Version = version
+
+	codeMessage := p.EquipmentResolver.Delete(trx, input)
+	ctx.JSON(preset.Status(codeMessage, code.PreconditionFailed), codeMessage)
 }
@@ -15,6 +15,10 @@ type Presenter interface {
 	GetByID(ctx *gin.Context)
 	Update(ctx *gin.Context)
 	Delete(ctx *gin.Context)
+	Trash(ctx *gin.Context)   // 資源回收筒列表
+	Restore(ctx *gin.Context) // 從資源回收筒還原
+	Replace(ctx *gin.Context) // 以完整清單取代設備
+	History(ctx *gin.Context) // 設備異動紀錄
 }
 
 type presenter struct {
@@ -0,0 +1,38 @@
+package equipment
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/equipments"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Trash 資源回收筒列表(管理員)
+func (p *presenter) Trash(ctx *gin.Context) {
+	input := &equipments.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	codeMessage := p.EquipmentResolver.Trash(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Restore 從資源回收筒還原(管理員)
+func (p *presenter) Restore(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &equipments.Field{}
+	input.EquipmentID = ctx.Param("equipmentId")
+
+	codeMessage := p.EquipmentResolver.Restore(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
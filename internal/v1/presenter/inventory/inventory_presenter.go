@@ -0,0 +1,72 @@
+package inventory
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/inventories"
+	"esst_sendEmail/internal/v1/structure/inventory_movements"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// List 查詢料號庫存(在庫、已保留、可用數量)
+func (p *presenter) List(ctx *gin.Context) {
+	input := &inventories.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	codeMessage := p.InventoryResolver.List(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// GetByID 取得單一料號庫存
+func (p *presenter) GetByID(ctx *gin.Context) {
+	input := &inventories.Field{}
+	input.InventoryID = ctx.Param("inventoryId")
+
+	codeMessage := p.InventoryResolver.GetByID(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// ListMovements 查詢料號的庫存異動紀錄
+func (p *presenter) ListMovements(ctx *gin.Context) {
+	input := &inventory_movements.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.InventoryID = ctx.Param("inventoryId")
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	codeMessage := p.InventoryResolver.ListMovements(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Record 登錄進貨、出貨或盤點調整 (僅限管理員)
+func (p *presenter) Record(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &inventory_movements.Created{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.CreatedBy = ctx.GetString("userID")
+
+	codeMessage := p.InventoryResolver.Record(trx, input)
+	ctx.JSON(preset.Status(codeMessage, code.Conflict), codeMessage)
+}
@@ -0,0 +1,25 @@
+package inventory
+
+import (
+	"esst_sendEmail/internal/v1/resolver/inventory"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	List(ctx *gin.Context)
+	GetByID(ctx *gin.Context)
+	ListMovements(ctx *gin.Context)
+	Record(ctx *gin.Context)
+}
+
+type presenter struct {
+	InventoryResolver inventory.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		InventoryResolver: inventory.New(db),
+	}
+}
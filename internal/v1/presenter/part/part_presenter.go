@@ -0,0 +1,83 @@
+package part
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/parts"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Create 新增料號 (僅限管理員)
+func (p *presenter) Create(ctx *gin.Context) {
+	input := &parts.Created{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	codeMessage := p.PartResolver.Create(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// List 查詢與搜尋料號目錄(q 可搜尋料號或品名)
+func (p *presenter) List(ctx *gin.Context) {
+	input := &parts.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	codeMessage := p.PartResolver.List(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// GetByID 取得單一料號
+func (p *presenter) GetByID(ctx *gin.Context) {
+	input := &parts.Field{}
+	input.PartID = ctx.Param("partId")
+
+	codeMessage := p.PartResolver.GetByID(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Update 更新料號 (僅限管理員)
+func (p *presenter) Update(ctx *gin.Context) {
+	input := &parts.Updated{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.PartID = ctx.Param("partId")
+
+	codeMessage := p.PartResolver.Update(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Delete 刪除料號 (僅限管理員),已參照的設備改回自由輸入
+func (p *presenter) Delete(ctx *gin.Context) {
+	input := &parts.Field{}
+	input.PartID = ctx.Param("partId")
+
+	codeMessage := p.PartResolver.Delete(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Suggest 料號自動完成,依歷史設備料號的前綴、相似度與使用次數排序
+func (p *presenter) Suggest(ctx *gin.Context) {
+	input := &parts.Suggest{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	codeMessage := p.PartResolver.Suggest(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
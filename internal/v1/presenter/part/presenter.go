@@ -0,0 +1,27 @@
+package part
+
+import (
+	"esst_sendEmail/internal/v1/resolver/part"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	Create(ctx *gin.Context)
+	List(ctx *gin.Context)
+	GetByID(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	Suggest(ctx *gin.Context)
+}
+
+type presenter struct {
+	PartResolver part.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		PartResolver: part.New(db),
+	}
+}
@@ -1,5 +1,69 @@
 package presenter
 
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"esst_sendEmail/internal/pkg/code"
+
+	"github.com/gin-gonic/gin"
+)
+
 const (
 	DefaultLimit = 20
 )
+
+// ErrIfMatchRequired 更新或刪除時未帶入 If-Match 或格式錯誤
+var ErrIfMatchRequired = errors.New("請以 If-Match 標頭帶入查詢時回傳的 ETag")
+
+// ETag 版本號對應的 ETag,例如 "3"
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ListETag 設備清單的 ETag,例如 "9f86d081884c7d65"
+func ListETag(checksum string) string {
+	return `"` + checksum + `"`
+}
+
+// IfMatch 取出 If-Match 標頭中引號內的值,接受 "abc" 或 W/"abc"
+func IfMatch(ctx *gin.Context) (string, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(ctx.GetHeader("If-Match")), "W/")
+	if len(value) < 3 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return "", ErrIfMatchRequired
+	}
+
+	return value[1 : len(value)-1], nil
+}
+
+// IfMatchVersion 解析 If-Match 標頭中的版本號,接受 "3" 或 W/"3"
+func IfMatchVersion(ctx *gin.Context) (int64, error) {
+	value, err := IfMatch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, ErrIfMatchRequired
+	}
+
+	return version, nil
+}
+
+// Status 回應內容的錯誤代碼屬於 codes 時以該代碼作為 HTTP 狀態,其餘維持 200
+func Status(codeMessage interface{}, codes ...int) int {
+	message, ok := codeMessage.(*code.ErrorMessage)
+	if !ok {
+		return http.StatusOK
+	}
+
+	for _, c := range codes {
+		if message.Code == c {
+			return c
+		}
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,72 @@
+package presenter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"esst_sendEmail/internal/pkg/code"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		codeMessage interface{}
+		want        int
+	}{
+		{"successful", code.GetCodeMessage(code.Successful, "ok"), http.StatusOK},
+		{"listed error code", code.GetCodeMessage(code.PreconditionFailed, nil), http.StatusPreconditionFailed},
+		{"other error code", code.GetCodeMessage(code.DoesNotExist, "missing"), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Status(tt.codeMessage, code.PreconditionFailed); got != tt.want {
+				t.Errorf("Status() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    string
+		version int64
+		wantErr bool
+	}{
+		{"version", `"3"`, "3", 3, false},
+		{"weak version", `W/"3"`, "3", 3, false},
+		{"list checksum", `"9f86d081884c7d65"`, "9f86d081884c7d65", 0, false},
+		{"missing", "", "", 0, true},
+		{"unquoted", "3", "", 0, true},
+		{"empty quotes", `""`, "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				ctx.Request.Header.Set("If-Match", tt.header)
+			}
+
+			got, err := IfMatch(ctx)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("IfMatch() = %q, %v, want %q", got, err, tt.want)
+			}
+
+			version, err := IfMatchVersion(ctx)
+			if tt.version == 0 {
+				if err == nil {
+					t.Errorf("IfMatchVersion() = %d, want error", version)
+				}
+			} else if err != nil || version != tt.version {
+				t.Errorf("IfMatchVersion() = %d, %v, want %d", version, err, tt.version)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package project
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/equipments"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateWithEquipments 同時建立專案與設備
+func (p *presenter) CreateWithEquipments(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &equipments.ProjectCreated{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	// 只有管理員可以指定保護期天數
+	if ctx.GetString("role") != "admin" {
+		input.ProtectionDays = 0
+	}
+	input.CreatedBy = ctx.GetString("userID")
+
+	codeMessage := p.ProjectResolver.CreateWithEquipments(trx, input)
+	ctx.JSON(preset.Status(codeMessage, code.Conflict), codeMessage)
+}
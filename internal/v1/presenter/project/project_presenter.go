@@ -21,8 +21,14 @@ func (p *presenter) Create(ctx *gin.Context) {
 		return
 	}
 
+	// 只有管理員可以指定保護期天數
+	if ctx.GetString("role") != "admin" {
+		input.ProtectionDays = 0
+	}
+	input.CreatedBy = ctx.GetString("userID")
+
 	codeMessage := p.ProjectResolver.Create(trx, input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	ctx.JSON(preset.Status(codeMessage, code.Conflict), codeMessage)
 }
 
 func (p *presenter) List(ctx *gin.Context) {
@@ -48,10 +54,17 @@ func (p *presenter) GetByID(ctx *gin.Context) {
 
 	// GetByID 不需要從 body 取得資料，直接使用 URL 參數
 	codeMessage := p.ProjectResolver.GetByID(input)
+	// 以版本號作為 ETag,更新或刪除時需以 If-Match 帶回
+	if message, ok := codeMessage.(*code.SuccessfulMessage); ok {
+		if single, ok := message.Body.(*projects.Single); ok {
+			ctx.Header("ETag", preset.ETag(single.Version))
+		}
+	}
 	ctx.JSON(http.StatusOK, codeMessage)
 }
 
 func (p *presenter) Update(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
 	// 修正：URL 參數名稱改為 projectId（與 router 定義一致）
 	projectId := ctx.Param("projectId")
 	input := &projects.Updated{}
@@ -59,21 +72,44 @@ func (p *presenter) Update(ctx *gin.Context) {
 
 	if err := ctx.ShouldBindJSON(input); err != nil {
 		log.Error(err)
+		trx.Rollback()
 		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
 		return
 	}
 
-	codeMessage := p.ProjectResolver.Update(input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	version, err := preset.IfMatchVersion(ctx)
+	if err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusPreconditionRequired, code.GetCodeMessage(code.PreconditionRequired, err.Error()))
+		return
+	}
+	input.Version = version
+	input.UpdatedBy = ctx.GetString("userID")
+
+	codeMessage := p.ProjectResolver.Update(trx, input)
+	if _, ok := codeMessage.(*code.SuccessfulMessage); ok {
+		ctx.Header("ETag", preset.ETag(input.Version+1))
+	}
+	ctx.JSON(preset.Status(codeMessage, code.PreconditionFailed, code.Conflict), codeMessage)
 }
 
 func (p *presenter) Delete(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
 	// 修正：URL 參數名稱改為 projectId（與 router 定義一致）
 	projectId := ctx.Param("projectId")
 	input := &projects.Updated{}
 	input.ProjectID = projectId
+	input.DeletedBy = ctx.GetString("userID")
 
-	// Delete 操作不需要從 body 取得額外資料
-	codeMessage := p.ProjectResolver.Delete(input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	// Delete 操作不需要從 body 取得額外資料,版本號由 If-Match 取得
+	version, err := preset.IfMatchVersion(ctx)
+	if err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusPreconditionRequired, code.GetCodeMessage(code.PreconditionRequired, err.Error()))
+		return
+	}
+	input.Version = version
+
+	codeMessage := p.ProjectResolver.Delete(trx, input)
+	ctx.JSON(preset.Status(codeMessage, code.PreconditionFailed), codeMessage)
 }
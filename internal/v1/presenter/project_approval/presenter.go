@@ -0,0 +1,25 @@
+package project_approval
+
+import (
+	"esst_sendEmail/internal/v1/resolver/project_approval"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	Queue(ctx *gin.Context)
+	ListByProjectID(ctx *gin.Context)
+	Approve(ctx *gin.Context)
+	Reject(ctx *gin.Context)
+}
+
+type presenter struct {
+	ProjectApprovalResolver project_approval.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		ProjectApprovalResolver: project_approval.New(db),
+	}
+}
@@ -0,0 +1,83 @@
+package project_approval
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/project_approvals"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Queue 查詢目前使用者可審核的報備
+func (p *presenter) Queue(ctx *gin.Context) {
+	input := &project_approvals.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	codeMessage := p.ProjectApprovalResolver.Queue(input, ctx.GetString("role"))
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// ListByProjectID 查詢專案的審核紀錄
+func (p *presenter) ListByProjectID(ctx *gin.Context) {
+	input := &project_approvals.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	projectID := ctx.Param("projectId")
+	input.ProjectID = &projectID
+
+	codeMessage := p.ProjectApprovalResolver.ListByProjectID(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Approve 核准報備目前的審核關卡
+func (p *presenter) Approve(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &project_approvals.Decided{}
+	if err := ctx.ShouldBindJSON(input); err != nil && ctx.Request.ContentLength > 0 {
+		trx.Rollback()
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.ApprovalID = ctx.Param("approvalId")
+	input.DecidedBy = ctx.GetString("userID")
+	input.DecidedRole = ctx.GetString("role")
+
+	codeMessage := p.ProjectApprovalResolver.Approve(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Reject 駁回報備,需填寫原因
+func (p *presenter) Reject(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &project_approvals.Decided{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.ApprovalID = ctx.Param("approvalId")
+	input.DecidedBy = ctx.GetString("userID")
+	input.DecidedRole = ctx.GetString("role")
+
+	codeMessage := p.ProjectApprovalResolver.Reject(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
@@ -0,0 +1,24 @@
+package project_conflict
+
+import (
+	"esst_sendEmail/internal/v1/resolver/project_conflict"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	List(ctx *gin.Context)
+	GetByID(ctx *gin.Context)
+	Review(ctx *gin.Context)
+}
+
+type presenter struct {
+	ProjectConflictResolver project_conflict.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		ProjectConflictResolver: project_conflict.New(db),
+	}
+}
@@ -0,0 +1,51 @@
+package project_conflict
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/project_conflicts"
+
+	"github.com/gin-gonic/gin"
+)
+
+// List 重複報備審核佇列 (僅限管理員),可依狀態與專案篩選
+func (p *presenter) List(ctx *gin.Context) {
+	input := &project_conflicts.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	codeMessage := p.ProjectConflictResolver.List(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// GetByID 取得單一重複報備紀錄 (僅限管理員)
+func (p *presenter) GetByID(ctx *gin.Context) {
+	input := &project_conflicts.Field{}
+	input.ConflictID = ctx.Param("conflictId")
+
+	codeMessage := p.ProjectConflictResolver.GetByID(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Review 審核重複報備紀錄:確認重複或排除 (僅限管理員)
+func (p *presenter) Review(ctx *gin.Context) {
+	input := &project_conflicts.Reviewed{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.ConflictID = ctx.Param("conflictId")
+	input.ReviewedBy = ctx.GetString("userID")
+
+	codeMessage := p.ProjectConflictResolver.Review(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
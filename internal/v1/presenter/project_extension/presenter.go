@@ -0,0 +1,26 @@
+package project_extension
+
+import (
+	"esst_sendEmail/internal/v1/resolver/project_extension"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	Create(ctx *gin.Context)
+	ListByProjectID(ctx *gin.Context)
+	List(ctx *gin.Context)
+	GetByID(ctx *gin.Context)
+	Review(ctx *gin.Context)
+}
+
+type presenter struct {
+	ProjectExtensionResolver project_extension.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		ProjectExtensionResolver: project_extension.New(db),
+	}
+}
@@ -0,0 +1,88 @@
+package project_extension
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/project_extensions"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Create 申請延長專案保護期
+func (p *presenter) Create(ctx *gin.Context) {
+	input := &project_extensions.Created{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.ProjectID = ctx.Param("projectId")
+	input.RequestedBy = ctx.GetString("userID")
+
+	codeMessage := p.ProjectExtensionResolver.Create(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// ListByProjectID 查詢專案的延長申請紀錄
+func (p *presenter) ListByProjectID(ctx *gin.Context) {
+	input := &project_extensions.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	projectID := ctx.Param("projectId")
+	input.ProjectID = &projectID
+
+	codeMessage := p.ProjectExtensionResolver.List(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// List 延長申請審核佇列 (僅限管理員)
+func (p *presenter) List(ctx *gin.Context) {
+	input := &project_extensions.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	codeMessage := p.ProjectExtensionResolver.List(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// GetByID 取得單一延長申請 (僅限管理員)
+func (p *presenter) GetByID(ctx *gin.Context) {
+	input := &project_extensions.Field{}
+	input.ExtensionID = ctx.Param("extensionId")
+
+	codeMessage := p.ProjectExtensionResolver.GetByID(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Review 核准或駁回延長申請 (僅限管理員)
+func (p *presenter) Review(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &project_extensions.Reviewed{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.ExtensionID = ctx.Param("extensionId")
+	input.ReviewedBy = ctx.GetString("userID")
+
+	codeMessage := p.ProjectExtensionResolver.Review(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
@@ -0,0 +1,28 @@
+package quotation
+
+import (
+	"esst_sendEmail/internal/v1/resolver/quotation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	Pricing(ctx *gin.Context)
+	Create(ctx *gin.Context)
+	List(ctx *gin.Context)
+	GetByID(ctx *gin.Context)
+	PDF(ctx *gin.Context)
+	Send(ctx *gin.Context)
+	Accept(ctx *gin.Context)
+}
+
+type presenter struct {
+	QuotationResolver quotation.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		QuotationResolver: quotation.New(db),
+	}
+}
@@ -0,0 +1,107 @@
+package quotation
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/quotations"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Pricing 查詢專案設備的金額與總計
+func (p *presenter) Pricing(ctx *gin.Context) {
+	codeMessage := p.QuotationResolver.Pricing(ctx.Param("projectId"))
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Create 以專案目前的設備建立新版本的報價單
+func (p *presenter) Create(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &quotations.Created{}
+	if err := ctx.ShouldBindJSON(input); err != nil && ctx.Request.ContentLength > 0 {
+		trx.Rollback()
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.ProjectID = ctx.Param("projectId")
+	input.CreatedBy = ctx.GetString("userID")
+
+	codeMessage := p.QuotationResolver.Create(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// List 查詢專案的報價單版本
+func (p *presenter) List(ctx *gin.Context) {
+	input := &quotations.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	input.ProjectID = ctx.Param("projectId")
+
+	codeMessage := p.QuotationResolver.List(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// GetByID 查詢報價單與明細
+func (p *presenter) GetByID(ctx *gin.Context) {
+	input := &quotations.Field{
+		ProjectID:   ctx.Param("projectId"),
+		QuotationID: ctx.Param("quotationId"),
+	}
+
+	codeMessage := p.QuotationResolver.GetByID(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// PDF 下載報價單 PDF
+func (p *presenter) PDF(ctx *gin.Context) {
+	input := &quotations.Field{
+		ProjectID:   ctx.Param("projectId"),
+		QuotationID: ctx.Param("quotationId"),
+	}
+
+	filename, content, codeMessage := p.QuotationResolver.Render(input)
+	if codeMessage != nil {
+		ctx.JSON(http.StatusOK, codeMessage)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	ctx.Data(http.StatusOK, "application/pdf", content)
+}
+
+// Send 寄送報價單給專案聯絡人
+func (p *presenter) Send(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &quotations.Action{
+		ProjectID:   ctx.Param("projectId"),
+		QuotationID: ctx.Param("quotationId"),
+		By:          ctx.GetString("userID"),
+	}
+
+	codeMessage := p.QuotationResolver.Send(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Accept 標記客戶已接受報價單
+func (p *presenter) Accept(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &quotations.Action{
+		ProjectID:   ctx.Param("projectId"),
+		QuotationID: ctx.Param("quotationId"),
+		By:          ctx.GetString("userID"),
+	}
+
+	codeMessage := p.QuotationResolver.Accept(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
@@ -0,0 +1,24 @@
+package role_policy
+
+import (
+	"esst_sendEmail/internal/v1/resolver/role_policy"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	List(ctx *gin.Context)
+	GetByRole(ctx *gin.Context)
+	Update(ctx *gin.Context)
+}
+
+type presenter struct {
+	RolePolicyResolver role_policy.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		RolePolicyResolver: role_policy.New(db),
+	}
+}
@@ -0,0 +1,42 @@
+package role_policy
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/v1/structure/role_policies"
+
+	"github.com/gin-gonic/gin"
+)
+
+// List 取得所有角色政策 (僅限管理員)
+func (p *presenter) List(ctx *gin.Context) {
+	codeMessage := p.RolePolicyResolver.List()
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// GetByRole 取得單一角色政策 (僅限管理員)
+func (p *presenter) GetByRole(ctx *gin.Context) {
+	input := &role_policies.Field{}
+	input.Role = ctx.Param("role")
+
+	codeMessage := p.RolePolicyResolver.GetByRole(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Update 設定角色政策 (僅限管理員)
+func (p *presenter) Update(ctx *gin.Context) {
+	input := &role_policies.Updated{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.Role = ctx.Param("role")
+
+	codeMessage := p.RolePolicyResolver.Update(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
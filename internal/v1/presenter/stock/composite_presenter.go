@@ -0,0 +1,29 @@
+package stock
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/stock_equipments"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateWithEquipments 同時建立現貨報備與設備
+func (p *presenter) CreateWithEquipments(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &stock_equipments.StockCreated{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.CreatedBy = ctx.GetString("userID")
+
+	codeMessage := p.StockResolver.CreateWithEquipments(trx, input)
+	ctx.JSON(preset.Status(codeMessage, code.Conflict), codeMessage)
+}
@@ -9,10 +9,13 @@ import (
 
 type Presenter interface {
 	Create(ctx *gin.Context)
+	CreateWithEquipments(ctx *gin.Context)
 	List(ctx *gin.Context)
 	GetByID(ctx *gin.Context)
 	Update(ctx *gin.Context)
 	Delete(ctx *gin.Context)
+	Trash(ctx *gin.Context)   // 資源回收筒列表
+	Restore(ctx *gin.Context) // 從資源回收筒還原
 }
 
 type presenter struct {
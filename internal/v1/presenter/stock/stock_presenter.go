@@ -21,6 +21,8 @@ func (p *presenter) Create(ctx *gin.Context) {
 		return
 	}
 
+	input.CreatedBy = ctx.GetString("userID")
+
 	codeMessage := p.StockResolver.Create(trx, input)
 	ctx.JSON(http.StatusOK, codeMessage)
 }
@@ -46,29 +48,59 @@ func (p *presenter) GetByID(ctx *gin.Context) {
 	input.StockID = stockId
 
 	codeMessage := p.StockResolver.GetByID(input)
+	// 以版本號作為 ETag,更新或刪除時需以 If-Match 帶回
+	if message, ok := codeMessage.(*code.SuccessfulMessage); ok {
+		if single, ok := message.Body.(*stocks.Single); ok {
+			ctx.Header("ETag", preset.ETag(single.Version))
+		}
+	}
 	ctx.JSON(http.StatusOK, codeMessage)
 }
 
 func (p *presenter) Update(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
 	stockId := ctx.Param("stockId")
 	input := &stocks.Updated{}
 	input.StockID = stockId
 
 	if err := ctx.ShouldBindJSON(input); err != nil {
 		log.Error(err)
+		trx.Rollback()
 		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
 		return
 	}
 
-	codeMessage := p.StockResolver.Update(input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	version, err := preset.IfMatchVersion(ctx)
+	if err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusPreconditionRequired, code.GetCodeMessage(code.PreconditionRequired, err.Error()))
+		return
+	}
+	input.Version = version
+	input.UpdatedBy = ctx.GetString("userID")
+
+	codeMessage := p.StockResolver.Update(trx, input)
+	if _, ok := codeMessage.(*code.SuccessfulMessage); ok {
+		ctx.Header("ETag", preset.ETag(input.Version+1))
+	}
+	ctx.JSON(preset.Status(codeMessage, code.PreconditionFailed), codeMessage)
 }
 
 func (p *presenter) Delete(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
 	stockId := ctx.Param("stockId")
 	input := &stocks.Updated{}
 	input.StockID = stockId
+	input.DeletedBy = ctx.GetString("userID")
 
-	codeMessage := p.StockResolver.Delete(input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	version, err := preset.IfMatchVersion(ctx)
+	if err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusPreconditionRequired, code.GetCodeMessage(code.PreconditionRequired, err.Error()))
+		return
+	}
+	input.Version = version
+
+	codeMessage := p.StockResolver.Delete(trx, input)
+	ctx.JSON(preset.Status(codeMessage, code.PreconditionFailed, code.Conflict), codeMessage)
 }
@@ -0,0 +1,38 @@
+package stock
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/stocks"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Trash 資源回收筒列表(管理員)
+func (p *presenter) Trash(ctx *gin.Context) {
+	input := &stocks.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	codeMessage := p.StockResolver.Trash(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Restore 從資源回收筒還原(管理員)
+func (p *presenter) Restore(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &stocks.Field{}
+	input.StockID = ctx.Param("stockId")
+
+	codeMessage := p.StockResolver.Restore(trx, input)
+	ctx.JSON(preset.Status(codeMessage, code.Conflict), codeMessage)
+}
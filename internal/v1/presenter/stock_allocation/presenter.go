@@ -0,0 +1,24 @@
+package stock_allocation
+
+import (
+	"esst_sendEmail/internal/v1/resolver/stock_allocation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type Presenter interface {
+	Summary(ctx *gin.Context)
+	Create(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type presenter struct {
+	StockAllocationResolver stock_allocation.Resolver
+}
+
+func New(db *gorm.DB) Presenter {
+	return &presenter{
+		StockAllocationResolver: stock_allocation.New(db),
+	}
+}
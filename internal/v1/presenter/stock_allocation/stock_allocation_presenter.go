@@ -0,0 +1,52 @@
+package stock_allocation
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/v1/structure/stock_allocations"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Summary 查詢專案設備的現貨支應情況
+func (p *presenter) Summary(ctx *gin.Context) {
+	input := &stock_allocations.Field{ProjectID: ctx.Param("projectId")}
+	if err := ctx.ShouldBind(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	codeMessage := p.StockAllocationResolver.Summary(input.ProjectID)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Create 將現貨設備分配給專案設備
+func (p *presenter) Create(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &stock_allocations.Created{}
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.ProjectID = ctx.Param("projectId")
+	input.AllocatedBy = ctx.GetString("userID")
+
+	codeMessage := p.StockAllocationResolver.Create(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// Delete 取消分配
+func (p *presenter) Delete(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &stock_allocations.Field{
+		ProjectID:    ctx.Param("projectId"),
+		AllocationID: ctx.Param("allocationId"),
+	}
+
+	codeMessage := p.StockAllocationResolver.Delete(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
@@ -0,0 +1,71 @@
+package stock_equipment
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/equipment_changes"
+	"esst_sendEmail/internal/v1/structure/stock_equipments"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Replace 以完整清單取代現貨設備
+func (p *presenter) Replace(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &stock_equipments.Replaced{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Error(err)
+		trx.Rollback()
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	input.StockID = ctx.Param("stockId")
+	input.ChangedBy = ctx.GetString("userID")
+	input.ChangedByName = ctx.GetString("username")
+
+	checksum, err := preset.IfMatch(ctx)
+	if err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusPreconditionRequired, code.GetCodeMessage(code.PreconditionRequired, err.Error()))
+		return
+	}
+	input.Checksum = checksum
+
+	codeMessage := p.StockEquipmentResolver.Replace(trx, input)
+	// 回傳取代後(或版本不符時目前)清單的 ETag
+	switch message := codeMessage.(type) {
+	case *code.SuccessfulMessage:
+		if diff, ok := message.Body.(*stock_equipments.Diff); ok {
+			ctx.Header("ETag", preset.ListETag(diff.Checksum))
+		}
+	case *code.ErrorMessage:
+		if list, ok := message.Detailed.([]*stock_equipments.Base); ok {
+			ctx.Header("ETag", preset.ListETag(equipment_changes.Checksum(list)))
+		}
+	}
+	ctx.JSON(preset.Status(codeMessage, code.PreconditionFailed, code.Conflict), codeMessage)
+}
+
+// History 現貨設備的異動紀錄
+func (p *presenter) History(ctx *gin.Context) {
+	input := &equipment_changes.Fields{}
+	if err := ctx.ShouldBindQuery(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	if input.Limit == 0 || input.Limit > preset.DefaultLimit {
+		input.Limit = preset.DefaultLimit
+	}
+
+	input.TargetID = ctx.Param("stockId")
+
+	codeMessage := p.StockEquipmentResolver.History(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
@@ -6,6 +6,7 @@ import (
 	"esst_sendEmail/internal/pkg/code"
 	"esst_sendEmail/internal/pkg/log"
 	preset "esst_sendEmail/internal/v1/presenter"
+	"esst_sendEmail/internal/v1/structure/equipment_changes"
 	"esst_sendEmail/internal/v1/structure/stock_equipments"
 
 	"github.com/gin-gonic/gin"
@@ -22,7 +23,7 @@ func (p *presenter) Create(ctx *gin.Context) {
 	}
 
 	codeMessage := p.StockEquipmentResolver.Create(trx, input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	ctx.JSON(preset.Status(codeMessage, code.Conflict), codeMessage)
 }
 
 func (p *presenter) CreateBatch(ctx *gin.Context) {
@@ -35,7 +36,7 @@ func (p *presenter) CreateBatch(ctx *gin.Context) {
 	}
 
 	codeMessage := p.StockEquipmentResolver.CreateBatch(trx, input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	ctx.JSON(preset.Status(codeMessage, code.Conflict), codeMessage)
 }
 
 func (p *presenter) List(ctx *gin.Context) {
@@ -62,6 +63,12 @@ func (p *presenter) ListByStockID(ctx *gin.Context) {
 	}
 
 	codeMessage := p.StockEquipmentResolver.ListByStockID(stockID)
+	// 以清單雜湊作為 ETag,整批取代時需以 If-Match 帶回
+	if message, ok := codeMessage.(*code.SuccessfulMessage); ok {
+		if list, ok := message.Body.([]*stock_equipments.Base); ok {
+			ctx.Header("ETag", preset.ListETag(equipment_changes.Checksum(list)))
+		}
+	}
 	ctx.JSON(http.StatusOK, codeMessage)
 }
 
@@ -76,35 +83,66 @@ func (p *presenter) GetByID(ctx *gin.Context) {
 	}
 
 	codeMessage := p.StockEquipmentResolver.GetByID(input)
+	// 以版本號作為 ETag,更新或刪除時需以 If-Match 帶回
+	if message, ok := codeMessage.(*code.SuccessfulMessage); ok {
+		if single, ok := message.Body.(*stock_equipments.Single); ok {
+			ctx.Header("ETag", preset.ETag(single.Version))
+		}
+	}
 	ctx.JSON(http.StatusOK, codeMessage)
 }
 
 func (p *presenter) Update(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
 	equipmentID := ctx.Param("equipmentId")
 	input := &stock_equipments.Updated{}
 
 	if err := ctx.ShouldBindJSON(input); err != nil {
 		log.Error(err)
+		trx.Rollback()
 		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
 		return
 	}
 
 	input.StockEquipmentID = equipmentID
 
-	codeMessage := p.StockEquipmentResolver.Update(input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	version, err := preset.IfMatchVersion(ctx)
+	if err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusPreconditionRequired, code.GetCodeMessage(code.PreconditionRequired, err.Error()))
+		return
+	}
+	input.Version = version
+
+	codeMessage := p.StockEquipmentResolver.Update(trx, input)
+	if _, ok := codeMessage.(*code.SuccessfulMessage); ok {
+		ctx.Header("ETag", preset.ETag(input.Version+1))
+	}
+	ctx.JSON(preset.Status(codeMessage, code.PreconditionFailed, code.Conflict), codeMessage)
 }
 
 func (p *presenter) Delete(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
 	equipmentID := ctx.Param("equipmentId")
 	input := &stock_equipments.Updated{}
 	input.StockEquipmentID = equipmentID
 
 	if equipmentID == "" {
+		trx.Rollback()
 		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, "Equipment ID is required"))
 		return
 	}
 
-	codeMessage := p.StockEquipmentResolver.Delete(input)
-	ctx.JSON(http.StatusOK, codeMessage)
+	input.DeletedBy = ctx.GetString("userID")
+
+	version, err := preset.IfMatchVersion(ctx)
+	if err != nil {
+		trx.Rollback()
+		ctx.JSON(http.StatusPreconditionRequired, code.GetCodeMessage(code.PreconditionRequired, err.Error()))
+		return
+	}
+	input.Version = version
+
+	codeMessage := p.StockEquipmentResolver.Delete(trx, input)
+	ctx.JSON(preset.Status(codeMessage, code.PreconditionFailed, code.Conflict), codeMessage)
 }
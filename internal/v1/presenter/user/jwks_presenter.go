@@ -0,0 +1,16 @@
+package user
+
+import (
+	"net/http"
+
+	"esst_sendEmail/internal/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS 公開驗證 token 用的公開金鑰
+// 回傳標準 JWK Set 格式(不包裝 code/message),供其他服務的 JWT 函式庫直接使用
+func (p *presenter) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, auth.JWKS())
+}
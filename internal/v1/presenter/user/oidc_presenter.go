@@ -0,0 +1,103 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/sso"
+	"esst_sendEmail/internal/v1/structure/users"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oidcFlowCookie 暫存 state、nonce 與 PKCE verifier 的 cookie
+const oidcFlowCookie = "oidc_flow"
+
+// OIDCLogin 導向身分提供者進行單一登入
+func (p *presenter) OIDCLogin(ctx *gin.Context) {
+	flow, err := sso.NewFlow()
+	if err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusInternalServerError, code.GetCodeMessage(code.InternalServerError, err.Error()))
+		return
+	}
+
+	authURL, err := sso.AuthCodeURL(ctx.Request.Context(), flow)
+	if err != nil {
+		if errors.Is(err, sso.ErrDisabled) {
+			ctx.JSON(http.StatusServiceUnavailable, code.GetCodeMessage(code.ServerDown, err.Error()))
+			return
+		}
+		log.Error(err)
+		ctx.JSON(http.StatusBadGateway, code.GetCodeMessage(code.InternalServerError, "無法連線至身分提供者"))
+		return
+	}
+
+	value, err := sso.EncodeFlow(flow)
+	if err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusInternalServerError, code.GetCodeMessage(code.InternalServerError, err.Error()))
+		return
+	}
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcFlowCookie, value, int(sso.FlowTTL.Seconds()), "/auth/oidc", "", false, true)
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback 身分提供者回呼,驗證 state 後以授權碼完成登入
+func (p *presenter) OIDCCallback(ctx *gin.Context) {
+	// 流程資料僅能使用一次
+	value, err := ctx.Cookie(oidcFlowCookie)
+	ctx.SetCookie(oidcFlowCookie, "", -1, "/auth/oidc", "", false, true)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.JWTRejected, sso.ErrInvalidFlow.Error()))
+		return
+	}
+
+	flow, err := sso.DecodeFlow(value)
+	if err != nil || flow.State != ctx.Query("state") {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.JWTRejected, sso.ErrInvalidFlow.Error()))
+		return
+	}
+
+	// 身分提供者回傳錯誤(例如使用者取消授權)
+	if errorCode := ctx.Query("error"); errorCode != "" {
+		log.Info("OIDC authorization error:", errorCode, ctx.Query("error_description"))
+		ctx.JSON(http.StatusOK, code.GetCodeMessage(code.JWTRejected, errorCode))
+		return
+	}
+
+	authCode := ctx.Query("code")
+	if authCode == "" {
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, "missing authorization code"))
+		return
+	}
+
+	result := p.UserResolver.OIDCLogin(ctx.Request.Context(), flow, authCode, ctx.ClientIP())
+
+	// 有設定前端頁面時,登入成功後設定 cookie 並導回前端
+	successURL := os.Getenv("OIDC_SUCCESS_REDIRECT_URL")
+	successMsg, ok := result.(*code.SuccessfulMessage)
+	if successURL != "" && ok && successMsg.Code == code.Successful {
+		if userData, ok := successMsg.Body.(*users.Base); ok {
+			ctx.SetCookie(
+				"token",
+				userData.Token,
+				int(24*time.Hour.Seconds()), // 24 小時
+				"/",
+				"",
+				false, // 開發環境設為 false，生產環境改為 true
+				true,
+			)
+			ctx.Redirect(http.StatusFound, successURL)
+			return
+		}
+	}
+
+	p.respondLogin(ctx, result)
+}
@@ -9,14 +9,24 @@ import (
 
 type Presenter interface {
 	Create(ctx *gin.Context)
-	RequestVerificationCode(ctx *gin.Context) // 新增:請求驗證碼
-	VerifyAndLogin(ctx *gin.Context)          // 新增:驗證碼登入
-	Login(ctx *gin.Context)
+	Login(ctx *gin.Context)          // 驗證帳號密碼,依登入政策回傳 token 或登入票證
+	VerifyAndLogin(ctx *gin.Context) // 以登入票證與驗證碼完成登入
+	OIDCLogin(ctx *gin.Context)
+	OIDCCallback(ctx *gin.Context)
+	JWKS(ctx *gin.Context) // 公開金鑰集合
 	Logout(ctx *gin.Context)
 	GetByID(ctx *gin.Context)
 	List(ctx *gin.Context)
 	Update(ctx *gin.Context)
 	Delete(ctx *gin.Context)
+	Unlock(ctx *gin.Context)
+	ChangePassword(ctx *gin.Context)
+	ForgotPassword(ctx *gin.Context)
+	ResetPassword(ctx *gin.Context)
+	SetupTOTP(ctx *gin.Context)
+	ConfirmTOTP(ctx *gin.Context)
+	DisableTOTP(ctx *gin.Context)
+	RegenerateRecoveryCodes(ctx *gin.Context)
 }
 
 type presenter struct {
@@ -6,8 +6,6 @@ import (
 
 	"esst_sendEmail/internal/pkg/code"
 	"esst_sendEmail/internal/pkg/log"
-	"esst_sendEmail/internal/pkg/mail"
-	"esst_sendEmail/internal/pkg/verification"
 	preset "esst_sendEmail/internal/v1/presenter"
 	"esst_sendEmail/internal/v1/structure/users"
 
@@ -29,8 +27,8 @@ func (p *presenter) Create(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, codeMessage)
 }
 
-// RequestVerificationCode 請求驗證碼 - 第一步驗證帳號密碼
-func (p *presenter) RequestVerificationCode(ctx *gin.Context) {
+// Login 驗證帳號密碼 - 依登入政策直接登入,或寄送驗證碼並回傳登入票證
+func (p *presenter) Login(ctx *gin.Context) {
 	input := &users.Login{}
 
 	if err := ctx.ShouldBindJSON(input); err != nil {
@@ -39,172 +37,45 @@ func (p *presenter) RequestVerificationCode(ctx *gin.Context) {
 		return
 	}
 
-	// 先驗證帳號密碼
-	result := p.UserResolver.ValidateCredentials(input.Username, input.Password)
-
-	// 檢查驗證是否成功
-	successMsg, ok := result.(*code.SuccessfulMessage)
-	if !ok || successMsg.Code != code.Successful {
-		ctx.JSON(http.StatusOK, result)
-		return
-	}
-
-	// 獲取使用者資料
-	userData, ok := successMsg.Body.(*users.Base)
-	if !ok {
-		log.Error("Failed to convert user data")
-		ctx.JSON(http.StatusInternalServerError, code.GetCodeMessage(code.InternalServerError, "Internal server error"))
-		return
-	}
-
-	// 檢查使用者是否有設定 email
-	if userData.Email == "" {
-		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, "使用者未設定電子信箱，無法發送驗證碼"))
-		return
-	}
-
-	// 生成驗證碼
-	verificationService := verification.New()
-	verificationCode, err := verificationService.GenerateCode(userData.Email)
-	if err != nil {
-		log.Error("Failed to generate verification code:", err)
-		ctx.JSON(http.StatusInternalServerError, code.GetCodeMessage(code.InternalServerError, "Failed to generate verification code"))
-		return
-	}
-
-	// 發送驗證碼郵件
-	emailService := mail.New()
-	err = emailService.SendVerificationCode(userData.Email, verificationCode, userData.Username)
-	if err != nil {
-		log.Error("Failed to send verification code email:", err)
-		ctx.JSON(http.StatusInternalServerError, code.GetCodeMessage(code.InternalServerError, "無法發送驗證碼郵件"))
-		return
-	}
-
-	log.Info("Verification code sent to:", userData.Email)
-
-	// 返回成功訊息(不返回驗證碼)
-	ctx.JSON(http.StatusOK, code.GetCodeMessage(code.Successful, gin.H{
-		"message": "驗證碼已發送至您的信箱",
-		"email":   maskEmail(userData.Email), // 遮罩部分信箱
-	}))
+	input.IP = ctx.ClientIP()
+	result := p.UserResolver.Login(input)
+	p.respondLogin(ctx, result)
 }
 
-// VerifyAndLogin 驗證驗證碼並登入 - 第二步驗證驗證碼
+// VerifyAndLogin 以登入票證與驗證碼完成登入
 func (p *presenter) VerifyAndLogin(ctx *gin.Context) {
-	type VerifyInput struct {
-		Username string `json:"username" binding:"required"`
-		Password string `json:"password" binding:"required"`
-		Code     string `json:"code" binding:"required,len=6"`
-	}
+	input := &users.LoginVerified{}
 
-	input := &VerifyInput{}
 	if err := ctx.ShouldBindJSON(input); err != nil {
 		log.Error(err)
 		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
 		return
 	}
 
-	// 再次驗證帳號密碼(避免重放攻擊)
-	result := p.UserResolver.ValidateCredentials(input.Username, input.Password)
-
-	successMsg, ok := result.(*code.SuccessfulMessage)
-	if !ok || successMsg.Code != code.Successful {
-		ctx.JSON(http.StatusOK, code.GetCodeMessage(code.JWTRejected, "帳號或密碼錯誤"))
-		return
-	}
-
-	userData, ok := successMsg.Body.(*users.Base)
-	if !ok {
-		log.Error("Failed to convert user data")
-		ctx.JSON(http.StatusInternalServerError, code.GetCodeMessage(code.InternalServerError, "Internal server error"))
-		return
-	}
-
-	// 驗證驗證碼
-	verificationService := verification.New()
-	if !verificationService.VerifyCode(userData.Email, input.Code) {
-		ctx.JSON(http.StatusOK, code.GetCodeMessage(code.JWTRejected, "驗證碼錯誤或已過期"))
-		return
-	}
-
-	// 驗證成功，執行登入
-	loginResult := p.UserResolver.Login(&users.Login{
-		Username: input.Username,
-		Password: input.Password,
-	})
-
-	// 檢查登入是否成功
-	loginSuccessMsg, ok := loginResult.(*code.SuccessfulMessage)
-	if ok && loginSuccessMsg.Code == code.Successful {
-		// 從成功訊息中獲取用戶資料
-		loginUserData, ok := loginSuccessMsg.Body.(*users.Base)
-		if !ok {
-			log.Error("Failed to convert user data")
-			ctx.JSON(http.StatusInternalServerError, code.GetCodeMessage(code.InternalServerError, "Internal server error"))
-			return
-		}
-
-		// 設定 token cookie
-		ctx.SetCookie(
-			"token",
-			loginUserData.Token,
-			int(24*time.Hour.Seconds()), // 24 小時
-			"/",
-			"",
-			false, // 開發環境設為 false，生產環境改為 true
-			true,
-		)
-
-		// 返回帶有 token 的回應
-		ctx.JSON(http.StatusOK, code.GetCodeMessage(code.Successful, loginUserData))
-	} else {
-		// 登入失敗，直接返回錯誤結果
-		ctx.JSON(http.StatusOK, loginResult)
-	}
+	input.IP = ctx.ClientIP()
+	result := p.UserResolver.VerifyLogin(input)
+	p.respondLogin(ctx, result)
 }
 
-// Login 原有的直接登入(保留作為備用)
-func (p *presenter) Login(ctx *gin.Context) {
-	input := &users.Login{}
-
-	if err := ctx.ShouldBindJSON(input); err != nil {
-		log.Error(err)
-		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
-		return
-	}
-
-	// 執行登入
-	result := p.UserResolver.Login(input)
-
-	// 檢查登入是否成功
+// respondLogin 登入成功取得 token 時設定 cookie,其餘(登入票證或錯誤)直接回傳
+func (p *presenter) respondLogin(ctx *gin.Context, result interface{}) {
 	successMsg, ok := result.(*code.SuccessfulMessage)
 	if ok && successMsg.Code == code.Successful {
-		// 從成功訊息中獲取用戶資料
-		userData, ok := successMsg.Body.(*users.Base)
-		if !ok {
-			log.Error("Failed to convert user data")
-			ctx.JSON(http.StatusInternalServerError, code.GetCodeMessage(code.InternalServerError, "Internal server error"))
-			return
+		if userData, ok := successMsg.Body.(*users.Base); ok && userData.Token != "" {
+			// 設定 token cookie
+			ctx.SetCookie(
+				"token",
+				userData.Token,
+				int(24*time.Hour.Seconds()), // 24 小時
+				"/",
+				"",
+				false, // 開發環境設為 false，生產環境改為 true
+				true,
+			)
 		}
-
-		// 設定 token cookie
-		ctx.SetCookie(
-			"token",
-			userData.Token,
-			int(24*time.Hour.Seconds()), // 24 小時
-			"/",
-			"",
-			false, // 開發環境設為 false，生產環境改為 true
-			true,
-		)
-
-		// 返回帶有 token 的回應
-		ctx.JSON(http.StatusOK, code.GetCodeMessage(code.Successful, userData))
-	} else {
-		// 登入失敗，直接返回錯誤結果
-		ctx.JSON(http.StatusOK, result)
 	}
+
+	ctx.JSON(http.StatusOK, result)
 }
 
 // Logout 登出
@@ -275,35 +146,131 @@ func (p *presenter) Delete(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, codeMessage)
 }
 
-// maskEmail 遮罩信箱地址
-func maskEmail(email string) string {
-	if email == "" {
-		return ""
+// Unlock 解除帳號鎖定 (僅限管理員)
+func (p *presenter) Unlock(ctx *gin.Context) {
+	userId := ctx.Param("userId")
+	input := &users.Field{}
+	input.ID = &userId
+
+	codeMessage := p.UserResolver.Unlock(input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// ChangePassword 變更自己的密碼
+func (p *presenter) ChangePassword(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	userID := ctx.GetString("userID")
+	input := &users.PasswordChanged{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
 	}
 
-	// 找到 @ 的位置
-	atIndex := -1
-	for i, c := range email {
-		if c == '@' {
-			atIndex = i
-			break
+	result := p.UserResolver.ChangePassword(trx, userID, input)
+
+	// 變更成功後以新的 token 取代舊的 token
+	successMsg, ok := result.(*code.SuccessfulMessage)
+	if ok && successMsg.Code == code.Successful {
+		if userData, ok := successMsg.Body.(*users.Base); ok {
+			ctx.SetCookie(
+				"token",
+				userData.Token,
+				int(24*time.Hour.Seconds()), // 24 小時
+				"/",
+				"",
+				false, // 開發環境設為 false，生產環境改為 true
+				true,
+			)
 		}
 	}
 
-	if atIndex < 0 {
-		return email
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ForgotPassword 忘記密碼 - 寄送重設密碼連結
+func (p *presenter) ForgotPassword(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &users.PasswordForgot{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	codeMessage := p.UserResolver.ForgotPassword(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// ResetPassword 以重設連結設定新密碼
+func (p *presenter) ResetPassword(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	input := &users.PasswordReset{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
+	}
+
+	codeMessage := p.UserResolver.ResetPassword(trx, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// SetupTOTP 產生 TOTP 金鑰(需再呼叫 ConfirmTOTP 才會生效)
+func (p *presenter) SetupTOTP(ctx *gin.Context) {
+	userID := ctx.GetString("userID")
+
+	codeMessage := p.UserResolver.SetupTOTP(userID)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// ConfirmTOTP 以驗證器 App 的第一組驗證碼確認綁定
+func (p *presenter) ConfirmTOTP(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	userID := ctx.GetString("userID")
+	input := &users.TOTPConfirm{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
 	}
 
-	// 保留前 2 個字元和 @ 後面的部分
-	if atIndex <= 2 {
-		return email
+	codeMessage := p.UserResolver.ConfirmTOTP(trx, userID, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// DisableTOTP 停用 TOTP,改回 email 驗證碼
+func (p *presenter) DisableTOTP(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	userID := ctx.GetString("userID")
+	input := &users.TOTPDisable{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
 	}
 
-	masked := email[:2]
-	for i := 2; i < atIndex; i++ {
-		masked += "*"
+	codeMessage := p.UserResolver.DisableTOTP(trx, userID, input)
+	ctx.JSON(http.StatusOK, codeMessage)
+}
+
+// RegenerateRecoveryCodes 重新產生復原碼
+func (p *presenter) RegenerateRecoveryCodes(ctx *gin.Context) {
+	trx := ctx.MustGet("db_trx").(*gorm.DB)
+	userID := ctx.GetString("userID")
+	input := &users.TOTPConfirm{}
+
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		log.Error(err)
+		ctx.JSON(http.StatusBadRequest, code.GetCodeMessage(code.FormatError, err.Error()))
+		return
 	}
-	masked += email[atIndex:]
 
-	return masked
+	codeMessage := p.UserResolver.RegenerateRecoveryCodes(trx, userID, input)
+	ctx.JSON(http.StatusOK, codeMessage)
 }
@@ -0,0 +1,119 @@
+package api_key
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/service/api_key"
+	model "esst_sendEmail/internal/v1/structure/api_keys"
+	userModel "esst_sendEmail/internal/v1/structure/users"
+
+	"gorm.io/gorm"
+)
+
+// Create 建立金鑰,一般使用者只能替自己建立,管理員可指定擁有者
+func (r *resolver) Create(trx *gorm.DB, requester *Requester, input *model.Created) interface{} {
+	defer trx.Rollback()
+
+	if input.UserID == "" {
+		input.UserID = requester.UserID
+	}
+
+	if input.UserID != requester.UserID {
+		if requester.Role != "admin" {
+			return code.GetCodeMessage(code.PermissionDenied, "Admin permission required")
+		}
+
+		_, err := r.UserService.GetByID(&userModel.Field{ID: &input.UserID})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return code.GetCodeMessage(code.DoesNotExist, err)
+			}
+
+			log.Error(err)
+			return code.GetCodeMessage(code.InternalServerError, err.Error())
+		}
+	}
+
+	issued, err := r.APIKeyService.WithTrx(trx).Create(input)
+	if err != nil {
+		if errors.Is(err, api_key.ErrInvalidScope) {
+			return code.GetCodeMessage(code.FormatError, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	trx.Commit()
+	return code.GetCodeMessage(code.Successful, issued)
+}
+
+func (r *resolver) GetByID(requester *Requester, input *model.Field) interface{} {
+	restrictToOwner(requester, &input.UserID)
+
+	apiKey, err := r.APIKeyService.GetByID(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err)
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err)
+	}
+
+	return code.GetCodeMessage(code.Successful, apiKey)
+}
+
+// List 一般使用者只能查詢自己的金鑰,管理員可查詢全部或以 user_id 篩選
+func (r *resolver) List(requester *Requester, input *model.Fields) interface{} {
+	restrictToOwner(requester, &input.UserID)
+
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	quantity, apiKeys, err := r.APIKeyService.List(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Pages = util.Pagination(quantity, output.Limit)
+	output.Total = quantity
+	output.APIKeys = apiKeys
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+// Revoke 撤銷金鑰,擁有者或管理員可操作
+func (r *resolver) Revoke(requester *Requester, input *model.Field) interface{} {
+	restrictToOwner(requester, &input.UserID)
+
+	_, err := r.APIKeyService.GetByID(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err)
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err)
+	}
+
+	err = r.APIKeyService.Revoke(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err)
+	}
+
+	return code.GetCodeMessage(code.Successful, "Revoke ok!")
+}
+
+// restrictToOwner 非管理員只能存取自己的金鑰
+func restrictToOwner(requester *Requester, userID **string) {
+	if requester.Role != "admin" {
+		*userID = &requester.UserID
+	}
+}
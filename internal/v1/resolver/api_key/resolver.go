@@ -0,0 +1,34 @@
+package api_key
+
+import (
+	"esst_sendEmail/internal/v1/service/api_key"
+	"esst_sendEmail/internal/v1/service/user"
+	model "esst_sendEmail/internal/v1/structure/api_keys"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	Create(trx *gorm.DB, requester *Requester, input *model.Created) interface{}
+	GetByID(requester *Requester, input *model.Field) interface{}
+	List(requester *Requester, input *model.Fields) interface{}
+	Revoke(requester *Requester, input *model.Field) interface{}
+}
+
+// Requester 目前登入的使用者
+type Requester struct {
+	UserID string
+	Role   string
+}
+
+type resolver struct {
+	APIKeyService api_key.Service
+	UserService   user.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		APIKeyService: api_key.New(db),
+		UserService:   user.New(db),
+	}
+}
@@ -0,0 +1,82 @@
+package approval_rule
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/approval_rules"
+
+	"gorm.io/gorm"
+)
+
+func (r *resolver) Create(input *model.Created) interface{} {
+	rule, err := r.ApprovalRuleService.Create(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, rule)
+}
+
+func (r *resolver) List(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	total, rules, err := r.ApprovalRuleService.List(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Rules = rules
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) GetByID(input *model.Field) interface{} {
+	rule, err := r.ApprovalRuleService.GetByID(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, rule)
+}
+
+func (r *resolver) Update(input *model.Updated) interface{} {
+	rule, err := r.ApprovalRuleService.Update(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, rule)
+}
+
+func (r *resolver) Delete(input *model.Field) interface{} {
+	err := r.ApprovalRuleService.Delete(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, "Delete ok!")
+}
@@ -0,0 +1,26 @@
+package approval_rule
+
+import (
+	"esst_sendEmail/internal/v1/service/approval_rule"
+	model "esst_sendEmail/internal/v1/structure/approval_rules"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	Create(input *model.Created) interface{}
+	List(input *model.Fields) interface{}
+	GetByID(input *model.Field) interface{}
+	Update(input *model.Updated) interface{}
+	Delete(input *model.Field) interface{}
+}
+
+type resolver struct {
+	ApprovalRuleService approval_rule.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		ApprovalRuleService: approval_rule.New(db),
+	}
+}
@@ -0,0 +1,79 @@
+package attachment
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/service/attachment"
+	model "esst_sendEmail/internal/v1/structure/attachments"
+
+	"gorm.io/gorm"
+)
+
+func (r *resolver) Create(input *model.Created) interface{} {
+	output, err := r.AttachmentService.Create(input)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		case errors.Is(err, attachment.ErrTooLarge), errors.Is(err, attachment.ErrTypeNotAllowed):
+			return code.GetCodeMessage(code.UnprocessableEntity, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) List(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	total, attachments, err := r.AttachmentService.List(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Attachments = attachments
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) Download(input *model.Field) interface{} {
+	output, err := r.AttachmentService.Download(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) Delete(input *model.Field) interface{} {
+	err := r.AttachmentService.Delete(input)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		case errors.Is(err, attachment.ErrNotUploader):
+			return code.GetCodeMessage(code.PermissionDenied, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, "Delete ok!")
+}
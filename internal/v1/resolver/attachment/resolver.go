@@ -0,0 +1,25 @@
+package attachment
+
+import (
+	"esst_sendEmail/internal/v1/service/attachment"
+	model "esst_sendEmail/internal/v1/structure/attachments"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	Create(input *model.Created) interface{}
+	List(input *model.Fields) interface{}
+	Download(input *model.Field) interface{}
+	Delete(input *model.Field) interface{}
+}
+
+type resolver struct {
+	AttachmentService attachment.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		AttachmentService: attachment.New(db),
+	}
+}
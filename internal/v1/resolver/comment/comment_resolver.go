@@ -0,0 +1,83 @@
+package comment
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/service/comment"
+	model "esst_sendEmail/internal/v1/structure/comments"
+
+	"gorm.io/gorm"
+)
+
+func (r *resolver) Create(input *model.Created) interface{} {
+	output, mentions, err := r.CommentService.Create(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	go r.CommentService.NotifyMentions(output, mentions)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) List(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	total, comments, err := r.CommentService.List(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Comments = comments
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) Update(input *model.Updated) interface{} {
+	output, mentions, err := r.CommentService.Update(input)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		case errors.Is(err, comment.ErrNotAuthor):
+			return code.GetCodeMessage(code.PermissionDenied, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	go r.CommentService.NotifyMentions(output, mentions)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) Delete(input *model.Field) interface{} {
+	err := r.CommentService.Delete(input)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		case errors.Is(err, comment.ErrNotAuthor):
+			return code.GetCodeMessage(code.PermissionDenied, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, "Delete ok!")
+}
@@ -0,0 +1,25 @@
+package comment
+
+import (
+	"esst_sendEmail/internal/v1/service/comment"
+	model "esst_sendEmail/internal/v1/structure/comments"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	Create(input *model.Created) interface{}
+	List(input *model.Fields) interface{}
+	Update(input *model.Updated) interface{}
+	Delete(input *model.Field) interface{}
+}
+
+type resolver struct {
+	CommentService comment.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		CommentService: comment.New(db),
+	}
+}
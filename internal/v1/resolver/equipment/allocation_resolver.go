@@ -0,0 +1,26 @@
+package equipment
+
+import (
+	"fmt"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+
+	"gorm.io/gorm"
+)
+
+// checkAllocated 設備數量低於已由現貨支應的數量時回傳 409 訊息,需先取消部分分配
+// 在交易中鎖定設備後才計算已分配數量,避免與分配同時進行造成超額
+func (r *resolver) checkAllocated(trx *gorm.DB, equipmentID string, quantity int64) interface{} {
+	allocated, err := r.StockAllocationService.WithTrx(trx).Allocated(equipmentID)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	if quantity < allocated {
+		return code.GetCodeMessage(code.Conflict, fmt.Sprintf("已由現貨支應 %d 個,數量不可低於已分配數量", allocated))
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package equipment
+
+import (
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	partModel "esst_sendEmail/internal/v1/structure/parts"
+	conflictModel "esst_sendEmail/internal/v1/structure/project_conflicts"
+
+	"gorm.io/gorm"
+)
+
+// detectPartDuplicates 新增設備後以專案目前的所有料號檢查與其他專案的料號重疊,並加入審核佇列
+// 名稱與聯絡方式已於建立專案時檢查,這裡只比對料號;block 模式下發現重疊時回傳 409 訊息
+func (r *resolver) detectPartDuplicates(trx *gorm.DB, projectID string) ([]*conflictModel.Conflict, interface{}) {
+	equipments, err := r.EquipmentService.WithTrx(trx).ListByProjectID(projectID)
+	if err != nil {
+		log.Error(err)
+		return nil, code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	partNumbers := make([]string, 0, len(equipments))
+	for _, eq := range equipments {
+		partNumbers = append(partNumbers, eq.PartNumber)
+	}
+
+	conflicts, blocked := r.ProjectConflictService.WithTrx(trx).Check(&conflictModel.Checked{
+		PartNumbers:      partNumbers,
+		ExcludeProjectID: projectID,
+	})
+	if blocked {
+		return nil, code.GetCodeMessage(code.Conflict, conflicts)
+	}
+
+	err = r.ProjectConflictService.WithTrx(trx).Record(projectID, conflicts)
+	if err != nil {
+		return nil, code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return conflicts, nil
+}
+
+// warningMessage 成功的回傳訊息,附上無法對應到料號目錄的設備與料號重疊的專案
+func warningMessage(data interface{}, unmatched []*partModel.Unmatched, conflicts []*conflictModel.Conflict) interface{} {
+	message := code.GetCodeMessage(code.Successful, data).(*code.SuccessfulMessage)
+	warnings := make([]interface{}, 0, len(conflicts)+len(unmatched))
+	for _, conflict := range conflicts {
+		warnings = append(warnings, conflict)
+	}
+	for _, line := range unmatched {
+		warnings = append(warnings, line)
+	}
+	if len(warnings) > 0 {
+		message.Warnings = warnings
+	}
+	return message
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 
 	"esst_sendEmail/internal/pkg/code"
-	"esst_sendEmail/internal/pkg/linebot"
 	"esst_sendEmail/internal/pkg/log"
 	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/service/part"
+	"esst_sendEmail/internal/v1/structure"
 	model "esst_sendEmail/internal/v1/structure/equipments"
+	approvalModel "esst_sendEmail/internal/v1/structure/project_approvals"
 
 	"gorm.io/gorm"
 )
@@ -16,20 +18,28 @@ import (
 func (r *resolver) Create(trx *gorm.DB, input *model.Created) interface{} {
 	defer trx.Rollback()
 
+	// 料號目錄比對
+	equipments := []model.BatchEquipment{{PartID: input.PartID, PartNumber: input.PartNumber}}
+	unmatched, err := part.MatchBatch(r.PartService, equipments)
+	if err != nil {
+		return code.GetCodeMessage(code.UnprocessableEntity, unmatched)
+	}
+	input.PartID, input.PartNumber = equipments[0].PartID, equipments[0].PartNumber
+
 	equipment, err := r.EquipmentService.WithTrx(trx).Create(input)
 	if err != nil {
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err.Error())
 	}
 
-	trx.Commit()
-	return code.GetCodeMessage(code.Successful, equipment.EquipmentID)
-}
-
-func (r *resolver) CreateBatch(trx *gorm.DB, input *model.BatchCreated) interface{} {
-	defer trx.Rollback()
+	// 重複報備檢查(料號重疊)
+	conflicts, blocked := r.detectPartDuplicates(trx, input.ProjectID)
+	if blocked != nil {
+		return blocked
+	}
 
-	err := r.EquipmentService.WithTrx(trx).CreateBatch(input)
+	// 數量增加,依規則重新計算審核關卡並重新送審
+	approval, err := r.ProjectApprovalService.WithTrx(trx).Submit(input.ProjectID, true)
 	if err != nil {
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err.Error())
@@ -37,63 +47,48 @@ func (r *resolver) CreateBatch(trx *gorm.DB, input *model.BatchCreated) interfac
 
 	trx.Commit()
 
-	// 設備建立完成後,發送第一階段 LINE 通知
-	go r.sendProjectStep1LineNotification(input.ProjectID)
+	go r.ProjectApprovalService.NotifyApprovers(approval)
 
-	return code.GetCodeMessage(code.Successful, "Batch created successfully")
+	return warningMessage(equipment.EquipmentID, unmatched, conflicts)
 }
 
-// sendProjectStep1LineNotification 發送第一階段 LINE 通知
-func (r *resolver) sendProjectStep1LineNotification(projectID string) {
-	log.Info("Preparing to send step1 LINE notification for project:", projectID)
+func (r *resolver) CreateBatch(trx *gorm.DB, input *model.BatchCreated) interface{} {
+	defer trx.Rollback()
 
-	// 查詢專案資訊
-	projectField := &Field{ProjectID: projectID}
-	project, err := r.ProjectService.GetByID(projectField.ToProjectField())
+	// 料號目錄比對
+	unmatched, err := part.MatchBatch(r.PartService, input.Equipments)
 	if err != nil {
-		log.Error("Failed to query project for LINE notification:", err)
-		return
+		return code.GetCodeMessage(code.UnprocessableEntity, unmatched)
 	}
 
-	// 查詢設備清單
-	equipments, err := r.EquipmentService.ListByProjectID(projectID)
+	err = r.EquipmentService.WithTrx(trx).CreateBatch(input)
 	if err != nil {
-		log.Error("Failed to query equipments for LINE notification:", err)
-		return
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
 	}
 
-	// 轉換設備資料格式
-	lineEquipments := make([]linebot.Equipment, 0)
-	if equipments != nil {
-		for _, eq := range equipments {
-			lineEquipments = append(lineEquipments, linebot.Equipment{
-				PartNumber:  eq.PartNumber,
-				Quantity:    eq.Quantity,
-				Description: eq.Description,
-			})
-		}
+	// 重複報備檢查(料號重疊),分兩次建立時建立專案的當下還沒有料號
+	conflicts, blocked := r.detectPartDuplicates(trx, input.ProjectID)
+	if blocked != nil {
+		return blocked
 	}
 
-	log.Info("Found", len(lineEquipments), "equipments for project", projectID)
-
-	notificationData := &linebot.ProjectStep1Data{
-		ProjectID:    project.ProjectID,
-		ProjectName:  project.ProjectName,
-		ContactName:  project.ContactName,
-		ContactPhone: project.ContactPhone,
-		ContactEmail: project.ContactEmail,
-		Owner:        project.Owner,
-		Remark:       project.Remark,
-		Equipments:   lineEquipments,
-		CreatedTime:  project.CreatedTime,
+	// 依設備數量建立審核關卡,已核准的專案新增設備時重新送審
+	approval, err := r.ProjectApprovalService.WithTrx(trx).Submit(input.ProjectID, true)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
 	}
 
-	lineBotService := linebot.New()
-	if err := lineBotService.SendProjectStep1Notification(notificationData); err != nil {
-		log.Error("Failed to send step1 LINE notification:", err)
-	} else {
-		log.Info("Step1 LINE notification sent successfully for project:", projectID)
-	}
+	trx.Commit()
+
+	// 設備建立完成後,發送第一階段 LINE 通知並通知審核者
+	go func() {
+		r.ProjectService.NotifyStep1(input.ProjectID, r.QuotationService.LinePricing(input.ProjectID))
+		r.ProjectApprovalService.NotifyApprovers(approval)
+	}()
+
+	return warningMessage("Batch created successfully", unmatched, conflicts)
 }
 
 func (r *resolver) List(input *model.Fields) interface{} {
@@ -155,7 +150,9 @@ func (r *resolver) GetByID(input *model.Field) interface{} {
 	return code.GetCodeMessage(code.Successful, frontEquipment)
 }
 
-func (r *resolver) Update(input *model.Updated) interface{} {
+func (r *resolver) Update(trx *gorm.DB, input *model.Updated) interface{} {
+	defer trx.Rollback()
+
 	equipment, err := r.EquipmentService.GetByID(&model.Field{EquipmentID: input.EquipmentID})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -166,17 +163,57 @@ func (r *resolver) Update(input *model.Updated) interface{} {
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
-	err = r.EquipmentService.Update(input)
+	// 版本不符表示已被其他人修改,回傳目前的資料
+	if equipment.Version != input.Version {
+		return r.versionConflict(&model.Field{EquipmentID: input.EquipmentID})
+	}
+
+	// 料號目錄比對
+	equipments := []model.BatchEquipment{{PartID: input.PartID, PartNumber: input.PartNumber}}
+	unmatched, err := part.MatchBatch(r.PartService, equipments)
 	if err != nil {
+		return code.GetCodeMessage(code.UnprocessableEntity, unmatched)
+	}
+	input.PartID, input.PartNumber = equipments[0].PartID, equipments[0].PartNumber
+
+	// 已由現貨支應的數量不可被調低
+	if input.Quantity < equipment.Quantity {
+		if blocked := r.checkAllocated(trx, input.EquipmentID, input.Quantity); blocked != nil {
+			return blocked
+		}
+	}
+
+	err = r.EquipmentService.WithTrx(trx).Update(input)
+	if err != nil {
+		if errors.Is(err, structure.ErrVersionConflict) {
+			return r.versionConflict(&model.Field{EquipmentID: input.EquipmentID})
+		}
+
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
-	return code.GetCodeMessage(code.Successful, equipment.EquipmentID)
+	// 數量改變時依規則重新計算審核關卡,數量增加時重新送審
+	var approval *approvalModel.Base
+	if input.Quantity != equipment.Quantity {
+		approval, err = r.ProjectApprovalService.WithTrx(trx).Submit(equipment.ProjectID, input.Quantity > equipment.Quantity)
+		if err != nil {
+			log.Error(err)
+			return code.GetCodeMessage(code.InternalServerError, err)
+		}
+	}
+
+	trx.Commit()
+
+	go r.ProjectApprovalService.NotifyApprovers(approval)
+
+	return partMessage(equipment.EquipmentID, unmatched)
 }
 
-func (r *resolver) Delete(input *model.Updated) interface{} {
-	_, err := r.EquipmentService.GetByID(&model.Field{EquipmentID: input.EquipmentID})
+func (r *resolver) Delete(trx *gorm.DB, input *model.Updated) interface{} {
+	defer trx.Rollback()
+
+	current, err := r.EquipmentService.GetByID(&model.Field{EquipmentID: input.EquipmentID})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return code.GetCodeMessage(code.DoesNotExist, err)
@@ -186,11 +223,30 @@ func (r *resolver) Delete(input *model.Updated) interface{} {
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
-	err = r.EquipmentService.Delete(input)
+	// 版本不符表示已被其他人修改,回傳目前的資料
+	if current.Version != input.Version {
+		return r.versionConflict(&model.Field{EquipmentID: input.EquipmentID})
+	}
+
+	err = r.EquipmentService.WithTrx(trx).Delete(input)
 	if err != nil {
+		if errors.Is(err, structure.ErrVersionConflict) {
+			return r.versionConflict(&model.Field{EquipmentID: input.EquipmentID})
+		}
+
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
+	// 數量減少,依規則重新計算審核關卡(取消不再需要的關卡)
+	approval, err := r.ProjectApprovalService.WithTrx(trx).Submit(current.ProjectID, false)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err)
+	}
+
+	trx.Commit()
+	go r.ProjectApprovalService.NotifyApprovers(approval)
+
 	return code.GetCodeMessage(code.Successful, "Delete ok!")
 }
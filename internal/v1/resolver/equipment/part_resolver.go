@@ -0,0 +1,15 @@
+package equipment
+
+import (
+	"esst_sendEmail/internal/pkg/code"
+	partModel "esst_sendEmail/internal/v1/structure/parts"
+)
+
+// partMessage 成功的回傳訊息,有無法對應到料號目錄的設備時附上建議料號
+func partMessage(data interface{}, unmatched []*partModel.Unmatched) interface{} {
+	message := code.GetCodeMessage(code.Successful, data).(*code.SuccessfulMessage)
+	if len(unmatched) > 0 {
+		message.Warnings = unmatched
+	}
+	return message
+}
@@ -0,0 +1,126 @@
+package equipment
+
+import (
+	"errors"
+	"time"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/v1/service/part"
+	"esst_sendEmail/internal/v1/structure"
+	changeModel "esst_sendEmail/internal/v1/structure/equipment_changes"
+	model "esst_sendEmail/internal/v1/structure/equipments"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+
+	"gorm.io/gorm"
+)
+
+// Replace 以完整清單取代專案設備,新增、修改、刪除在同一交易中完成
+func (r *resolver) Replace(trx *gorm.DB, input *model.Replaced) interface{} {
+	defer trx.Rollback()
+
+	project, err := r.ProjectService.GetByID(&projectModel.Field{ProjectID: input.ProjectID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	// 料號目錄比對
+	equipments := make([]model.BatchEquipment, 0, len(input.Equipments))
+	for _, eq := range input.Equipments {
+		equipments = append(equipments, eq.BatchEquipment)
+	}
+	unmatched, err := part.MatchBatch(r.PartService, equipments)
+	if err != nil {
+		return code.GetCodeMessage(code.UnprocessableEntity, unmatched)
+	}
+	for i := range input.Equipments {
+		input.Equipments[i].PartID, input.Equipments[i].PartNumber = equipments[i].PartID, equipments[i].PartNumber
+	}
+
+	diff, err := r.EquipmentService.WithTrx(trx).Replace(input)
+	if err != nil {
+		return r.replaceError(input.ProjectID, err)
+	}
+
+	if !diff.Changed() {
+		return partMessage(diff, unmatched)
+	}
+
+	// 已由現貨支應的數量不可被調低
+	for _, change := range diff.Updated {
+		if change.After.Quantity < change.Before.Quantity {
+			if blocked := r.checkAllocated(trx, change.After.EquipmentID, change.After.Quantity); blocked != nil {
+				return blocked
+			}
+		}
+	}
+
+	// 重複報備檢查(料號重疊)
+	conflicts, blocked := r.detectPartDuplicates(trx, input.ProjectID)
+	if blocked != nil {
+		return blocked
+	}
+
+	// 依設備數量重新計算審核關卡,總數量增加時已核准的關卡需重新審核
+	approval, err := r.ProjectApprovalService.WithTrx(trx).Submit(input.ProjectID, diff.QuantityDelta() > 0)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	trx.Commit()
+
+	go func() {
+		if input.Notify {
+			r.EquipmentChangeResolver.Notify(&linebot.EquipmentChangeData{
+				TargetType: changeModel.TargetProject,
+				TargetID:   project.ProjectID,
+				TargetName: project.ProjectName,
+				ChangedBy:  input.ChangedByName,
+				ChangedAt:  time.Now(),
+			}, diff.Entries())
+		}
+		r.ProjectApprovalService.NotifyApprovers(approval)
+	}()
+
+	return warningMessage(diff, unmatched, conflicts)
+}
+
+// History 專案設備的異動紀錄
+func (r *resolver) History(input *changeModel.Fields) interface{} {
+	_, err := r.ProjectService.GetByID(&projectModel.Field{ProjectID: input.TargetID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	input.TargetType = changeModel.TargetProject
+	return r.EquipmentChangeResolver.History(input)
+}
+
+// replaceError 取代清單的錯誤訊息,版本不符時回傳 412 並附上目前的設備清單
+func (r *resolver) replaceError(projectID string, err error) interface{} {
+	switch {
+	case errors.Is(err, changeModel.ErrUnknownLine), errors.Is(err, changeModel.ErrDuplicateLine):
+		return code.GetCodeMessage(code.UnprocessableEntity, err.Error())
+	case errors.Is(err, structure.ErrVersionConflict):
+		current := r.ListByProjectID(projectID)
+		if message, ok := current.(*code.SuccessfulMessage); ok {
+			return code.GetCodeMessage(code.PreconditionFailed, message.Body)
+		}
+		return current
+	default:
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+}
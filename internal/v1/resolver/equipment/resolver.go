@@ -1,10 +1,16 @@
 package equipment
 
 import (
+	"esst_sendEmail/internal/v1/resolver/equipment_change"
 	"esst_sendEmail/internal/v1/service/equipment"
+	"esst_sendEmail/internal/v1/service/part"
 	"esst_sendEmail/internal/v1/service/project"
+	"esst_sendEmail/internal/v1/service/project_approval"
+	"esst_sendEmail/internal/v1/service/project_conflict"
+	"esst_sendEmail/internal/v1/service/quotation"
+	"esst_sendEmail/internal/v1/service/stock_allocation"
+	changeModel "esst_sendEmail/internal/v1/structure/equipment_changes"
 	model "esst_sendEmail/internal/v1/structure/equipments"
-	projectModel "esst_sendEmail/internal/v1/structure/projects"
 
 	"gorm.io/gorm"
 )
@@ -15,30 +21,34 @@ type Resolver interface {
 	List(input *model.Fields) interface{}
 	ListByProjectID(projectID string) interface{}
 	GetByID(input *model.Field) interface{}
-	Update(input *model.Updated) interface{}
-	Delete(input *model.Updated) interface{}
+	Update(trx *gorm.DB, input *model.Updated) interface{}
+	Delete(trx *gorm.DB, input *model.Updated) interface{}
+	Trash(input *model.Fields) interface{}
+	Restore(trx *gorm.DB, input *model.Field) interface{}
+	Replace(trx *gorm.DB, input *model.Replaced) interface{}
+	History(input *changeModel.Fields) interface{}
 }
 
 type resolver struct {
-	EquipmentService equipment.Service
-	ProjectService   project.Service
-}
-
-// Field 用於查詢專案
-type Field struct {
-	ProjectID string
+	EquipmentService        equipment.Service
+	ProjectService          project.Service
+	ProjectApprovalService  project_approval.Service
+	ProjectConflictService  project_conflict.Service
+	PartService             part.Service
+	StockAllocationService  stock_allocation.Service
+	QuotationService        quotation.Service
+	EquipmentChangeResolver equipment_change.Resolver
 }
 
 func New(db *gorm.DB) Resolver {
 	return &resolver{
-		EquipmentService: equipment.New(db),
-		ProjectService:   project.New(db),
-	}
-}
-
-// GetByID 的輔助方法
-func (f *Field) ToProjectField() *projectModel.Field {
-	return &projectModel.Field{
-		ProjectID: f.ProjectID,
+		EquipmentService:        equipment.New(db),
+		ProjectService:          project.New(db),
+		ProjectApprovalService:  project_approval.New(db),
+		ProjectConflictService:  project_conflict.New(db),
+		PartService:             part.New(db),
+		StockAllocationService:  stock_allocation.New(db),
+		QuotationService:        quotation.New(db),
+		EquipmentChangeResolver: equipment_change.New(db),
 	}
 }
@@ -0,0 +1,86 @@
+package equipment
+
+import (
+	"encoding/json"
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/equipments"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+
+	"gorm.io/gorm"
+)
+
+// Trash 資源回收筒中的設備(管理員)
+func (r *resolver) Trash(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	quantity, records, err := r.EquipmentService.ListDeleted(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	recordsByte, err := json.Marshal(records)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Total = quantity
+	output.Pages = util.Pagination(quantity, output.Limit)
+	err = json.Unmarshal(recordsByte, &output.Equipments)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+// Restore 從資源回收筒還原設備(管理員),所屬專案已刪除時需先還原專案
+func (r *resolver) Restore(trx *gorm.DB, input *model.Field) interface{} {
+	defer trx.Rollback()
+
+	deleted, err := r.EquipmentService.GetDeletedByID(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, "設備不在資源回收筒中")
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	_, err = r.ProjectService.GetByID(&projectModel.Field{ProjectID: deleted.ProjectID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, "所屬專案已刪除,請先還原專案")
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	err = r.EquipmentService.WithTrx(trx).Restore(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	// 還原使設備總數量增加,已核准的關卡需重新審核
+	approval, err := r.ProjectApprovalService.WithTrx(trx).Submit(deleted.ProjectID, true)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	trx.Commit()
+	go r.ProjectApprovalService.NotifyApprovers(approval)
+
+	return code.GetCodeMessage(code.Successful, input.EquipmentID)
+}
@@ -0,0 +1,73 @@
+package equipment_change
+
+import (
+	"encoding/json"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/equipment_changes"
+)
+
+// History 專案或現貨設備的異動紀錄(對象是否存在由呼叫端檢查)
+func (r *resolver) History(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	quantity, changes, err := r.EquipmentChangeService.List(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Pages = util.Pagination(quantity, output.Limit)
+	output.Changes = changes
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+// Notify 發送設備清單異動 LINE 通知
+func (r *resolver) Notify(data *linebot.EquipmentChangeData, entries []model.Entry) {
+	for _, entry := range entries {
+		switch entry.Action {
+		case model.ActionAdd:
+			data.Added = append(data.Added, toLine(entry.After))
+		case model.ActionUpdate:
+			data.Updated = append(data.Updated, linebot.EquipmentChange{Before: toLine(entry.Before), After: toLine(entry.After)})
+		case model.ActionDelete:
+			data.Deleted = append(data.Deleted, toLine(entry.Before))
+		}
+	}
+
+	lineBotService := linebot.New()
+	if err := lineBotService.SendEquipmentChangeNotification(data); err != nil {
+		log.Error("Failed to send equipment change LINE notification:", err)
+	} else {
+		log.Info("Equipment change LINE notification sent successfully for "+data.TargetType+":", data.TargetID)
+	}
+}
+
+// toLine 專案設備或現貨設備轉為通知中的設備
+func toLine(eq interface{}) linebot.Equipment {
+	line := struct {
+		PartNumber  string `json:"part_number"`
+		Quantity    int64  `json:"quantity"`
+		Description string `json:"description"`
+	}{}
+
+	marshal, err := json.Marshal(eq)
+	if err == nil {
+		err = json.Unmarshal(marshal, &line)
+	}
+	if err != nil {
+		log.Error(err)
+	}
+
+	return linebot.Equipment{
+		PartNumber:  line.PartNumber,
+		Quantity:    line.Quantity,
+		Description: line.Description,
+	}
+}
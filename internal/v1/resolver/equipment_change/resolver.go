@@ -0,0 +1,24 @@
+package equipment_change
+
+import (
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/v1/service/equipment_change"
+	model "esst_sendEmail/internal/v1/structure/equipment_changes"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	History(input *model.Fields) interface{}
+	Notify(data *linebot.EquipmentChangeData, entries []model.Entry)
+}
+
+type resolver struct {
+	EquipmentChangeService equipment_change.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		EquipmentChangeService: equipment_change.New(db),
+	}
+}
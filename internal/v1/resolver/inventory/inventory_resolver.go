@@ -0,0 +1,103 @@
+package inventory
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/service/inventory"
+	model "esst_sendEmail/internal/v1/structure/inventories"
+	movementModel "esst_sendEmail/internal/v1/structure/inventory_movements"
+
+	"gorm.io/gorm"
+)
+
+func (r *resolver) List(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	total, inventories, err := r.InventoryService.List(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Inventories = inventories
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) GetByID(input *model.Field) interface{} {
+	output, err := r.InventoryService.GetByID(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) ListMovements(input *movementModel.Fields) interface{} {
+	output := &movementModel.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	total, movements, err := r.InventoryService.ListMovements(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Movements = movements
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+// Record 登錄進貨、出貨或盤點調整
+func (r *resolver) Record(trx *gorm.DB, input *movementModel.Created) interface{} {
+	defer trx.Rollback()
+
+	output, err := r.InventoryService.WithTrx(trx).Record(input)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return code.GetCodeMessage(code.DoesNotExist, "現貨設備不存在")
+		case errors.Is(err, inventory.ErrInsufficient), errors.Is(err, inventory.ErrNotReserved), errors.Is(err, inventory.ErrBelowReserved):
+			return code.GetCodeMessage(code.Conflict, err.Error())
+		case errors.Is(err, inventory.ErrInvalidQuantity), errors.Is(err, inventory.ErrShipOnly):
+			return code.GetCodeMessage(code.UnprocessableEntity, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	trx.Commit()
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+// Sync 依現貨報備目前的設備重新計算庫存保留(INVENTORY_ENABLED 啟用時),供現貨報備與現貨設備共用
+// 可用數量不足時回傳 409 訊息與不足的料號,呼叫端不應提交交易;成功時回傳 nil
+func (r *resolver) Sync(trx *gorm.DB, stockID string) interface{} {
+	shortages, err := r.InventoryService.WithTrx(trx).Sync(stockID)
+	if err != nil {
+		if errors.Is(err, inventory.ErrInsufficient) {
+			return code.GetCodeMessage(code.Conflict, shortages)
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package inventory
+
+import (
+	"esst_sendEmail/internal/v1/service/inventory"
+	model "esst_sendEmail/internal/v1/structure/inventories"
+	movementModel "esst_sendEmail/internal/v1/structure/inventory_movements"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	List(input *model.Fields) interface{}
+	GetByID(input *model.Field) interface{}
+	ListMovements(input *movementModel.Fields) interface{}
+	Record(trx *gorm.DB, input *movementModel.Created) interface{}
+	Sync(trx *gorm.DB, stockID string) interface{}
+}
+
+type resolver struct {
+	InventoryService inventory.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		InventoryService: inventory.New(db),
+	}
+}
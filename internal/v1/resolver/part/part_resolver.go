@@ -0,0 +1,100 @@
+package part
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/service/part"
+	model "esst_sendEmail/internal/v1/structure/parts"
+
+	"gorm.io/gorm"
+)
+
+func (r *resolver) Create(input *model.Created) interface{} {
+	output, err := r.PartService.Create(input)
+	if err != nil {
+		if errors.Is(err, part.ErrPartNumberExists) {
+			return code.GetCodeMessage(code.Conflict, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) List(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	total, parts, err := r.PartService.List(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Parts = parts
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) GetByID(input *model.Field) interface{} {
+	output, err := r.PartService.GetByID(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) Update(input *model.Updated) interface{} {
+	output, err := r.PartService.Update(input)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		case errors.Is(err, part.ErrPartNumberExists):
+			return code.GetCodeMessage(code.Conflict, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) Delete(input *model.Field) interface{} {
+	err := r.PartService.Delete(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, "Delete ok!")
+}
+
+func (r *resolver) Suggest(input *model.Suggest) interface{} {
+	output, err := r.PartService.Suggest(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, output)
+}
@@ -0,0 +1,27 @@
+package part
+
+import (
+	"esst_sendEmail/internal/v1/service/part"
+	model "esst_sendEmail/internal/v1/structure/parts"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	Create(input *model.Created) interface{}
+	List(input *model.Fields) interface{}
+	GetByID(input *model.Field) interface{}
+	Update(input *model.Updated) interface{}
+	Delete(input *model.Field) interface{}
+	Suggest(input *model.Suggest) interface{}
+}
+
+type resolver struct {
+	PartService part.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		PartService: part.New(db),
+	}
+}
@@ -0,0 +1,35 @@
+package project
+
+import (
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/pkg/log"
+)
+
+// stockCoverage 第二階段通知的現貨支應摘要,沒有分配現貨或查詢失敗時回傳 nil(不顯示該區塊)
+func (r *resolver) stockCoverage(projectID string) *linebot.StockCoverage {
+	summary, err := r.StockAllocationService.Summary(projectID)
+	if err != nil {
+		log.Error("Failed to query stock allocations for step2 LINE notification:", err)
+		return nil
+	}
+
+	if summary.FromStock == 0 {
+		return nil
+	}
+
+	coverage := &linebot.StockCoverage{
+		FromStock: summary.FromStock,
+		ToOrder:   summary.ToOrder,
+		Lines:     make([]linebot.CoverageLine, 0, len(summary.Equipments)),
+	}
+	for _, eq := range summary.Equipments {
+		coverage.Lines = append(coverage.Lines, linebot.CoverageLine{
+			PartNumber: eq.PartNumber,
+			Quantity:   eq.Quantity,
+			FromStock:  eq.FromStock,
+			ToOrder:    eq.ToOrder,
+		})
+	}
+
+	return coverage
+}
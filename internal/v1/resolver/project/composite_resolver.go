@@ -0,0 +1,74 @@
+package project
+
+import (
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/v1/service/part"
+	equipmentModel "esst_sendEmail/internal/v1/structure/equipments"
+	conflictModel "esst_sendEmail/internal/v1/structure/project_conflicts"
+
+	"gorm.io/gorm"
+)
+
+// CreateWithEquipments 在同一個交易中建立專案與設備,成功後只發送一次第一階段通知
+func (r *resolver) CreateWithEquipments(trx *gorm.DB, input *equipmentModel.ProjectCreated) interface{} {
+	defer trx.Rollback()
+
+	// 料號目錄比對,重複報備檢查使用比對後的標準料號
+	unmatched, err := part.MatchBatch(r.PartService, input.Equipments)
+	if err != nil {
+		return code.GetCodeMessage(code.UnprocessableEntity, unmatched)
+	}
+
+	// 重複報備檢查
+	partNumbers := make([]string, 0, len(input.Equipments))
+	for _, eq := range input.Equipments {
+		partNumbers = append(partNumbers, eq.PartNumber)
+	}
+	conflicts, blocked := r.detectDuplicates(&conflictModel.Checked{
+		ProjectName:  input.ProjectName,
+		ContactEmail: input.ContactEmail,
+		ContactPhone: input.ContactPhone,
+		PartNumbers:  partNumbers,
+	})
+	if blocked != nil {
+		return blocked
+	}
+
+	project, err := r.ProjectService.WithTrx(trx).Create(&input.Created)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	err = r.ProjectConflictService.WithTrx(trx).Record(project.ProjectID, conflicts)
+	if err != nil {
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	err = r.EquipmentService.WithTrx(trx).CreateBatch(&equipmentModel.BatchCreated{
+		ProjectID:  project.ProjectID,
+		Equipments: input.Equipments,
+	})
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	// 依設備數量與建立者團隊送審
+	approval, err := r.ProjectApprovalService.WithTrx(trx).Submit(project.ProjectID, false)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	trx.Commit()
+
+	// 專案與設備皆建立完成後,發送第一階段 LINE 通知並通知審核者
+	go func() {
+		r.ProjectService.NotifyStep1(project.ProjectID, r.QuotationService.LinePricing(project.ProjectID))
+		r.ProjectApprovalService.NotifyApprovers(approval)
+	}()
+
+	return createdMessage(project.ProjectID, conflicts, unmatched)
+}
@@ -0,0 +1,33 @@
+package project
+
+import (
+	"esst_sendEmail/internal/pkg/code"
+	partModel "esst_sendEmail/internal/v1/structure/parts"
+	conflictModel "esst_sendEmail/internal/v1/structure/project_conflicts"
+)
+
+// detectDuplicates 重複報備檢查,block 模式下發現可能重複的專案時回傳 409 訊息(含重複專案與負責人)
+func (r *resolver) detectDuplicates(input *conflictModel.Checked) ([]*conflictModel.Conflict, interface{}) {
+	conflicts, blocked := r.ProjectConflictService.Check(input)
+	if blocked {
+		return nil, code.GetCodeMessage(code.Conflict, conflicts)
+	}
+
+	return conflicts, nil
+}
+
+// createdMessage 建立成功的回傳訊息,有可能重複的專案或無法對應到料號目錄的設備時附上警告
+func createdMessage(projectID string, conflicts []*conflictModel.Conflict, unmatched []*partModel.Unmatched) interface{} {
+	message := code.GetCodeMessage(code.Successful, projectID).(*code.SuccessfulMessage)
+	warnings := make([]interface{}, 0, len(conflicts)+len(unmatched))
+	for _, conflict := range conflicts {
+		warnings = append(warnings, conflict)
+	}
+	for _, line := range unmatched {
+		warnings = append(warnings, line)
+	}
+	if len(warnings) > 0 {
+		message.Warnings = warnings
+	}
+	return message
+}
@@ -9,6 +9,8 @@ import (
 	"esst_sendEmail/internal/pkg/linebot"
 	"esst_sendEmail/internal/pkg/log"
 	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/structure"
+	conflictModel "esst_sendEmail/internal/v1/structure/project_conflicts"
 	model "esst_sendEmail/internal/v1/structure/projects"
 
 	"gorm.io/gorm"
@@ -17,18 +19,33 @@ import (
 func (r *resolver) Create(trx *gorm.DB, input *model.Created) interface{} {
 	defer trx.Rollback()
 
+	// 重複報備檢查(分兩次建立時尚無料號,只比對名稱與聯絡方式,料號於新增設備時再檢查)
+	conflicts, blocked := r.detectDuplicates(&conflictModel.Checked{
+		ProjectName:  input.ProjectName,
+		ContactEmail: input.ContactEmail,
+		ContactPhone: input.ContactPhone,
+	})
+	if blocked != nil {
+		return blocked
+	}
+
 	project, err := r.ProjectService.WithTrx(trx).Create(input)
 	if err != nil {
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err.Error())
 	}
 
+	err = r.ProjectConflictService.WithTrx(trx).Record(project.ProjectID, conflicts)
+	if err != nil {
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
 	trx.Commit()
 
-	// 注意: 第一階段 LINE 通知將在設備建立完成後發送
+	// 注意: 第一階段 LINE 通知與送審將在設備建立完成後進行,審核關卡依設備數量決定
 	// 見 equipment/equipment_resolver.go 的 CreateBatch 函數
 
-	return code.GetCodeMessage(code.Successful, project.ProjectID)
+	return createdMessage(project.ProjectID, conflicts, nil)
 }
 
 func (r *resolver) List(input *model.Fields) interface{} {
@@ -80,7 +97,9 @@ func (r *resolver) GetByID(input *model.Field) interface{} {
 	return code.GetCodeMessage(code.Successful, frontProject)
 }
 
-func (r *resolver) Update(input *model.Updated) interface{} {
+func (r *resolver) Update(trx *gorm.DB, input *model.Updated) interface{} {
+	defer trx.Rollback()
+
 	// 驗證專案是否存在
 	project, err := r.ProjectService.GetByID(&model.Field{ProjectID: input.ProjectID})
 	if err != nil {
@@ -92,6 +111,19 @@ func (r *resolver) Update(input *model.Updated) interface{} {
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
+	// 版本不符表示已被其他人修改,回傳目前的資料
+	if project.Version != input.Version {
+		return r.versionConflict(&model.Field{ProjectID: input.ProjectID})
+	}
+
+	// 保護期已過的專案需先申請延長;expired 只能由排程設定
+	if project.Status == model.StatusExpired {
+		return code.GetCodeMessage(code.Conflict, "報備保護期已過,請先申請延長")
+	}
+	if input.Status == model.StatusExpired {
+		return code.GetCodeMessage(code.FormatError, "狀態不可設為 expired")
+	}
+
 	// 檢查是否為第二階段更新
 	isStep2Update := false
 	if input.ExpectedDeliveryPeriod != "" ||
@@ -108,13 +140,26 @@ func (r *resolver) Update(input *model.Updated) interface{} {
 		}
 	}
 
+	// 報備需核准後才能進入第二階段
+	if isStep2Update || input.Status == model.StatusStep2 || input.Status == model.StatusCompleted {
+		if project.ApprovalStatus != model.ApprovalApproved {
+			return code.GetCodeMessage(code.Conflict, "報備尚未核准,無法填寫第二階段資訊")
+		}
+	}
+
 	// 執行更新
-	err = r.ProjectService.Update(input)
+	err = r.ProjectService.WithTrx(trx).Update(input)
 	if err != nil {
+		if errors.Is(err, structure.ErrVersionConflict) {
+			return r.versionConflict(&model.Field{ProjectID: input.ProjectID})
+		}
+
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
+	trx.Commit()
+
 	// 如果是第二階段更新,發送 LINE 通知
 	if isStep2Update {
 		go func() {
@@ -159,6 +204,8 @@ func (r *resolver) Update(input *model.Updated) interface{} {
 				SpecialRequirements:    input.SpecialRequirements,
 				Equipments:             lineEquipments,
 				UpdatedTime:            time.Now(),
+				Coverage:               r.stockCoverage(input.ProjectID),
+				Pricing:                r.QuotationService.LinePricing(input.ProjectID),
 			}
 
 			lineBotService := linebot.New()
@@ -173,9 +220,11 @@ func (r *resolver) Update(input *model.Updated) interface{} {
 	return code.GetCodeMessage(code.Successful, project.ProjectID)
 }
 
-func (r *resolver) Delete(input *model.Updated) interface{} {
+func (r *resolver) Delete(trx *gorm.DB, input *model.Updated) interface{} {
+	defer trx.Rollback()
+
 	// 驗證專案是否存在
-	_, err := r.ProjectService.GetByID(&model.Field{ProjectID: input.ProjectID})
+	current, err := r.ProjectService.GetByID(&model.Field{ProjectID: input.ProjectID})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return code.GetCodeMessage(code.DoesNotExist, err)
@@ -185,11 +234,23 @@ func (r *resolver) Delete(input *model.Updated) interface{} {
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
-	err = r.ProjectService.Delete(input)
+	// 版本不符表示已被其他人修改,回傳目前的資料
+	if current.Version != input.Version {
+		return r.versionConflict(&model.Field{ProjectID: input.ProjectID})
+	}
+
+	// 軟刪除,設備一併移至資源回收筒
+	err = r.ProjectService.WithTrx(trx).Delete(input)
 	if err != nil {
+		if errors.Is(err, structure.ErrVersionConflict) {
+			return r.versionConflict(&model.Field{ProjectID: input.ProjectID})
+		}
+
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
+	trx.Commit()
+
 	return code.GetCodeMessage(code.Successful, "Delete ok!")
 }
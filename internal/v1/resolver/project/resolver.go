@@ -2,7 +2,13 @@ package project
 
 import (
 	"esst_sendEmail/internal/v1/service/equipment"
+	"esst_sendEmail/internal/v1/service/part"
 	"esst_sendEmail/internal/v1/service/project"
+	"esst_sendEmail/internal/v1/service/project_approval"
+	"esst_sendEmail/internal/v1/service/project_conflict"
+	"esst_sendEmail/internal/v1/service/quotation"
+	"esst_sendEmail/internal/v1/service/stock_allocation"
+	equipmentModel "esst_sendEmail/internal/v1/structure/equipments"
 	model "esst_sendEmail/internal/v1/structure/projects"
 
 	"gorm.io/gorm"
@@ -10,20 +16,33 @@ import (
 
 type Resolver interface {
 	Create(trx *gorm.DB, input *model.Created) interface{}
+	CreateWithEquipments(trx *gorm.DB, input *equipmentModel.ProjectCreated) interface{}
 	List(input *model.Fields) interface{}
 	GetByID(input *model.Field) interface{}
-	Update(input *model.Updated) interface{}
-	Delete(input *model.Updated) interface{}
+	Update(trx *gorm.DB, input *model.Updated) interface{}
+	Delete(trx *gorm.DB, input *model.Updated) interface{}
+	Trash(input *model.Fields) interface{}
+	Restore(trx *gorm.DB, input *model.Field) interface{}
 }
 
 type resolver struct {
-	ProjectService   project.Service
-	EquipmentService equipment.Service
+	ProjectService         project.Service
+	EquipmentService       equipment.Service
+	ProjectConflictService project_conflict.Service
+	ProjectApprovalService project_approval.Service
+	PartService            part.Service
+	StockAllocationService stock_allocation.Service
+	QuotationService       quotation.Service
 }
 
 func New(db *gorm.DB) Resolver {
 	return &resolver{
-		ProjectService:   project.New(db),
-		EquipmentService: equipment.New(db),
+		ProjectService:         project.New(db),
+		EquipmentService:       equipment.New(db),
+		ProjectConflictService: project_conflict.New(db),
+		ProjectApprovalService: project_approval.New(db),
+		PartService:            part.New(db),
+		StockAllocationService: stock_allocation.New(db),
+		QuotationService:       quotation.New(db),
 	}
 }
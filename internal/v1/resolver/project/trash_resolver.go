@@ -0,0 +1,61 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/projects"
+
+	"gorm.io/gorm"
+)
+
+// Trash 資源回收筒中的專案(管理員)
+func (r *resolver) Trash(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	quantity, records, err := r.ProjectService.ListDeleted(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	recordsByte, err := json.Marshal(records)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Total = quantity
+	output.Pages = util.Pagination(quantity, output.Limit)
+	err = json.Unmarshal(recordsByte, &output.Projects)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+// Restore 從資源回收筒還原專案(管理員)
+func (r *resolver) Restore(trx *gorm.DB, input *model.Field) interface{} {
+	defer trx.Rollback()
+
+	err := r.ProjectService.WithTrx(trx).Restore(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, "專案不在資源回收筒中")
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	trx.Commit()
+
+	return code.GetCodeMessage(code.Successful, input.ProjectID)
+}
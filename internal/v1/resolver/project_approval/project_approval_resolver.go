@@ -0,0 +1,106 @@
+package project_approval
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/service/project_approval"
+	model "esst_sendEmail/internal/v1/structure/project_approvals"
+
+	"gorm.io/gorm"
+)
+
+// Queue 審核佇列,管理員可看到所有待審核的關卡
+func (r *resolver) Queue(input *model.Fields, role string) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	if role == "admin" {
+		role = ""
+	}
+
+	total, approvals, err := r.ProjectApprovalService.Queue(input, role)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Approvals = approvals
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) ListByProjectID(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	total, approvals, err := r.ProjectApprovalService.ListByProjectID(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Approvals = approvals
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+// Approve 核准目前關卡,有下一關時通知下一關審核者,否則通知審核結果
+func (r *resolver) Approve(trx *gorm.DB, input *model.Decided) interface{} {
+	defer trx.Rollback()
+
+	approval, next, err := r.ProjectApprovalService.WithTrx(trx).Approve(input)
+	if err != nil {
+		return decideError(err)
+	}
+
+	trx.Commit()
+
+	if next != nil {
+		go r.ProjectApprovalService.NotifyApprovers(next)
+	} else {
+		go r.ProjectApprovalService.NotifyDecision(approval)
+	}
+
+	return code.GetCodeMessage(code.Successful, approval)
+}
+
+// Reject 駁回報備並通知審核結果
+func (r *resolver) Reject(trx *gorm.DB, input *model.Decided) interface{} {
+	defer trx.Rollback()
+
+	approval, err := r.ProjectApprovalService.WithTrx(trx).Reject(input)
+	if err != nil {
+		return decideError(err)
+	}
+
+	trx.Commit()
+
+	go r.ProjectApprovalService.NotifyDecision(approval)
+
+	return code.GetCodeMessage(code.Successful, approval)
+}
+
+func decideError(err error) interface{} {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return code.GetCodeMessage(code.DoesNotExist, err.Error())
+	case errors.Is(err, project_approval.ErrNotApprover):
+		return code.GetCodeMessage(code.PermissionDenied, err.Error())
+	case errors.Is(err, project_approval.ErrReasonRequired):
+		return code.GetCodeMessage(code.FormatError, err.Error())
+	case errors.Is(err, project_approval.ErrAlreadyDecided), errors.Is(err, project_approval.ErrNotCurrentLevel):
+		return code.GetCodeMessage(code.Conflict, err.Error())
+	}
+
+	log.Error(err)
+	return code.GetCodeMessage(code.InternalServerError, err.Error())
+}
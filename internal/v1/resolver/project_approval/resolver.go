@@ -0,0 +1,25 @@
+package project_approval
+
+import (
+	"esst_sendEmail/internal/v1/service/project_approval"
+	model "esst_sendEmail/internal/v1/structure/project_approvals"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	Queue(input *model.Fields, role string) interface{}
+	ListByProjectID(input *model.Fields) interface{}
+	Approve(trx *gorm.DB, input *model.Decided) interface{}
+	Reject(trx *gorm.DB, input *model.Decided) interface{}
+}
+
+type resolver struct {
+	ProjectApprovalService project_approval.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		ProjectApprovalService: project_approval.New(db),
+	}
+}
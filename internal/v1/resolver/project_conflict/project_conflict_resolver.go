@@ -0,0 +1,57 @@
+package project_conflict
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/project_conflicts"
+
+	"gorm.io/gorm"
+)
+
+func (r *resolver) List(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	total, conflicts, err := r.ProjectConflictService.List(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Conflicts = conflicts
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) GetByID(input *model.Field) interface{} {
+	conflict, err := r.ProjectConflictService.GetByID(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, conflict)
+}
+
+func (r *resolver) Review(input *model.Reviewed) interface{} {
+	err := r.ProjectConflictService.Review(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return r.GetByID(&model.Field{ConflictID: input.ConflictID})
+}
@@ -0,0 +1,24 @@
+package project_conflict
+
+import (
+	"esst_sendEmail/internal/v1/service/project_conflict"
+	model "esst_sendEmail/internal/v1/structure/project_conflicts"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	List(input *model.Fields) interface{}
+	GetByID(input *model.Field) interface{}
+	Review(input *model.Reviewed) interface{}
+}
+
+type resolver struct {
+	ProjectConflictService project_conflict.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		ProjectConflictService: project_conflict.New(db),
+	}
+}
@@ -0,0 +1,83 @@
+package project_extension
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/service/project_extension"
+	model "esst_sendEmail/internal/v1/structure/project_extensions"
+
+	"gorm.io/gorm"
+)
+
+func (r *resolver) Create(input *model.Created) interface{} {
+	extension, err := r.ProjectExtensionService.Create(input)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		case errors.Is(err, project_extension.ErrNotExtendable), errors.Is(err, project_extension.ErrPendingExists):
+			return code.GetCodeMessage(code.Conflict, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, extension)
+}
+
+func (r *resolver) List(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	total, extensions, err := r.ProjectExtensionService.List(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Extensions = extensions
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) GetByID(input *model.Field) interface{} {
+	extension, err := r.ProjectExtensionService.GetByID(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, extension)
+}
+
+// Review 審核延長申請,核准時同時更新專案的保護期
+func (r *resolver) Review(trx *gorm.DB, input *model.Reviewed) interface{} {
+	defer trx.Rollback()
+
+	extension, err := r.ProjectExtensionService.WithTrx(trx).Review(input)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		case errors.Is(err, project_extension.ErrAlreadyReviewed), errors.Is(err, project_extension.ErrNotExtendable):
+			return code.GetCodeMessage(code.Conflict, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	trx.Commit()
+	return code.GetCodeMessage(code.Successful, extension)
+}
@@ -0,0 +1,25 @@
+package project_extension
+
+import (
+	"esst_sendEmail/internal/v1/service/project_extension"
+	model "esst_sendEmail/internal/v1/structure/project_extensions"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	Create(input *model.Created) interface{}
+	List(input *model.Fields) interface{}
+	GetByID(input *model.Field) interface{}
+	Review(trx *gorm.DB, input *model.Reviewed) interface{}
+}
+
+type resolver struct {
+	ProjectExtensionService project_extension.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		ProjectExtensionService: project_extension.New(db),
+	}
+}
@@ -0,0 +1,160 @@
+package quotation
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/service/quotation"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+	model "esst_sendEmail/internal/v1/structure/quotations"
+
+	"gorm.io/gorm"
+)
+
+// Pricing 專案設備的金額與總計
+func (r *resolver) Pricing(projectID string) interface{} {
+	if message := r.checkProject(projectID); message != nil {
+		return message
+	}
+
+	pricing, err := r.QuotationService.Pricing(projectID)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, pricing)
+}
+
+// Create 以專案目前的設備建立新版本的報價單草稿
+func (r *resolver) Create(trx *gorm.DB, input *model.Created) interface{} {
+	defer trx.Rollback()
+
+	if message := r.checkProject(input.ProjectID); message != nil {
+		return message
+	}
+
+	quotation, err := r.QuotationService.WithTrx(trx).Create(input)
+	if err != nil {
+		return quotationError(err)
+	}
+
+	trx.Commit()
+	return code.GetCodeMessage(code.Successful, quotation)
+}
+
+func (r *resolver) List(input *model.Fields) interface{} {
+	if message := r.checkProject(input.ProjectID); message != nil {
+		return message
+	}
+
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	total, quotations, err := r.QuotationService.List(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Quotations = quotations
+	output.Total = total
+	output.Pages = util.Pagination(total, output.Limit)
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+func (r *resolver) GetByID(input *model.Field) interface{} {
+	quotation, err := r.QuotationService.GetByID(input)
+	if err != nil {
+		return quotationError(err)
+	}
+
+	return code.GetCodeMessage(code.Successful, quotation)
+}
+
+// Render 產生報價單 PDF,失敗時回傳錯誤訊息
+func (r *resolver) Render(input *model.Field) (string, []byte, interface{}) {
+	filename, content, err := r.QuotationService.Render(input)
+	if err != nil {
+		return "", nil, quotationError(err)
+	}
+
+	return filename, content, nil
+}
+
+// Send 寄送報價單給專案聯絡人,寄送成功後通知主管群組
+func (r *resolver) Send(trx *gorm.DB, input *model.Action) interface{} {
+	defer trx.Rollback()
+
+	quotation, err := r.QuotationService.WithTrx(trx).Send(input)
+	if err != nil {
+		return quotationError(err)
+	}
+
+	// 交易提交後才寄信,提交失敗時客戶不會收到報價單
+	err = trx.Commit().Error
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	err = r.QuotationService.Deliver(quotation)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, "報價單已標記為已寄出,但寄信失敗,請重新寄送: "+err.Error())
+	}
+
+	go r.QuotationService.Notify(quotation)
+
+	return code.GetCodeMessage(code.Successful, quotation)
+}
+
+// Accept 標記客戶已接受報價單並通知主管群組
+func (r *resolver) Accept(trx *gorm.DB, input *model.Action) interface{} {
+	defer trx.Rollback()
+
+	quotation, err := r.QuotationService.WithTrx(trx).Accept(input)
+	if err != nil {
+		return quotationError(err)
+	}
+
+	trx.Commit()
+
+	go r.QuotationService.Notify(quotation)
+
+	return code.GetCodeMessage(code.Successful, quotation)
+}
+
+// checkProject 專案不存在時回傳錯誤訊息
+func (r *resolver) checkProject(projectID string) interface{} {
+	_, err := r.ProjectService.GetByID(&projectModel.Field{ProjectID: projectID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return nil
+}
+
+func quotationError(err error) interface{} {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return code.GetCodeMessage(code.DoesNotExist, err.Error())
+	case errors.Is(err, quotation.ErrAlreadyAccepted), errors.Is(err, quotation.ErrInvalidStatus):
+		return code.GetCodeMessage(code.Conflict, err.Error())
+	case errors.Is(err, quotation.ErrNoEquipment), errors.Is(err, quotation.ErrUnpriced),
+		errors.Is(err, quotation.ErrMixedCurrency), errors.Is(err, quotation.ErrNoContactEmail):
+		return code.GetCodeMessage(code.UnprocessableEntity, err.Error())
+	}
+
+	log.Error(err)
+	return code.GetCodeMessage(code.InternalServerError, err.Error())
+}
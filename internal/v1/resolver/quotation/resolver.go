@@ -0,0 +1,31 @@
+package quotation
+
+import (
+	"esst_sendEmail/internal/v1/service/project"
+	"esst_sendEmail/internal/v1/service/quotation"
+	model "esst_sendEmail/internal/v1/structure/quotations"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	Pricing(projectID string) interface{}
+	Create(trx *gorm.DB, input *model.Created) interface{}
+	List(input *model.Fields) interface{}
+	GetByID(input *model.Field) interface{}
+	Render(input *model.Field) (string, []byte, interface{})
+	Send(trx *gorm.DB, input *model.Action) interface{}
+	Accept(trx *gorm.DB, input *model.Action) interface{}
+}
+
+type resolver struct {
+	ProjectService   project.Service
+	QuotationService quotation.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		ProjectService:   project.New(db),
+		QuotationService: quotation.New(db),
+	}
+}
@@ -0,0 +1,24 @@
+package role_policy
+
+import (
+	"esst_sendEmail/internal/v1/service/role_policy"
+	model "esst_sendEmail/internal/v1/structure/role_policies"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	List() interface{}
+	GetByRole(input *model.Field) interface{}
+	Update(input *model.Updated) interface{}
+}
+
+type resolver struct {
+	RolePolicyService role_policy.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		RolePolicyService: role_policy.New(db),
+	}
+}
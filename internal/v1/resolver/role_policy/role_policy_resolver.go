@@ -0,0 +1,37 @@
+package role_policy
+
+import (
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	model "esst_sendEmail/internal/v1/structure/role_policies"
+)
+
+func (r *resolver) List() interface{} {
+	policies, err := r.RolePolicyService.List()
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, policies)
+}
+
+func (r *resolver) GetByRole(input *model.Field) interface{} {
+	policy, err := r.RolePolicyService.GetByRole(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, policy)
+}
+
+func (r *resolver) Update(input *model.Updated) interface{} {
+	policy, err := r.RolePolicyService.Update(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, policy)
+}
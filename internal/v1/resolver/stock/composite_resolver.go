@@ -0,0 +1,48 @@
+package stock
+
+import (
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/v1/service/part"
+	stockEquipmentModel "esst_sendEmail/internal/v1/structure/stock_equipments"
+
+	"gorm.io/gorm"
+)
+
+// CreateWithEquipments 在同一個交易中建立現貨報備與設備,成功後只發送一次現貨報備通知
+func (r *resolver) CreateWithEquipments(trx *gorm.DB, input *stockEquipmentModel.StockCreated) interface{} {
+	defer trx.Rollback()
+
+	// 料號目錄比對
+	unmatched, err := part.MatchBatch(r.PartService, input.Equipments)
+	if err != nil {
+		return code.GetCodeMessage(code.UnprocessableEntity, unmatched)
+	}
+
+	stock, err := r.StockService.WithTrx(trx).Create(&input.Created)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	err = r.StockEquipmentService.WithTrx(trx).CreateBatch(&stockEquipmentModel.BatchCreated{
+		StockID:    stock.StockID,
+		Equipments: input.Equipments,
+	})
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	// 依現貨設備保留庫存
+	if blocked := r.InventoryResolver.Sync(trx, stock.StockID); blocked != nil {
+		return blocked
+	}
+
+	trx.Commit()
+
+	// 現貨與設備皆建立完成後,發送現貨報備 LINE 通知
+	go r.sendStockLineNotification(stock.StockID)
+
+	return partMessage(stock.StockID, unmatched)
+}
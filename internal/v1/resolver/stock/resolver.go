@@ -1,8 +1,11 @@
 package stock
 
 import (
+	"esst_sendEmail/internal/v1/resolver/inventory"
+	"esst_sendEmail/internal/v1/service/part"
 	"esst_sendEmail/internal/v1/service/stock"
 	"esst_sendEmail/internal/v1/service/stock_equipment"
+	stockEquipmentModel "esst_sendEmail/internal/v1/structure/stock_equipments"
 	model "esst_sendEmail/internal/v1/structure/stocks"
 
 	"gorm.io/gorm"
@@ -10,20 +13,27 @@ import (
 
 type Resolver interface {
 	Create(trx *gorm.DB, input *model.Created) interface{}
+	CreateWithEquipments(trx *gorm.DB, input *stockEquipmentModel.StockCreated) interface{}
 	List(input *model.Fields) interface{}
 	GetByID(input *model.Field) interface{}
-	Update(input *model.Updated) interface{}
-	Delete(input *model.Updated) interface{}
+	Update(trx *gorm.DB, input *model.Updated) interface{}
+	Delete(trx *gorm.DB, input *model.Updated) interface{}
+	Trash(input *model.Fields) interface{}
+	Restore(trx *gorm.DB, input *model.Field) interface{}
 }
 
 type resolver struct {
 	StockService          stock.Service
 	StockEquipmentService stock_equipment.Service
+	PartService           part.Service
+	InventoryResolver     inventory.Resolver
 }
 
 func New(db *gorm.DB) Resolver {
 	return &resolver{
 		StockService:          stock.New(db),
 		StockEquipmentService: stock_equipment.New(db),
+		PartService:           part.New(db),
+		InventoryResolver:     inventory.New(db),
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"esst_sendEmail/internal/pkg/linebot"
 	"esst_sendEmail/internal/pkg/log"
 	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/structure"
 	model "esst_sendEmail/internal/v1/structure/stocks"
 
 	"gorm.io/gorm"
@@ -81,7 +82,9 @@ func (r *resolver) GetByID(input *model.Field) interface{} {
 	return code.GetCodeMessage(code.Successful, frontStock)
 }
 
-func (r *resolver) Update(input *model.Updated) interface{} {
+func (r *resolver) Update(trx *gorm.DB, input *model.Updated) interface{} {
+	defer trx.Rollback()
+
 	// 驗證現貨是否存在
 	stock, err := r.StockService.GetByID(&model.Field{StockID: input.StockID})
 	if err != nil {
@@ -93,19 +96,31 @@ func (r *resolver) Update(input *model.Updated) interface{} {
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
+	// 版本不符表示已被其他人修改,回傳目前的資料
+	if stock.Version != input.Version {
+		return r.versionConflict(&model.Field{StockID: input.StockID})
+	}
+
 	// 執行更新
-	err = r.StockService.Update(input)
+	err = r.StockService.WithTrx(trx).Update(input)
 	if err != nil {
+		if errors.Is(err, structure.ErrVersionConflict) {
+			return r.versionConflict(&model.Field{StockID: input.StockID})
+		}
+
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
+	trx.Commit()
 	return code.GetCodeMessage(code.Successful, stock.StockID)
 }
 
-func (r *resolver) Delete(input *model.Updated) interface{} {
+func (r *resolver) Delete(trx *gorm.DB, input *model.Updated) interface{} {
+	defer trx.Rollback()
+
 	// 驗證現貨是否存在
-	_, err := r.StockService.GetByID(&model.Field{StockID: input.StockID})
+	current, err := r.StockService.GetByID(&model.Field{StockID: input.StockID})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return code.GetCodeMessage(code.DoesNotExist, err)
@@ -115,12 +130,29 @@ func (r *resolver) Delete(input *model.Updated) interface{} {
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
-	err = r.StockService.Delete(input)
+	// 版本不符表示已被其他人修改,回傳目前的資料
+	if current.Version != input.Version {
+		return r.versionConflict(&model.Field{StockID: input.StockID})
+	}
+
+	// 軟刪除,設備一併移至資源回收筒
+	err = r.StockService.WithTrx(trx).Delete(input)
 	if err != nil {
+		if errors.Is(err, structure.ErrVersionConflict) {
+			return r.versionConflict(&model.Field{StockID: input.StockID})
+		}
+
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
+	// 取消報備,釋放全部庫存保留
+	if blocked := r.InventoryResolver.Sync(trx, input.StockID); blocked != nil {
+		return blocked
+	}
+
+	trx.Commit()
+
 	return code.GetCodeMessage(code.Successful, "Delete ok!")
 }
 
@@ -0,0 +1,66 @@
+package stock
+
+import (
+	"encoding/json"
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/stocks"
+
+	"gorm.io/gorm"
+)
+
+// Trash 資源回收筒中的現貨報備(管理員)
+func (r *resolver) Trash(input *model.Fields) interface{} {
+	output := &model.List{}
+	output.Limit = input.Limit
+	output.Page = input.Page
+
+	quantity, records, err := r.StockService.ListDeleted(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	recordsByte, err := json.Marshal(records)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	output.Total = quantity
+	output.Pages = util.Pagination(quantity, output.Limit)
+	err = json.Unmarshal(recordsByte, &output.Stocks)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, output)
+}
+
+// Restore 從資源回收筒還原現貨報備(管理員)
+func (r *resolver) Restore(trx *gorm.DB, input *model.Field) interface{} {
+	defer trx.Rollback()
+
+	err := r.StockService.WithTrx(trx).Restore(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, "現貨報備不在資源回收筒中")
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	// 還原的設備重新保留庫存,可用數量不足時無法還原
+	if blocked := r.InventoryResolver.Sync(trx, input.StockID); blocked != nil {
+		return blocked
+	}
+
+	trx.Commit()
+
+	return code.GetCodeMessage(code.Successful, input.StockID)
+}
@@ -0,0 +1,16 @@
+package stock
+
+import (
+	"esst_sendEmail/internal/pkg/code"
+	model "esst_sendEmail/internal/v1/structure/stocks"
+)
+
+// versionConflict If-Match 版本不符時回傳 412,並附上目前的資料供前端重新比對
+func (r *resolver) versionConflict(field *model.Field) interface{} {
+	current := r.GetByID(field)
+	if message, ok := current.(*code.SuccessfulMessage); ok {
+		return code.GetCodeMessage(code.PreconditionFailed, message.Body)
+	}
+
+	return current
+}
@@ -0,0 +1,27 @@
+package stock_allocation
+
+import (
+	"esst_sendEmail/internal/v1/service/project"
+	"esst_sendEmail/internal/v1/service/stock_allocation"
+	model "esst_sendEmail/internal/v1/structure/stock_allocations"
+
+	"gorm.io/gorm"
+)
+
+type Resolver interface {
+	Summary(projectID string) interface{}
+	Create(trx *gorm.DB, input *model.Created) interface{}
+	Delete(trx *gorm.DB, input *model.Field) interface{}
+}
+
+type resolver struct {
+	ProjectService         project.Service
+	StockAllocationService stock_allocation.Service
+}
+
+func New(db *gorm.DB) Resolver {
+	return &resolver{
+		ProjectService:         project.New(db),
+		StockAllocationService: stock_allocation.New(db),
+	}
+}
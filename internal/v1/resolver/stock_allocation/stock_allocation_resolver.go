@@ -0,0 +1,74 @@
+package stock_allocation
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/v1/service/stock_allocation"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+	model "esst_sendEmail/internal/v1/structure/stock_allocations"
+
+	"gorm.io/gorm"
+)
+
+// Summary 專案設備由現貨支應與需另行採購的數量
+func (r *resolver) Summary(projectID string) interface{} {
+	_, err := r.ProjectService.GetByID(&projectModel.Field{ProjectID: projectID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	summary, err := r.StockAllocationService.Summary(projectID)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	return code.GetCodeMessage(code.Successful, summary)
+}
+
+// Create 將現貨設備分配給專案設備
+func (r *resolver) Create(trx *gorm.DB, input *model.Created) interface{} {
+	defer trx.Rollback()
+
+	allocation, err := r.StockAllocationService.WithTrx(trx).Create(input)
+	if err != nil {
+		return allocationError(err)
+	}
+
+	trx.Commit()
+	return code.GetCodeMessage(code.Successful, allocation)
+}
+
+// Delete 取消分配,數量改回需另行採購
+func (r *resolver) Delete(trx *gorm.DB, input *model.Field) interface{} {
+	defer trx.Rollback()
+
+	err := r.StockAllocationService.WithTrx(trx).Delete(input)
+	if err != nil {
+		return allocationError(err)
+	}
+
+	trx.Commit()
+	return code.GetCodeMessage(code.Successful, "Delete ok!")
+}
+
+func allocationError(err error) interface{} {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return code.GetCodeMessage(code.DoesNotExist, err.Error())
+	case errors.Is(err, stock_allocation.ErrOverAllocated):
+		return code.GetCodeMessage(code.Conflict, err.Error())
+	case errors.Is(err, stock_allocation.ErrNotInProject), errors.Is(err, stock_allocation.ErrPartMismatch):
+		return code.GetCodeMessage(code.UnprocessableEntity, err.Error())
+	}
+
+	log.Error(err)
+	return code.GetCodeMessage(code.InternalServerError, err.Error())
+}
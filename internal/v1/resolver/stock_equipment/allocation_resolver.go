@@ -0,0 +1,25 @@
+package stock_equipment
+
+import (
+	"fmt"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+
+	"gorm.io/gorm"
+)
+
+// checkAllocated 現貨數量低於已分配給專案的數量時回傳 409 訊息,需先取消部分分配
+func (r *resolver) checkAllocated(trx *gorm.DB, stockEquipmentID string, quantity int64) interface{} {
+	allocated, err := r.StockAllocationService.WithTrx(trx).AllocatedFromStock(stockEquipmentID)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	if quantity < allocated {
+		return code.GetCodeMessage(code.Conflict, fmt.Sprintf("已分配給專案 %d 個,數量不可低於已分配數量", allocated))
+	}
+
+	return nil
+}
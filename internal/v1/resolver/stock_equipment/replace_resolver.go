@@ -0,0 +1,115 @@
+package stock_equipment
+
+import (
+	"errors"
+	"time"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/v1/service/part"
+	"esst_sendEmail/internal/v1/structure"
+	changeModel "esst_sendEmail/internal/v1/structure/equipment_changes"
+	model "esst_sendEmail/internal/v1/structure/stock_equipments"
+	stockModel "esst_sendEmail/internal/v1/structure/stocks"
+
+	"gorm.io/gorm"
+)
+
+// Replace 以完整清單取代現貨設備,新增、修改、刪除與庫存保留在同一交易中完成
+func (r *resolver) Replace(trx *gorm.DB, input *model.Replaced) interface{} {
+	defer trx.Rollback()
+
+	stock, err := r.StockService.GetByID(&stockModel.Field{StockID: input.StockID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	// 料號目錄比對
+	equipments := make([]model.BatchEquipment, 0, len(input.Equipments))
+	for _, eq := range input.Equipments {
+		equipments = append(equipments, eq.BatchEquipment)
+	}
+	unmatched, err := part.MatchBatch(r.PartService, equipments)
+	if err != nil {
+		return code.GetCodeMessage(code.UnprocessableEntity, unmatched)
+	}
+	for i := range input.Equipments {
+		input.Equipments[i].PartID, input.Equipments[i].PartNumber = equipments[i].PartID, equipments[i].PartNumber
+	}
+
+	diff, err := r.StockEquipmentService.WithTrx(trx).Replace(input)
+	if err != nil {
+		return r.replaceError(input.StockID, err)
+	}
+
+	if !diff.Changed() {
+		return partMessage(diff, unmatched)
+	}
+
+	// 已分配給專案的數量不可被調低
+	for _, change := range diff.Updated {
+		if change.After.Quantity < change.Before.Quantity {
+			if blocked := r.checkAllocated(trx, change.After.StockEquipmentID, change.After.Quantity); blocked != nil {
+				return blocked
+			}
+		}
+	}
+
+	// 依異動後的設備重新計算庫存保留
+	if blocked := r.InventoryResolver.Sync(trx, input.StockID); blocked != nil {
+		return blocked
+	}
+
+	trx.Commit()
+
+	if input.Notify {
+		go r.EquipmentChangeResolver.Notify(&linebot.EquipmentChangeData{
+			TargetType: changeModel.TargetStock,
+			TargetID:   stock.StockID,
+			TargetName: stock.StockName,
+			ChangedBy:  input.ChangedByName,
+			ChangedAt:  time.Now(),
+		}, diff.Entries())
+	}
+
+	return partMessage(diff, unmatched)
+}
+
+// History 現貨設備的異動紀錄
+func (r *resolver) History(input *changeModel.Fields) interface{} {
+	_, err := r.StockService.GetByID(&stockModel.Field{StockID: input.TargetID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	input.TargetType = changeModel.TargetStock
+	return r.EquipmentChangeResolver.History(input)
+}
+
+// replaceError 取代清單的錯誤訊息,版本不符時回傳 412 並附上目前的設備清單
+func (r *resolver) replaceError(stockID string, err error) interface{} {
+	switch {
+	case errors.Is(err, changeModel.ErrUnknownLine), errors.Is(err, changeModel.ErrDuplicateLine):
+		return code.GetCodeMessage(code.UnprocessableEntity, err.Error())
+	case errors.Is(err, structure.ErrVersionConflict):
+		current := r.ListByStockID(stockID)
+		if message, ok := current.(*code.SuccessfulMessage); ok {
+			return code.GetCodeMessage(code.PreconditionFailed, message.Body)
+		}
+		return current
+	default:
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+}
@@ -1,8 +1,13 @@
 package stock_equipment
 
 import (
+	"esst_sendEmail/internal/v1/resolver/equipment_change"
+	"esst_sendEmail/internal/v1/resolver/inventory"
+	"esst_sendEmail/internal/v1/service/part"
 	"esst_sendEmail/internal/v1/service/stock"
+	"esst_sendEmail/internal/v1/service/stock_allocation"
 	"esst_sendEmail/internal/v1/service/stock_equipment"
+	changeModel "esst_sendEmail/internal/v1/structure/equipment_changes"
 	model "esst_sendEmail/internal/v1/structure/stock_equipments"
 	stockModel "esst_sendEmail/internal/v1/structure/stocks"
 
@@ -15,13 +20,21 @@ type Resolver interface {
 	List(input *model.Fields) interface{}
 	ListByStockID(stockID string) interface{}
 	GetByID(input *model.Field) interface{}
-	Update(input *model.Updated) interface{}
-	Delete(input *model.Updated) interface{}
+	Update(trx *gorm.DB, input *model.Updated) interface{}
+	Delete(trx *gorm.DB, input *model.Updated) interface{}
+	Trash(input *model.Fields) interface{}
+	Restore(trx *gorm.DB, input *model.Field) interface{}
+	Replace(trx *gorm.DB, input *model.Replaced) interface{}
+	History(input *changeModel.Fields) interface{}
 }
 
 type resolver struct {
-	StockEquipmentService stock_equipment.Service
-	StockService          stock.Service
+	StockEquipmentService   stock_equipment.Service
+	StockService            stock.Service
+	PartService             part.Service
+	InventoryResolver       inventory.Resolver
+	StockAllocationService  stock_allocation.Service
+	EquipmentChangeResolver equipment_change.Resolver
 }
 
 // Field 用於查詢現貨
@@ -31,8 +44,12 @@ type Field struct {
 
 func New(db *gorm.DB) Resolver {
 	return &resolver{
-		StockEquipmentService: stock_equipment.New(db),
-		StockService:          stock.New(db),
+		StockEquipmentService:   stock_equipment.New(db),
+		StockService:            stock.New(db),
+		PartService:             part.New(db),
+		InventoryResolver:       inventory.New(db),
+		StockAllocationService:  stock_allocation.New(db),
+		EquipmentChangeResolver: equipment_change.New(db),
 	}
 }
 
@@ -9,6 +9,8 @@ import (
 	"esst_sendEmail/internal/pkg/linebot"
 	"esst_sendEmail/internal/pkg/log"
 	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/service/part"
+	"esst_sendEmail/internal/v1/structure"
 	model "esst_sendEmail/internal/v1/structure/stock_equipments"
 
 	"gorm.io/gorm"
@@ -17,31 +19,55 @@ import (
 func (r *resolver) Create(trx *gorm.DB, input *model.Created) interface{} {
 	defer trx.Rollback()
 
+	// 料號目錄比對
+	equipments := []model.BatchEquipment{{PartID: input.PartID, PartNumber: input.PartNumber}}
+	unmatched, err := part.MatchBatch(r.PartService, equipments)
+	if err != nil {
+		return code.GetCodeMessage(code.UnprocessableEntity, unmatched)
+	}
+	input.PartID, input.PartNumber = equipments[0].PartID, equipments[0].PartNumber
+
 	equipment, err := r.StockEquipmentService.WithTrx(trx).Create(input)
 	if err != nil {
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err.Error())
 	}
 
+	// 依現貨設備保留庫存
+	if blocked := r.InventoryResolver.Sync(trx, input.StockID); blocked != nil {
+		return blocked
+	}
+
 	trx.Commit()
-	return code.GetCodeMessage(code.Successful, equipment.StockEquipmentID)
+	return partMessage(equipment.StockEquipmentID, unmatched)
 }
 
 func (r *resolver) CreateBatch(trx *gorm.DB, input *model.BatchCreated) interface{} {
 	defer trx.Rollback()
 
-	err := r.StockEquipmentService.WithTrx(trx).CreateBatch(input)
+	// 料號目錄比對
+	unmatched, err := part.MatchBatch(r.PartService, input.Equipments)
+	if err != nil {
+		return code.GetCodeMessage(code.UnprocessableEntity, unmatched)
+	}
+
+	err = r.StockEquipmentService.WithTrx(trx).CreateBatch(input)
 	if err != nil {
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err.Error())
 	}
 
+	// 依現貨設備保留庫存
+	if blocked := r.InventoryResolver.Sync(trx, input.StockID); blocked != nil {
+		return blocked
+	}
+
 	trx.Commit()
 
 	// 設備建立完成後,發送現貨報備 LINE 通知
 	go r.sendStockLineNotification(input.StockID)
 
-	return code.GetCodeMessage(code.Successful, "Batch created successfully")
+	return partMessage("Batch created successfully", unmatched)
 }
 
 // sendStockLineNotification 發送現貨報備 LINE 通知
@@ -171,7 +197,9 @@ func (r *resolver) GetByID(input *model.Field) interface{} {
 	return code.GetCodeMessage(code.Successful, frontEquipment)
 }
 
-func (r *resolver) Update(input *model.Updated) interface{} {
+func (r *resolver) Update(trx *gorm.DB, input *model.Updated) interface{} {
+	defer trx.Rollback()
+
 	equipment, err := r.StockEquipmentService.GetByID(&model.Field{StockEquipmentID: input.StockEquipmentID})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -182,17 +210,54 @@ func (r *resolver) Update(input *model.Updated) interface{} {
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
-	err = r.StockEquipmentService.Update(input)
+	// 版本不符表示已被其他人修改,回傳目前的資料
+	if equipment.Version != input.Version {
+		return r.versionConflict(&model.Field{StockEquipmentID: input.StockEquipmentID})
+	}
+
+	// 料號目錄比對
+	equipments := []model.BatchEquipment{{PartID: input.PartID, PartNumber: input.PartNumber}}
+	unmatched, err := part.MatchBatch(r.PartService, equipments)
+	if err != nil {
+		return code.GetCodeMessage(code.UnprocessableEntity, unmatched)
+	}
+	input.PartID, input.PartNumber = equipments[0].PartID, equipments[0].PartNumber
+
+	// 已分配給專案的數量不可被調低
+	if input.Quantity < equipment.Quantity {
+		if blocked := r.checkAllocated(trx, input.StockEquipmentID, input.Quantity); blocked != nil {
+			return blocked
+		}
+	}
+
+	err = r.StockEquipmentService.WithTrx(trx).Update(input)
 	if err != nil {
+		if errors.Is(err, structure.ErrVersionConflict) {
+			return r.versionConflict(&model.Field{StockEquipmentID: input.StockEquipmentID})
+		}
+
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
-	return code.GetCodeMessage(code.Successful, equipment.StockEquipmentID)
+	// 依更新後的數量與料號調整庫存保留,移到其他現貨報備時原報備一併釋放
+	if blocked := r.InventoryResolver.Sync(trx, input.StockID); blocked != nil {
+		return blocked
+	}
+	if equipment.StockID != input.StockID {
+		if blocked := r.InventoryResolver.Sync(trx, equipment.StockID); blocked != nil {
+			return blocked
+		}
+	}
+
+	trx.Commit()
+	return partMessage(equipment.StockEquipmentID, unmatched)
 }
 
-func (r *resolver) Delete(input *model.Updated) interface{} {
-	_, err := r.StockEquipmentService.GetByID(&model.Field{StockEquipmentID: input.StockEquipmentID})
+func (r *resolver) Delete(trx *gorm.DB, input *model.Updated) interface{} {
+	defer trx.Rollback()
+
+	current, err := r.StockEquipmentService.GetByID(&model.Field{StockEquipmentID: input.StockEquipmentID})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return code.GetCodeMessage(code.DoesNotExist, err)
@@ -202,11 +267,26 @@ func (r *resolver) Delete(input *model.Updated) interface{} {
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
-	err = r.StockEquipmentService.Delete(input)
+	// 版本不符表示已被其他人修改,回傳目前的資料
+	if current.Version != input.Version {
+		return r.versionConflict(&model.Field{StockEquipmentID: input.StockEquipmentID})
+	}
+
+	err = r.StockEquipmentService.WithTrx(trx).Delete(input)
 	if err != nil {
+		if errors.Is(err, structure.ErrVersionConflict) {
+			return r.versionConflict(&model.Field{StockEquipmentID: input.StockEquipmentID})
+		}
+
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
 
+	// 釋放此設備的庫存保留
+	if blocked := r.InventoryResolver.Sync(trx, current.StockID); blocked != nil {
+		return blocked
+	}
+
+	trx.Commit()
 	return code.GetCodeMessage(code.Successful, "Delete ok!")
 }
@@ -0,0 +1,272 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/auth"
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/directory"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/mail"
+	"esst_sendEmail/internal/pkg/verification"
+	"esst_sendEmail/internal/v1/service/role_policy"
+	userService "esst_sendEmail/internal/v1/service/user"
+	rolePolicyModel "esst_sendEmail/internal/v1/structure/role_policies"
+	model "esst_sendEmail/internal/v1/structure/users"
+
+	"gorm.io/gorm"
+)
+
+// Login 驗證帳號密碼,依登入政策直接核發 token 或回傳第二因子驗證用的登入票證
+func (r *resolver) Login(input *model.Login) interface{} {
+	user, err := r.UserService.Authenticate(input)
+	if err != nil {
+		return authenticateErrorMessage(err)
+	}
+
+	method, setupRequired, err := r.loginMethod(user)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	// 僅需密碼
+	if method == role_policy.LoginPolicyPassword {
+		return r.issueToken(user, input.IP)
+	}
+
+	// email 驗證碼登入,或需設定驗證器時以 email 驗證碼確認本人
+	if (method == role_policy.LoginPolicyEmail || setupRequired) && user.Email == "" {
+		return code.GetCodeMessage(code.FormatError, "使用者未設定電子信箱，無法發送驗證碼")
+	}
+
+	ticket, expiresAt, err := r.UserService.CreateLoginTicket(user.ID)
+	if err != nil {
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	challenge := &model.LoginChallenge{
+		Method:            method,
+		Ticket:            ticket,
+		ExpiresAt:         expiresAt,
+		TOTPSetupRequired: setupRequired,
+	}
+
+	// 政策要求驗證器 App 但尚未設定:產生金鑰,完成設定才能登入
+	if setupRequired {
+		challenge.TOTPSetup, err = r.UserService.SetupTOTP(user.ID)
+		if err != nil {
+			return twoFactorErrorMessage(err)
+		}
+	}
+
+	// 使用驗證器 App 的使用者不需寄送 email 驗證碼
+	if method == role_policy.LoginPolicyTOTP && !setupRequired {
+		challenge.Message = "請輸入驗證器 App 中的驗證碼"
+		return code.GetCodeMessage(code.Successful, challenge)
+	}
+
+	// 生成驗證碼
+	verificationService := verification.New()
+	verificationCode, err := verificationService.GenerateCode(user.Email)
+	if err != nil {
+		log.Error("Failed to generate verification code:", err)
+		return code.GetCodeMessage(code.InternalServerError, "Failed to generate verification code")
+	}
+
+	// 發送驗證碼郵件
+	emailService := mail.New()
+	err = emailService.SendVerificationCode(user.Email, verificationCode, user.Username)
+	if err != nil {
+		log.Error("Failed to send verification code email:", err)
+		return code.GetCodeMessage(code.InternalServerError, "無法發送驗證碼郵件")
+	}
+
+	log.Info("Verification code sent to:", user.Email)
+
+	challenge.Message = "驗證碼已發送至您的信箱"
+	if setupRequired {
+		challenge.Message = "驗證碼已發送至您的信箱,請以驗證器 App 掃描 QR Code,並一併輸入驗證器的驗證碼完成設定"
+	}
+	challenge.Email = maskEmail(user.Email) // 遮罩部分信箱
+
+	return code.GetCodeMessage(code.Successful, challenge)
+}
+
+// VerifyLogin 以登入票證與第二因子驗證碼完成登入
+func (r *resolver) VerifyLogin(input *model.LoginVerified) interface{} {
+	userID, err := r.UserService.ValidateLoginTicket(input.Ticket)
+	if err != nil {
+		return code.GetCodeMessage(code.JWTRejected, err.Error())
+	}
+
+	user, err := r.UserService.GetByID(&model.Field{ID: &userID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.JWTRejected, userService.ErrInvalidLoginTicket.Error())
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	// 取得票證後帳號才被鎖定時,不允許完成登入
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return authenticateErrorMessage(&userService.AccountLockedError{User: user, Until: *user.LockedUntil})
+	}
+
+	method, setupRequired, err := r.loginMethod(user)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	// 依使用者的第二因子驗證驗證碼
+	var recoveryCodes *model.RecoveryCodes
+	switch {
+	case setupRequired:
+		// 強制設定驗證器:email 驗證碼確認本人,新驗證器的驗證碼確認綁定
+		verificationService := verification.New()
+		if input.TOTPCode == "" || !verificationService.VerifyCode(user.Email, strings.TrimSpace(input.Code)) {
+			err = userService.ErrInvalidTwoFactorCode
+			break
+		}
+		recoveryCodes, err = r.UserService.ConfirmTOTP(user.ID, &model.TOTPConfirm{Code: input.TOTPCode})
+	case method == role_policy.LoginPolicyTOTP:
+		err = r.UserService.VerifyTOTP(user.ID, input.Code)
+	case method == role_policy.LoginPolicyEmail:
+		verificationService := verification.New()
+		if !verificationService.VerifyCode(user.Email, strings.TrimSpace(input.Code)) {
+			err = userService.ErrInvalidTwoFactorCode
+		}
+	}
+
+	if err != nil {
+		// 驗證碼錯誤同樣累計登入失敗次數
+		if errors.Is(err, userService.ErrInvalidTwoFactorCode) {
+			failErr := r.UserService.RecordLoginFailure(user.ID, input.IP)
+			var lockedErr *userService.AccountLockedError
+			if errors.As(failErr, &lockedErr) {
+				return authenticateErrorMessage(failErr)
+			}
+		}
+
+		return twoFactorErrorMessage(err)
+	}
+
+	if recoveryCodes != nil {
+		user.TwoFactorMethod = userService.TwoFactorTOTP
+		user.RecoveryCodes = recoveryCodes.Codes
+	}
+
+	return r.issueToken(user, input.IP)
+}
+
+// loginMethod 決定使用者登入時的第二因子
+// 已啟用驗證器 App 的使用者一律使用 TOTP;政策要求 TOTP 但尚未設定時回傳需設定,必須完成設定才能登入
+func (r *resolver) loginMethod(user *model.Base) (string, bool, error) {
+	if user.TwoFactorMethod == userService.TwoFactorTOTP {
+		return role_policy.LoginPolicyTOTP, false, nil
+	}
+
+	policy, err := r.RolePolicyService.GetByRole(&rolePolicyModel.Field{Role: user.Role})
+	if err != nil {
+		return "", false, err
+	}
+
+	if policy.EffectiveLoginPolicy == role_policy.LoginPolicyTOTP {
+		return role_policy.LoginPolicyTOTP, true, nil
+	}
+
+	return policy.EffectiveLoginPolicy, false, nil
+}
+
+// issueToken 產生 JWT 並記錄登入成功
+func (r *resolver) issueToken(user *model.Base, ip string) interface{} {
+	// 生成 JWT token
+	token, err := auth.GenerateToken(user.ID, user.Username, user.Role, user.MustChangePassword)
+	if err != nil {
+		log.Error("Failed to generate token:", err)
+		return code.GetCodeMessage(code.InternalServerError, "Failed to generate token")
+	}
+
+	err = r.UserService.RecordLoginSuccess(user.ID, ip)
+	if err != nil {
+		log.Error(err)
+	}
+
+	// 將 token 加入回傳資料
+	user.Token = token
+
+	return code.GetCodeMessage(code.Successful, user)
+}
+
+// authenticateErrorMessage 將登入驗證錯誤轉換為回傳訊息
+func authenticateErrorMessage(err error) interface{} {
+	var lockedErr *userService.AccountLockedError
+	switch {
+	case errors.As(err, &lockedErr):
+		if lockedErr.JustLocked {
+			go sendAccountLockedEmail(lockedErr)
+		}
+		return code.GetCodeMessage(code.PermissionDenied, lockedErr.Error())
+	case errors.Is(err, userService.ErrTooManyAttempts):
+		return code.GetCodeMessage(code.TooManyRequests, err.Error())
+	case errors.Is(err, directory.ErrNoRole):
+		return code.GetCodeMessage(code.PermissionDenied, err.Error())
+	case errors.Is(err, directory.ErrUnavailable), errors.Is(err, directory.ErrDisabled):
+		log.Error(err)
+		return code.GetCodeMessage(code.ServerDown, directory.ErrUnavailable.Error())
+	default:
+		log.Error(err)
+		return code.GetCodeMessage(code.JWTRejected, "Invalid username or password")
+	}
+}
+
+// sendAccountLockedEmail 通知使用者帳號已被鎖定
+func sendAccountLockedEmail(lockedErr *userService.AccountLockedError) {
+	if lockedErr.User.Email == "" {
+		return
+	}
+
+	emailService := mail.New()
+	err := emailService.SendAccountLockedEmail(lockedErr.User.Email, lockedErr.User.Username, lockedErr.Until)
+	if err != nil {
+		log.Error("Failed to send account locked email:", err)
+	}
+}
+
+// maskEmail 遮罩信箱地址
+func maskEmail(email string) string {
+	if email == "" {
+		return ""
+	}
+
+	// 找到 @ 的位置
+	atIndex := -1
+	for i, c := range email {
+		if c == '@' {
+			atIndex = i
+			break
+		}
+	}
+
+	if atIndex < 0 {
+		return email
+	}
+
+	// 保留前 2 個字元和 @ 後面的部分
+	if atIndex <= 2 {
+		return email
+	}
+
+	masked := email[:2]
+	for i := 2; i < atIndex; i++ {
+		masked += "*"
+	}
+	masked += email[atIndex:]
+
+	return masked
+}
@@ -0,0 +1,50 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/sso"
+	userService "esst_sendEmail/internal/v1/service/user"
+	model "esst_sendEmail/internal/v1/structure/users"
+)
+
+// OIDCLogin 以授權碼完成 OIDC 單一登入,自動建立或同步帳號後核發內部 JWT
+// 兩步驟驗證由身分提供者負責,不再要求本地驗證碼
+func (r *resolver) OIDCLogin(ctx context.Context, flow *sso.Flow, authCode, ip string) interface{} {
+	identity, err := sso.Exchange(ctx, flow, authCode)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.JWTRejected, "單一登入驗證失敗")
+	}
+
+	role, err := sso.RoleForGroups(identity.Groups)
+	if err != nil {
+		log.Info("OIDC login rejected, no matching group:", identity.Subject)
+		return code.GetCodeMessage(code.PermissionDenied, err.Error())
+	}
+
+	user, err := r.UserService.ProvisionOIDCUser(&model.ExternalIdentity{
+		Provider:      userService.AuthProviderOIDC,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Username:      identity.Username,
+		Role:          role,
+	})
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return authenticateErrorMessage(&userService.AccountLockedError{User: user, Until: *user.LockedUntil})
+	}
+
+	// 單一登入帳號不使用本地密碼,不需強制變更
+	user.MustChangePassword = false
+
+	return r.issueToken(user, ip)
+}
@@ -0,0 +1,151 @@
+package user
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"time"
+
+	"esst_sendEmail/internal/pkg/auth"
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/mail"
+	"esst_sendEmail/internal/pkg/password"
+	"esst_sendEmail/internal/v1/service/user"
+	model "esst_sendEmail/internal/v1/structure/users"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// ChangePassword 變更自己的密碼,成功後回傳新的 token(清除強制變更旗標)
+func (r *resolver) ChangePassword(trx *gorm.DB, userID string, input *model.PasswordChanged) interface{} {
+	defer trx.Rollback()
+
+	err := r.UserService.WithTrx(trx).ChangePassword(userID, input)
+	if err != nil {
+		return passwordErrorMessage(err)
+	}
+
+	trx.Commit()
+
+	base, err := r.UserService.GetByID(&model.Field{ID: &userID})
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	token, err := auth.GenerateToken(base.ID, base.Username, base.Role, base.MustChangePassword)
+	if err != nil {
+		log.Error("Failed to generate token:", err)
+		return code.GetCodeMessage(code.InternalServerError, "Failed to generate token")
+	}
+	base.Token = token
+
+	go sendPasswordChangedEmail(base)
+
+	return code.GetCodeMessage(code.Successful, base)
+}
+
+// ForgotPassword 寄送重設密碼連結,不論信箱是否存在都回傳相同訊息以避免帳號探測
+func (r *resolver) ForgotPassword(trx *gorm.DB, input *model.PasswordForgot) interface{} {
+	defer trx.Rollback()
+
+	const message = "若此信箱已註冊，您將收到重設密碼的郵件"
+
+	request, err := r.UserService.WithTrx(trx).CreatePasswordReset(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Info("Password reset requested for unknown email")
+			return code.GetCodeMessage(code.Successful, message)
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+
+	trx.Commit()
+
+	go func() {
+		resetURL, err := passwordResetURL(request.Token)
+		if err != nil {
+			log.Error("Failed to build password reset URL:", err)
+			return
+		}
+
+		emailService := mail.New()
+		err = emailService.SendPasswordResetEmail(request.User.Email, request.User.Username, resetURL, request.ExpiresAt)
+		if err != nil {
+			log.Error("Failed to send password reset email:", err)
+		}
+	}()
+
+	return code.GetCodeMessage(code.Successful, message)
+}
+
+// ResetPassword 以重設連結中的 token 設定新密碼
+func (r *resolver) ResetPassword(trx *gorm.DB, input *model.PasswordReset) interface{} {
+	defer trx.Rollback()
+
+	base, err := r.UserService.WithTrx(trx).ResetPassword(input)
+	if err != nil {
+		return passwordErrorMessage(err)
+	}
+
+	trx.Commit()
+
+	go sendPasswordChangedEmail(base)
+
+	return code.GetCodeMessage(code.Successful, "密碼已重設，請使用新密碼登入")
+}
+
+// passwordErrorMessage 將密碼相關錯誤轉換為回傳訊息
+func passwordErrorMessage(err error) interface{} {
+	var policyErr *password.PolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		return code.GetCodeMessage(code.FormatError, policyErr.Error())
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return code.GetCodeMessage(code.JWTRejected, "目前密碼錯誤")
+	case errors.Is(err, user.ErrPasswordReused):
+		return code.GetCodeMessage(code.FormatError, err.Error())
+	case errors.Is(err, user.ErrInvalidResetToken):
+		return code.GetCodeMessage(code.JWTRejected, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return code.GetCodeMessage(code.DoesNotExist, err)
+	default:
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+}
+
+// passwordResetURL 組出前端重設密碼頁面的連結
+func passwordResetURL(token string) (string, error) {
+	base := os.Getenv("PASSWORD_RESET_URL")
+	if base == "" {
+		return "", errors.New("PASSWORD_RESET_URL is not set")
+	}
+
+	resetURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+
+	query := resetURL.Query()
+	query.Set("token", token)
+	resetURL.RawQuery = query.Encode()
+
+	return resetURL.String(), nil
+}
+
+// sendPasswordChangedEmail 通知使用者密碼已變更
+func sendPasswordChangedEmail(base *model.Base) {
+	if base.Email == "" {
+		return
+	}
+
+	emailService := mail.New()
+	if err := emailService.SendPasswordChangedEmail(base.Email, base.Username, time.Now()); err != nil {
+		log.Error("Failed to send password changed email:", err)
+	}
+}
@@ -1,6 +1,10 @@
 package user
 
 import (
+	"context"
+
+	"esst_sendEmail/internal/pkg/sso"
+	"esst_sendEmail/internal/v1/service/role_policy"
 	"esst_sendEmail/internal/v1/service/user"
 	model "esst_sendEmail/internal/v1/structure/users"
 
@@ -9,20 +13,32 @@ import (
 
 type Resolver interface {
 	Create(trx *gorm.DB, input *model.Created) interface{}
-	ValidateCredentials(username, password string) interface{} // 🔴 新增這行
 	Login(input *model.Login) interface{}
+	VerifyLogin(input *model.LoginVerified) interface{}
+	OIDCLogin(ctx context.Context, flow *sso.Flow, authCode, ip string) interface{}
+	Unlock(input *model.Field) interface{}
 	GetByID(input *model.Field) interface{}
 	List(input *model.Fields) interface{}
 	Update(input *model.Updated) interface{}
 	Delete(input *model.Field) interface{}
+	SetupTOTP(userID string) interface{}
+	ConfirmTOTP(trx *gorm.DB, userID string, input *model.TOTPConfirm) interface{}
+	DisableTOTP(trx *gorm.DB, userID string, input *model.TOTPDisable) interface{}
+	RegenerateRecoveryCodes(trx *gorm.DB, userID string, input *model.TOTPConfirm) interface{}
+	VerifyTOTP(userID, code string) interface{}
+	ChangePassword(trx *gorm.DB, userID string, input *model.PasswordChanged) interface{}
+	ForgotPassword(trx *gorm.DB, input *model.PasswordForgot) interface{}
+	ResetPassword(trx *gorm.DB, input *model.PasswordReset) interface{}
 }
 
 type resolver struct {
-	UserService user.Service
+	UserService       user.Service
+	RolePolicyService role_policy.Service
 }
 
 func New(db *gorm.DB) Resolver {
 	return &resolver{
-		UserService: user.New(db),
+		UserService:       user.New(db),
+		RolePolicyService: role_policy.New(db),
 	}
 }
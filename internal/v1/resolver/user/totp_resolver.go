@@ -0,0 +1,91 @@
+package user
+
+import (
+	"errors"
+
+	"esst_sendEmail/internal/pkg/code"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/v1/service/user"
+	model "esst_sendEmail/internal/v1/structure/users"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// SetupTOTP 產生 TOTP 金鑰與 otpauth:// URI
+func (r *resolver) SetupTOTP(userID string) interface{} {
+	setup, err := r.UserService.SetupTOTP(userID)
+	if err != nil {
+		return twoFactorErrorMessage(err)
+	}
+
+	return code.GetCodeMessage(code.Successful, setup)
+}
+
+// ConfirmTOTP 確認 TOTP 綁定並回傳復原碼
+func (r *resolver) ConfirmTOTP(trx *gorm.DB, userID string, input *model.TOTPConfirm) interface{} {
+	defer trx.Rollback()
+
+	recoveryCodes, err := r.UserService.WithTrx(trx).ConfirmTOTP(userID, input)
+	if err != nil {
+		return twoFactorErrorMessage(err)
+	}
+
+	trx.Commit()
+	return code.GetCodeMessage(code.Successful, recoveryCodes)
+}
+
+// DisableTOTP 停用 TOTP
+func (r *resolver) DisableTOTP(trx *gorm.DB, userID string, input *model.TOTPDisable) interface{} {
+	defer trx.Rollback()
+
+	err := r.UserService.WithTrx(trx).DisableTOTP(userID, input)
+	if err != nil {
+		return twoFactorErrorMessage(err)
+	}
+
+	trx.Commit()
+	return code.GetCodeMessage(code.Successful, "TOTP disabled")
+}
+
+// RegenerateRecoveryCodes 重新產生復原碼
+func (r *resolver) RegenerateRecoveryCodes(trx *gorm.DB, userID string, input *model.TOTPConfirm) interface{} {
+	defer trx.Rollback()
+
+	recoveryCodes, err := r.UserService.WithTrx(trx).RegenerateRecoveryCodes(userID, input)
+	if err != nil {
+		return twoFactorErrorMessage(err)
+	}
+
+	trx.Commit()
+	return code.GetCodeMessage(code.Successful, recoveryCodes)
+}
+
+// VerifyTOTP 驗證登入時輸入的 TOTP 驗證碼或復原碼
+func (r *resolver) VerifyTOTP(userID, codeValue string) interface{} {
+	err := r.UserService.VerifyTOTP(userID, codeValue)
+	if err != nil {
+		return twoFactorErrorMessage(err)
+	}
+
+	return code.GetCodeMessage(code.Successful, userID)
+}
+
+// twoFactorErrorMessage 將兩步驟驗證錯誤轉換為回傳訊息
+func twoFactorErrorMessage(err error) interface{} {
+	switch {
+	case errors.Is(err, user.ErrInvalidTwoFactorCode):
+		return code.GetCodeMessage(code.JWTRejected, "驗證碼錯誤或已過期")
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return code.GetCodeMessage(code.JWTRejected, "密碼錯誤")
+	case errors.Is(err, user.ErrTOTPAlreadyEnabled),
+		errors.Is(err, user.ErrTOTPNotSetup),
+		errors.Is(err, user.ErrTOTPNotEnabled):
+		return code.GetCodeMessage(code.FormatError, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return code.GetCodeMessage(code.DoesNotExist, err)
+	default:
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err.Error())
+	}
+}
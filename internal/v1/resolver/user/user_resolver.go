@@ -4,9 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 
-	"esst_sendEmail/internal/pkg/auth"
 	"esst_sendEmail/internal/pkg/code"
 	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/password"
 	"esst_sendEmail/internal/pkg/util"
 	model "esst_sendEmail/internal/v1/structure/users"
 
@@ -18,6 +18,11 @@ func (r *resolver) Create(trx *gorm.DB, input *model.Created) interface{} {
 
 	user, err := r.UserService.WithTrx(trx).Create(input)
 	if err != nil {
+		var policyErr *password.PolicyError
+		if errors.As(err, &policyErr) {
+			return code.GetCodeMessage(code.FormatError, policyErr.Error())
+		}
+
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err.Error())
 	}
@@ -26,40 +31,6 @@ func (r *resolver) Create(trx *gorm.DB, input *model.Created) interface{} {
 	return code.GetCodeMessage(code.Successful, user.ID)
 }
 
-// ValidateCredentials 驗證帳號密碼(不生成 token)
-func (r *resolver) ValidateCredentials(username, password string) interface{} {
-	// 驗證用戶
-	user, err := r.UserService.Authenticate(username, password)
-	if err != nil {
-		log.Error(err)
-		return code.GetCodeMessage(code.JWTRejected, "Invalid username or password")
-	}
-
-	// 返回用戶資料(不含 token)
-	return code.GetCodeMessage(code.Successful, user)
-}
-
-func (r *resolver) Login(input *model.Login) interface{} {
-	// 驗證用戶
-	user, err := r.UserService.Authenticate(input.Username, input.Password)
-	if err != nil {
-		log.Error(err)
-		return code.GetCodeMessage(code.JWTRejected, "Invalid username or password")
-	}
-
-	// 生成 JWT token
-	token, err := auth.GenerateToken(user.ID, user.Username, user.Role)
-	if err != nil {
-		log.Error("Failed to generate token:", err)
-		return code.GetCodeMessage(code.InternalServerError, "Failed to generate token")
-	}
-
-	// 將 token 加入回傳資料
-	user.Token = token
-
-	return code.GetCodeMessage(code.Successful, user)
-}
-
 func (r *resolver) GetByID(input *model.Field) interface{} {
 	user, err := r.UserService.GetByID(input)
 	if err != nil {
@@ -116,6 +87,11 @@ func (r *resolver) Update(input *model.Updated) interface{} {
 
 	err = r.UserService.Update(input)
 	if err != nil {
+		var policyErr *password.PolicyError
+		if errors.As(err, &policyErr) {
+			return code.GetCodeMessage(code.FormatError, policyErr.Error())
+		}
+
 		log.Error(err)
 		return code.GetCodeMessage(code.InternalServerError, err)
 	}
@@ -143,3 +119,24 @@ func (r *resolver) Delete(input *model.Field) interface{} {
 
 	return code.GetCodeMessage(code.Successful, "Delete ok!")
 }
+
+// Unlock 管理員解除帳號鎖定
+func (r *resolver) Unlock(input *model.Field) interface{} {
+	_, err := r.UserService.GetByID(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.GetCodeMessage(code.DoesNotExist, err)
+		}
+
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err)
+	}
+
+	err = r.UserService.Unlock(input)
+	if err != nil {
+		log.Error(err)
+		return code.GetCodeMessage(code.InternalServerError, err)
+	}
+
+	return code.GetCodeMessage(code.Successful, "Unlock ok!")
+}
@@ -0,0 +1,27 @@
+package api_key
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/api_key"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := api_key.New(db)
+	v10 := route.Group("authority").Group("v1.0").Group("api-keys")
+	v10.Use(middleware.JWTMiddleware()) // 僅接受 JWT,API 金鑰不能建立或管理金鑰
+	{
+		// 建立金鑰
+		v10.POST("", middleware.Transaction(db), controller.Create)
+		// 查詢金鑰列表
+		v10.GET("", controller.List)
+		// 查詢單一金鑰
+		v10.GET("/:apiKeyId", controller.GetByID)
+		// 撤銷金鑰
+		v10.DELETE("/:apiKeyId", controller.Revoke)
+	}
+
+	return route
+}
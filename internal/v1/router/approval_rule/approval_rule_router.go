@@ -0,0 +1,29 @@
+package approval_rule
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/approval_rule"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := approval_rule.New(db)
+	v10 := route.Group("authority").Group("v1.0").Group("approval-rules")
+	v10.Use(middleware.JWTMiddleware(), middleware.AdminMiddleware())
+	{
+		// 新增審核規則
+		v10.POST("", controller.Create)
+		// 查詢審核規則列表
+		v10.GET("", controller.List)
+		// 查詢單一審核規則
+		v10.GET("/:ruleId", controller.GetByID)
+		// 更新審核規則
+		v10.PATCH("/:ruleId", controller.Update)
+		// 刪除審核規則
+		v10.DELETE("/:ruleId", controller.Delete)
+	}
+
+	return route
+}
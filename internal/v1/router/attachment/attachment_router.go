@@ -0,0 +1,51 @@
+package attachment
+
+import (
+	"esst_sendEmail/internal/pkg/storage"
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/attachment"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := attachment.New(db)
+
+	// 專案附件
+	projects := route.Group("authority").Group("v1.0").Group("projects")
+	projects.Use(middleware.APIKeyMiddleware(db, "projects"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	projects.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))           // 限制資料異動頻率
+	{
+		// 上傳附件
+		projects.POST("/:projectId/attachments", controller.Create)
+		// 查詢附件列表
+		projects.GET("/:projectId/attachments", controller.List)
+		// 取得限時下載網址
+		projects.GET("/:projectId/attachments/:attachmentId/download", controller.Download)
+		// 刪除附件
+		projects.DELETE("/:projectId/attachments/:attachmentId", controller.Delete)
+	}
+
+	// 現貨附件
+	stocks := route.Group("authority").Group("v1.0").Group("stocks")
+	stocks.Use(middleware.APIKeyMiddleware(db, "stocks"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	stocks.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))         // 限制資料異動頻率
+	{
+		// 上傳附件
+		stocks.POST("/:stockId/attachments", controller.Create)
+		// 查詢附件列表
+		stocks.GET("/:stockId/attachments", controller.List)
+		// 取得限時下載網址
+		stocks.GET("/:stockId/attachments/:attachmentId/download", controller.Download)
+		// 刪除附件
+		stocks.DELETE("/:stockId/attachments/:attachmentId", controller.Delete)
+	}
+
+	// 本機儲存的檔案下載(僅 STORAGE_DRIVER=local 時提供)
+	if storage.Driver() == storage.DriverLocal {
+		route.GET(storage.LocalPath, controller.File)
+	}
+
+	return route
+}
@@ -0,0 +1,45 @@
+package comment
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/comment"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := comment.New(db)
+
+	// 專案留言
+	projects := route.Group("authority").Group("v1.0").Group("projects")
+	projects.Use(middleware.APIKeyMiddleware(db, "projects"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	projects.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))           // 限制資料異動頻率
+	{
+		// 新增留言
+		projects.POST("/:projectId/comments", controller.Create)
+		// 查詢留言串
+		projects.GET("/:projectId/comments", controller.List)
+		// 編輯留言
+		projects.PATCH("/:projectId/comments/:commentId", controller.Update)
+		// 刪除留言
+		projects.DELETE("/:projectId/comments/:commentId", controller.Delete)
+	}
+
+	// 現貨留言
+	stocks := route.Group("authority").Group("v1.0").Group("stocks")
+	stocks.Use(middleware.APIKeyMiddleware(db, "stocks"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	stocks.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))         // 限制資料異動頻率
+	{
+		// 新增留言
+		stocks.POST("/:stockId/comments", controller.Create)
+		// 查詢留言串
+		stocks.GET("/:stockId/comments", controller.List)
+		// 編輯留言
+		stocks.PATCH("/:stockId/comments/:commentId", controller.Update)
+		// 刪除留言
+		stocks.DELETE("/:stockId/comments/:commentId", controller.Delete)
+	}
+
+	return route
+}
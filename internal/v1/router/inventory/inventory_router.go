@@ -0,0 +1,35 @@
+package inventory
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/inventory"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := inventory.New(db)
+
+	// 查詢庫存(登入即可)
+	v10 := route.Group("authority").Group("v1.0").Group("inventories")
+	v10.Use(middleware.JWTMiddleware())
+	{
+		// 查詢料號庫存列表
+		v10.GET("", controller.List)
+		// 查詢單一料號庫存
+		v10.GET("/:inventoryId", controller.GetByID)
+		// 查詢庫存異動紀錄
+		v10.GET("/:inventoryId/movements", controller.ListMovements)
+	}
+
+	// 登錄庫存異動(需要管理員權限)
+	admin := route.Group("authority").Group("v1.0").Group("inventories")
+	admin.Use(middleware.JWTMiddleware(), middleware.AdminMiddleware())
+	{
+		// 登錄進貨、出貨或盤點調整
+		admin.POST("/movements", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.Record)
+	}
+
+	return route
+}
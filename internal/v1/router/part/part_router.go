@@ -0,0 +1,39 @@
+package part
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/part"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := part.New(db)
+
+	// 查詢料號目錄(登入即可)
+	v10 := route.Group("authority").Group("v1.0").Group("parts")
+	v10.Use(middleware.JWTMiddleware())
+	{
+		// 查詢與搜尋料號
+		v10.GET("", controller.List)
+		// 料號自動完成
+		v10.GET("/suggest", controller.Suggest)
+		// 查詢單一料號
+		v10.GET("/:partId", controller.GetByID)
+	}
+
+	// 維護料號目錄(需要管理員權限)
+	admin := route.Group("authority").Group("v1.0").Group("parts")
+	admin.Use(middleware.JWTMiddleware(), middleware.AdminMiddleware())
+	{
+		// 新增料號
+		admin.POST("", controller.Create)
+		// 更新料號
+		admin.PATCH("/:partId", controller.Update)
+		// 刪除料號
+		admin.DELETE("/:partId", controller.Delete)
+	}
+
+	return route
+}
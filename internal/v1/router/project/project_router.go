@@ -11,18 +11,26 @@ import (
 func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
 	controller := project.New(db)
 	v10 := route.Group("authority").Group("v1.0").Group("projects")
-	v10.Use(middleware.JWTMiddleware()) // 加上 JWT 驗證
+	v10.Use(middleware.APIKeyMiddleware(db, "projects"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	v10.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))           // 限制資料異動頻率
 	{
 		// 建立專案（第一階段）
-		v10.POST("", middleware.Transaction(db), controller.Create)
+		v10.POST("", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.Create)
+		// 同時建立專案與設備(單一交易,只發送一次通知)
+		v10.POST("/with-equipments", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.CreateWithEquipments)
 		// 查詢專案列表
 		v10.GET("", controller.List)
 		// 查詢單一專案
 		v10.GET("/:projectId", controller.GetByID)
-		// 刪除專案
-		v10.DELETE("/:projectId", controller.Delete)
+		// 刪除專案(移至資源回收筒)
+		v10.DELETE("/:projectId", middleware.Transaction(db), controller.Delete)
 		// 更新專案（包含第二階段）
-		v10.PATCH("/:projectId", controller.Update)
+		v10.PATCH("/:projectId", middleware.Transaction(db), controller.Update)
+
+		// 資源回收筒(管理員)
+		v10.GET("/trash", middleware.AdminMiddleware(), controller.Trash)
+		// 還原專案(管理員)
+		v10.POST("/:projectId/restore", middleware.AdminMiddleware(), middleware.Transaction(db), controller.Restore)
 	}
 
 	return route
@@ -0,0 +1,36 @@
+package project_approval
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/project_approval"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := project_approval.New(db)
+
+	// 專案的審核紀錄
+	projects := route.Group("authority").Group("v1.0").Group("projects")
+	projects.Use(middleware.APIKeyMiddleware(db, "projects"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	{
+		// 查詢專案的審核紀錄
+		projects.GET("/:projectId/approvals", controller.ListByProjectID)
+	}
+
+	// 審核者的審核佇列,角色權限於審核時檢查
+	v10 := route.Group("authority").Group("v1.0").Group("approvals")
+	v10.Use(middleware.JWTMiddleware())
+	v10.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit)) // 限制資料異動頻率
+	{
+		// 查詢待審核的報備
+		v10.GET("", controller.Queue)
+		// 核准
+		v10.POST("/:approvalId/approve", middleware.Transaction(db), controller.Approve)
+		// 駁回
+		v10.POST("/:approvalId/reject", middleware.Transaction(db), controller.Reject)
+	}
+
+	return route
+}
@@ -0,0 +1,25 @@
+package project_conflict
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/project_conflict"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := project_conflict.New(db)
+	v10 := route.Group("authority").Group("v1.0").Group("project-conflicts")
+	v10.Use(middleware.JWTMiddleware(), middleware.AdminMiddleware())
+	{
+		// 查詢重複報備審核佇列
+		v10.GET("", controller.List)
+		// 查詢單一重複報備紀錄
+		v10.GET("/:conflictId", controller.GetByID)
+		// 審核重複報備紀錄
+		v10.PATCH("/:conflictId", controller.Review)
+	}
+
+	return route
+}
@@ -0,0 +1,38 @@
+package project_extension
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/project_extension"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := project_extension.New(db)
+
+	// 專案的延長申請
+	projects := route.Group("authority").Group("v1.0").Group("projects")
+	projects.Use(middleware.APIKeyMiddleware(db, "projects"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	projects.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))           // 限制資料異動頻率
+	{
+		// 申請延長保護期
+		projects.POST("/:projectId/extensions", controller.Create)
+		// 查詢專案的延長申請紀錄
+		projects.GET("/:projectId/extensions", controller.ListByProjectID)
+	}
+
+	// 管理員審核
+	v10 := route.Group("authority").Group("v1.0").Group("project-extensions")
+	v10.Use(middleware.JWTMiddleware(), middleware.AdminMiddleware())
+	{
+		// 查詢延長申請審核佇列
+		v10.GET("", controller.List)
+		// 查詢單一延長申請
+		v10.GET("/:extensionId", controller.GetByID)
+		// 核准或駁回延長申請
+		v10.PATCH("/:extensionId", middleware.Transaction(db), controller.Review)
+	}
+
+	return route
+}
@@ -0,0 +1,36 @@
+package quotation
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/quotation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := quotation.New(db)
+
+	// 專案報價
+	v10 := route.Group("authority").Group("v1.0").Group("projects")
+	v10.Use(middleware.APIKeyMiddleware(db, "projects"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	v10.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))           // 限制資料異動頻率
+	{
+		// 查詢設備金額與總計
+		v10.GET("/:projectId/pricing", controller.Pricing)
+		// 以目前的設備建立新版本報價單
+		v10.POST("/:projectId/quotations", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.Create)
+		// 查詢報價單版本
+		v10.GET("/:projectId/quotations", controller.List)
+		// 查詢報價單與明細
+		v10.GET("/:projectId/quotations/:quotationId", controller.GetByID)
+		// 下載報價單 PDF
+		v10.GET("/:projectId/quotations/:quotationId/pdf", controller.PDF)
+		// 寄送報價單給專案聯絡人
+		v10.POST("/:projectId/quotations/:quotationId/send", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.Send)
+		// 標記客戶已接受
+		v10.POST("/:projectId/quotations/:quotationId/accept", middleware.Transaction(db), controller.Accept)
+	}
+
+	return route
+}
@@ -0,0 +1,25 @@
+package role_policy
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/role_policy"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := role_policy.New(db)
+	v10 := route.Group("authority").Group("v1.0").Group("role-policies")
+	v10.Use(middleware.JWTMiddleware(), middleware.AdminMiddleware())
+	{
+		// 查詢角色政策列表
+		v10.GET("", controller.List)
+		// 查詢單一角色政策
+		v10.GET("/:role", controller.GetByRole)
+		// 設定角色政策
+		v10.PUT("/:role", controller.Update)
+	}
+
+	return route
+}
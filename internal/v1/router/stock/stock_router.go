@@ -11,18 +11,26 @@ import (
 func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
 	controller := stock.New(db)
 	v10 := route.Group("authority").Group("v1.0").Group("stocks")
-	v10.Use(middleware.JWTMiddleware()) // 加上 JWT 驗證
+	v10.Use(middleware.APIKeyMiddleware(db, "stocks"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	v10.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))         // 限制資料異動頻率
 	{
 		// 建立現貨報備
-		v10.POST("", middleware.Transaction(db), controller.Create)
+		v10.POST("", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.Create)
+		// 同時建立現貨報備與設備(單一交易,只發送一次通知)
+		v10.POST("/with-equipments", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.CreateWithEquipments)
 		// 查詢現貨報備列表
 		v10.GET("", controller.List)
 		// 查詢單一現貨報備
 		v10.GET("/:stockId", controller.GetByID)
-		// 刪除現貨報備
-		v10.DELETE("/:stockId", controller.Delete)
+		// 刪除現貨報備(移至資源回收筒)
+		v10.DELETE("/:stockId", middleware.Transaction(db), controller.Delete)
 		// 更新現貨報備
-		v10.PATCH("/:stockId", controller.Update)
+		v10.PATCH("/:stockId", middleware.Transaction(db), controller.Update)
+
+		// 資源回收筒(管理員)
+		v10.GET("/trash", middleware.AdminMiddleware(), controller.Trash)
+		// 還原現貨報備(管理員)
+		v10.POST("/:stockId/restore", middleware.AdminMiddleware(), middleware.Transaction(db), controller.Restore)
 	}
 
 	return route
@@ -0,0 +1,28 @@
+package stock_allocation
+
+import (
+	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/presenter/stock_allocation"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
+	controller := stock_allocation.New(db)
+
+	// 專案設備的現貨分配
+	v10 := route.Group("authority").Group("v1.0").Group("projects")
+	v10.Use(middleware.APIKeyMiddleware(db, "projects"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	v10.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))           // 限制資料異動頻率
+	{
+		// 查詢由現貨支應與需另行採購的數量
+		v10.GET("/:projectId/allocations", controller.Summary)
+		// 將現貨設備分配給專案設備
+		v10.POST("/:projectId/allocations", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.Create)
+		// 取消分配
+		v10.DELETE("/:projectId/allocations/:allocationId", middleware.Transaction(db), controller.Delete)
+	}
+
+	return route
+}
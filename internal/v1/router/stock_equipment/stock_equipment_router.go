@@ -11,12 +11,13 @@ import (
 func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
 	controller := stock_equipment.New(db)
 	v10 := route.Group("authority").Group("v1.0").Group("stock-equipments")
-	v10.Use(middleware.JWTMiddleware()) // 加上 JWT 驗證
+	v10.Use(middleware.APIKeyMiddleware(db, "stock-equipments"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	v10.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))                   // 限制資料異動頻率
 	{
 		// 單筆建立現貨設備
-		v10.POST("", middleware.Transaction(db), controller.Create)
+		v10.POST("", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.Create)
 		// 批次建立現貨設備
-		v10.POST("/batch", middleware.Transaction(db), controller.CreateBatch)
+		v10.POST("/batch", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.CreateBatch)
 		// 獲取現貨設備列表
 		v10.GET("", controller.List)
 		// 根據現貨ID獲取設備列表
@@ -24,9 +25,26 @@ func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
 		// 根據設備ID獲取單筆設備
 		v10.GET("/:equipmentId", controller.GetByID)
 		// 更新設備
-		v10.PATCH("/:equipmentId", controller.Update)
-		// 刪除設備
-		v10.DELETE("/:equipmentId", controller.Delete)
+		v10.PATCH("/:equipmentId", middleware.Transaction(db), controller.Update)
+		// 刪除設備(移至資源回收筒)
+		v10.DELETE("/:equipmentId", middleware.Transaction(db), controller.Delete)
+
+		// 資源回收筒(管理員)
+		v10.GET("/trash", middleware.AdminMiddleware(), controller.Trash)
+		// 還原現貨設備(管理員)
+		v10.POST("/:equipmentId/restore", middleware.AdminMiddleware(), middleware.Transaction(db), controller.Restore)
 	}
+
+	// 現貨設備清單
+	stocks := route.Group("authority").Group("v1.0").Group("stocks")
+	stocks.Use(middleware.APIKeyMiddleware(db, "stocks"), middleware.JWTMiddleware()) // 加上 API 金鑰 / JWT 驗證
+	stocks.Use(middleware.RateLimitMiddleware(db, middleware.WriteRateLimit))         // 限制資料異動頻率
+	{
+		// 以完整清單取代現貨設備
+		stocks.PUT("/:stockId/equipments", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.Replace)
+		// 現貨設備異動紀錄
+		stocks.GET("/:stockId/equipments/history", controller.History)
+	}
+
 	return route
 }
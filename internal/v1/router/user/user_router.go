@@ -11,11 +11,25 @@ import (
 func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
 	controller := user.New(db)
 
+	// 公開路由的請求頻率限制:登入相關以 IP + 路由計算,主動要求寄送 email 的路由另以 IP 合併計算
+	authLimit := middleware.RateLimitMiddleware(db, middleware.AuthRateLimit)
+	emailLimit := middleware.RateLimitMiddleware(db, middleware.EmailRateLimit)
+
 	// 公開路由 - 登入相關
-	route.POST("/auth/request-code", controller.RequestVerificationCode) // 新增:請求驗證碼
-	route.POST("/auth/verify-login", controller.VerifyAndLogin)          // 新增:驗證碼登入
-	route.POST("/auth/login", controller.Login)                          // 保留原有的直接登入
+	// 登入政策由伺服器端決定,兩個入口行為相同:僅需密碼時直接回傳 token,否則回傳登入票證
+	route.POST("/auth/login", authLimit, controller.Login)
+	route.POST("/auth/request-code", authLimit, emailLimit, controller.Login)
+	route.POST("/auth/verify-login", authLimit, controller.VerifyAndLogin) // 以登入票證與驗證碼完成登入
 	route.POST("/auth/logout", controller.Logout)
+	route.POST("/auth/password/forgot", authLimit, emailLimit, middleware.Transaction(db), controller.ForgotPassword) // 寄送重設密碼連結
+	route.POST("/auth/password/reset", authLimit, middleware.Transaction(db), controller.ResetPassword)               // 以連結設定新密碼
+
+	// 公開路由 - OIDC 單一登入
+	route.GET("/auth/oidc/login", authLimit, controller.OIDCLogin)       // 導向身分提供者
+	route.GET("/auth/oidc/callback", authLimit, controller.OIDCCallback) // 身分提供者回呼
+
+	// 公開路由 - 驗證 token 用的公開金鑰
+	route.GET("/.well-known/jwks.json", controller.JWKS)
 
 	// 需要身份驗證的路由
 	auth := route.Group("/auth")
@@ -26,13 +40,24 @@ func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
 			userID, _ := ctx.Get("userID")
 			username, _ := ctx.Get("username")
 			role, _ := ctx.Get("role")
+			mustChangePassword, _ := ctx.Get("mustChangePassword")
 
 			ctx.JSON(200, gin.H{
-				"id":       userID,
-				"username": username,
-				"role":     role,
+				"id":                   userID,
+				"username":             username,
+				"role":                 role,
+				"must_change_password": mustChangePassword,
 			})
 		})
+
+		// 變更自己的密碼
+		auth.POST("/password/change", middleware.Transaction(db), controller.ChangePassword)
+
+		// 兩步驟驗證 - 驗證器 App (TOTP)
+		auth.POST("/2fa/totp/setup", controller.SetupTOTP)
+		auth.POST("/2fa/totp/confirm", middleware.Transaction(db), controller.ConfirmTOTP)
+		auth.POST("/2fa/totp/disable", middleware.Transaction(db), controller.DisableTOTP)
+		auth.POST("/2fa/recovery-codes", middleware.Transaction(db), controller.RegenerateRecoveryCodes)
 	}
 
 	// 管理員路由 - 用戶管理
@@ -40,7 +65,7 @@ func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
 	v10.Use(middleware.JWTMiddleware(), middleware.AdminMiddleware())
 	{
 		// 建立用戶
-		v10.POST("", middleware.Transaction(db), controller.Create)
+		v10.POST("", middleware.IdempotencyMiddleware(db), middleware.Transaction(db), controller.Create)
 		// 查詢用戶列表
 		v10.GET("", controller.List)
 		// 查詢單一用戶
@@ -49,6 +74,8 @@ func GetRoute(route *gin.Engine, db *gorm.DB) *gin.Engine {
 		v10.PATCH("/:userId", controller.Update)
 		// 刪除用戶
 		v10.DELETE("/:userId", controller.Delete)
+		// 解除帳號鎖定
+		v10.POST("/:userId/unlock", controller.Unlock)
 	}
 
 	return route
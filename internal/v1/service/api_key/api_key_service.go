@@ -0,0 +1,208 @@
+package api_key
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/api_keys"
+	userModel "esst_sendEmail/internal/v1/structure/users"
+
+	"gorm.io/gorm"
+)
+
+// keyPrefix 金鑰開頭,方便辨識與外洩掃描
+const keyPrefix = "esst_"
+
+// touchInterval 最後使用時間的更新間隔,避免每個請求都寫入資料庫
+const touchInterval = time.Minute
+
+// Scopes 可授權的範圍,資源名稱與路由群組相同
+var Scopes = []string{
+	"projects:read", "projects:write",
+	"equipments:read", "equipments:write",
+	"stocks:read", "stocks:write",
+	"stock-equipments:read", "stock-equipments:write",
+}
+
+var (
+	ErrInvalidScope = errors.New("不支援的授權範圍")
+	ErrInvalidKey   = errors.New("API 金鑰無效、已過期或已撤銷")
+)
+
+// Create 建立金鑰,完整金鑰僅在回傳值中出現一次,資料庫只保存雜湊
+func (s *service) Create(input *model.Created) (*model.Issued, error) {
+	for _, scope := range input.Scopes {
+		if !validScope(scope) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	prefix, err := randomHex(6)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := randomSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	table := &model.Table{
+		APIKeyID:   util.GenerateUUID(),
+		UserID:     input.UserID,
+		Name:       input.Name,
+		Prefix:     prefix,
+		SecretHash: hashSecret(secret),
+		Scopes:     strings.Join(input.Scopes, " "),
+		CreatedAt:  now,
+	}
+	if input.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, input.ExpiresInDays)
+		table.ExpiresAt = &expiresAt
+	}
+
+	err = s.Entity.Create(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return &model.Issued{
+		Base: *toBase(table),
+		Key:  keyPrefix + prefix + "." + secret,
+	}, nil
+}
+
+func (s *service) GetByID(input *model.Field) (*model.Base, error) {
+	table, err := s.Entity.GetByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBase(table), nil
+}
+
+func (s *service) List(input *model.Fields) (int64, []*model.Base, error) {
+	total, records, err := s.Entity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	output := make([]*model.Base, 0, len(records))
+	for _, record := range records {
+		output = append(output, toBase(record))
+	}
+
+	return total, output, nil
+}
+
+// Revoke 撤銷金鑰,撤銷後立即失效
+func (s *service) Revoke(input *model.Field) error {
+	return s.Entity.Revoke(input.APIKeyID, time.Now())
+}
+
+// Authenticate 驗證金鑰並回傳擁有者身分,角色以擁有者目前的角色為準
+func (s *service) Authenticate(key, ip string) (*model.Principal, error) {
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(key), keyPrefix), ".")
+	if !ok || prefix == "" || secret == "" {
+		return nil, ErrInvalidKey
+	}
+
+	table, err := s.Entity.GetByPrefix(prefix)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidKey
+		}
+		log.Error(err)
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(table.SecretHash), []byte(hashSecret(secret))) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	now := time.Now()
+	if table.RevokedAt != nil || (table.ExpiresAt != nil && now.After(*table.ExpiresAt)) {
+		return nil, ErrInvalidKey
+	}
+
+	owner, err := s.UserEntity.GetByID(&userModel.Field{ID: &table.UserID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidKey
+		}
+		log.Error(err)
+		return nil, err
+	}
+
+	if table.LastUsedAt == nil || now.Sub(*table.LastUsedAt) > touchInterval || table.LastUsedIP != ip {
+		if err := s.Entity.Touch(table.APIKeyID, ip, now); err != nil {
+			log.Error("Failed to update API key last used:", err)
+		}
+	}
+
+	return &model.Principal{
+		APIKeyID: table.APIKeyID,
+		UserID:   owner.ID,
+		Username: owner.Username,
+		Role:     owner.Role,
+		Scopes:   strings.Fields(table.Scopes),
+	}, nil
+}
+
+func toBase(table *model.Table) *model.Base {
+	return &model.Base{
+		APIKeyID:   table.APIKeyID,
+		UserID:     table.UserID,
+		Name:       table.Name,
+		Prefix:     keyPrefix + table.Prefix,
+		Scopes:     strings.Fields(table.Scopes),
+		ExpiresAt:  table.ExpiresAt,
+		LastUsedAt: table.LastUsedAt,
+		LastUsedIP: table.LastUsedIP,
+		RevokedAt:  table.RevokedAt,
+		CreatedAt:  table.CreatedAt,
+	}
+}
+
+func validScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// hashSecret 密鑰為高熵隨機值,以 SHA-256 雜湊即可
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func randomSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,37 @@
+package api_key
+
+import (
+	"esst_sendEmail/internal/v1/entity/api_key"
+	"esst_sendEmail/internal/v1/entity/user"
+	model "esst_sendEmail/internal/v1/structure/api_keys"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Create(input *model.Created) (*model.Issued, error)
+	GetByID(input *model.Field) (*model.Base, error)
+	List(input *model.Fields) (int64, []*model.Base, error)
+	Revoke(input *model.Field) error
+	Authenticate(key, ip string) (*model.Principal, error)
+}
+
+type service struct {
+	Entity     api_key.Entity
+	UserEntity user.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity:     api_key.New(db),
+		UserEntity: user.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity:     s.Entity.WithTrx(tx),
+		UserEntity: s.UserEntity.WithTrx(tx),
+	}
+}
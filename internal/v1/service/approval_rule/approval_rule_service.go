@@ -0,0 +1,121 @@
+package approval_rule
+
+import (
+	"encoding/json"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/approval_rules"
+)
+
+func (s *service) Create(input *model.Created) (*model.Base, error) {
+	table := &model.Table{
+		RuleID:       util.GenerateUUID(),
+		Name:         input.Name,
+		Level:        input.Level,
+		MinQuantity:  input.MinQuantity,
+		Team:         input.Team,
+		ApproverRole: input.ApproverRole,
+		Enabled:      true,
+		CreatedAt:    time.Now(),
+	}
+	if input.Enabled != nil {
+		table.Enabled = *input.Enabled
+	}
+
+	err := s.Entity.Create(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return toBase(table)
+}
+
+func (s *service) List(input *model.Fields) (quantity int64, output []*model.Base, err error) {
+	amount, fields, err := s.Entity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	marshal, err := json.Marshal(fields)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	return amount, output, nil
+}
+
+func (s *service) GetByID(input *model.Field) (*model.Base, error) {
+	field, err := s.Entity.GetByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBase(field)
+}
+
+func (s *service) Update(input *model.Updated) (*model.Base, error) {
+	field, err := s.Entity.GetByID(&model.Field{RuleID: input.RuleID})
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		field.Name = *input.Name
+	}
+	if input.Level != nil {
+		field.Level = *input.Level
+	}
+	if input.MinQuantity != nil {
+		field.MinQuantity = input.MinQuantity
+	}
+	if input.Team != nil {
+		field.Team = input.Team
+	}
+	if input.ApproverRole != nil {
+		field.ApproverRole = *input.ApproverRole
+	}
+	if input.Enabled != nil {
+		field.Enabled = *input.Enabled
+	}
+	now := time.Now()
+	field.UpdatedAt = &now
+
+	err = s.Entity.Update(field)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return toBase(field)
+}
+
+func (s *service) Delete(input *model.Field) error {
+	return s.Entity.Delete(input)
+}
+
+func toBase(table *model.Table) (output *model.Base, err error) {
+	marshal, err := json.Marshal(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,33 @@
+package approval_rule
+
+import (
+	"esst_sendEmail/internal/v1/entity/approval_rule"
+	model "esst_sendEmail/internal/v1/structure/approval_rules"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Create(input *model.Created) (*model.Base, error)
+	List(input *model.Fields) (int64, []*model.Base, error)
+	GetByID(input *model.Field) (*model.Base, error)
+	Update(input *model.Updated) (*model.Base, error)
+	Delete(input *model.Field) error
+}
+
+type service struct {
+	Entity approval_rule.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity: approval_rule.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity: s.Entity.WithTrx(tx),
+	}
+}
@@ -0,0 +1,298 @@
+package attachment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/attachments"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+	stockModel "esst_sendEmail/internal/v1/structure/stocks"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTooLarge       = errors.New("檔案大小超過上限")
+	ErrTypeNotAllowed = errors.New("不允許的檔案類型")
+	ErrNotUploader    = errors.New("只有上傳者或管理員可以刪除附件")
+)
+
+// storageTimeout 單次儲存服務操作的時間上限
+const storageTimeout = 5 * time.Minute
+
+// defaultExtensions 預設允許的副檔名(報價單、採購單、規格書常見格式)
+const defaultExtensions = ".pdf,.png,.jpg,.jpeg,.xls,.xlsx,.doc,.docx,.csv,.txt,.zip"
+
+// MaxSize 單一檔案大小上限,由 ATTACHMENT_MAX_SIZE_MB 設定,預設 20MB
+func MaxSize() int64 {
+	mb, err := strconv.ParseInt(os.Getenv("ATTACHMENT_MAX_SIZE_MB"), 10, 64)
+	if err != nil || mb <= 0 {
+		mb = 20
+	}
+	return mb << 20
+}
+
+// URLExpires 下載網址有效時間,由 ATTACHMENT_URL_EXPIRE_MINUTES 設定,預設 15 分鐘
+func URLExpires() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("ATTACHMENT_URL_EXPIRE_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 15
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// allowedExtension 副檔名是否在 ATTACHMENT_ALLOWED_EXTENSIONS 允許清單中
+func allowedExtension(ext string) bool {
+	allowed := os.Getenv("ATTACHMENT_ALLOWED_EXTENSIONS")
+	if allowed == "" {
+		allowed = defaultExtensions
+	}
+	for _, item := range strings.Split(allowed, ",") {
+		if strings.EqualFold(strings.TrimSpace(item), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffMatches 檔案內容是否與副檔名相符,避免以改副檔名的方式上傳其他檔案
+func sniffMatches(ext, detected string) bool {
+	switch ext {
+	case ".pdf":
+		return detected == "application/pdf"
+	case ".png", ".jpg", ".jpeg":
+		return strings.HasPrefix(detected, "image/")
+	case ".csv", ".txt":
+		return strings.HasPrefix(detected, "text/plain")
+	}
+	return true
+}
+
+// Create 上傳附件,檔案寫入儲存服務後才建立紀錄,建立失敗時移除已上傳的檔案
+func (s *service) Create(input *model.Created) (*model.Base, error) {
+	err := s.checkTarget(input.TargetType, input.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.File.Size > MaxSize() {
+		return nil, ErrTooLarge
+	}
+
+	filename := filepath.Base(input.File.Filename)
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !allowedExtension(ext) {
+		return nil, ErrTypeNotAllowed
+	}
+
+	file, err := input.File.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// 檢查檔案內容並計算檢查碼
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if !sniffMatches(ext, http.DetectContentType(head[:n])) {
+		return nil, ErrTypeNotAllowed
+	}
+
+	hash := sha256.New()
+	hash.Write(head[:n])
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, err
+	}
+	size += int64(n)
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	table := &model.Table{
+		AttachmentID: util.GenerateUUID(),
+		TargetType:   input.TargetType,
+		TargetID:     input.TargetID,
+		Filename:     filename,
+		Size:         size,
+		ContentType:  contentType,
+		Checksum:     hex.EncodeToString(hash.Sum(nil)),
+		UploadedBy:   &input.UploadedBy,
+		CreatedAt:    time.Now(),
+	}
+	table.StorageKey = fmt.Sprintf("%ss/%s/%s%s", table.TargetType, table.TargetID, table.AttachmentID, ext)
+
+	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+
+	err = s.Storage.Put(ctx, table.StorageKey, file, size, contentType)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = s.Entity.Create(table)
+	if err != nil {
+		log.Error(err)
+		if deleteErr := s.Storage.Delete(ctx, table.StorageKey); deleteErr != nil {
+			log.Error(deleteErr)
+		}
+		return nil, err
+	}
+
+	return toBase(table)
+}
+
+func (s *service) List(input *model.Fields) (quantity int64, output []*model.Base, err error) {
+	amount, fields, err := s.Entity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	marshal, err := json.Marshal(fields)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	return amount, output, nil
+}
+
+// Download 產生限時下載網址
+func (s *service) Download(input *model.Field) (*model.Download, error) {
+	field, err := s.Entity.GetByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+
+	expires := URLExpires()
+	url, err := s.Storage.PresignGet(ctx, field.StorageKey, field.Filename, expires)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return &model.Download{
+		URL:       url,
+		ExpiresAt: time.Now().Add(expires),
+	}, nil
+}
+
+// Delete 刪除附件與儲存服務中的檔案(僅限上傳者或管理員)
+func (s *service) Delete(input *model.Field) error {
+	field, err := s.Entity.GetByID(input)
+	if err != nil {
+		return err
+	}
+	if input.Role != "admin" && (field.UploadedBy == nil || *field.UploadedBy != input.UserID) {
+		return ErrNotUploader
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+
+	err = s.Storage.Delete(ctx, field.StorageKey)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = s.Entity.Delete(input)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge 永久刪除對象已超過保留期限(或已不存在)的附件與儲存服務中的檔案
+// 檔案刪除失敗時保留紀錄,下次排程再重試
+func (s *service) Purge(before time.Time) (int64, error) {
+	fields, err := s.Entity.ListPurgeable(before)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	var count int64
+	for _, field := range fields {
+		ctx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+		err = s.Storage.Delete(ctx, field.StorageKey)
+		cancel()
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+
+		err = s.Entity.Delete(&model.Field{AttachmentID: field.AttachmentID})
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error(err)
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// checkTarget 確認附件對象存在
+func (s *service) checkTarget(targetType, targetID string) error {
+	if targetType == model.TargetStock {
+		_, err := s.StockEntity.GetByID(&stockModel.Field{StockID: targetID})
+		return err
+	}
+
+	_, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: targetID})
+	return err
+}
+
+func toBase(table *model.Table) (output *model.Base, err error) {
+	marshal, err := json.Marshal(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
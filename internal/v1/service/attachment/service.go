@@ -0,0 +1,47 @@
+package attachment
+
+import (
+	"time"
+
+	"esst_sendEmail/internal/pkg/storage"
+	"esst_sendEmail/internal/v1/entity/attachment"
+	"esst_sendEmail/internal/v1/entity/project"
+	"esst_sendEmail/internal/v1/entity/stock"
+	model "esst_sendEmail/internal/v1/structure/attachments"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Create(input *model.Created) (*model.Base, error)
+	List(input *model.Fields) (int64, []*model.Base, error)
+	Download(input *model.Field) (*model.Download, error)
+	Delete(input *model.Field) error
+	Purge(before time.Time) (int64, error)
+}
+
+type service struct {
+	Entity        attachment.Entity
+	ProjectEntity project.Entity
+	StockEntity   stock.Entity
+	Storage       storage.Storage
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity:        attachment.New(db),
+		ProjectEntity: project.New(db),
+		StockEntity:   stock.New(db),
+		Storage:       storage.New(),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity:        s.Entity.WithTrx(tx),
+		ProjectEntity: s.ProjectEntity.WithTrx(tx),
+		StockEntity:   s.StockEntity.WithTrx(tx),
+		Storage:       s.Storage,
+	}
+}
@@ -0,0 +1,190 @@
+package comment
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/comments"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+	stockModel "esst_sendEmail/internal/v1/structure/stocks"
+)
+
+var ErrNotAuthor = errors.New("只有留言者本人可以編輯或刪除留言")
+
+// mentionPattern @帳號,帳號前不可緊接英數字(避免誤判 email)
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.])@([A-Za-z0-9_.\-]+)`)
+
+// Mentions 取出留言中提及的帳號(不重複,依出現順序)
+func Mentions(body string) []string {
+	usernames := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// 句尾標點不算帳號的一部分
+		username := strings.TrimRight(match[1], ".-")
+		if username != "" && !seen[username] {
+			seen[username] = true
+			usernames = append(usernames, username)
+		}
+	}
+	return usernames
+}
+
+// Create 新增留言,回傳留言與需要通知的提及帳號
+func (s *service) Create(input *model.Created) (*model.Base, []string, error) {
+	_, err := s.targetName(input.TargetType, input.TargetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	table := &model.Table{
+		CommentID:  util.GenerateUUID(),
+		TargetType: input.TargetType,
+		TargetID:   input.TargetID,
+		AuthorID:   &input.AuthorID,
+		Body:       input.Body,
+		CreatedAt:  time.Now(),
+	}
+
+	err = s.Entity.Create(table)
+	if err != nil {
+		log.Error(err)
+		return nil, nil, err
+	}
+
+	output, err := s.get(&model.Field{CommentID: table.CommentID, TargetType: table.TargetType, TargetID: table.TargetID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output, Mentions(input.Body), nil
+}
+
+func (s *service) List(input *model.Fields) (quantity int64, output []*model.Base, err error) {
+	amount, rows, err := s.Entity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	marshal, err := json.Marshal(rows)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	return amount, output, nil
+}
+
+// Update 編輯留言,只通知新增的提及帳號
+func (s *service) Update(input *model.Updated) (*model.Base, []string, error) {
+	field := &model.Field{CommentID: input.CommentID, TargetType: input.TargetType, TargetID: input.TargetID}
+	row, err := s.Entity.GetByID(field)
+	if err != nil {
+		return nil, nil, err
+	}
+	if row.AuthorID == nil || *row.AuthorID != input.AuthorID {
+		return nil, nil, ErrNotAuthor
+	}
+
+	previous := make(map[string]bool)
+	for _, username := range Mentions(row.Body) {
+		previous[username] = true
+	}
+
+	now := time.Now()
+	row.Body = input.Body
+	row.UpdatedAt = &now
+
+	err = s.Entity.Update(&row.Table)
+	if err != nil {
+		log.Error(err)
+		return nil, nil, err
+	}
+
+	added := make([]string, 0)
+	for _, username := range Mentions(input.Body) {
+		if !previous[username] {
+			added = append(added, username)
+		}
+	}
+
+	output, err := s.get(field)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output, added, nil
+}
+
+// Delete 刪除留言(僅限留言者本人)
+func (s *service) Delete(input *model.Field) error {
+	row, err := s.Entity.GetByID(input)
+	if err != nil {
+		return err
+	}
+	if row.AuthorID == nil || *row.AuthorID != input.AuthorID {
+		return ErrNotAuthor
+	}
+
+	err = s.Entity.Delete(input)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge 永久刪除對象已超過保留期限或已不存在的留言
+func (s *service) Purge(before time.Time) (int64, error) {
+	return s.Entity.Purge(before)
+}
+
+// targetName 確認留言對象存在並回傳名稱
+func (s *service) targetName(targetType, targetID string) (string, error) {
+	if targetType == model.TargetStock {
+		stock, err := s.StockEntity.GetByID(&stockModel.Field{StockID: targetID})
+		if err != nil {
+			return "", err
+		}
+		return stock.StockName, nil
+	}
+
+	project, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: targetID})
+	if err != nil {
+		return "", err
+	}
+	return project.ProjectName, nil
+}
+
+func (s *service) get(input *model.Field) (output *model.Base, err error) {
+	row, err := s.Entity.GetByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	marshal, err := json.Marshal(row)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
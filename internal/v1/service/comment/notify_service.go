@@ -0,0 +1,70 @@
+package comment
+
+import (
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/mail"
+	model "esst_sendEmail/internal/v1/structure/comments"
+)
+
+// NotifyMentions 通知被提及的使用者(LINE 群組與個人信箱),不存在的帳號略過
+func (s *service) NotifyMentions(comment *model.Base, usernames []string) {
+	if comment == nil || len(usernames) == 0 {
+		return
+	}
+
+	users, err := s.UserEntity.ListByUsernames(usernames)
+	if err != nil {
+		log.Error("Failed to query mentioned users:", err)
+		return
+	}
+	if len(users) == 0 {
+		return
+	}
+
+	targetName, err := s.targetName(comment.TargetType, comment.TargetID)
+	if err != nil {
+		log.Error("Failed to query comment target:", err)
+		return
+	}
+
+	author := ""
+	if comment.AuthorName != nil {
+		author = *comment.AuthorName
+	}
+
+	mentioned := make([]string, 0, len(users))
+	for _, u := range users {
+		mentioned = append(mentioned, u.Username)
+	}
+
+	lineBotService := linebot.New()
+	err = lineBotService.SendCommentMentionNotification(&linebot.CommentMentionData{
+		TargetType: comment.TargetType,
+		TargetID:   comment.TargetID,
+		TargetName: targetName,
+		Author:     author,
+		Body:       comment.Body,
+		Mentioned:  mentioned,
+	})
+	if err != nil {
+		log.Error("Failed to send comment mention LINE notification:", err)
+	}
+
+	emailService := mail.New()
+	for _, u := range users {
+		if u.Email == "" || (comment.AuthorID != nil && u.ID == *comment.AuthorID) {
+			continue
+		}
+		err = emailService.SendCommentMentionEmail(u.Email, u.Username, &mail.CommentMentionData{
+			TargetType: comment.TargetType,
+			TargetID:   comment.TargetID,
+			TargetName: targetName,
+			Author:     author,
+			Body:       comment.Body,
+		})
+		if err != nil {
+			log.Error("Failed to send comment mention email:", err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package comment
+
+import (
+	"time"
+
+	"esst_sendEmail/internal/v1/entity/comment"
+	"esst_sendEmail/internal/v1/entity/project"
+	"esst_sendEmail/internal/v1/entity/stock"
+	"esst_sendEmail/internal/v1/entity/user"
+	model "esst_sendEmail/internal/v1/structure/comments"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Create(input *model.Created) (*model.Base, []string, error)
+	List(input *model.Fields) (int64, []*model.Base, error)
+	Update(input *model.Updated) (*model.Base, []string, error)
+	Delete(input *model.Field) error
+	NotifyMentions(comment *model.Base, usernames []string)
+	Purge(before time.Time) (int64, error)
+}
+
+type service struct {
+	Entity        comment.Entity
+	ProjectEntity project.Entity
+	StockEntity   stock.Entity
+	UserEntity    user.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity:        comment.New(db),
+		ProjectEntity: project.New(db),
+		StockEntity:   stock.New(db),
+		UserEntity:    user.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity:        s.Entity.WithTrx(tx),
+		ProjectEntity: s.ProjectEntity.WithTrx(tx),
+		StockEntity:   s.StockEntity.WithTrx(tx),
+		UserEntity:    s.UserEntity.WithTrx(tx),
+	}
+}
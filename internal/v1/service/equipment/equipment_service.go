@@ -3,8 +3,10 @@ package equipment
 import (
 	"encoding/json"
 	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/pricing"
 	"esst_sendEmail/internal/pkg/util"
 	model "esst_sendEmail/internal/v1/structure/equipments"
+	"time"
 )
 
 func (s *service) Create(input *model.Created) (*model.Base, error) {
@@ -39,6 +41,7 @@ func (s *service) Create(input *model.Created) (*model.Base, error) {
 		return nil, err
 	}
 
+	withAmount(&output)
 	return &output, nil
 }
 
@@ -68,6 +71,7 @@ func (s *service) List(input *model.Fields) (quantity int64, output []*model.Bas
 		return 0, output, err
 	}
 
+	withAmount(output...)
 	return amount, output, err
 }
 
@@ -90,6 +94,7 @@ func (s *service) ListByProjectID(projectID string) (output []*model.Base, err e
 		return nil, err
 	}
 
+	withAmount(output...)
 	return output, nil
 }
 
@@ -115,9 +120,11 @@ func (s *service) GetByID(input *model.Field) (output *model.Base, err error) {
 		return nil, err
 	}
 
+	withAmount(output)
 	return output, nil
 }
 
+// Delete 軟刪除設備
 func (s *service) Delete(input *model.Updated) (err error) {
 	field, err := s.Entity.GetByID(&model.Field{EquipmentID: input.EquipmentID})
 	if err != nil {
@@ -125,7 +132,15 @@ func (s *service) Delete(input *model.Updated) (err error) {
 
 		return err
 	}
-	err = s.Entity.Update(field)
+	err = s.Entity.Delete(&model.Field{EquipmentID: field.EquipmentID}, input.Version, input.DeletedBy, time.Now())
+	if err != nil {
+		log.Error(err)
+
+		return err
+	}
+
+	// 一併取消分配,還原時才不會帶回未檢查庫存的分配
+	err = s.AllocationEntity.DeleteByEquipmentID(field.EquipmentID)
 
 	return err
 }
@@ -152,7 +167,19 @@ func (s *service) Update(input *model.Updated) (err error) {
 		return err
 	}
 
+	// 料號目錄參照隨料號一起更新,未對應到目錄時清除
+	field.PartID = input.PartID
+	// 以 If-Match 的版本號更新,避免覆蓋他人的修改
+	field.Version = input.Version
+
 	err = s.Entity.Update(field)
 
 	return err
 }
+
+// withAmount 依單價、折扣、幣別與稅率計算每筆設備的金額
+func withAmount(output ...*model.Base) {
+	for _, eq := range output {
+		eq.Amount = pricing.Of(eq.Quantity, eq.UnitPrice, eq.Discount, eq.Currency, eq.TaxRate)
+	}
+}
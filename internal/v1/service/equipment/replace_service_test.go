@@ -0,0 +1,89 @@
+package equipment
+
+import (
+	"testing"
+
+	model "esst_sendEmail/internal/v1/structure/equipments"
+)
+
+func TestSame(t *testing.T) {
+	price := func(v float64) *float64 { return &v }
+	text := func(v string) *string { return &v }
+
+	current := &model.Table{
+		PartNumber:  "ABC100",
+		PartID:      text("part"),
+		Quantity:    2,
+		Description: "switch",
+		UnitPrice:   price(100),
+		Currency:    text("TWD"),
+	}
+	unchanged := model.BatchEquipment{
+		PartNumber:  "ABC100",
+		PartID:      text("part"),
+		Quantity:    2,
+		Description: "switch",
+		UnitPrice:   price(100),
+		Currency:    text("TWD"),
+	}
+
+	tests := []struct {
+		name   string
+		modify func(eq *model.BatchEquipment)
+		want   bool
+	}{
+		{"identical", func(eq *model.BatchEquipment) {}, true},
+		{"quantity changed", func(eq *model.BatchEquipment) { eq.Quantity = 3 }, false},
+		{"part number changed", func(eq *model.BatchEquipment) { eq.PartNumber = "ABC200" }, false},
+		{"description changed", func(eq *model.BatchEquipment) { eq.Description = "router" }, false},
+		{"price changed", func(eq *model.BatchEquipment) { eq.UnitPrice = price(90) }, false},
+		{"price removed", func(eq *model.BatchEquipment) { eq.UnitPrice = nil }, false},
+		{"discount added", func(eq *model.BatchEquipment) { eq.Discount = price(10) }, false},
+		{"currency changed", func(eq *model.BatchEquipment) { eq.Currency = text("USD") }, false},
+		{"part unlinked", func(eq *model.BatchEquipment) { eq.PartID = nil }, false},
+		{"same values in new pointers", func(eq *model.BatchEquipment) { eq.UnitPrice, eq.PartID = price(100), text("part") }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eq := unchanged
+			tt.modify(&eq)
+			if got := same(current, eq); got != tt.want {
+				t.Errorf("same() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffEntries(t *testing.T) {
+	diff := &model.Diff{
+		Added:   []*model.Base{{EquipmentID: "a", PartNumber: "P-A", Quantity: 1}},
+		Updated: []*model.Change{{Before: &model.Base{EquipmentID: "b", PartNumber: "P-B", Quantity: 1}, After: &model.Base{EquipmentID: "b", PartNumber: "P-B", Quantity: 5}}},
+		Deleted: []*model.Base{{EquipmentID: "c", PartNumber: "P-C", Quantity: 2}},
+	}
+
+	entries := diff.Entries()
+	want := []struct {
+		action    string
+		lineID    string
+		hasBefore bool
+		hasAfter  bool
+	}{
+		{"add", "a", false, true},
+		{"update", "b", true, true},
+		{"delete", "c", true, false},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Entries() = %d entries, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		entry := entries[i]
+		if entry.Action != w.action || entry.LineID != w.lineID || (entry.Before != nil) != w.hasBefore || (entry.After != nil) != w.hasAfter {
+			t.Errorf("entry %d = %+v, want %+v", i, entry, w)
+		}
+	}
+
+	if got := diff.QuantityDelta(); got != 1+4-2 {
+		t.Errorf("QuantityDelta() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,47 @@
+package equipment_change
+
+import (
+	"encoding/json"
+
+	"esst_sendEmail/internal/pkg/log"
+	model "esst_sendEmail/internal/v1/structure/equipment_changes"
+)
+
+func (s *service) List(input *model.Fields) (int64, []*model.Base, error) {
+	total, records, err := s.Entity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	output := make([]*model.Base, 0, len(records))
+	for _, record := range records {
+		output = append(output, toBase(record))
+	}
+
+	return total, output, nil
+}
+
+// toBase 異動前後的設備以 JSON 文字保存,回傳時還原為物件
+func toBase(record *model.Table) *model.Base {
+	base := &model.Base{
+		ChangeID:   record.ChangeID,
+		BatchID:    record.BatchID,
+		TargetType: record.TargetType,
+		TargetID:   record.TargetID,
+		LineID:     record.LineID,
+		Action:     record.Action,
+		PartNumber: record.PartNumber,
+		ChangedBy:  record.ChangedBy,
+		ChangedAt:  record.ChangedAt,
+	}
+
+	if record.Before != nil && json.Valid([]byte(*record.Before)) {
+		base.Before = json.RawMessage(*record.Before)
+	}
+	if record.After != nil && json.Valid([]byte(*record.After)) {
+		base.After = json.RawMessage(*record.After)
+	}
+
+	return base
+}
@@ -0,0 +1,29 @@
+package equipment_change
+
+import (
+	"esst_sendEmail/internal/v1/entity/equipment_change"
+	model "esst_sendEmail/internal/v1/structure/equipment_changes"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	List(input *model.Fields) (int64, []*model.Base, error)
+}
+
+type service struct {
+	Entity equipment_change.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity: equipment_change.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity: s.Entity.WithTrx(tx),
+	}
+}
@@ -0,0 +1,115 @@
+package idempotency_key
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/idempotency_keys"
+)
+
+// staleAfter 處理中的紀錄超過此時間仍未完成,視為前一次請求已中斷,允許重新處理
+const staleAfter = 5 * time.Minute
+
+var (
+	ErrKeyMismatch = errors.New("Idempotency-Key 已用於不同的請求內容")
+	ErrInProgress  = errors.New("相同 Idempotency-Key 的請求仍在處理中")
+)
+
+// TTL 回應保存時間,由 IDEMPOTENCY_TTL_HOURS 設定,預設 24 小時
+func TTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("IDEMPOTENCY_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 24
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// Begin 登記一筆請求
+// 首次出現時回傳紀錄編號,呼叫端處理完成後需呼叫 Complete 或 Release
+// 已完成的相同請求回傳原始回應;內容不同回傳 ErrKeyMismatch;仍在處理中回傳 ErrInProgress
+func (s *service) Begin(input *model.Begun) (string, *model.Replay, error) {
+	// 最多重試一次:既有紀錄已過期或中斷時刪除後重新登記
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		table := &model.Table{
+			IdempotencyKeyID: util.GenerateUUID(),
+			UserID:           input.UserID,
+			Key:              input.Key,
+			Method:           input.Method,
+			Path:             input.Path,
+			RequestHash:      input.RequestHash,
+			CreatedAt:        now,
+			ExpiresAt:        now.Add(TTL()),
+		}
+
+		created, err := s.Entity.Create(table)
+		if err != nil {
+			log.Error(err)
+			return "", nil, err
+		}
+		if created {
+			return table.IdempotencyKeyID, nil, nil
+		}
+
+		existing, err := s.Entity.Get(input.UserID, input.Key)
+		if err != nil {
+			log.Error(err)
+			return "", nil, err
+		}
+
+		expired := !now.Before(existing.ExpiresAt)
+		stale := existing.StatusCode == nil && now.Sub(existing.CreatedAt) > staleAfter
+		if expired || stale {
+			if err := s.Entity.Delete(existing.IdempotencyKeyID); err != nil {
+				log.Error(err)
+				return "", nil, err
+			}
+			continue
+		}
+
+		if existing.Method != input.Method || existing.Path != input.Path || existing.RequestHash != input.RequestHash {
+			return "", nil, ErrKeyMismatch
+		}
+		if existing.StatusCode == nil || existing.ResponseBody == nil {
+			return "", nil, ErrInProgress
+		}
+
+		return "", &model.Replay{
+			StatusCode:   *existing.StatusCode,
+			ResponseBody: []byte(*existing.ResponseBody),
+		}, nil
+	}
+
+	return "", nil, ErrInProgress
+}
+
+// Complete 保存回應,之後相同的請求直接回傳此回應
+func (s *service) Complete(input *model.Completed) error {
+	err := s.Entity.Complete(input.IdempotencyKeyID, input.StatusCode, string(input.ResponseBody), time.Now())
+	if err != nil {
+		log.Error(err)
+	}
+	return err
+}
+
+// Release 放棄紀錄(例如伺服器錯誤),讓用戶端可以使用相同的金鑰重試
+func (s *service) Release(id string) error {
+	err := s.Entity.Delete(id)
+	if err != nil {
+		log.Error(err)
+	}
+	return err
+}
+
+// Purge 刪除已過期的紀錄
+func (s *service) Purge(before time.Time) (int64, error) {
+	count, err := s.Entity.Purge(before)
+	if err != nil {
+		log.Error(err)
+	}
+	return count, err
+}
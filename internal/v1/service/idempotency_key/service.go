@@ -0,0 +1,34 @@
+package idempotency_key
+
+import (
+	"time"
+
+	"esst_sendEmail/internal/v1/entity/idempotency_key"
+	model "esst_sendEmail/internal/v1/structure/idempotency_keys"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Begin(input *model.Begun) (string, *model.Replay, error)
+	Complete(input *model.Completed) error
+	Release(id string) error
+	Purge(before time.Time) (int64, error)
+}
+
+type service struct {
+	Entity idempotency_key.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity: idempotency_key.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity: s.Entity.WithTrx(tx),
+	}
+}
@@ -0,0 +1,242 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/similarity"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/inventories"
+	movementModel "esst_sendEmail/internal/v1/structure/inventory_movements"
+	stockEquipmentModel "esst_sendEmail/internal/v1/structure/stock_equipments"
+)
+
+var (
+	ErrInsufficient    = errors.New("可用庫存不足")
+	ErrNotReserved     = errors.New("出貨數量超過此現貨設備尚未出貨的保留數量")
+	ErrBelowReserved   = errors.New("調整後的在庫數量不可少於已保留數量")
+	ErrInvalidQuantity = errors.New("進貨與出貨數量需大於 0")
+	ErrShipOnly        = errors.New("只有出貨可以指定現貨設備")
+)
+
+// Enabled 是否啟用現貨庫存保留,由 INVENTORY_ENABLED 設定,預設不啟用
+// 啟用後建立現貨報備會自動保留庫存,可用數量不足時拒絕建立
+func Enabled() bool {
+	return os.Getenv("INVENTORY_ENABLED") == "true"
+}
+
+func (s *service) List(input *model.Fields) (quantity int64, output []*model.Base, err error) {
+	amount, fields, err := s.Entity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	output = make([]*model.Base, 0, len(fields))
+	for _, field := range fields {
+		base, err := toBase(field)
+		if err != nil {
+			return 0, nil, err
+		}
+		output = append(output, base)
+	}
+
+	return amount, output, nil
+}
+
+func (s *service) GetByID(input *model.Field) (*model.Base, error) {
+	field, err := s.Entity.GetByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBase(field)
+}
+
+func (s *service) ListMovements(input *movementModel.Fields) (quantity int64, output []*movementModel.Base, err error) {
+	amount, fields, err := s.MovementEntity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	marshal, err := json.Marshal(fields)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	return amount, output, nil
+}
+
+// Record 手動登錄進貨、出貨或盤點調整
+// 出貨指定現貨設備時由該設備的保留數量出貨,未指定時只能使用可用數量
+func (s *service) Record(input *movementModel.Created) (*movementModel.Base, error) {
+	if input.Type != movementModel.TypeAdjust && input.Quantity < 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	now := time.Now()
+	movement := &movementModel.Table{
+		MovementID: util.GenerateUUID(),
+		Type:       input.Type,
+		Quantity:   input.Quantity,
+		Note:       input.Note,
+		CreatedAt:  now,
+	}
+	if input.CreatedBy != "" {
+		movement.CreatedBy = &input.CreatedBy
+	}
+
+	partNumber, partID := input.PartNumber, input.PartID
+	var line *stockEquipmentModel.Table
+	if input.StockEquipmentID != nil {
+		if input.Type != movementModel.TypeShip {
+			return nil, ErrShipOnly
+		}
+
+		var err error
+		line, err = s.StockEquipmentEntity.GetByID(&stockEquipmentModel.Field{StockEquipmentID: *input.StockEquipmentID})
+		if err != nil {
+			return nil, err
+		}
+
+		partNumber, partID = line.PartNumber, line.PartID
+		movement.StockID = &line.StockID
+		movement.StockEquipmentID = &line.StockEquipmentID
+	}
+
+	inventories, err := s.lock([]*model.Table{newInventory(partNumber, partID, now)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	current, ok := inventories[similarity.NormalizePartNumber(partNumber)]
+	if !ok {
+		return nil, ErrInsufficient
+	}
+	movement.InventoryID = current.InventoryID
+
+	switch input.Type {
+	case movementModel.TypeReceive:
+		current.OnHand += input.Quantity
+	case movementModel.TypeAdjust:
+		if current.OnHand+input.Quantity < current.Reserved {
+			return nil, ErrBelowReserved
+		}
+		current.OnHand += input.Quantity
+	case movementModel.TypeShip:
+		if line != nil {
+			outstanding, err := s.MovementEntity.Outstanding("", line.StockEquipmentID)
+			if err != nil {
+				return nil, err
+			}
+
+			var reserved int64
+			for _, o := range outstanding {
+				if o.InventoryID == current.InventoryID {
+					reserved += o.Reserved
+				}
+			}
+			if input.Quantity > reserved {
+				return nil, ErrNotReserved
+			}
+			current.Reserved -= input.Quantity
+		} else if input.Quantity > current.OnHand-current.Reserved {
+			return nil, ErrInsufficient
+		}
+		current.OnHand -= input.Quantity
+	}
+
+	if current.PartID == nil {
+		current.PartID = partID
+	}
+	current.UpdatedAt = now
+
+	err = s.Entity.Update(current)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = s.MovementEntity.CreateBatch([]*movementModel.Table{movement})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	output := &movementModel.Base{}
+	marshal, err := json.Marshal(movement)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// lock 建立尚未存在的料號庫存後鎖定,回傳以正規化料號為鍵的庫存
+func (s *service) lock(ensure []*model.Table, ids []string) (map[string]*model.Table, error) {
+	normalized := make([]string, 0, len(ensure))
+	for _, inventory := range ensure {
+		normalized = append(normalized, inventory.Normalized)
+	}
+
+	err := s.Entity.Ensure(ensure)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	records, err := s.Entity.ListForUpdate(normalized, ids)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	output := make(map[string]*model.Table, len(records))
+	for _, record := range records {
+		output[record.Normalized] = record
+	}
+
+	return output, nil
+}
+
+func newInventory(partNumber string, partID *string, now time.Time) *model.Table {
+	return &model.Table{
+		InventoryID: util.GenerateUUID(),
+		PartNumber:  partNumber,
+		Normalized:  similarity.NormalizePartNumber(partNumber),
+		PartID:      partID,
+		UpdatedAt:   now,
+	}
+}
+
+func toBase(table *model.Table) (output *model.Base, err error) {
+	marshal, err := json.Marshal(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	output.Available = table.OnHand - table.Reserved
+	return output, nil
+}
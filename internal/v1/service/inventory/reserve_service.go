@@ -0,0 +1,190 @@
+package inventory
+
+import (
+	"sort"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/similarity"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/inventories"
+	movementModel "esst_sendEmail/internal/v1/structure/inventory_movements"
+	stockEquipmentModel "esst_sendEmail/internal/v1/structure/stock_equipments"
+)
+
+// reservation 現貨設備在某個料號庫存上的保留
+type reservation struct {
+	stockEquipmentID string
+	inventoryID      string
+}
+
+// Sync 依現貨報備目前的設備重新計算庫存保留
+// 新增或增量的設備保留庫存,刪除、減量或改料號的設備釋放保留(已出貨的數量不再保留);
+// 現貨報備刪除後設備皆已移除,會釋放全部保留。可用數量不足時不做任何異動並回傳 ErrInsufficient
+func (s *service) Sync(stockID string) ([]*model.Shortage, error) {
+	if !Enabled() {
+		return nil, nil
+	}
+
+	lines, err := s.StockEquipmentEntity.ListByStockID(stockID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	outstanding, err := s.MovementEntity.Outstanding(stockID, "")
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	ensure := make([]*model.Table, 0, len(lines))
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		normalized := similarity.NormalizePartNumber(line.PartNumber)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		ensure = append(ensure, newInventory(line.PartNumber, line.PartID, now))
+	}
+
+	ids := make([]string, 0, len(outstanding))
+	for _, o := range outstanding {
+		ids = append(ids, o.InventoryID)
+	}
+
+	inventories, err := s.lock(ensure, ids)
+	if err != nil {
+		return nil, err
+	}
+	movements, changes, shortages := reconcile(stockID, lines, outstanding, inventories, now)
+	if len(shortages) > 0 {
+		return shortages, ErrInsufficient
+	}
+
+	byID := make(map[string]*model.Table, len(inventories))
+	for _, inventory := range inventories {
+		byID[inventory.InventoryID] = inventory
+	}
+	for _, inventoryID := range sortedKeys(changes) {
+		inventory := byID[inventoryID]
+		inventory.Reserved += changes[inventoryID]
+		inventory.UpdatedAt = now
+
+		err = s.Entity.Update(inventory)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	}
+
+	err = s.MovementEntity.CreateBatch(movements)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// reconcile 比對現貨設備與目前的保留,產生保留/釋放異動與各庫存的保留增減,並找出可用數量不足的料號
+// inventories 以正規化料號為 key;已出貨的數量不再保留
+func reconcile(stockID string, lines []*stockEquipmentModel.Table, outstanding []*movementModel.Outstanding, inventories map[string]*model.Table, now time.Time) ([]*movementModel.Table, map[string]int64, []*model.Shortage) {
+	byID := make(map[string]*model.Table, len(inventories))
+	for _, inventory := range inventories {
+		byID[inventory.InventoryID] = inventory
+	}
+
+	current := make(map[reservation]*movementModel.Outstanding, len(outstanding))
+	desired := make(map[reservation]int64)
+	for _, o := range outstanding {
+		key := reservation{stockEquipmentID: o.StockEquipmentID, inventoryID: o.InventoryID}
+		current[key] = o
+		desired[key] = 0
+	}
+	for _, line := range lines {
+		inventory, ok := inventories[similarity.NormalizePartNumber(line.PartNumber)]
+		if !ok {
+			continue
+		}
+
+		key := reservation{stockEquipmentID: line.StockEquipmentID, inventoryID: inventory.InventoryID}
+		quantity := line.Quantity
+		if o, ok := current[key]; ok {
+			quantity -= o.Shipped
+		}
+		if quantity < 0 {
+			quantity = 0
+		}
+		desired[key] = quantity
+	}
+
+	keys := make([]reservation, 0, len(desired))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].inventoryID != keys[j].inventoryID {
+			return keys[i].inventoryID < keys[j].inventoryID
+		}
+		return keys[i].stockEquipmentID < keys[j].stockEquipmentID
+	})
+
+	movements := make([]*movementModel.Table, 0)
+	changes := make(map[string]int64)
+	for _, key := range keys {
+		var reserved int64
+		if o, ok := current[key]; ok {
+			reserved = o.Reserved
+		}
+
+		delta := desired[key] - reserved
+		if delta == 0 {
+			continue
+		}
+
+		movement := &movementModel.Table{
+			MovementID:       util.GenerateUUID(),
+			InventoryID:      key.inventoryID,
+			Type:             movementModel.TypeReserve,
+			Quantity:         delta,
+			StockID:          &stockID,
+			StockEquipmentID: &key.stockEquipmentID,
+			CreatedAt:        now,
+		}
+		if delta < 0 {
+			movement.Type = movementModel.TypeRelease
+			movement.Quantity = -delta
+		}
+
+		movements = append(movements, movement)
+		changes[key.inventoryID] += delta
+	}
+
+	shortages := make([]*model.Shortage, 0)
+	for _, inventoryID := range sortedKeys(changes) {
+		inventory := byID[inventoryID]
+		available := inventory.OnHand - inventory.Reserved
+		if changes[inventoryID] > available {
+			shortages = append(shortages, &model.Shortage{
+				PartNumber: inventory.PartNumber,
+				Requested:  changes[inventoryID],
+				Available:  max(available, 0),
+			})
+		}
+	}
+
+	return movements, changes, shortages
+}
+
+// sortedKeys 依庫存編號排序,固定更新順序
+func sortedKeys(changes map[string]int64) []string {
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
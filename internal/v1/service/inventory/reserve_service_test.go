@@ -0,0 +1,150 @@
+package inventory
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/inventories"
+	movementModel "esst_sendEmail/internal/v1/structure/inventory_movements"
+	stockEquipmentModel "esst_sendEmail/internal/v1/structure/stock_equipments"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	inventories := func() map[string]*model.Table {
+		return map[string]*model.Table{
+			"ABC100": {InventoryID: "inv-a", PartNumber: "ABC100", Normalized: "ABC100", OnHand: 10, Reserved: 4},
+			"XYZ200": {InventoryID: "inv-x", PartNumber: "XYZ200", Normalized: "XYZ200", OnHand: 0, Reserved: 0},
+		}
+	}
+	line := func(id, partNumber string, quantity int64) *stockEquipmentModel.Table {
+		return &stockEquipmentModel.Table{StockEquipmentID: id, PartNumber: partNumber, Quantity: quantity}
+	}
+
+	tests := []struct {
+		name        string
+		lines       []*stockEquipmentModel.Table
+		outstanding []*movementModel.Outstanding
+		// 各庫存的保留增減
+		wantChanges map[string]int64
+		// 異動類型與數量,依庫存與設備編號排序
+		wantMovements []string
+		wantShortages []string
+	}{
+		{
+			name:          "new line reserves",
+			lines:         []*stockEquipmentModel.Table{line("seq-1", "abc 100", 3)},
+			wantChanges:   map[string]int64{"inv-a": 3},
+			wantMovements: []string{"reserve:3"},
+		},
+		{
+			name:          "unchanged line does nothing",
+			lines:         []*stockEquipmentModel.Table{line("seq-1", "ABC100", 3)},
+			outstanding:   []*movementModel.Outstanding{{StockEquipmentID: "seq-1", InventoryID: "inv-a", Reserved: 3}},
+			wantChanges:   map[string]int64{},
+			wantMovements: []string{},
+		},
+		{
+			name:          "decrease releases",
+			lines:         []*stockEquipmentModel.Table{line("seq-1", "ABC100", 1)},
+			outstanding:   []*movementModel.Outstanding{{StockEquipmentID: "seq-1", InventoryID: "inv-a", Reserved: 3}},
+			wantChanges:   map[string]int64{"inv-a": -2},
+			wantMovements: []string{"release:2"},
+		},
+		{
+			name:          "removed line releases everything",
+			outstanding:   []*movementModel.Outstanding{{StockEquipmentID: "seq-1", InventoryID: "inv-a", Reserved: 3}},
+			wantChanges:   map[string]int64{"inv-a": -3},
+			wantMovements: []string{"release:3"},
+		},
+		{
+			name:          "shipped quantity is not reserved again",
+			lines:         []*stockEquipmentModel.Table{line("seq-1", "ABC100", 5)},
+			outstanding:   []*movementModel.Outstanding{{StockEquipmentID: "seq-1", InventoryID: "inv-a", Reserved: 1, Shipped: 2}},
+			wantChanges:   map[string]int64{"inv-a": 2},
+			wantMovements: []string{"reserve:2"},
+		},
+		{
+			name:  "part number change moves the reservation",
+			lines: []*stockEquipmentModel.Table{line("seq-1", "XYZ200", 2)},
+			outstanding: []*movementModel.Outstanding{
+				{StockEquipmentID: "seq-1", InventoryID: "inv-a", Reserved: 2},
+			},
+			wantChanges:   map[string]int64{"inv-a": -2, "inv-x": 2},
+			wantMovements: []string{"release:2", "reserve:2"},
+			wantShortages: []string{"XYZ200"},
+		},
+		{
+			name:          "exceeding available is a shortage",
+			lines:         []*stockEquipmentModel.Table{line("seq-1", "ABC100", 4), line("seq-2", "ABC100", 3)},
+			wantChanges:   map[string]int64{"inv-a": 7},
+			wantMovements: []string{"reserve:4", "reserve:3"},
+			wantShortages: []string{"ABC100"},
+		},
+		{
+			name:          "unknown part number is skipped",
+			lines:         []*stockEquipmentModel.Table{line("seq-1", "NOPE", 1)},
+			wantChanges:   map[string]int64{},
+			wantMovements: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movements, changes, shortages := reconcile("stock-1", tt.lines, tt.outstanding, inventories(), now)
+
+			if len(changes) != len(tt.wantChanges) {
+				t.Errorf("changes = %v, want %v", changes, tt.wantChanges)
+			}
+			for id, want := range tt.wantChanges {
+				if changes[id] != want {
+					t.Errorf("changes[%s] = %d, want %d", id, changes[id], want)
+				}
+			}
+
+			got := make([]string, 0, len(movements))
+			for _, m := range movements {
+				got = append(got, m.Type+":"+strconv.FormatInt(m.Quantity, 10))
+				if *m.StockID != "stock-1" || !m.CreatedAt.Equal(now) {
+					t.Errorf("movement = %+v", m)
+				}
+			}
+			if !equal(got, tt.wantMovements) {
+				t.Errorf("movements = %v, want %v", got, tt.wantMovements)
+			}
+
+			parts := make([]string, 0, len(shortages))
+			for _, s := range shortages {
+				parts = append(parts, s.PartNumber)
+			}
+			if !equal(parts, tt.wantShortages) {
+				t.Errorf("shortages = %v, want %v", parts, tt.wantShortages)
+			}
+		})
+	}
+}
+
+func TestReconcileShortage(t *testing.T) {
+	inventories := map[string]*model.Table{
+		"ABC100": {InventoryID: "inv-a", PartNumber: "ABC100", OnHand: 5, Reserved: 6},
+	}
+	lines := []*stockEquipmentModel.Table{{StockEquipmentID: "seq-1", PartNumber: "ABC100", Quantity: 1}}
+
+	_, _, shortages := reconcile("stock-1", lines, nil, inventories, time.Now())
+	if len(shortages) != 1 || shortages[0].Requested != 1 || shortages[0].Available != 0 {
+		t.Fatalf("shortages = %+v, want requested 1 and available clamped to 0", shortages)
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package inventory
+
+import (
+	"esst_sendEmail/internal/v1/entity/inventory"
+	"esst_sendEmail/internal/v1/entity/inventory_movement"
+	"esst_sendEmail/internal/v1/entity/stock_equipment"
+	model "esst_sendEmail/internal/v1/structure/inventories"
+	movementModel "esst_sendEmail/internal/v1/structure/inventory_movements"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	List(input *model.Fields) (int64, []*model.Base, error)
+	GetByID(input *model.Field) (*model.Base, error)
+	ListMovements(input *movementModel.Fields) (int64, []*movementModel.Base, error)
+	Record(input *movementModel.Created) (*movementModel.Base, error)
+	Sync(stockID string) ([]*model.Shortage, error)
+}
+
+type service struct {
+	Entity               inventory.Entity
+	MovementEntity       inventory_movement.Entity
+	StockEquipmentEntity stock_equipment.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity:               inventory.New(db),
+		MovementEntity:       inventory_movement.New(db),
+		StockEquipmentEntity: stock_equipment.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity:               s.Entity.WithTrx(tx),
+		MovementEntity:       s.MovementEntity.WithTrx(tx),
+		StockEquipmentEntity: s.StockEquipmentEntity.WithTrx(tx),
+	}
+}
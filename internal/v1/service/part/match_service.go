@@ -0,0 +1,244 @@
+package part
+
+import (
+	"errors"
+	"os"
+	"sort"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/similarity"
+	model "esst_sendEmail/internal/v1/structure/parts"
+)
+
+// 料號目錄比對模式
+const (
+	// ModeOff 不比對,料號維持自由輸入
+	ModeOff = "off"
+	// ModeSuggest 對應到目錄的設備會連結目錄,無法對應時照常建立並回傳建議料號
+	ModeSuggest = "suggest"
+	// ModeStrict 有無法對應的料號時拒絕建立,回傳 422 與建議料號
+	ModeStrict = "strict"
+)
+
+const (
+	// 建議料號的最低相似度
+	suggestThreshold = 0.6
+	// 每個料號最多建議幾筆
+	suggestLimit = 3
+	// 以前綴取候選料號的長度與筆數
+	candidatePrefix = 3
+	candidateLimit  = 200
+	// 自動完成預設筆數
+	defaultSuggestLimit = 10
+)
+
+// Mode 料號目錄比對模式,由 PARTS_CATALOG_MODE 設定,預設 suggest
+func Mode() string {
+	switch mode := os.Getenv("PARTS_CATALOG_MODE"); mode {
+	case ModeOff, ModeStrict:
+		return mode
+	default:
+		return ModeSuggest
+	}
+}
+
+// Match 將設備料號對應到料號目錄
+// 對應成功時寫回目錄編號與標準料號;無法對應時清除目錄編號並保留原輸入(自由輸入)
+// strict 模式下有無法對應的料號時回傳 ErrUnmatched
+func (s *service) Match(lines []*model.Line) ([]*model.Unmatched, error) {
+	if Mode() == ModeOff || len(lines) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0)
+	numbers := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line.PartID != nil {
+			ids = append(ids, *line.PartID)
+		}
+		numbers = append(numbers, similarity.NormalizePartNumber(line.PartNumber))
+	}
+
+	byID, err := s.Entity.ListByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	idParts := make(map[string]*model.Table, len(byID))
+	for _, part := range byID {
+		idParts[part.PartID] = part
+	}
+
+	byNumber, err := s.Entity.ListByNormalized(numbers)
+	if err != nil {
+		return nil, err
+	}
+	numberParts := make(map[string]*model.Table, len(byNumber))
+	for _, part := range byNumber {
+		numberParts[part.Normalized] = part
+	}
+
+	unmatched := make([]*model.Unmatched, 0)
+	for index, line := range lines {
+		reason := model.ReasonUnknownPartNumber
+		if line.PartID != nil {
+			if part, ok := idParts[*line.PartID]; ok {
+				line.PartNumber = part.PartNumber
+				continue
+			}
+			reason = model.ReasonUnknownPartID
+		}
+
+		if part, ok := numberParts[numbers[index]]; ok {
+			line.PartID = &part.PartID
+			line.PartNumber = part.PartNumber
+			continue
+		}
+
+		line.PartID = nil
+		suggestions, err := s.suggest(numbers[index])
+		if err != nil {
+			return nil, err
+		}
+
+		unmatched = append(unmatched, &model.Unmatched{
+			Index:       index,
+			PartNumber:  line.PartNumber,
+			Reason:      reason,
+			Suggestions: suggestions,
+		})
+	}
+
+	if len(unmatched) > 0 && Mode() == ModeStrict {
+		return unmatched, ErrUnmatched
+	}
+
+	return unmatched, nil
+}
+
+// MatchBatch 將批次設備的料號對應到料號目錄,對應結果寫回各筆設備
+// strict 模式下有無法對應的料號時回傳 ErrUnmatched;其他比對失敗只記錄錯誤並保留自由輸入的料號
+func MatchBatch[T any, P interface {
+	*T
+	model.Referenced
+}](s Service, equipments []T) ([]*model.Unmatched, error) {
+	lines := make([]*model.Line, 0, len(equipments))
+	for i := range equipments {
+		lines = append(lines, P(&equipments[i]).PartLine())
+	}
+
+	unmatched, err := s.Match(lines)
+	if err != nil {
+		if errors.Is(err, ErrUnmatched) {
+			return unmatched, err
+		}
+
+		log.Error("Failed to match parts catalog:", err)
+		return nil, nil
+	}
+
+	for i, line := range lines {
+		P(&equipments[i]).SetPart(line)
+	}
+
+	return unmatched, nil
+}
+
+// suggest 以相同前綴的目錄料號為候選,依相似度排序取前幾筆,不足時以歷史設備料號補上
+func (s *service) suggest(normalized string) ([]*model.Suggestion, error) {
+	suggestions := make([]*model.Suggestion, 0)
+	if normalized == "" {
+		return suggestions, nil
+	}
+
+	prefix := []rune(normalized)
+	if len(prefix) > candidatePrefix {
+		prefix = prefix[:candidatePrefix]
+	}
+
+	candidates, err := s.Entity.ListByPrefix(string(prefix), candidateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range candidates {
+		score := similarity.Ratio(normalized, candidate.Normalized)
+		if score < suggestThreshold {
+			continue
+		}
+
+		suggestions = append(suggestions, &model.Suggestion{
+			PartID:     candidate.PartID,
+			PartNumber: candidate.PartNumber,
+			Name:       candidate.Name,
+			Score:      score,
+			Source:     model.SourceCatalog,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > suggestLimit {
+		suggestions = suggestions[:suggestLimit]
+	}
+
+	// 目錄中的建議不足時,以歷史設備中相近的料號補上(您是不是要輸入 X?)
+	if len(suggestions) < suggestLimit {
+		history, err := s.Entity.Suggest(normalized, suggestLimit*3)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range history {
+			if len(suggestions) >= suggestLimit {
+				break
+			}
+			if record.Normalized == normalized || containsPart(suggestions, record.Normalized) {
+				continue
+			}
+
+			score := similarity.Ratio(normalized, record.Normalized)
+			if score < suggestThreshold {
+				continue
+			}
+
+			suggestion := &model.Suggestion{
+				PartNumber: record.PartNumber,
+				Name:       record.Description,
+				Score:      score,
+				Source:     model.SourceHistory,
+			}
+			if record.PartID != nil {
+				suggestion.PartID = *record.PartID
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	return suggestions, nil
+}
+
+// containsPart 檢查建議中是否已有相同的正規化料號
+func containsPart(suggestions []*model.Suggestion, normalized string) bool {
+	for _, suggestion := range suggestions {
+		if similarity.NormalizePartNumber(suggestion.PartNumber) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggest 料號自動完成,依歷史專案設備與現貨設備的料號排序
+func (s *service) Suggest(input *model.Suggest) ([]*model.Suggested, error) {
+	normalized := similarity.NormalizePartNumber(input.Query)
+	if normalized == "" {
+		return make([]*model.Suggested, 0), nil
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultSuggestLimit
+	}
+
+	return s.Entity.Suggest(normalized, limit)
+}
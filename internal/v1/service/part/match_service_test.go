@@ -0,0 +1,84 @@
+package part
+
+import (
+	"errors"
+	"testing"
+
+	equipmentModel "esst_sendEmail/internal/v1/structure/equipments"
+	model "esst_sendEmail/internal/v1/structure/parts"
+)
+
+// matchStub 只實作 Match,依 match 函式決定比對結果
+type matchStub struct {
+	Service
+	match func(lines []*model.Line) ([]*model.Unmatched, error)
+}
+
+func (s *matchStub) Match(lines []*model.Line) ([]*model.Unmatched, error) {
+	return s.match(lines)
+}
+
+func TestMatchBatch(t *testing.T) {
+	catalogID := "6f1c2d3e-0000-4000-8000-000000000001"
+	unmatched := []*model.Unmatched{{Index: 1, PartNumber: "xyz"}}
+
+	tests := []struct {
+		name          string
+		match         func(lines []*model.Line) ([]*model.Unmatched, error)
+		wantErr       error
+		wantUnmatched int
+		wantNumber    string
+		wantPartID    *string
+	}{
+		{
+			name: "matched lines are written back",
+			match: func(lines []*model.Line) ([]*model.Unmatched, error) {
+				lines[0].PartID, lines[0].PartNumber = &catalogID, "ABC-100"
+				return unmatched, nil
+			},
+			wantUnmatched: 1,
+			wantNumber:    "ABC-100",
+			wantPartID:    &catalogID,
+		},
+		{
+			name: "strict mode blocks",
+			match: func(lines []*model.Line) ([]*model.Unmatched, error) {
+				return unmatched, ErrUnmatched
+			},
+			wantErr:       ErrUnmatched,
+			wantUnmatched: 1,
+			wantNumber:    "abc 100",
+		},
+		{
+			name: "lookup failure keeps free text",
+			match: func(lines []*model.Line) ([]*model.Unmatched, error) {
+				lines[0].PartNumber = "changed"
+				return nil, errors.New("connection refused")
+			},
+			wantNumber: "abc 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equipments := []equipmentModel.BatchEquipment{
+				{PartNumber: "abc 100", Quantity: 1},
+				{PartNumber: "xyz", Quantity: 2},
+			}
+
+			got, err := MatchBatch(&matchStub{match: tt.match}, equipments)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MatchBatch() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantUnmatched {
+				t.Errorf("MatchBatch() unmatched = %d, want %d", len(got), tt.wantUnmatched)
+			}
+			if equipments[0].PartNumber != tt.wantNumber {
+				t.Errorf("PartNumber = %q, want %q", equipments[0].PartNumber, tt.wantNumber)
+			}
+			if (equipments[0].PartID == nil) != (tt.wantPartID == nil) {
+				t.Errorf("PartID = %v, want %v", equipments[0].PartID, tt.wantPartID)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package part
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/similarity"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/parts"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPartNumberExists = errors.New("料號已存在於目錄中")
+	ErrUnmatched        = errors.New("料號不在目錄中")
+)
+
+func (s *service) Create(input *model.Created) (*model.Base, error) {
+	partNumber := strings.TrimSpace(input.PartNumber)
+	normalized := similarity.NormalizePartNumber(partNumber)
+
+	err := s.checkExists(normalized, "")
+	if err != nil {
+		return nil, err
+	}
+
+	table := &model.Table{
+		PartID:     util.GenerateUUID(),
+		PartNumber: partNumber,
+		Normalized: normalized,
+		Name:       strings.TrimSpace(input.Name),
+		Category:   input.Category,
+		Brand:      input.Brand,
+		Unit:       input.Unit,
+		ListPrice:  input.ListPrice,
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	if input.Active != nil {
+		table.Active = *input.Active
+	}
+
+	err = s.Entity.Create(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return toBase(table)
+}
+
+func (s *service) List(input *model.Fields) (quantity int64, output []*model.Base, err error) {
+	amount, fields, err := s.Entity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	marshal, err := json.Marshal(fields)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	return amount, output, nil
+}
+
+func (s *service) GetByID(input *model.Field) (*model.Base, error) {
+	field, err := s.Entity.GetByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBase(field)
+}
+
+func (s *service) Update(input *model.Updated) (*model.Base, error) {
+	field, err := s.Entity.GetByID(&model.Field{PartID: input.PartID})
+	if err != nil {
+		return nil, err
+	}
+
+	if input.PartNumber != nil {
+		partNumber := strings.TrimSpace(*input.PartNumber)
+		normalized := similarity.NormalizePartNumber(partNumber)
+		if normalized != field.Normalized {
+			err = s.checkExists(normalized, field.PartID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		field.PartNumber = partNumber
+		field.Normalized = normalized
+	}
+	if input.Name != nil {
+		field.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Category != nil {
+		field.Category = input.Category
+	}
+	if input.Brand != nil {
+		field.Brand = input.Brand
+	}
+	if input.Unit != nil {
+		field.Unit = input.Unit
+	}
+	if input.ListPrice != nil {
+		field.ListPrice = input.ListPrice
+	}
+	if input.Active != nil {
+		field.Active = *input.Active
+	}
+	now := time.Now()
+	field.UpdatedAt = &now
+
+	err = s.Entity.Update(field)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return toBase(field)
+}
+
+// Delete 刪除料號,已參照此料號的設備改回自由輸入(保留原料號文字)
+func (s *service) Delete(input *model.Field) error {
+	return s.Entity.Delete(input)
+}
+
+// checkExists 檢查正規化料號是否已被其他料號使用
+func (s *service) checkExists(normalized, exceptID string) error {
+	existing, err := s.Entity.GetByNormalized(normalized)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+
+		log.Error(err)
+		return err
+	}
+
+	if existing.PartID != exceptID {
+		return ErrPartNumberExists
+	}
+
+	return nil
+}
+
+func toBase(table *model.Table) (output *model.Base, err error) {
+	marshal, err := json.Marshal(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,35 @@
+package part
+
+import (
+	"esst_sendEmail/internal/v1/entity/part"
+	model "esst_sendEmail/internal/v1/structure/parts"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Create(input *model.Created) (*model.Base, error)
+	List(input *model.Fields) (int64, []*model.Base, error)
+	GetByID(input *model.Field) (*model.Base, error)
+	Update(input *model.Updated) (*model.Base, error)
+	Delete(input *model.Field) error
+	Match(lines []*model.Line) ([]*model.Unmatched, error)
+	Suggest(input *model.Suggest) ([]*model.Suggested, error)
+}
+
+type service struct {
+	Entity part.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity: part.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity: s.Entity.WithTrx(tx),
+	}
+}
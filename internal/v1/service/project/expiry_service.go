@@ -0,0 +1,91 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	model "esst_sendEmail/internal/v1/structure/projects"
+)
+
+// ProtectionDays 新專案的保護期天數,由 PROJECT_PROTECTION_DAYS 設定,預設 90 天
+func ProtectionDays() int {
+	days, err := strconv.Atoi(os.Getenv("PROJECT_PROTECTION_DAYS"))
+	if err != nil || days <= 0 {
+		return 90
+	}
+	return days
+}
+
+// ListExpiring 查詢在 within 內到期且尚未提醒的專案
+func (s *service) ListExpiring(within time.Duration) ([]*model.Base, error) {
+	now := time.Now()
+	fields, err := s.Entity.ListExpiring(now, now.Add(within))
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return toBases(fields)
+}
+
+// MarkExpiryWarned 記錄到期前提醒已發送,避免重複提醒
+func (s *service) MarkExpiryWarned(projectID string) error {
+	err := s.Entity.MarkExpiryWarned(projectID, time.Now())
+	if err != nil {
+		log.Error(err)
+	}
+	return err
+}
+
+// ExpireOverdue 將保護期已過的第一階段專案轉為 expired,回傳本次轉換的專案
+func (s *service) ExpireOverdue() ([]*model.Base, error) {
+	now := time.Now()
+	fields, err := s.Entity.ListOverdue(now)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	expired := make([]*model.Table, 0, len(fields))
+	for _, field := range fields {
+		ok, err := s.Entity.Expire(field.ProjectID, now)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		if ok {
+			field.Status = model.StatusExpired
+			expired = append(expired, field)
+		}
+	}
+
+	return toBases(expired)
+}
+
+// Extend 延長保護期
+func (s *service) Extend(projectID string, expiresAt time.Time) error {
+	err := s.Entity.Extend(projectID, expiresAt)
+	if err != nil {
+		log.Error(err)
+	}
+	return err
+}
+
+func toBases(fields []*model.Table) (output []*model.Base, err error) {
+	marshal, err := json.Marshal(fields)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,55 @@
+package project
+
+import (
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/pkg/log"
+	model "esst_sendEmail/internal/v1/structure/projects"
+)
+
+// NotifyStep1 發送第一階段 LINE 通知(專案資料與設備清單),pricing 為附給主管群組的報價金額
+func (s *service) NotifyStep1(projectID string, pricing *linebot.Pricing) {
+	log.Info("Preparing to send step1 LINE notification for project:", projectID)
+
+	project, err := s.GetByID(&model.Field{ProjectID: projectID})
+	if err != nil {
+		log.Error("Failed to query project for LINE notification:", err)
+		return
+	}
+
+	equipments, err := s.EquipmentEntity.ListByProjectID(projectID)
+	if err != nil {
+		log.Error("Failed to query equipments for LINE notification:", err)
+		return
+	}
+
+	lineEquipments := make([]linebot.Equipment, 0, len(equipments))
+	for _, eq := range equipments {
+		lineEquipments = append(lineEquipments, linebot.Equipment{
+			PartNumber:  eq.PartNumber,
+			Quantity:    eq.Quantity,
+			Description: eq.Description,
+		})
+	}
+
+	log.Info("Found", len(lineEquipments), "equipments for project", projectID)
+
+	notificationData := &linebot.ProjectStep1Data{
+		ProjectID:      project.ProjectID,
+		ProjectName:    project.ProjectName,
+		ContactName:    project.ContactName,
+		ContactPhone:   project.ContactPhone,
+		ContactEmail:   project.ContactEmail,
+		Owner:          project.Owner,
+		Remark:         project.Remark,
+		Equipments:     lineEquipments,
+		CreatedTime:    project.CreatedTime,
+		ApprovalStatus: project.ApprovalStatus,
+		Pricing:        pricing,
+	}
+
+	if err := linebot.New().SendProjectStep1Notification(notificationData); err != nil {
+		log.Error("Failed to send step1 LINE notification:", err)
+	} else {
+		log.Info("Step1 LINE notification sent successfully for project:", projectID)
+	}
+}
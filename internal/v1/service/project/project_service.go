@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"esst_sendEmail/internal/pkg/log"
 	"esst_sendEmail/internal/pkg/util"
+	commentModel "esst_sendEmail/internal/v1/structure/comments"
 	model "esst_sendEmail/internal/v1/structure/projects"
+	"strings"
 	"time"
 )
 
@@ -47,6 +49,19 @@ func (s *service) Create(input *model.Created) (*model.Base, error) {
 	output.CreatedTime = time.Now()
 	output.Status = "step1" // 初始狀態為第一階段
 
+	// 保護期自第一階段建立起算
+	days := input.ProtectionDays
+	if days <= 0 {
+		days = ProtectionDays()
+	}
+	expiresAt := output.CreatedTime.AddDate(0, 0, days)
+	output.ExpiresAt = &expiresAt
+	if input.CreatedBy != "" {
+		output.CreatedBy = &input.CreatedBy
+	}
+	// 送審前先視為待審核,由審核流程決定關卡或直接核准
+	output.ApprovalStatus = model.ApprovalPending
+
 	table := &model.Table{}
 	marshal, err = json.Marshal(output)
 	if err != nil {
@@ -57,12 +72,18 @@ func (s *service) Create(input *model.Created) (*model.Base, error) {
 	if err != nil {
 		return nil, err
 	}
+	table.Remark = ""
 
 	err = s.Entity.Create(table)
 	if err != nil {
 		return nil, err
 	}
 
+	err = s.addRemark(output.ProjectID, input.Remark, input.CreatedBy, output.CreatedTime)
+	if err != nil {
+		return nil, err
+	}
+
 	return &output, nil
 }
 
@@ -116,6 +137,7 @@ func (s *service) GetByID(input *model.Field) (output *model.Base, err error) {
 	return output, nil
 }
 
+// Delete 軟刪除專案,並以相同的刪除時間一併軟刪除設備
 func (s *service) Delete(input *model.Updated) (err error) {
 	field, err := s.Entity.GetByID(&model.Field{ProjectID: input.ProjectID})
 	if err != nil {
@@ -123,7 +145,25 @@ func (s *service) Delete(input *model.Updated) (err error) {
 
 		return err
 	}
-	err = s.Entity.Delete(&model.Field{ProjectID: field.ProjectID})
+
+	// 資料庫時間精度為微秒,截斷後還原時才能以刪除時間比對
+	deletedAt := time.Now().Truncate(time.Microsecond)
+	err = s.Entity.Delete(&model.Field{ProjectID: field.ProjectID}, input.Version, input.DeletedBy, deletedAt)
+	if err != nil {
+		log.Error(err)
+
+		return err
+	}
+
+	err = s.EquipmentEntity.DeleteByProjectID(field.ProjectID, input.DeletedBy, deletedAt)
+	if err != nil {
+		log.Error(err)
+
+		return err
+	}
+
+	// 一併取消分配,還原時才不會帶回未檢查庫存的分配
+	err = s.AllocationEntity.DeleteByProjectID(field.ProjectID)
 
 	return err
 }
@@ -152,9 +192,6 @@ func (s *service) Update(input *model.Updated) (err error) {
 	if input.Owner != "" {
 		field.Owner = input.Owner
 	}
-	if input.Remark != "" {
-		field.Remark = input.Remark
-	}
 
 	// 處理第二階段欄位
 	if input.ExpectedDeliveryPeriod != "" {
@@ -198,7 +235,37 @@ func (s *service) Update(input *model.Updated) (err error) {
 	now := time.Now()
 	field.UpdatedTime = &now
 
+	// 以 If-Match 的版本號更新,避免覆蓋他人的修改
+	field.Version = input.Version
+
 	err = s.Entity.Update(field)
+	if err != nil {
+		return err
+	}
+
+	return s.addRemark(field.ProjectID, input.Remark, input.UpdatedBy, now)
+}
 
+// addRemark 備註改以留言保存,避免每次更新覆寫先前的內容;需與建立或更新在同一交易中呼叫
+func (s *service) addRemark(projectID, remark, authorID string, createdAt time.Time) error {
+	if strings.TrimSpace(remark) == "" {
+		return nil
+	}
+
+	comment := &commentModel.Table{
+		CommentID:  util.GenerateUUID(),
+		TargetType: commentModel.TargetProject,
+		TargetID:   projectID,
+		Body:       remark,
+		CreatedAt:  createdAt,
+	}
+	if authorID != "" {
+		comment.AuthorID = &authorID
+	}
+
+	err := s.CommentEntity.Create(comment)
+	if err != nil {
+		log.Error(err)
+	}
 	return err
 }
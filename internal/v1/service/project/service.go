@@ -1,7 +1,13 @@
 package project
 
 import (
+	"time"
+
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/v1/entity/comment"
+	"esst_sendEmail/internal/v1/entity/equipment"
 	"esst_sendEmail/internal/v1/entity/project"
+	"esst_sendEmail/internal/v1/entity/stock_allocation"
 	model "esst_sendEmail/internal/v1/structure/projects"
 
 	"gorm.io/gorm"
@@ -14,20 +20,38 @@ type Service interface {
 	GetByID(input *model.Field) (*model.Base, error)
 	Update(input *model.Updated) error
 	Delete(input *model.Updated) error
+	ListDeleted(input *model.Fields) (int64, []*model.Base, error)
+	GetDeletedByID(input *model.Field) (*model.Base, error)
+	Restore(input *model.Field) error
+	Purge(before time.Time) (int64, error)
+	ListExpiring(within time.Duration) ([]*model.Base, error)
+	MarkExpiryWarned(projectID string) error
+	ExpireOverdue() ([]*model.Base, error)
+	Extend(projectID string, expiresAt time.Time) error
+	NotifyStep1(projectID string, pricing *linebot.Pricing)
 }
 
 type service struct {
-	Entity project.Entity
+	Entity           project.Entity
+	EquipmentEntity  equipment.Entity
+	CommentEntity    comment.Entity
+	AllocationEntity stock_allocation.Entity
 }
 
 func New(db *gorm.DB) Service {
 	return &service{
-		Entity: project.New(db),
+		Entity:           project.New(db),
+		EquipmentEntity:  equipment.New(db),
+		CommentEntity:    comment.New(db),
+		AllocationEntity: stock_allocation.New(db),
 	}
 }
 
 func (s *service) WithTrx(tx *gorm.DB) Service {
 	return &service{
-		Entity: s.Entity.WithTrx(tx),
+		Entity:           s.Entity.WithTrx(tx),
+		EquipmentEntity:  s.EquipmentEntity.WithTrx(tx),
+		CommentEntity:    s.CommentEntity.WithTrx(tx),
+		AllocationEntity: s.AllocationEntity.WithTrx(tx),
 	}
 }
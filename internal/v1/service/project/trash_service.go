@@ -0,0 +1,89 @@
+package project
+
+import (
+	"encoding/json"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	model "esst_sendEmail/internal/v1/structure/projects"
+)
+
+// ListDeleted 資源回收筒中的專案
+func (s *service) ListDeleted(input *model.Fields) (quantity int64, output []*model.Base, err error) {
+	amount, fields, err := s.Entity.ListDeleted(input)
+	if err != nil {
+		log.Error(err)
+
+		return 0, output, err
+	}
+
+	marshal, err := json.Marshal(fields)
+	if err != nil {
+		log.Error(err)
+
+		return 0, output, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+
+		return 0, output, err
+	}
+
+	return amount, output, err
+}
+
+func (s *service) GetDeletedByID(input *model.Field) (output *model.Base, err error) {
+	field, err := s.Entity.GetDeletedByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	marshal, err := json.Marshal(field)
+	if err != nil {
+		log.Error(err)
+
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// Restore 還原專案與一併刪除的設備
+func (s *service) Restore(input *model.Field) error {
+	field, err := s.Entity.GetDeletedByID(input)
+	if err != nil {
+		log.Error(err)
+
+		return err
+	}
+
+	err = s.Entity.Restore(input)
+	if err != nil {
+		log.Error(err)
+
+		return err
+	}
+
+	err = s.EquipmentEntity.RestoreByProjectID(field.ProjectID, field.DeletedAt.Time)
+	if err != nil {
+		log.Error(err)
+
+		return err
+	}
+
+	return nil
+}
+
+// Purge 永久刪除超過保留期限的專案,設備由外鍵 ON DELETE CASCADE 一併刪除
+func (s *service) Purge(before time.Time) (int64, error) {
+	return s.Entity.Purge(before)
+}
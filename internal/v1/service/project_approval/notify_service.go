@@ -0,0 +1,115 @@
+package project_approval
+
+import (
+	"strings"
+
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/mail"
+	model "esst_sendEmail/internal/v1/structure/project_approvals"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+	userModel "esst_sendEmail/internal/v1/structure/users"
+)
+
+// NotifyApprovers 通知目前關卡的審核者(LINE 群組與具審核角色的使用者信箱)
+func (s *service) NotifyApprovers(approval *model.Base) {
+	if approval == nil {
+		return
+	}
+
+	roles := strings.Fields(approval.ApproverRoles)
+
+	lineBotService := linebot.New()
+	err := lineBotService.SendProjectApprovalNotification(&linebot.ProjectApprovalData{
+		ProjectID:     approval.ProjectID,
+		ProjectName:   approval.ProjectName,
+		ContactName:   approval.ContactName,
+		Owner:         approval.Owner,
+		Level:         approval.Level,
+		ApproverRoles: roles,
+		Status:        model.StatusPending,
+	})
+	if err != nil {
+		log.Error("Failed to send approval request LINE notification:", err)
+	}
+
+	approvers, err := s.UserEntity.ListByRoles(roles)
+	if err != nil {
+		log.Error("Failed to query approvers:", err)
+		return
+	}
+
+	emailService := mail.New()
+	for _, approver := range approvers {
+		if approver.Email == "" {
+			continue
+		}
+		err = emailService.SendApprovalRequestEmail(approver.Email, approver.Username, &mail.ProjectApprovalData{
+			ProjectID:   approval.ProjectID,
+			ProjectName: approval.ProjectName,
+			Owner:       approval.Owner,
+			Level:       approval.Level,
+		})
+		if err != nil {
+			log.Error("Failed to send approval request email:", err)
+		}
+	}
+}
+
+// NotifyDecision 通知審核結果(LINE 群組與專案建立者信箱)
+func (s *service) NotifyDecision(approval *model.Base) {
+	if approval == nil {
+		return
+	}
+
+	reason := ""
+	if approval.Reason != nil {
+		reason = *approval.Reason
+	}
+
+	decidedBy := ""
+	if approval.DecidedBy != nil {
+		approver, err := s.UserEntity.GetByID(&userModel.Field{ID: approval.DecidedBy})
+		if err == nil {
+			decidedBy = approver.Username
+		}
+	}
+
+	lineBotService := linebot.New()
+	err := lineBotService.SendProjectApprovalNotification(&linebot.ProjectApprovalData{
+		ProjectID:   approval.ProjectID,
+		ProjectName: approval.ProjectName,
+		ContactName: approval.ContactName,
+		Owner:       approval.Owner,
+		Level:       approval.Level,
+		Status:      approval.Status,
+		Reason:      reason,
+		DecidedBy:   decidedBy,
+	})
+	if err != nil {
+		log.Error("Failed to send approval decision LINE notification:", err)
+	}
+
+	project, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: approval.ProjectID})
+	if err != nil || project.CreatedBy == nil {
+		return
+	}
+
+	creator, err := s.UserEntity.GetByID(&userModel.Field{ID: project.CreatedBy})
+	if err != nil || creator.Email == "" {
+		return
+	}
+
+	err = mail.New().SendApprovalDecisionEmail(creator.Email, creator.Username, &mail.ProjectApprovalData{
+		ProjectID:   approval.ProjectID,
+		ProjectName: approval.ProjectName,
+		Owner:       approval.Owner,
+		Level:       approval.Level,
+		Status:      approval.Status,
+		Reason:      reason,
+		DecidedBy:   decidedBy,
+	})
+	if err != nil {
+		log.Error("Failed to send approval decision email:", err)
+	}
+}
@@ -0,0 +1,406 @@
+package project_approval
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/project_approvals"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+	userModel "esst_sendEmail/internal/v1/structure/users"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAlreadyDecided  = errors.New("此審核紀錄已審核")
+	ErrNotCurrentLevel = errors.New("尚未輪到此關卡審核")
+	ErrNotApprover     = errors.New("沒有審核此關卡的權限")
+	ErrReasonRequired  = errors.New("駁回時必須填寫原因")
+)
+
+// Enabled 是否啟用報備審核,由 APPROVAL_ENABLED 設定,預設啟用
+func Enabled() bool {
+	return os.Getenv("APPROVAL_ENABLED") != "false"
+}
+
+// DefaultRole 沒有符合的審核規則時使用的審核者角色,由 APPROVAL_DEFAULT_ROLE 設定,預設 manager
+func DefaultRole() string {
+	if role := os.Getenv("APPROVAL_DEFAULT_ROLE"); role != "" {
+		return role
+	}
+	return "manager"
+}
+
+// Submit 依目前的設備總數量與審核規則重新計算專案的審核關卡;未啟用審核時直接核准
+// 設備新增或數量改變時呼叫:補上新的關卡、更新關卡角色、取消不再需要的關卡
+// reset 為 true(數量增加)時已核准的關卡需重新審核;有新的待審核關卡時回傳該紀錄供通知審核者
+func (s *service) Submit(projectID string, reset bool) (*model.Base, error) {
+	project, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: projectID})
+	if err != nil {
+		return nil, err
+	}
+	if project.ApprovalStatus == projectModel.ApprovalRejected {
+		return nil, nil
+	}
+
+	if !Enabled() {
+		if project.ApprovalStatus == projectModel.ApprovalApproved {
+			return nil, nil
+		}
+		return nil, s.ProjectEntity.UpdateApproval(projectID, projectModel.ApprovalApproved, 0)
+	}
+
+	levels, err := s.levels(project)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Entity.ListLevels(projectID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	created, updated := plan(projectID, levels, existing, reset, time.Now())
+	err = s.Entity.CreateBatch(created)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	for _, approval := range updated {
+		err = s.Entity.UpdateLevel(approval)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	}
+
+	current, err := s.Entity.NextPending(projectID, 0)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 所有需要的關卡皆已核准
+		if project.ApprovalStatus == projectModel.ApprovalApproved {
+			return nil, nil
+		}
+		return nil, s.ProjectEntity.UpdateApproval(projectID, projectModel.ApprovalApproved, 0)
+	}
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	if project.ApprovalStatus == projectModel.ApprovalPending && project.ApprovalLevel == current.Level {
+		return nil, nil
+	}
+
+	err = s.ProjectEntity.UpdateApproval(projectID, projectModel.ApprovalPending, current.Level)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return s.GetByID(&model.Field{ApprovalID: current.ApprovalID})
+}
+
+// plan 比對規則要求的關卡與既有紀錄,回傳需新增與需更新的紀錄
+// 待審核的關卡更新為目前的角色,取消或(reset 時)已核准的關卡重新開啟,不再需要的待審核關卡取消
+func plan(projectID string, levels map[int][]string, existing []*model.Table, reset bool, now time.Time) (created, updated []*model.Table) {
+	rows := make(map[int]*model.Table, len(existing))
+	for _, row := range existing {
+		rows[row.Level] = row
+	}
+
+	for _, level := range sortedLevels(levels) {
+		roles := strings.Join(levels[level], " ")
+		row, ok := rows[level]
+		if !ok {
+			created = append(created, &model.Table{
+				ApprovalID:    util.GenerateUUID(),
+				ProjectID:     projectID,
+				Level:         level,
+				ApproverRoles: roles,
+				Status:        model.StatusPending,
+				CreatedAt:     now,
+			})
+			continue
+		}
+
+		switch {
+		case row.Status == model.StatusPending && row.ApproverRoles != roles,
+			row.Status == model.StatusCancelled,
+			row.Status == model.StatusApproved && reset:
+			row.ApproverRoles = roles
+			row.Status = model.StatusPending
+			row.DecidedBy, row.DecidedAt, row.Reason = nil, nil, nil
+			updated = append(updated, row)
+		}
+	}
+
+	for _, row := range existing {
+		if _, ok := levels[row.Level]; !ok && row.Status == model.StatusPending {
+			row.Status = model.StatusCancelled
+			updated = append(updated, row)
+		}
+	}
+
+	return created, updated
+}
+
+// levels 依設備總數量與建立者團隊找出符合的規則,回傳各關卡可審核的角色
+func (s *service) levels(project *projectModel.Table) (map[int][]string, error) {
+	equipments, err := s.EquipmentEntity.ListByProjectID(project.ProjectID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	var quantity int64
+	for _, eq := range equipments {
+		quantity += eq.Quantity
+	}
+
+	team := ""
+	if project.CreatedBy != nil {
+		creator, err := s.UserEntity.GetByID(&userModel.Field{ID: project.CreatedBy})
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error(err)
+			return nil, err
+		}
+		if err == nil && creator.Team != nil {
+			team = *creator.Team
+		}
+	}
+
+	rules, err := s.RuleEntity.ListEnabled()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	levels := make(map[int][]string)
+	for _, rule := range rules {
+		if rule.MinQuantity != nil && quantity < *rule.MinQuantity {
+			continue
+		}
+		if rule.Team != nil && *rule.Team != "" && *rule.Team != team {
+			continue
+		}
+		if !contains(levels[rule.Level], rule.ApproverRole) {
+			levels[rule.Level] = append(levels[rule.Level], rule.ApproverRole)
+		}
+	}
+
+	if len(levels) == 0 {
+		levels[1] = []string{DefaultRole()}
+	}
+
+	return levels, nil
+}
+
+func (s *service) Queue(input *model.Fields, role string) (int64, []*model.Base, error) {
+	amount, rows, err := s.Entity.Queue(input, role)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	output, err := toBases(rows)
+	return amount, output, err
+}
+
+func (s *service) ListByProjectID(input *model.Fields) (int64, []*model.Base, error) {
+	amount, rows, err := s.Entity.ListByProjectID(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	output, err := toBases(rows)
+	return amount, output, err
+}
+
+func (s *service) GetByID(input *model.Field) (*model.Base, error) {
+	row, err := s.Entity.GetByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBase(row)
+}
+
+// Approve 核准目前關卡;還有下一關時回傳下一關的紀錄,否則專案核准完成
+func (s *service) Approve(input *model.Decided) (*model.Base, *model.Base, error) {
+	row, err := s.check(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.decide(row, model.StatusApproved, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	next, err := s.Entity.NextPending(row.ProjectID, row.Level)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error(err)
+		return nil, nil, err
+	}
+
+	if err == nil {
+		err = s.ProjectEntity.UpdateApproval(row.ProjectID, projectModel.ApprovalPending, next.Level)
+		if err != nil {
+			log.Error(err)
+			return nil, nil, err
+		}
+
+		output, err := s.GetByID(&model.Field{ApprovalID: row.ApprovalID})
+		if err != nil {
+			return nil, nil, err
+		}
+		nextBase, err := s.GetByID(&model.Field{ApprovalID: next.ApprovalID})
+		return output, nextBase, err
+	}
+
+	err = s.ProjectEntity.UpdateApproval(row.ProjectID, projectModel.ApprovalApproved, 0)
+	if err != nil {
+		log.Error(err)
+		return nil, nil, err
+	}
+
+	output, err := s.GetByID(&model.Field{ApprovalID: row.ApprovalID})
+	return output, nil, err
+}
+
+// Reject 駁回目前關卡,其餘關卡一併取消
+func (s *service) Reject(input *model.Decided) (*model.Base, error) {
+	if input.Reason == nil || strings.TrimSpace(*input.Reason) == "" {
+		return nil, ErrReasonRequired
+	}
+
+	row, err := s.check(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.decide(row, model.StatusRejected, input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Entity.CancelPending(row.ProjectID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = s.ProjectEntity.UpdateApproval(row.ProjectID, projectModel.ApprovalRejected, 0)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return s.GetByID(&model.Field{ApprovalID: row.ApprovalID})
+}
+
+// check 確認紀錄為專案目前的待審關卡,且審核者具有對應角色(管理員不限)並非建立者本人
+func (s *service) check(input *model.Decided) (*model.Row, error) {
+	row, err := s.Entity.GetByID(&model.Field{ApprovalID: input.ApprovalID})
+	if err != nil {
+		return nil, err
+	}
+	if row.Status != model.StatusPending {
+		return nil, ErrAlreadyDecided
+	}
+
+	project, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: row.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	if project.ApprovalStatus != projectModel.ApprovalPending || project.ApprovalLevel != row.Level {
+		return nil, ErrNotCurrentLevel
+	}
+
+	if input.DecidedRole != "admin" {
+		if !contains(strings.Fields(row.ApproverRoles), input.DecidedRole) {
+			return nil, ErrNotApprover
+		}
+		if project.CreatedBy != nil && *project.CreatedBy == input.DecidedBy {
+			return nil, ErrNotApprover
+		}
+	}
+
+	return row, nil
+}
+
+func (s *service) decide(row *model.Row, status string, input *model.Decided) error {
+	now := time.Now()
+	row.Status = status
+	row.DecidedBy = &input.DecidedBy
+	row.DecidedAt = &now
+	row.Reason = input.Reason
+
+	err := s.Entity.Decide(&row.Table)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAlreadyDecided
+		}
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedLevels(levels map[int][]string) []int {
+	keys := make([]int, 0, len(levels))
+	for level := range levels {
+		keys = append(keys, level)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func toBase(row *model.Row) (output *model.Base, err error) {
+	marshal, err := json.Marshal(row)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
+
+func toBases(rows []*model.Row) (output []*model.Base, err error) {
+	marshal, err := json.Marshal(rows)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,93 @@
+package project_approval
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure/project_approvals"
+)
+
+func TestPlan(t *testing.T) {
+	row := func(level int, roles, status string) *model.Table {
+		return &model.Table{ApprovalID: "pa", Level: level, ApproverRoles: roles, Status: status}
+	}
+
+	tests := []struct {
+		name     string
+		levels   map[int][]string
+		existing []*model.Table
+		reset    bool
+		created  []int
+		updated  map[int]string
+	}{
+		{
+			name:    "first submit creates every level",
+			levels:  map[int][]string{1: {"manager"}, 2: {"director"}},
+			created: []int{1, 2},
+		},
+		{
+			name:     "quantity crossed a threshold after level 1 was approved",
+			levels:   map[int][]string{1: {"manager"}, 2: {"director"}},
+			existing: []*model.Table{row(1, "manager", model.StatusApproved)},
+			created:  []int{2},
+		},
+		{
+			name:     "new rule role joins an existing pending level",
+			levels:   map[int][]string{1: {"manager", "sales_lead"}},
+			existing: []*model.Table{row(1, "manager", model.StatusPending)},
+			updated:  map[int]string{1: model.StatusPending},
+		},
+		{
+			name:     "level no longer required is cancelled",
+			levels:   map[int][]string{1: {"manager"}},
+			existing: []*model.Table{row(1, "manager", model.StatusApproved), row(2, "director", model.StatusPending)},
+			updated:  map[int]string{2: model.StatusCancelled},
+		},
+		{
+			name:     "cancelled level required again is reopened",
+			levels:   map[int][]string{1: {"manager"}, 2: {"director"}},
+			existing: []*model.Table{row(1, "manager", model.StatusApproved), row(2, "director", model.StatusCancelled)},
+			updated:  map[int]string{2: model.StatusPending},
+		},
+		{
+			name:     "reset reopens approved levels",
+			levels:   map[int][]string{1: {"manager"}},
+			existing: []*model.Table{row(1, "manager", model.StatusApproved)},
+			reset:    true,
+			updated:  map[int]string{1: model.StatusPending},
+		},
+		{
+			name:     "unchanged levels are left alone",
+			levels:   map[int][]string{1: {"manager"}},
+			existing: []*model.Table{row(1, "manager", model.StatusApproved)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, updated := plan("p1", tt.levels, tt.existing, tt.reset, time.Now())
+
+			if len(created) != len(tt.created) {
+				t.Fatalf("created %d levels, want %v", len(created), tt.created)
+			}
+			for i, approval := range created {
+				if approval.Level != tt.created[i] || approval.Status != model.StatusPending || approval.ProjectID != "p1" {
+					t.Errorf("created[%d] = %+v, want pending level %d", i, approval, tt.created[i])
+				}
+			}
+
+			if len(updated) != len(tt.updated) {
+				t.Fatalf("updated %d levels, want %v", len(updated), tt.updated)
+			}
+			for _, approval := range updated {
+				if status, ok := tt.updated[approval.Level]; !ok || approval.Status != status {
+					t.Errorf("updated level %d to %q, want %q", approval.Level, approval.Status, status)
+				}
+				if approval.Status == model.StatusPending && approval.ApproverRoles != strings.Join(tt.levels[approval.Level], " ") {
+					t.Errorf("level %d roles = %q, want %v", approval.Level, approval.ApproverRoles, tt.levels[approval.Level])
+				}
+			}
+		})
+	}
+}
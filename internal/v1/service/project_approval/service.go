@@ -0,0 +1,52 @@
+package project_approval
+
+import (
+	"esst_sendEmail/internal/v1/entity/approval_rule"
+	"esst_sendEmail/internal/v1/entity/equipment"
+	"esst_sendEmail/internal/v1/entity/project"
+	"esst_sendEmail/internal/v1/entity/project_approval"
+	"esst_sendEmail/internal/v1/entity/user"
+	model "esst_sendEmail/internal/v1/structure/project_approvals"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Submit(projectID string, reset bool) (*model.Base, error)
+	Queue(input *model.Fields, role string) (int64, []*model.Base, error)
+	ListByProjectID(input *model.Fields) (int64, []*model.Base, error)
+	GetByID(input *model.Field) (*model.Base, error)
+	Approve(input *model.Decided) (*model.Base, *model.Base, error)
+	Reject(input *model.Decided) (*model.Base, error)
+	NotifyApprovers(approval *model.Base)
+	NotifyDecision(approval *model.Base)
+}
+
+type service struct {
+	Entity          project_approval.Entity
+	RuleEntity      approval_rule.Entity
+	ProjectEntity   project.Entity
+	EquipmentEntity equipment.Entity
+	UserEntity      user.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity:          project_approval.New(db),
+		RuleEntity:      approval_rule.New(db),
+		ProjectEntity:   project.New(db),
+		EquipmentEntity: equipment.New(db),
+		UserEntity:      user.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity:          s.Entity.WithTrx(tx),
+		RuleEntity:      s.RuleEntity.WithTrx(tx),
+		ProjectEntity:   s.ProjectEntity.WithTrx(tx),
+		EquipmentEntity: s.EquipmentEntity.WithTrx(tx),
+		UserEntity:      s.UserEntity.WithTrx(tx),
+	}
+}
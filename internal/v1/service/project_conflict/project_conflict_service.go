@@ -0,0 +1,227 @@
+package project_conflict
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/similarity"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/project_conflicts"
+)
+
+// 重複報備檢查模式
+const (
+	// ModeOff 不檢查
+	ModeOff = "off"
+	// ModeWarn 照常建立,回傳警告並記錄至審核佇列
+	ModeWarn = "warn"
+	// ModeBlock 拒絕建立,回傳 409 與可能重複的專案
+	ModeBlock = "block"
+)
+
+// Mode 重複報備檢查模式,由 DUPLICATE_CHECK_MODE 設定,預設 warn
+func Mode() string {
+	switch mode := os.Getenv("DUPLICATE_CHECK_MODE"); mode {
+	case ModeOff, ModeBlock:
+		return mode
+	default:
+		return ModeWarn
+	}
+}
+
+func envFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// Check 依 DUPLICATE_CHECK_MODE 檢查重複報備,block 模式下發現可能重複的專案時 blocked 為 true
+// 檢查失敗時記錄錯誤並照常建立
+func (s *service) Check(input *model.Checked) (conflicts []*model.Conflict, blocked bool) {
+	mode := Mode()
+	if mode == ModeOff {
+		return nil, false
+	}
+
+	conflicts, err := s.Detect(input)
+	if err != nil {
+		log.Error("Failed to detect duplicate projects:", err)
+		return nil, false
+	}
+
+	return conflicts, len(conflicts) > 0 && mode == ModeBlock
+}
+
+// Detect 找出檢查期間內可能重複的專案
+// 名稱正規化後相似度達門檻、聯絡信箱或電話相同、料號重疊比例達門檻,任一成立即視為可能重複
+// 候選專案由資料庫依三連字相似度、聯絡方式與相同料號預先篩選
+func (s *service) Detect(input *model.Checked) ([]*model.Conflict, error) {
+	windowDays := envFloat("DUPLICATE_CHECK_WINDOW_DAYS", 90)
+	nameThreshold := envFloat("DUPLICATE_NAME_THRESHOLD", 0.85)
+	partOverlap := envFloat("DUPLICATE_PART_OVERLAP", 0.5)
+
+	query := &model.Candidates{
+		Since:            time.Now().Add(-time.Duration(windowDays * float64(24*time.Hour))),
+		Name:             similarity.NormalizeName(input.ProjectName),
+		NamePrefilter:    envFloat("DUPLICATE_NAME_PREFILTER", 0.3),
+		Email:            similarity.NormalizeEmail(input.ContactEmail),
+		Phone:            similarity.NormalizePhone(input.ContactPhone),
+		PartNumbers:      normalizeParts(input.PartNumbers),
+		ExcludeProjectID: input.ExcludeProjectID,
+	}
+
+	candidates, err := s.Entity.ListCandidates(query)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	conflicts := make([]*model.Conflict, 0)
+	for _, candidate := range candidates {
+		if conflict := conflictOf(query, candidate, nameThreshold, partOverlap); conflict != nil {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// conflictOf 判定候選專案是否可能重複,不重複時回傳 nil
+func conflictOf(query *model.Candidates, candidate *model.Candidate, nameThreshold, partOverlap float64) *model.Conflict {
+	reasons := make([]string, 0)
+
+	ratio := similarity.Ratio(query.Name, similarity.NormalizeName(candidate.ProjectName))
+	if ratio >= nameThreshold {
+		reasons = append(reasons, model.ReasonName)
+	}
+	if query.Email != "" && query.Email == similarity.NormalizeEmail(candidate.ContactEmail) {
+		reasons = append(reasons, model.ReasonContactEmail)
+	}
+	if query.Phone != "" && query.Phone == similarity.NormalizePhone(candidate.ContactPhone) {
+		reasons = append(reasons, model.ReasonContactPhone)
+	}
+	if len(query.PartNumbers) > 0 && candidate.MatchedParts > 0 &&
+		float64(candidate.MatchedParts)/float64(len(query.PartNumbers)) >= partOverlap {
+		reasons = append(reasons, model.ReasonPartNumbers)
+	}
+
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	return &model.Conflict{
+		ProjectID:   candidate.ProjectID,
+		ProjectName: candidate.ProjectName,
+		Owner:       candidate.Owner,
+		ContactName: candidate.ContactName,
+		CreatedTime: candidate.CreatedTime,
+		Reasons:     reasons,
+		Similarity:  math.Round(ratio*1000) / 1000,
+	}
+}
+
+// normalizeParts 正規化並去除重複的料號
+func normalizeParts(partNumbers []string) []string {
+	seen := make(map[string]bool)
+	parts := make([]string, 0, len(partNumbers))
+	for _, partNumber := range partNumbers {
+		if part := similarity.NormalizePartNumber(partNumber); part != "" && !seen[part] {
+			seen[part] = true
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// Record 將可能重複的專案加入審核佇列
+func (s *service) Record(projectID string, conflicts []*model.Conflict) error {
+	now := time.Now()
+	tables := make([]*model.Table, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		tables = append(tables, &model.Table{
+			ConflictID:        util.GenerateUUID(),
+			ProjectID:         projectID,
+			ConflictProjectID: conflict.ProjectID,
+			Reasons:           strings.Join(conflict.Reasons, " "),
+			Similarity:        conflict.Similarity,
+			Status:            model.StatusPending,
+			CreatedAt:         now,
+		})
+	}
+
+	err := s.Entity.CreateBatch(tables)
+	if err != nil {
+		log.Error(err)
+	}
+	return err
+}
+
+func (s *service) List(input *model.Fields) (int64, []*model.Base, error) {
+	total, rows, err := s.Entity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	output := make([]*model.Base, 0, len(rows))
+	for _, row := range rows {
+		output = append(output, toBase(row))
+	}
+
+	return total, output, nil
+}
+
+func (s *service) GetByID(input *model.Field) (*model.Base, error) {
+	row, err := s.Entity.GetByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBase(row), nil
+}
+
+// Review 審核重複報備紀錄
+func (s *service) Review(input *model.Reviewed) error {
+	now := time.Now()
+	err := s.Entity.Review(&model.Table{
+		ConflictID: input.ConflictID,
+		Status:     input.Status,
+		ReviewedBy: &input.ReviewedBy,
+		ReviewedAt: &now,
+		ReviewNote: input.ReviewNote,
+	})
+	if err != nil {
+		log.Error(err)
+	}
+	return err
+}
+
+func toBase(row *model.Row) *model.Base {
+	reasons := strings.Fields(row.Reasons)
+	if reasons == nil {
+		reasons = []string{}
+	}
+
+	return &model.Base{
+		ConflictID:          row.ConflictID,
+		ProjectID:           row.ProjectID,
+		ProjectName:         row.ProjectName,
+		Owner:               row.Owner,
+		ConflictProjectID:   row.ConflictProjectID,
+		ConflictProjectName: row.ConflictProjectName,
+		ConflictOwner:       row.ConflictOwner,
+		Reasons:             reasons,
+		Similarity:          row.Similarity,
+		Status:              row.Status,
+		ReviewedBy:          row.ReviewedBy,
+		ReviewedAt:          row.ReviewedAt,
+		ReviewNote:          row.ReviewNote,
+		CreatedAt:           row.CreatedAt,
+	}
+}
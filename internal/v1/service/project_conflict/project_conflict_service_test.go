@@ -0,0 +1,72 @@
+package project_conflict
+
+import (
+	"reflect"
+	"testing"
+
+	model "esst_sendEmail/internal/v1/structure/project_conflicts"
+)
+
+func TestConflictOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     model.Candidates
+		candidate model.Candidate
+		reasons   []string
+	}{
+		{
+			name:      "similar name",
+			query:     model.Candidates{Name: "雙欣科技"},
+			candidate: model.Candidate{ProjectName: "雙欣科技股份有限公司"},
+			reasons:   []string{model.ReasonName},
+		},
+		{
+			name:      "same contact",
+			query:     model.Candidates{Name: "甲公司", Email: "a@example.com", Phone: "0912345678"},
+			candidate: model.Candidate{ProjectName: "乙工程", ContactEmail: " A@Example.com", ContactPhone: "+886 912-345-678"},
+			reasons:   []string{model.ReasonContactEmail, model.ReasonContactPhone},
+		},
+		{
+			name:      "part overlap reaches threshold",
+			query:     model.Candidates{PartNumbers: []string{"A1", "B2"}},
+			candidate: model.Candidate{ProjectName: "other", MatchedParts: 1},
+			reasons:   []string{model.ReasonPartNumbers},
+		},
+		{
+			name:      "part overlap below threshold",
+			query:     model.Candidates{PartNumbers: []string{"A1", "B2", "C3"}},
+			candidate: model.Candidate{ProjectName: "other", MatchedParts: 1},
+		},
+		{
+			name:      "no parts on either side",
+			query:     model.Candidates{Name: "甲公司"},
+			candidate: model.Candidate{ProjectName: "乙工程"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflict := conflictOf(&tt.query, &tt.candidate, 0.85, 0.5)
+			if tt.reasons == nil {
+				if conflict != nil {
+					t.Fatalf("conflictOf() = %+v, want nil", conflict)
+				}
+				return
+			}
+			if conflict == nil {
+				t.Fatalf("conflictOf() = nil, want reasons %v", tt.reasons)
+			}
+			if !reflect.DeepEqual(conflict.Reasons, tt.reasons) {
+				t.Errorf("reasons = %v, want %v", conflict.Reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestNormalizeParts(t *testing.T) {
+	got := normalizeParts([]string{"ab 12", "AB12", "", "  ", "cd-3"})
+	want := []string{"AB12", "CD-3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeParts() = %v, want %v", got, want)
+	}
+}
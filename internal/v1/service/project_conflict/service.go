@@ -0,0 +1,34 @@
+package project_conflict
+
+import (
+	"esst_sendEmail/internal/v1/entity/project_conflict"
+	model "esst_sendEmail/internal/v1/structure/project_conflicts"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Check(input *model.Checked) ([]*model.Conflict, bool)
+	Detect(input *model.Checked) ([]*model.Conflict, error)
+	Record(projectID string, conflicts []*model.Conflict) error
+	List(input *model.Fields) (int64, []*model.Base, error)
+	GetByID(input *model.Field) (*model.Base, error)
+	Review(input *model.Reviewed) error
+}
+
+type service struct {
+	Entity project_conflict.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity: project_conflict.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity: s.Entity.WithTrx(tx),
+	}
+}
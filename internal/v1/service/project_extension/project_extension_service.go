@@ -0,0 +1,158 @@
+package project_extension
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/project_extensions"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotExtendable   = errors.New("只有第一階段或保護期已過的專案可以申請延長")
+	ErrPendingExists   = errors.New("此專案已有待審核的延長申請")
+	ErrAlreadyReviewed = errors.New("此申請已審核")
+)
+
+// Create 申請延長保護期
+func (s *service) Create(input *model.Created) (*model.Base, error) {
+	project, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: input.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	if project.Status != projectModel.StatusStep1 && project.Status != projectModel.StatusExpired {
+		return nil, ErrNotExtendable
+	}
+
+	pending, err := s.Entity.HasPending(input.ProjectID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if pending {
+		return nil, ErrPendingExists
+	}
+
+	table := &model.Table{
+		ExtensionID: util.GenerateUUID(),
+		ProjectID:   input.ProjectID,
+		RequestedBy: input.RequestedBy,
+		Days:        input.Days,
+		Reason:      input.Reason,
+		Status:      model.StatusPending,
+		CreatedAt:   time.Now(),
+	}
+
+	err = s.Entity.Create(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return toBase(table)
+}
+
+func (s *service) List(input *model.Fields) (quantity int64, output []*model.Base, err error) {
+	amount, fields, err := s.Entity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	marshal, err := json.Marshal(fields)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	return amount, output, nil
+}
+
+func (s *service) GetByID(input *model.Field) (*model.Base, error) {
+	field, err := s.Entity.GetByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBase(field)
+}
+
+// Review 審核延長申請;核准時自目前到期時間(已過期則自現在)起延長申請的天數
+func (s *service) Review(input *model.Reviewed) (*model.Base, error) {
+	extension, err := s.Entity.GetByID(&model.Field{ExtensionID: input.ExtensionID})
+	if err != nil {
+		return nil, err
+	}
+	if extension.Status != model.StatusPending {
+		return nil, ErrAlreadyReviewed
+	}
+
+	now := time.Now()
+	extension.Status = input.Status
+	extension.ReviewedBy = &input.ReviewedBy
+	extension.ReviewedAt = &now
+	extension.ReviewNote = input.ReviewNote
+
+	if input.Status == model.StatusApproved {
+		project, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: extension.ProjectID})
+		if err != nil {
+			return nil, err
+		}
+
+		from := now
+		if project.ExpiresAt != nil && project.ExpiresAt.After(now) {
+			from = *project.ExpiresAt
+		}
+		expiresAt := from.AddDate(0, 0, extension.Days)
+
+		err = s.ProjectEntity.Extend(extension.ProjectID, expiresAt)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrNotExtendable
+			}
+			log.Error(err)
+			return nil, err
+		}
+
+		extension.PreviousExpiresAt = project.ExpiresAt
+		extension.NewExpiresAt = &expiresAt
+	}
+
+	err = s.Entity.Review(extension)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAlreadyReviewed
+		}
+		log.Error(err)
+		return nil, err
+	}
+
+	return toBase(extension)
+}
+
+func toBase(table *model.Table) (output *model.Base, err error) {
+	marshal, err := json.Marshal(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
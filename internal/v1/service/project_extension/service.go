@@ -0,0 +1,36 @@
+package project_extension
+
+import (
+	"esst_sendEmail/internal/v1/entity/project"
+	"esst_sendEmail/internal/v1/entity/project_extension"
+	model "esst_sendEmail/internal/v1/structure/project_extensions"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Create(input *model.Created) (*model.Base, error)
+	List(input *model.Fields) (int64, []*model.Base, error)
+	GetByID(input *model.Field) (*model.Base, error)
+	Review(input *model.Reviewed) (*model.Base, error)
+}
+
+type service struct {
+	Entity        project_extension.Entity
+	ProjectEntity project.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity:        project_extension.New(db),
+		ProjectEntity: project.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity:        s.Entity.WithTrx(tx),
+		ProjectEntity: s.ProjectEntity.WithTrx(tx),
+	}
+}
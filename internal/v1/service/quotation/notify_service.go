@@ -0,0 +1,84 @@
+package quotation
+
+import (
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/pricing"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+	model "esst_sendEmail/internal/v1/structure/quotations"
+)
+
+// Notify 通知主管群組報價單已寄出或已被接受(含金額,一般群組不會收到)
+func (s *service) Notify(quotation *model.Base) {
+	if quotation == nil {
+		return
+	}
+
+	project, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: quotation.ProjectID})
+	if err != nil {
+		log.Error("Failed to query project for quotation LINE notification:", err)
+		return
+	}
+
+	data := &linebot.QuotationData{
+		ProjectID:   quotation.ProjectID,
+		ProjectName: project.ProjectName,
+		Version:     quotation.Version,
+		Status:      quotation.Status,
+		Total: linebot.Total{
+			Currency: quotation.Currency,
+			Subtotal: quotation.Subtotal,
+			Discount: quotation.Discount,
+			Tax:      quotation.Tax,
+			Total:    quotation.Total,
+		},
+	}
+	if quotation.SentTo != nil {
+		data.SentTo = *quotation.SentTo
+	}
+	if quotation.UpdatedAt != nil {
+		data.Updated = *quotation.UpdatedAt
+	}
+
+	if err := linebot.New().SendQuotationNotification(data); err != nil {
+		log.Error("Failed to send quotation LINE notification:", err)
+	}
+}
+
+// LinePricing 專案通知附給主管群組的報價金額,沒有任何設備填寫單價或查詢失敗時回傳 nil
+func (s *service) LinePricing(projectID string) *linebot.Pricing {
+	summary, err := s.Pricing(projectID)
+	if err != nil {
+		log.Error("Failed to query pricing for LINE notification:", err)
+		return nil
+	}
+
+	if len(summary.Totals) == 0 {
+		return nil
+	}
+
+	output := &linebot.Pricing{Unpriced: summary.Unpriced}
+	for _, eq := range summary.Equipments {
+		if eq.Amount == nil {
+			continue
+		}
+		output.Lines = append(output.Lines, linebot.PricedLine{
+			PartNumber: eq.PartNumber,
+			Quantity:   eq.Quantity,
+			Currency:   eq.Amount.Currency,
+			UnitPrice:  pricing.FromFloat(*eq.UnitPrice),
+			Total:      eq.Amount.Total,
+		})
+	}
+	for _, total := range summary.Totals {
+		output.Totals = append(output.Totals, linebot.Total{
+			Currency: total.Currency,
+			Subtotal: total.Subtotal,
+			Discount: total.Discount,
+			Tax:      total.Tax,
+			Total:    total.Total,
+		})
+	}
+
+	return output
+}
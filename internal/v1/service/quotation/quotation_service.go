@@ -0,0 +1,366 @@
+package quotation
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/mail"
+	"esst_sendEmail/internal/pkg/pricing"
+	"esst_sendEmail/internal/pkg/util"
+	equipmentModel "esst_sendEmail/internal/v1/structure/equipments"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+	model "esst_sendEmail/internal/v1/structure/quotations"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNoEquipment     = errors.New("專案沒有設備,無法建立報價單")
+	ErrUnpriced        = errors.New("尚有設備未填寫單價,無法建立報價單")
+	ErrMixedCurrency   = errors.New("設備的幣別不一致,報價單只能使用單一幣別")
+	ErrNoContactEmail  = errors.New("專案未填寫聯絡人信箱,無法寄送報價單")
+	ErrAlreadyAccepted = errors.New("專案已有客戶接受的報價單")
+	ErrInvalidStatus   = errors.New("報價單目前的狀態不允許此操作")
+)
+
+// ValidDays 報價單預設有效天數,由 QUOTATION_VALID_DAYS 設定,預設 30 天
+func ValidDays() int {
+	days, err := strconv.Atoi(os.Getenv("QUOTATION_VALID_DAYS"))
+	if err != nil || days <= 0 {
+		return 30
+	}
+	return days
+}
+
+// Pricing 專案設備的金額與依幣別的總計
+func (s *service) Pricing(projectID string) (*model.Pricing, error) {
+	fields, err := s.EquipmentEntity.ListByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	equipments := make([]*equipmentModel.Base, 0, len(fields))
+	marshal, err := json.Marshal(fields)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, &equipments)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	output := &model.Pricing{ProjectID: projectID, Equipments: equipments}
+	amounts := make([]*pricing.Amount, 0, len(equipments))
+	for _, eq := range equipments {
+		eq.Amount = pricing.Of(eq.Quantity, eq.UnitPrice, eq.Discount, eq.Currency, eq.TaxRate)
+		if eq.Amount == nil {
+			output.Unpriced++
+			continue
+		}
+		amounts = append(amounts, eq.Amount)
+	}
+	output.Totals = pricing.Sum(amounts)
+
+	return output, nil
+}
+
+// Create 以專案目前的設備建立新版本的報價單草稿,需在交易中呼叫
+// 所有設備都需填寫單價且幣別一致
+func (s *service) Create(input *model.Created) (*model.Base, error) {
+	version, err := s.Entity.NextVersion(input.ProjectID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	summary, err := s.Pricing(input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(summary.Equipments) == 0 {
+		return nil, ErrNoEquipment
+	}
+	if summary.Unpriced > 0 {
+		return nil, ErrUnpriced
+	}
+	if len(summary.Totals) > 1 {
+		return nil, ErrMixedCurrency
+	}
+
+	now := util.NowToUTC()
+	days := input.ValidDays
+	if days == 0 {
+		days = ValidDays()
+	}
+
+	total := summary.Totals[0]
+	field := &model.Table{
+		QuotationID: util.GenerateUUID(),
+		ProjectID:   input.ProjectID,
+		Version:     version,
+		Status:      model.StatusDraft,
+		Currency:    total.Currency,
+		Subtotal:    total.Subtotal,
+		Discount:    total.Discount,
+		Tax:         total.Tax,
+		Total:       total.Total,
+		Note:        input.Note,
+		ValidUntil:  util.PointerTime(now.AddDate(0, 0, days)),
+		CreatedAt:   now,
+	}
+	if input.CreatedBy != "" {
+		field.CreatedBy = &input.CreatedBy
+	}
+
+	lines := make([]*model.LineTable, 0, len(summary.Equipments))
+	for i, eq := range summary.Equipments {
+		line := &model.LineTable{
+			LineID:      util.GenerateUUID(),
+			QuotationID: field.QuotationID,
+			EquipmentID: util.PointerString(eq.EquipmentID),
+			Position:    i + 1,
+			PartNumber:  eq.PartNumber,
+			Description: eq.Description,
+			Quantity:    eq.Quantity,
+			UnitPrice:   pricing.FromFloat(*eq.UnitPrice),
+			TaxRate:     pricing.DefaultTaxRate(),
+			Subtotal:    eq.Amount.Subtotal,
+			Discount:    eq.Amount.Discount,
+			Tax:         eq.Amount.Tax,
+			Total:       eq.Amount.Total,
+		}
+		if eq.Discount != nil {
+			line.DiscountRate = *eq.Discount
+		}
+		if eq.TaxRate != nil {
+			line.TaxRate = *eq.TaxRate
+		}
+		lines = append(lines, line)
+	}
+
+	err = s.Entity.Create(field, lines)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	output, err := toBase(field)
+	if err != nil {
+		return nil, err
+	}
+	output.Lines = lines
+
+	return output, nil
+}
+
+func (s *service) List(input *model.Fields) (quantity int64, output []*model.Base, err error) {
+	amount, fields, err := s.Entity.List(input)
+	if err != nil {
+		log.Error(err)
+		return 0, nil, err
+	}
+
+	output = make([]*model.Base, 0, len(fields))
+	for _, field := range fields {
+		base, err := toBase(field)
+		if err != nil {
+			return 0, nil, err
+		}
+		output = append(output, base)
+	}
+
+	return amount, output, nil
+}
+
+// GetByID 取得報價單與明細
+func (s *service) GetByID(input *model.Field) (*model.Base, error) {
+	field, err := s.Entity.GetByID(input)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := toBase(field)
+	if err != nil {
+		return nil, err
+	}
+
+	output.Lines, err = s.Entity.ListLines(field.QuotationID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// Send 標記報價單為已寄出並記錄收件人,交易提交後再以 Deliver 寄信,提交失敗時不會寄出
+// 草稿與已寄出的報價單都可以寄送(重寄),已接受的不可再寄
+func (s *service) Send(input *model.Action) (*model.Base, error) {
+	quotation, err := s.GetByID(&model.Field{QuotationID: input.QuotationID, ProjectID: input.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: input.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	if project.ContactEmail == "" {
+		return nil, ErrNoContactEmail
+	}
+
+	now := util.NowToUTC()
+	values := map[string]interface{}{
+		"status":     model.StatusSent,
+		"sent_at":    now,
+		"sent_to":    project.ContactEmail,
+		"updated_at": now,
+	}
+	if input.By != "" {
+		values["sent_by"] = input.By
+	}
+	updated, err := s.Entity.Transition(quotation.QuotationID, []string{model.StatusDraft, model.StatusSent}, values)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrInvalidStatus
+	}
+
+	quotation.Status = model.StatusSent
+	quotation.SentAt = &now
+	quotation.SentTo = util.PointerString(project.ContactEmail)
+	quotation.UpdatedAt = &now
+	if input.By != "" {
+		quotation.SentBy = &input.By
+	}
+
+	return quotation, nil
+}
+
+// Deliver 將報價單 PDF 寄給專案聯絡人,需在 Send 的交易提交後呼叫
+// 寄送失敗時報價單維持已寄出狀態,可再次寄送
+func (s *service) Deliver(quotation *model.Base) error {
+	project, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: quotation.ProjectID})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	content := render(project, quotation)
+	return mail.New().SendQuotationEmail(*quotation.SentTo, mailData(project, quotation), content)
+}
+
+// Accept 標記客戶已接受報價單,只有已寄出的報價單可以接受,每個專案只能接受一張
+func (s *service) Accept(input *model.Action) (*model.Base, error) {
+	quotation, err := s.GetByID(&model.Field{QuotationID: input.QuotationID, ProjectID: input.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.Entity.GetAccepted(input.ProjectID)
+	if err == nil {
+		return nil, ErrAlreadyAccepted
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error(err)
+		return nil, err
+	}
+
+	now := util.NowToUTC()
+	updated, err := s.Entity.Transition(quotation.QuotationID, []string{model.StatusSent}, map[string]interface{}{
+		"status":      model.StatusAccepted,
+		"accepted_at": now,
+		"updated_at":  now,
+	})
+	if err != nil {
+		// 同時接受同一專案的另一張報價單時由唯一索引擋下
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrAlreadyAccepted
+		}
+
+		log.Error(err)
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrInvalidStatus
+	}
+
+	quotation.Status = model.StatusAccepted
+	quotation.AcceptedAt = &now
+	quotation.UpdatedAt = &now
+
+	return quotation, nil
+}
+
+// Render 產生報價單 PDF,回傳檔名與內容
+func (s *service) Render(input *model.Field) (string, []byte, error) {
+	quotation, err := s.GetByID(input)
+	if err != nil {
+		return "", nil, err
+	}
+
+	project, err := s.ProjectEntity.GetByID(&projectModel.Field{ProjectID: quotation.ProjectID})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return filename(quotation), render(project, quotation), nil
+}
+
+// mailData 轉換為報價單 Email 資料
+func mailData(project *projectModel.Table, quotation *model.Base) *mail.QuotationData {
+	data := &mail.QuotationData{
+		ProjectID:   project.ProjectID,
+		ProjectName: project.ProjectName,
+		ContactName: project.ContactName,
+		Version:     quotation.Version,
+		Currency:    quotation.Currency,
+		Lines:       make([]mail.QuotationLine, 0, len(quotation.Lines)),
+		Subtotal:    quotation.Subtotal,
+		Discount:    quotation.Discount,
+		Tax:         quotation.Tax,
+		Total:       quotation.Total,
+	}
+	if quotation.ValidUntil != nil {
+		data.ValidUntil = *quotation.ValidUntil
+	}
+	if quotation.Note != nil {
+		data.Note = *quotation.Note
+	}
+	for _, line := range quotation.Lines {
+		data.Lines = append(data.Lines, mail.QuotationLine{
+			PartNumber:  line.PartNumber,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			Amount:      line.Subtotal - line.Discount,
+		})
+	}
+
+	return data
+}
+
+func toBase(input *model.Table) (*model.Base, error) {
+	output := &model.Base{}
+	marshal, err := json.Marshal(input)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
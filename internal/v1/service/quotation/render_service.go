@@ -0,0 +1,129 @@
+package quotation
+
+import (
+	"fmt"
+	"time"
+
+	"esst_sendEmail/internal/pkg/pdf"
+	"esst_sendEmail/internal/pkg/pricing"
+	projectModel "esst_sendEmail/internal/v1/structure/projects"
+	model "esst_sendEmail/internal/v1/structure/quotations"
+)
+
+// 版面設定(單位 pt)
+const (
+	marginLeft   = 40.0
+	marginRight  = pdf.PageWidth - 40
+	marginBottom = pdf.PageHeight - 60
+	rowHeight    = 18.0
+	fontSize     = 9.0
+)
+
+// 明細欄位位置,數字欄位為右邊界
+const (
+	colNo          = marginLeft + 4
+	colPartNumber  = marginLeft + 26
+	colDescription = marginLeft + 150
+	colQuantity    = marginLeft + 345
+	colUnitPrice   = marginLeft + 410
+	colDiscount    = marginLeft + 445
+	colAmount      = marginRight - 4
+)
+
+// filename 報價單 PDF 檔名
+func filename(quotation *model.Base) string {
+	return fmt.Sprintf("quotation-%s-v%d.pdf", quotation.ProjectID, quotation.Version)
+}
+
+// render 產生報價單 PDF
+func render(project *projectModel.Table, quotation *model.Base) []byte {
+	doc := pdf.New()
+	doc.AddPage()
+
+	// 標題與報價資訊
+	doc.Text(marginLeft, 60, 20, "報價單 QUOTATION")
+	doc.TextRight(marginRight, 48, fontSize, fmt.Sprintf("版本: 第 %d 版", quotation.Version))
+	doc.TextRight(marginRight, 62, fontSize, fmt.Sprintf("日期: %s", quotation.CreatedAt.In(time.Local).Format("2006-01-02")))
+	doc.TextRight(marginRight, 76, fontSize, fmt.Sprintf("有效期限: %s", validUntil(quotation)))
+	doc.Line(marginLeft, 86, marginRight, 86, 1)
+
+	// 客戶資訊
+	doc.Text(marginLeft, 106, 10, fmt.Sprintf("專案名稱: %s", project.ProjectName))
+	doc.Text(marginLeft, 122, 10, fmt.Sprintf("聯絡人: %s", project.ContactName))
+	doc.Text(marginLeft, 138, 10, fmt.Sprintf("電話: %s", project.ContactPhone))
+	doc.Text(marginLeft+260, 138, 10, fmt.Sprintf("信箱: %s", project.ContactEmail))
+
+	// 明細
+	y := renderHeader(doc, 156)
+	for i, line := range quotation.Lines {
+		if y+rowHeight > marginBottom {
+			doc.AddPage()
+			y = renderHeader(doc, 40)
+		}
+
+		baseline := y + 13
+		doc.Text(colNo, baseline, fontSize, fmt.Sprintf("%d", i+1))
+		doc.Text(colPartNumber, baseline, fontSize, pdf.Truncate(line.PartNumber, fontSize, colDescription-colPartNumber-6))
+		doc.Text(colDescription, baseline, fontSize, pdf.Truncate(line.Description, fontSize, colQuantity-colDescription-40))
+		doc.TextRight(colQuantity, baseline, fontSize, fmt.Sprintf("%d", line.Quantity))
+		doc.TextRight(colUnitPrice, baseline, fontSize, pricing.Format(line.UnitPrice))
+		if line.DiscountRate > 0 {
+			doc.TextRight(colDiscount, baseline, fontSize, fmt.Sprintf("%g%%", line.DiscountRate))
+		}
+		doc.TextRight(colAmount, baseline, fontSize, pricing.Format(line.Subtotal-line.Discount))
+		y += rowHeight
+		doc.Line(marginLeft, y, marginRight, y, 0.3)
+	}
+
+	// 總計
+	if y+100 > marginBottom {
+		doc.AddPage()
+		y = 40
+	}
+	y += 20
+	y = renderTotal(doc, y, "小計(折扣前)", quotation.Currency, quotation.Subtotal)
+	if quotation.Discount > 0 {
+		y = renderTotal(doc, y, "折扣", quotation.Currency, -quotation.Discount)
+	}
+	y = renderTotal(doc, y, "稅額", quotation.Currency, quotation.Tax)
+	doc.Line(colUnitPrice-60, y-10, marginRight, y-10, 0.5)
+	y = renderTotal(doc, y+4, "總計", quotation.Currency, quotation.Total)
+
+	// 備註
+	if quotation.Note != nil && *quotation.Note != "" {
+		doc.Text(marginLeft, y+20, fontSize, pdf.Truncate(fmt.Sprintf("備註: %s", *quotation.Note), fontSize, marginRight-marginLeft))
+	}
+
+	return doc.Bytes()
+}
+
+// renderHeader 繪製明細表頭,回傳下一列的位置
+func renderHeader(doc *pdf.Document, y float64) float64 {
+	doc.Rect(marginLeft, y, marginRight-marginLeft, rowHeight, 0.9)
+
+	baseline := y + 13
+	doc.Text(colNo, baseline, fontSize, "#")
+	doc.Text(colPartNumber, baseline, fontSize, "料號")
+	doc.Text(colDescription, baseline, fontSize, "說明")
+	doc.TextRight(colQuantity, baseline, fontSize, "數量")
+	doc.TextRight(colUnitPrice, baseline, fontSize, "單價")
+	doc.TextRight(colDiscount, baseline, fontSize, "折扣")
+	doc.TextRight(colAmount, baseline, fontSize, "未稅金額")
+
+	return y + rowHeight
+}
+
+// renderTotal 繪製一行總計,回傳下一行的位置
+func renderTotal(doc *pdf.Document, y float64, label, currency string, amount pricing.Money) float64 {
+	doc.TextRight(colUnitPrice, y, 10, label)
+	doc.TextRight(colAmount, y, 10, fmt.Sprintf("%s %s", currency, pricing.Format(amount)))
+	return y + 16
+}
+
+// validUntil 報價有效期限的顯示格式
+func validUntil(quotation *model.Base) string {
+	if quotation.ValidUntil == nil {
+		return "-"
+	}
+	return quotation.ValidUntil.In(time.Local).Format("2006-01-02")
+}
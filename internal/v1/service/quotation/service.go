@@ -0,0 +1,47 @@
+package quotation
+
+import (
+	"esst_sendEmail/internal/pkg/linebot"
+	"esst_sendEmail/internal/v1/entity/equipment"
+	"esst_sendEmail/internal/v1/entity/project"
+	"esst_sendEmail/internal/v1/entity/quotation"
+	model "esst_sendEmail/internal/v1/structure/quotations"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Pricing(projectID string) (*model.Pricing, error)
+	Create(input *model.Created) (*model.Base, error)
+	List(input *model.Fields) (int64, []*model.Base, error)
+	GetByID(input *model.Field) (*model.Base, error)
+	Send(input *model.Action) (*model.Base, error)
+	Deliver(quotation *model.Base) error
+	Accept(input *model.Action) (*model.Base, error)
+	Render(input *model.Field) (string, []byte, error)
+	Notify(quotation *model.Base)
+	LinePricing(projectID string) *linebot.Pricing
+}
+
+type service struct {
+	Entity          quotation.Entity
+	EquipmentEntity equipment.Entity
+	ProjectEntity   project.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity:          quotation.New(db),
+		EquipmentEntity: equipment.New(db),
+		ProjectEntity:   project.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity:          s.Entity.WithTrx(tx),
+		EquipmentEntity: s.EquipmentEntity.WithTrx(tx),
+		ProjectEntity:   s.ProjectEntity.WithTrx(tx),
+	}
+}
@@ -0,0 +1,38 @@
+package role_policy
+
+import (
+	"os"
+
+	model "esst_sendEmail/internal/v1/structure/role_policies"
+)
+
+// 登入方式
+const (
+	LoginPolicyPassword = "password" // 僅密碼
+	LoginPolicyEmail    = "email"    // 密碼 + email 驗證碼
+	LoginPolicyTOTP     = "totp"     // 密碼 + 驗證器 App
+)
+
+// DefaultLoginPolicy 系統預設登入方式,由 LOGIN_POLICY 設定,預設為密碼 + email 驗證碼
+func DefaultLoginPolicy() string {
+	switch policy := os.Getenv("LOGIN_POLICY"); policy {
+	case LoginPolicyPassword, LoginPolicyEmail, LoginPolicyTOTP:
+		return policy
+	default:
+		return LoginPolicyEmail
+	}
+}
+
+// effectiveLoginPolicy 角色未設定時使用系統預設,強制兩步驟驗證的角色不可僅用密碼登入
+func effectiveLoginPolicy(table *model.Table) string {
+	policy := table.LoginPolicy
+	if policy == "" {
+		policy = DefaultLoginPolicy()
+	}
+
+	if table.RequireTwoFactor && policy == LoginPolicyPassword {
+		return LoginPolicyEmail
+	}
+
+	return policy
+}
@@ -0,0 +1,69 @@
+package role_policy
+
+import (
+	"errors"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	model "esst_sendEmail/internal/v1/structure/role_policies"
+
+	"gorm.io/gorm"
+)
+
+func (s *service) List() ([]*model.Base, error) {
+	records, err := s.Entity.List()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	output := make([]*model.Base, 0, len(records))
+	for _, record := range records {
+		output = append(output, toBase(record))
+	}
+
+	return output, nil
+}
+
+// GetByRole 取得角色政策,尚未設定的角色回傳預設值(不強制兩步驟驗證,登入方式依系統預設)
+func (s *service) GetByRole(input *model.Field) (*model.Base, error) {
+	record, err := s.Entity.GetByRole(input)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return toBase(&model.Table{Role: input.Role}), nil
+		}
+		log.Error(err)
+		return nil, err
+	}
+
+	return toBase(record), nil
+}
+
+func (s *service) Update(input *model.Updated) (*model.Base, error) {
+	now := time.Now()
+	table := &model.Table{
+		Role:             input.Role,
+		RequireTwoFactor: *input.RequireTwoFactor,
+		LoginPolicy:      input.LoginPolicy,
+		UpdatedAt:        &now,
+	}
+
+	err := s.Entity.Upsert(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return toBase(table), nil
+}
+
+func toBase(table *model.Table) *model.Base {
+	return &model.Base{
+		Role:             table.Role,
+		RequireTwoFactor: table.RequireTwoFactor,
+		UpdatedAt:        table.UpdatedAt,
+
+		LoginPolicy:          table.LoginPolicy,
+		EffectiveLoginPolicy: effectiveLoginPolicy(table),
+	}
+}
@@ -0,0 +1,31 @@
+package role_policy
+
+import (
+	"esst_sendEmail/internal/v1/entity/role_policy"
+	model "esst_sendEmail/internal/v1/structure/role_policies"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	List() ([]*model.Base, error)
+	GetByRole(input *model.Field) (*model.Base, error)
+	Update(input *model.Updated) (*model.Base, error)
+}
+
+type service struct {
+	Entity role_policy.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity: role_policy.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity: s.Entity.WithTrx(tx),
+	}
+}
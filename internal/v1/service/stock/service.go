@@ -1,7 +1,12 @@
 package stock
 
 import (
+	"time"
+
+	"esst_sendEmail/internal/v1/entity/comment"
 	"esst_sendEmail/internal/v1/entity/stock"
+	"esst_sendEmail/internal/v1/entity/stock_allocation"
+	"esst_sendEmail/internal/v1/entity/stock_equipment"
 	model "esst_sendEmail/internal/v1/structure/stocks"
 
 	"gorm.io/gorm"
@@ -14,20 +19,33 @@ type Service interface {
 	GetByID(input *model.Field) (*model.Base, error)
 	Update(input *model.Updated) error
 	Delete(input *model.Updated) error
+	ListDeleted(input *model.Fields) (int64, []*model.Base, error)
+	GetDeletedByID(input *model.Field) (*model.Base, error)
+	Restore(input *model.Field) error
+	Purge(before time.Time) (int64, error)
 }
 
 type service struct {
-	Entity stock.Entity
+	Entity               stock.Entity
+	StockEquipmentEntity stock_equipment.Entity
+	CommentEntity        comment.Entity
+	AllocationEntity     stock_allocation.Entity
 }
 
 func New(db *gorm.DB) Service {
 	return &service{
-		Entity: stock.New(db),
+		Entity:               stock.New(db),
+		StockEquipmentEntity: stock_equipment.New(db),
+		CommentEntity:        comment.New(db),
+		AllocationEntity:     stock_allocation.New(db),
 	}
 }
 
 func (s *service) WithTrx(tx *gorm.DB) Service {
 	return &service{
-		Entity: s.Entity.WithTrx(tx),
+		Entity:               s.Entity.WithTrx(tx),
+		StockEquipmentEntity: s.StockEquipmentEntity.WithTrx(tx),
+		CommentEntity:        s.CommentEntity.WithTrx(tx),
+		AllocationEntity:     s.AllocationEntity.WithTrx(tx),
 	}
 }
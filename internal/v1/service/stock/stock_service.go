@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"esst_sendEmail/internal/pkg/log"
 	"esst_sendEmail/internal/pkg/util"
+	commentModel "esst_sendEmail/internal/v1/structure/comments"
 	model "esst_sendEmail/internal/v1/structure/stocks"
+	"strings"
 	"time"
 )
 
@@ -83,7 +85,6 @@ func (s *service) Create(input *model.Created) (*model.Base, error) {
 		ContractEndDate:        output.ContractEndDate,
 		DeliveryAddress:        output.DeliveryAddress,
 		SpecialRequirements:    output.SpecialRequirements,
-		Remark:                 output.Remark,
 		CreatedTime:            output.CreatedTime,
 		UpdatedTime:            output.UpdatedTime,
 	}
@@ -93,6 +94,11 @@ func (s *service) Create(input *model.Created) (*model.Base, error) {
 		return nil, err
 	}
 
+	err = s.addRemark(output.StockID, input.Remark, input.CreatedBy, output.CreatedTime)
+	if err != nil {
+		return nil, err
+	}
+
 	return &output, nil
 }
 
@@ -140,13 +146,34 @@ func (s *service) GetByID(input *model.Field) (output *model.Base, err error) {
 	return output, nil
 }
 
+// Delete 軟刪除現貨,並以相同的刪除時間一併軟刪除現貨設備
 func (s *service) Delete(input *model.Updated) (err error) {
 	field, err := s.Entity.GetByID(&model.Field{StockID: input.StockID})
 	if err != nil {
 		log.Error(err)
+
+		return err
+	}
+
+	// 資料庫時間精度為微秒,截斷後還原時才能以刪除時間比對
+	deletedAt := time.Now().Truncate(time.Microsecond)
+	err = s.Entity.Delete(&model.Field{StockID: field.StockID}, input.Version, input.DeletedBy, deletedAt)
+	if err != nil {
+		log.Error(err)
+
 		return err
 	}
-	err = s.Entity.Delete(&model.Field{StockID: field.StockID})
+
+	err = s.StockEquipmentEntity.DeleteByStockID(field.StockID, input.DeletedBy, deletedAt)
+	if err != nil {
+		log.Error(err)
+
+		return err
+	}
+
+	// 一併取消分配,還原時才不會帶回未檢查庫存的分配
+	err = s.AllocationEntity.DeleteByStockID(field.StockID)
+
 	return err
 }
 
@@ -173,9 +200,6 @@ func (s *service) Update(input *model.Updated) (err error) {
 	if input.Owner != "" {
 		field.Owner = input.Owner
 	}
-	if input.Remark != "" {
-		field.Remark = input.Remark
-	}
 
 	// 處理交貨資訊
 	if input.ExpectedDeliveryPeriod != "" {
@@ -210,6 +234,37 @@ func (s *service) Update(input *model.Updated) (err error) {
 	now := time.Now()
 	field.UpdatedTime = &now
 
+	// 以 If-Match 的版本號更新,避免覆蓋他人的修改
+	field.Version = input.Version
+
 	err = s.Entity.Update(field)
+	if err != nil {
+		return err
+	}
+
+	return s.addRemark(field.StockID, input.Remark, input.UpdatedBy, now)
+}
+
+// addRemark 備註改以留言保存,避免每次更新覆寫先前的內容;需與建立或更新在同一交易中呼叫
+func (s *service) addRemark(stockID, remark, authorID string, createdAt time.Time) error {
+	if strings.TrimSpace(remark) == "" {
+		return nil
+	}
+
+	comment := &commentModel.Table{
+		CommentID:  util.GenerateUUID(),
+		TargetType: commentModel.TargetStock,
+		TargetID:   stockID,
+		Body:       remark,
+		CreatedAt:  createdAt,
+	}
+	if authorID != "" {
+		comment.AuthorID = &authorID
+	}
+
+	err := s.CommentEntity.Create(comment)
+	if err != nil {
+		log.Error(err)
+	}
 	return err
 }
@@ -0,0 +1,41 @@
+package stock_allocation
+
+import (
+	"esst_sendEmail/internal/v1/entity/equipment"
+	"esst_sendEmail/internal/v1/entity/stock_allocation"
+	"esst_sendEmail/internal/v1/entity/stock_equipment"
+	model "esst_sendEmail/internal/v1/structure/stock_allocations"
+
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	WithTrx(tx *gorm.DB) Service
+	Create(input *model.Created) (*model.Base, error)
+	Delete(input *model.Field) error
+	Summary(projectID string) (*model.Summary, error)
+	Allocated(equipmentID string) (int64, error)
+	AllocatedFromStock(stockEquipmentID string) (int64, error)
+}
+
+type service struct {
+	Entity               stock_allocation.Entity
+	EquipmentEntity      equipment.Entity
+	StockEquipmentEntity stock_equipment.Entity
+}
+
+func New(db *gorm.DB) Service {
+	return &service{
+		Entity:               stock_allocation.New(db),
+		EquipmentEntity:      equipment.New(db),
+		StockEquipmentEntity: stock_equipment.New(db),
+	}
+}
+
+func (s *service) WithTrx(tx *gorm.DB) Service {
+	return &service{
+		Entity:               s.Entity.WithTrx(tx),
+		EquipmentEntity:      s.EquipmentEntity.WithTrx(tx),
+		StockEquipmentEntity: s.StockEquipmentEntity.WithTrx(tx),
+	}
+}
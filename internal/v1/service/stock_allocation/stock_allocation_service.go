@@ -0,0 +1,182 @@
+package stock_allocation
+
+import (
+	"encoding/json"
+	"errors"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/similarity"
+	"esst_sendEmail/internal/pkg/util"
+	equipmentModel "esst_sendEmail/internal/v1/structure/equipments"
+	model "esst_sendEmail/internal/v1/structure/stock_allocations"
+	stockEquipmentModel "esst_sendEmail/internal/v1/structure/stock_equipments"
+)
+
+var (
+	ErrNotInProject  = errors.New("專案設備不屬於此專案")
+	ErrPartMismatch  = errors.New("現貨設備與專案設備的料號不同")
+	ErrOverAllocated = errors.New("分配數量超過專案設備需求或現貨設備數量")
+)
+
+// Create 將現貨設備分配給專案設備,需在交易中呼叫,會鎖定兩筆設備避免同時分配造成超額
+func (s *service) Create(input *model.Created) (*model.Base, error) {
+	err := s.Entity.LockLines(input.EquipmentID, input.StockEquipmentID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	eq, err := s.EquipmentEntity.GetByID(&equipmentModel.Field{EquipmentID: input.EquipmentID})
+	if err != nil {
+		return nil, err
+	}
+	if eq.ProjectID != input.ProjectID {
+		return nil, ErrNotInProject
+	}
+
+	seq, err := s.StockEquipmentEntity.GetByID(&stockEquipmentModel.Field{StockEquipmentID: input.StockEquipmentID})
+	if err != nil {
+		return nil, err
+	}
+	if similarity.NormalizePartNumber(eq.PartNumber) != similarity.NormalizePartNumber(seq.PartNumber) {
+		return nil, ErrPartMismatch
+	}
+
+	allocated, err := s.Entity.SumByEquipment(input.EquipmentID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if allocated+input.Quantity > eq.Quantity {
+		return nil, ErrOverAllocated
+	}
+
+	fromStock, err := s.Entity.SumByStockEquipment(input.StockEquipmentID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if fromStock+input.Quantity > seq.Quantity {
+		return nil, ErrOverAllocated
+	}
+
+	field := &model.Table{
+		AllocationID:     util.GenerateUUID(),
+		ProjectID:        input.ProjectID,
+		EquipmentID:      input.EquipmentID,
+		StockEquipmentID: input.StockEquipmentID,
+		Quantity:         input.Quantity,
+		CreatedAt:        util.NowToUTC(),
+	}
+	if input.AllocatedBy != "" {
+		field.AllocatedBy = &input.AllocatedBy
+	}
+	err = s.Entity.Upsert(field)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return toBase(&model.Row{Table: *field, StockID: seq.StockID, PartNumber: seq.PartNumber})
+}
+
+func (s *service) Delete(input *model.Field) error {
+	err := s.Entity.Delete(input)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Summary 列出專案每筆設備由現貨支應與需另行採購的數量
+func (s *service) Summary(projectID string) (*model.Summary, error) {
+	equipments, err := s.EquipmentEntity.ListByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Entity.ListByProjectID(projectID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	allocations := make(map[string][]*model.Base)
+	for _, row := range rows {
+		base, err := toBase(row)
+		if err != nil {
+			return nil, err
+		}
+		allocations[row.EquipmentID] = append(allocations[row.EquipmentID], base)
+	}
+
+	output := &model.Summary{ProjectID: projectID, Equipments: make([]*model.Coverage, 0, len(equipments))}
+	for _, eq := range equipments {
+		coverage := &model.Coverage{
+			EquipmentID: eq.EquipmentID,
+			PartNumber:  eq.PartNumber,
+			Description: eq.Description,
+			Quantity:    eq.Quantity,
+			Allocations: make([]*model.Base, 0),
+		}
+		for _, allocation := range allocations[eq.EquipmentID] {
+			coverage.FromStock += allocation.Quantity
+			coverage.Allocations = append(coverage.Allocations, allocation)
+		}
+		// 設備數量被調低時可能低於已分配數量,需求以設備數量為上限
+		if coverage.FromStock > coverage.Quantity {
+			coverage.FromStock = coverage.Quantity
+		}
+		coverage.ToOrder = coverage.Quantity - coverage.FromStock
+
+		output.Quantity += coverage.Quantity
+		output.FromStock += coverage.FromStock
+		output.ToOrder += coverage.ToOrder
+		output.Equipments = append(output.Equipments, coverage)
+	}
+
+	return output, nil
+}
+
+// Allocated 專案設備已由現貨支應的數量,調整設備數量時不可低於此值
+// 需在交易中呼叫,會先鎖定專案設備,交易結束前無法同時分配
+func (s *service) Allocated(equipmentID string) (int64, error) {
+	err := s.Entity.LockEquipment(equipmentID)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	return s.Entity.SumByEquipment(equipmentID)
+}
+
+// AllocatedFromStock 現貨設備已分配給專案的數量,調整現貨數量時不可低於此值
+// 需在交易中呼叫,會先鎖定現貨設備,交易結束前無法同時分配
+func (s *service) AllocatedFromStock(stockEquipmentID string) (int64, error) {
+	err := s.Entity.LockStockEquipment(stockEquipmentID)
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	return s.Entity.SumByStockEquipment(stockEquipmentID)
+}
+
+func toBase(input *model.Row) (*model.Base, error) {
+	output := &model.Base{}
+	marshal, err := json.Marshal(input)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
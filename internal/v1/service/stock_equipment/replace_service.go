@@ -0,0 +1,160 @@
+package stock_equipment
+
+import (
+	"encoding/json"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	changeModel "esst_sendEmail/internal/v1/structure/equipment_changes"
+	model "esst_sendEmail/internal/v1/structure/stock_equipments"
+)
+
+// Replace 以完整清單取代現貨設備:未帶 seq_id 的新增、內容不同的修改、未列出的刪除,並記錄異動紀錄
+// 需在交易中呼叫,任一筆失敗時由呼叫端回滾
+func (s *service) Replace(input *model.Replaced) (*model.Diff, error) {
+	current, err := s.Entity.ListByStockID(input.StockID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	// 先檢查清單,避免寫入一半才發現錯誤
+	listed, err := changeModel.Listed(current, input.Checksum, input.Requested())
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &model.Diff{
+		Added:   make([]*model.Base, 0),
+		Updated: make([]*model.Change, 0),
+		Deleted: make([]*model.Base, 0),
+	}
+	kept := make([]*model.Table, 0, len(input.Equipments))
+	now := util.NowToUTC()
+
+	for _, eq := range input.Equipments {
+		if eq.StockEquipmentID == nil {
+			table := &model.Table{
+				StockEquipmentID: util.GenerateUUID(),
+				StockID:          input.StockID,
+				CreatedTime:      now,
+				Version:          1,
+			}
+			apply(table, eq.BatchEquipment)
+
+			if err := s.Entity.Create(table); err != nil {
+				log.Error(err)
+				return nil, err
+			}
+
+			after, err := toBase(table)
+			if err != nil {
+				return nil, err
+			}
+			diff.Added = append(diff.Added, after)
+			kept = append(kept, table)
+			continue
+		}
+
+		table := *listed[*eq.StockEquipmentID]
+		kept = append(kept, &table)
+		if same(&table, eq.BatchEquipment) {
+			diff.Unchanged++
+			continue
+		}
+
+		before, err := toBase(&table)
+		if err != nil {
+			return nil, err
+		}
+
+		apply(&table, eq.BatchEquipment)
+		if err := s.Entity.Update(&table); err != nil {
+			return nil, err
+		}
+
+		after, err := toBase(&table)
+		if err != nil {
+			return nil, err
+		}
+		diff.Updated = append(diff.Updated, &model.Change{Before: before, After: after})
+	}
+
+	for _, eq := range current {
+		if _, ok := listed[eq.StockEquipmentID]; ok {
+			continue
+		}
+
+		err := s.Entity.Delete(&model.Field{StockEquipmentID: eq.StockEquipmentID}, eq.Version, input.ChangedBy, now)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.AllocationEntity.DeleteByStockEquipmentID(eq.StockEquipmentID)
+		if err != nil {
+			return nil, err
+		}
+
+		before, err := toBase(eq)
+		if err != nil {
+			return nil, err
+		}
+		diff.Deleted = append(diff.Deleted, before)
+	}
+
+	diff.Checksum = changeModel.Checksum(kept)
+	if !diff.Changed() {
+		return diff, nil
+	}
+
+	diff.BatchID = util.GenerateUUID()
+	records := changeModel.Records(&changeModel.Batch{
+		BatchID:    diff.BatchID,
+		TargetType: changeModel.TargetStock,
+		TargetID:   input.StockID,
+		ChangedBy:  input.ChangedBy,
+		ChangedAt:  now,
+		Entries:    diff.Entries(),
+	})
+	if err := s.ChangeEntity.CreateBatch(records); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+// apply 將清單中的設備內容寫入資料表結構
+func apply(table *model.Table, eq model.BatchEquipment) {
+	table.PartNumber = eq.PartNumber
+	table.PartID = eq.PartID
+	table.Quantity = eq.Quantity
+	table.Description = eq.Description
+}
+
+// same 清單中的設備內容與目前的設備是否相同
+func same(table *model.Table, eq model.BatchEquipment) bool {
+	return table.PartNumber == eq.PartNumber &&
+		util.Equal(table.PartID, eq.PartID) &&
+		table.Quantity == eq.Quantity &&
+		table.Description == eq.Description
+}
+
+// toBase 轉換為回傳結構
+func toBase(table *model.Table) (*model.Base, error) {
+	output := &model.Base{}
+
+	marshal, err := json.Marshal(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(marshal, output)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
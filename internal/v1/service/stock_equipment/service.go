@@ -1,6 +1,10 @@
 package stock_equipment
 
 import (
+	"time"
+
+	"esst_sendEmail/internal/v1/entity/equipment_change"
+	"esst_sendEmail/internal/v1/entity/stock_allocation"
 	"esst_sendEmail/internal/v1/entity/stock_equipment"
 	model "esst_sendEmail/internal/v1/structure/stock_equipments"
 
@@ -16,20 +20,31 @@ type Service interface {
 	GetByID(input *model.Field) (*model.Base, error)
 	Update(input *model.Updated) error
 	Delete(input *model.Updated) error
+	ListDeleted(input *model.Fields) (int64, []*model.Base, error)
+	GetDeletedByID(input *model.Field) (*model.Base, error)
+	Restore(input *model.Field) error
+	Purge(before time.Time) (int64, error)
+	Replace(input *model.Replaced) (*model.Diff, error)
 }
 
 type service struct {
-	Entity stock_equipment.Entity
+	Entity           stock_equipment.Entity
+	ChangeEntity     equipment_change.Entity
+	AllocationEntity stock_allocation.Entity
 }
 
 func New(db *gorm.DB) Service {
 	return &service{
-		Entity: stock_equipment.New(db),
+		Entity:           stock_equipment.New(db),
+		ChangeEntity:     equipment_change.New(db),
+		AllocationEntity: stock_allocation.New(db),
 	}
 }
 
 func (s *service) WithTrx(tx *gorm.DB) Service {
 	return &service{
-		Entity: s.Entity.WithTrx(tx),
+		Entity:           s.Entity.WithTrx(tx),
+		ChangeEntity:     s.ChangeEntity.WithTrx(tx),
+		AllocationEntity: s.AllocationEntity.WithTrx(tx),
 	}
 }
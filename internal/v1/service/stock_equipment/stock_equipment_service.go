@@ -5,6 +5,7 @@ import (
 	"esst_sendEmail/internal/pkg/log"
 	"esst_sendEmail/internal/pkg/util"
 	model "esst_sendEmail/internal/v1/structure/stock_equipments"
+	"time"
 )
 
 func (s *service) Create(input *model.Created) (*model.Base, error) {
@@ -112,13 +113,23 @@ func (s *service) GetByID(input *model.Field) (output *model.Base, err error) {
 	return output, nil
 }
 
+// Delete 軟刪除現貨設備
 func (s *service) Delete(input *model.Updated) (err error) {
 	field, err := s.Entity.GetByID(&model.Field{StockEquipmentID: input.StockEquipmentID})
 	if err != nil {
 		log.Error(err)
+
 		return err
 	}
-	err = s.Entity.Update(field)
+	err = s.Entity.Delete(&model.Field{StockEquipmentID: field.StockEquipmentID}, input.Version, input.DeletedBy, time.Now())
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	// 一併取消分配,還原時才不會帶回未檢查庫存的分配
+	err = s.AllocationEntity.DeleteByStockEquipmentID(field.StockEquipmentID)
+
 	return err
 }
 
@@ -141,6 +152,11 @@ func (s *service) Update(input *model.Updated) (err error) {
 		return err
 	}
 
+	// 料號目錄參照隨料號一起更新,未對應到目錄時清除
+	field.PartID = input.PartID
+	// 以 If-Match 的版本號更新,避免覆蓋他人的修改
+	field.Version = input.Version
+
 	err = s.Entity.Update(field)
 	return err
 }
@@ -0,0 +1,182 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/users"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// AuthProviderLocal 本地帳號密碼
+	AuthProviderLocal = "local"
+	// AuthProviderOIDC OIDC 單一登入
+	AuthProviderOIDC = "oidc"
+	// AuthProviderLDAP LDAP / Active Directory
+	AuthProviderLDAP = "ldap"
+)
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// ProvisionOIDCUser 依 OIDC subject 取得使用者,首次登入時以已驗證的信箱連結既有帳號或自動建立帳號
+// 每次登入都會同步信箱;群組對應的角色只同步到由身分提供者建立的帳號
+func (s *service) ProvisionOIDCUser(input *model.ExternalIdentity) (*model.Base, error) {
+	user, err := s.Entity.GetByOIDCSubject(input.Subject)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error(err)
+			return nil, err
+		}
+
+		user, err = s.linkOrCreateOIDCUser(input)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	columns := identityColumns(user, input)
+	if len(columns) > 0 {
+		now := time.Now()
+		columns["updated_at"] = now
+		user.UpdatedAt = &now
+
+		err = s.Entity.UpdateColumns(user.ID, columns)
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+	}
+
+	return toBase(user), nil
+}
+
+// identityColumns 依身分提供者的資料計算需同步的欄位並寫回 user
+// 以信箱連結的本地帳號保留原角色,避免群組對應意外提升或降低既有管理員的權限
+func identityColumns(user *model.Table, input *model.ExternalIdentity) map[string]interface{} {
+	columns := map[string]interface{}{}
+	if input.Email != "" && input.Email != user.Email {
+		columns["email"] = input.Email
+		user.Email = input.Email
+	}
+	if user.AuthProvider == AuthProviderOIDC && input.Role != "" && input.Role != user.Role {
+		columns["role"] = input.Role
+		user.Role = input.Role
+	}
+
+	return columns
+}
+
+// linkOrCreateOIDCUser 以已驗證的信箱連結既有帳號,找不到時建立新帳號
+func (s *service) linkOrCreateOIDCUser(input *model.ExternalIdentity) (*model.Table, error) {
+	if input.Email != "" && input.EmailVerified {
+		user, err := s.Entity.GetByEmail(&model.Field{Email: &input.Email})
+		if err == nil {
+			err = s.Entity.UpdateColumns(user.ID, map[string]interface{}{"oidc_subject": input.Subject})
+			if err != nil {
+				log.Error(err)
+				return nil, err
+			}
+
+			log.Info("OIDC subject linked to existing user:", user.Username)
+			user.OIDCSubject = &input.Subject
+			return user, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error(err)
+			return nil, err
+		}
+	}
+
+	username, err := s.availableUsername(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// 單一登入帳號不使用本地密碼,填入無法猜測的隨機密碼
+	hashedPassword, err := randomPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+
+	role := input.Role
+	if role == "" {
+		role = "user"
+	}
+
+	user := &model.Table{
+		ID:        util.GenerateUUID(),
+		Username:  username,
+		Email:     input.Email,
+		Password:  hashedPassword,
+		Role:      role,
+		CreatedAt: time.Now(),
+
+		TwoFactorMethod: TwoFactorEmail,
+
+		AuthProvider: AuthProviderOIDC,
+		OIDCSubject:  &input.Subject,
+	}
+
+	err = s.Entity.Create(user)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	log.Info("OIDC user provisioned:", user.Username)
+	return user, nil
+}
+
+// availableUsername 以 preferred_username 或信箱帳號作為使用者名稱,重複時加上流水號
+func (s *service) availableUsername(input *model.ExternalIdentity) (string, error) {
+	base := input.Username
+	if base == "" {
+		base, _, _ = strings.Cut(input.Email, "@")
+	}
+	base = usernameInvalidChars.ReplaceAllString(base, "")
+	if base == "" {
+		base = "sso-user"
+	}
+
+	for i := 0; i < 100; i++ {
+		candidate := base
+		if i > 0 {
+			candidate = fmt.Sprintf("%s-%d", base, i+1)
+		}
+
+		_, err := s.Entity.GetByUsername(&model.Field{Username: &candidate})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			log.Error(err)
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("no available username for %s", base)
+}
+
+func randomPasswordHash() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(buf)), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashed), nil
+}
@@ -0,0 +1,42 @@
+package user
+
+import (
+	"testing"
+
+	model "esst_sendEmail/internal/v1/structure/users"
+)
+
+func TestIdentityColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		input    model.ExternalIdentity
+		role     string
+		columns  []string
+	}{
+		{"idp managed account follows group role", AuthProviderOIDC, model.ExternalIdentity{Role: "user"}, "user", []string{"role"}},
+		{"linked local account keeps its role", AuthProviderLocal, model.ExternalIdentity{Role: "user"}, "admin", nil},
+		{"linked ldap account keeps its role", AuthProviderLDAP, model.ExternalIdentity{Role: "user"}, "admin", nil},
+		{"empty mapped role is ignored", AuthProviderOIDC, model.ExternalIdentity{}, "admin", nil},
+		{"email is synced for any account", AuthProviderLocal, model.ExternalIdentity{Email: "new@example.com", Role: "user"}, "admin", []string{"email"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &model.Table{Email: "old@example.com", Role: "admin", AuthProvider: tt.provider}
+
+			columns := identityColumns(user, &tt.input)
+			if len(columns) != len(tt.columns) {
+				t.Fatalf("identityColumns() = %v, want keys %v", columns, tt.columns)
+			}
+			for _, key := range tt.columns {
+				if _, ok := columns[key]; !ok {
+					t.Errorf("identityColumns() missing %q", key)
+				}
+			}
+			if user.Role != tt.role {
+				t.Errorf("role = %q, want %q", user.Role, tt.role)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package user
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/directory"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure/users"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// checkPassword 依使用者的登入來源驗證密碼
+// LDAP 驗證失敗或目錄服務無法連線時,具備 break-glass 角色的本地帳號改以本地密碼驗證
+func (s *service) checkPassword(user *model.Table, password string) error {
+	if !usesLDAP(user) {
+		return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	}
+
+	entry, err := directory.Authenticate(user.Username, password)
+	if err == nil {
+		return s.syncLDAPUser(user, entry)
+	}
+
+	if localFallback(user) {
+		log.Info("LDAP authentication failed, falling back to local account:", user.Username)
+		return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	}
+
+	if errors.Is(err, directory.ErrInvalidCredentials) || errors.Is(err, directory.ErrUserNotFound) {
+		return bcrypt.ErrMismatchedHashAndPassword
+	}
+
+	log.Error(err)
+	return err
+}
+
+// provisionLDAPUser 本地查無帳號時以 LDAP 驗證,成功後自動建立帳號
+func (s *service) provisionLDAPUser(input *model.Login) (*model.Table, error) {
+	entry, err := directory.Authenticate(input.Username, input.Password)
+	if err != nil {
+		if !errors.Is(err, directory.ErrInvalidCredentials) && !errors.Is(err, directory.ErrUserNotFound) {
+			log.Error(err)
+		}
+		return nil, err
+	}
+
+	role, err := directory.RoleForGroups(entry.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	// LDAP 帳號不使用本地密碼,填入無法猜測的隨機密碼
+	hashedPassword, err := randomPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.Table{
+		ID:        util.GenerateUUID(),
+		Username:  input.Username,
+		Email:     entry.Email,
+		Password:  hashedPassword,
+		Role:      role,
+		CreatedAt: time.Now(),
+
+		TwoFactorMethod: TwoFactorEmail,
+
+		AuthProvider: AuthProviderLDAP,
+	}
+
+	err = s.Entity.Create(user)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	log.Info("LDAP user provisioned:", user.Username)
+	return user, nil
+}
+
+// syncLDAPUser 同步目錄中的信箱與群組角色,讓 email 驗證碼與權限維持一致
+func (s *service) syncLDAPUser(user *model.Table, entry *directory.Entry) error {
+	role, err := directory.RoleForGroups(entry.Groups)
+	if err != nil {
+		return err
+	}
+
+	columns := ldapColumns(user, entry, role)
+	if len(columns) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	columns["updated_at"] = now
+	user.UpdatedAt = &now
+
+	err = s.Entity.UpdateColumns(user.ID, columns)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// ldapColumns 依目錄資料計算需同步的欄位並寫回 user
+// 可改以本地密碼登入的 break-glass 帳號保留 local 來源,LDAP 無法連線時才能繼續登入
+func ldapColumns(user *model.Table, entry *directory.Entry, role string) map[string]interface{} {
+	keepLocal := localFallback(user)
+
+	columns := map[string]interface{}{}
+	if entry.Email != "" && entry.Email != user.Email {
+		columns["email"] = entry.Email
+		user.Email = entry.Email
+	}
+	if role != user.Role {
+		columns["role"] = role
+		user.Role = role
+	}
+	if user.AuthProvider != AuthProviderLDAP && !keepLocal {
+		columns["auth_provider"] = AuthProviderLDAP
+		user.AuthProvider = AuthProviderLDAP
+	}
+
+	return columns
+}
+
+// usesLDAP 個別帳號設定為 ldap,或全域設定為 ldap 時的本地帳號皆以 LDAP 驗證
+func usesLDAP(user *model.Table) bool {
+	switch user.AuthProvider {
+	case AuthProviderLDAP:
+		return true
+	case AuthProviderOIDC:
+		return false
+	default:
+		return authBackend() == AuthProviderLDAP
+	}
+}
+
+// localFallback 尚未轉為 LDAP 的本地帳號且角色在 LDAP_LOCAL_FALLBACK_ROLES 中(預設 admin)
+func localFallback(user *model.Table) bool {
+	if user.AuthProvider == AuthProviderLDAP {
+		return false
+	}
+
+	roles := os.Getenv("LDAP_LOCAL_FALLBACK_ROLES")
+	if roles == "" {
+		roles = "admin"
+	}
+
+	for _, role := range strings.Split(roles, ",") {
+		if strings.TrimSpace(role) == user.Role {
+			return true
+		}
+	}
+	return false
+}
+
+// authBackend 全域登入來源,由 AUTH_BACKEND 設定 (local/ldap),預設 local
+func authBackend() string {
+	if os.Getenv("AUTH_BACKEND") == AuthProviderLDAP {
+		return AuthProviderLDAP
+	}
+	return AuthProviderLocal
+}
@@ -0,0 +1,44 @@
+package user
+
+import (
+	"testing"
+
+	"esst_sendEmail/internal/pkg/directory"
+	model "esst_sendEmail/internal/v1/structure/users"
+)
+
+func TestLDAPColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     string
+		provider string
+		ldapRole string
+		want     string
+		fallback bool
+	}{
+		{"break-glass admin keeps local login", "admin", AuthProviderLocal, "admin", AuthProviderLocal, true},
+		{"demoted break-glass admin keeps local login", "admin", AuthProviderLocal, "user", AuthProviderLocal, false},
+		{"ordinary local account moves to ldap", "user", AuthProviderLocal, "user", AuthProviderLDAP, false},
+		{"ldap account stays ldap", "admin", AuthProviderLDAP, "admin", AuthProviderLDAP, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LDAP_LOCAL_FALLBACK_ROLES", "admin")
+			user := &model.Table{Role: tt.role, AuthProvider: tt.provider}
+
+			ldapColumns(user, &directory.Entry{}, tt.ldapRole)
+			if user.AuthProvider != tt.want {
+				t.Errorf("auth provider = %q, want %q", user.AuthProvider, tt.want)
+			}
+			if user.Role != tt.ldapRole {
+				t.Errorf("role = %q, want %q", user.Role, tt.ldapRole)
+			}
+
+			// 下次登入時 LDAP 無法連線,仍為 admin 的帳號可改以本地密碼登入
+			if got := localFallback(user); got != tt.fallback {
+				t.Errorf("localFallback() = %v, want %v", got, tt.fallback)
+			}
+		})
+	}
+}
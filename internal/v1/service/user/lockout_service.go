@@ -0,0 +1,212 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	attemptModel "esst_sendEmail/internal/v1/structure/login_attempts"
+	model "esst_sendEmail/internal/v1/structure/users"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// ErrTooManyAttempts 同一 IP 短時間內登入失敗次數過多
+var ErrTooManyAttempts = errors.New("登入嘗試次數過多，請稍後再試")
+
+// AccountLockedError 帳號因連續登入失敗被暫時鎖定
+type AccountLockedError struct {
+	User  *model.Base
+	Until time.Time
+	// 本次失敗才觸發鎖定(需通知使用者)
+	JustLocked bool
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("帳號已鎖定至 %s", e.Until.Format("2006-01-02 15:04:05"))
+}
+
+// Authenticate 驗證帳號密碼,失敗次數達上限時鎖定帳號
+// 密碼正確時不會清除失敗次數,需完成整個登入流程後呼叫 RecordLoginSuccess
+func (s *service) Authenticate(input *model.Login) (*model.Base, error) {
+	// 以 IP 限制嘗試次數,避免對多個帳號猜測密碼
+	if input.IP != "" {
+		failed, err := s.LoginAttemptEntity.CountFailedByIP(input.IP, time.Now().Add(-ipWindow()))
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+		if failed >= int64(ipMaxFailedAttempts()) {
+			return nil, ErrTooManyAttempts
+		}
+	}
+
+	user, err := s.Entity.GetByUsername(&model.Field{Username: &input.Username})
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		// 全域使用 LDAP 時,首次登入的目錄帳號自動建立
+		if authBackend() == AuthProviderLDAP {
+			user, err = s.provisionLDAPUser(input)
+			if err == nil {
+				return toBase(user), nil
+			}
+		}
+
+		s.recordAttempt(input.Username, nil, input.IP, false)
+		return nil, err
+	}
+
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		s.recordAttempt(user.Username, &user.ID, input.IP, false)
+		return nil, &AccountLockedError{User: toBase(user), Until: *user.LockedUntil}
+	}
+
+	// 驗證密碼(本地或 LDAP)
+	err = s.checkPassword(user, input.Password)
+	if err != nil {
+		if !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return nil, err
+		}
+		if lockErr := s.registerFailure(user, input.IP); lockErr != nil {
+			return nil, lockErr
+		}
+		return nil, err
+	}
+
+	return toBase(user), nil
+}
+
+// RecordLoginFailure 第二因子驗證失敗時累計失敗次數
+func (s *service) RecordLoginFailure(userID, ip string) error {
+	user, err := s.Entity.GetByID(&model.Field{ID: &userID})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return s.registerFailure(user, ip)
+}
+
+// RecordLoginSuccess 完成登入後清除失敗次數並記錄登入時間
+func (s *service) RecordLoginSuccess(userID, ip string) error {
+	user, err := s.Entity.GetByID(&model.Field{ID: &userID})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = s.Entity.UpdateColumns(user.ID, map[string]interface{}{
+		"failed_login_count": 0,
+		"locked_until":       nil,
+		"last_login_at":      time.Now(),
+	})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	s.recordAttempt(user.Username, &user.ID, ip, true)
+	return nil
+}
+
+// Unlock 管理員解除帳號鎖定
+func (s *service) Unlock(input *model.Field) error {
+	return s.Entity.UpdateColumns(*input.ID, map[string]interface{}{
+		"failed_login_count": 0,
+		"locked_until":       nil,
+	})
+}
+
+// registerFailure 累計失敗次數,達上限時鎖定帳號並回傳 AccountLockedError
+// 次數在資料庫中原子累加,並行的失敗請求不會互相覆蓋
+func (s *service) registerFailure(user *model.Table, ip string) error {
+	s.recordAttempt(user.Username, &user.ID, ip, false)
+
+	max := maxFailedAttempts()
+	now := time.Now()
+	result, err := s.Entity.IncrementFailedLogin(user.ID, max, now, now.Add(lockoutDuration()))
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if result.LockedUntil != nil && now.Before(*result.LockedUntil) {
+		// 剛好達上限的這次請求才觸發鎖定通知,其餘並行請求只回傳已鎖定
+		justLocked := result.FailedLoginCount == max
+		if justLocked {
+			log.Info("Account locked:", user.Username)
+		}
+		return &AccountLockedError{User: toBase(user), Until: *result.LockedUntil, JustLocked: justLocked}
+	}
+
+	return nil
+}
+
+// recordAttempt 寫入登入紀錄,失敗不影響登入流程
+func (s *service) recordAttempt(username string, userID *string, ip string, success bool) {
+	err := s.LoginAttemptEntity.Create(&attemptModel.Table{
+		LoginAttemptID: util.GenerateUUID(),
+		Username:       username,
+		UserID:         userID,
+		IP:             ip,
+		Success:        success,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		log.Error("Failed to record login attempt:", err)
+	}
+}
+
+func toBase(user *model.Table) *model.Base {
+	return &model.Base{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+
+		TwoFactorMethod:    user.TwoFactorMethod,
+		MustChangePassword: user.MustChangePassword,
+		LockedUntil:        user.LockedUntil,
+		LastLoginAt:        user.LastLoginAt,
+		AuthProvider:       user.AuthProvider,
+		Team:               user.Team,
+	}
+}
+
+// maxFailedAttempts 連續失敗幾次後鎖定帳號,預設 5 次
+func maxFailedAttempts() int {
+	return envInt("LOGIN_MAX_FAILED_ATTEMPTS", 5)
+}
+
+// lockoutDuration 帳號鎖定時間,預設 15 分鐘
+func lockoutDuration() time.Duration {
+	return time.Duration(envInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute
+}
+
+// ipMaxFailedAttempts 同一 IP 在時間窗內允許的失敗次數,預設 20 次
+func ipMaxFailedAttempts() int {
+	return envInt("LOGIN_IP_MAX_FAILED_ATTEMPTS", 20)
+}
+
+// ipWindow 計算 IP 失敗次數的時間窗,預設 15 分鐘
+func ipWindow() time.Duration {
+	return time.Duration(envInt("LOGIN_IP_WINDOW_MINUTES", 15)) * time.Minute
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,43 @@
+package user
+
+import (
+	"errors"
+	"time"
+
+	"esst_sendEmail/internal/pkg/auth"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+)
+
+// LoginTicketPurpose 登入票證的用途
+const LoginTicketPurpose = "login"
+
+// ErrInvalidLoginTicket 登入票證無效或已過期
+var ErrInvalidLoginTicket = errors.New("登入票證無效或已過期，請重新登入")
+
+// CreateLoginTicket 密碼驗證通過後核發短期登入票證,供第二因子驗證時識別使用者
+func (s *service) CreateLoginTicket(userID string) (string, time.Time, error) {
+	ttl := loginTicketTTL()
+	ticket, err := auth.GenerateActionToken(userID, util.GenerateUUID(), LoginTicketPurpose, ttl)
+	if err != nil {
+		log.Error(err)
+		return "", time.Time{}, err
+	}
+
+	return ticket, time.Now().Add(ttl), nil
+}
+
+// ValidateLoginTicket 驗證登入票證並回傳使用者編號
+func (s *service) ValidateLoginTicket(ticket string) (string, error) {
+	claims, err := auth.ValidateActionToken(ticket, LoginTicketPurpose)
+	if err != nil {
+		return "", ErrInvalidLoginTicket
+	}
+
+	return claims.Subject, nil
+}
+
+// loginTicketTTL 登入票證有效時間,預設 5 分鐘(與 email 驗證碼相同)
+func loginTicketTTL() time.Duration {
+	return time.Duration(envInt("LOGIN_TICKET_TTL_MINUTES", 5)) * time.Minute
+}
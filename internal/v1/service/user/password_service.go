@@ -0,0 +1,185 @@
+package user
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"esst_sendEmail/internal/pkg/auth"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/password"
+	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/entity/password_reset"
+	resetModel "esst_sendEmail/internal/v1/structure/password_resets"
+	model "esst_sendEmail/internal/v1/structure/users"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// PasswordResetPurpose 重設密碼 token 的用途
+const PasswordResetPurpose = "password_reset"
+
+var (
+	ErrPasswordReused    = errors.New("新密碼不可與目前密碼相同")
+	ErrInvalidResetToken = errors.New("重設密碼連結無效或已過期")
+)
+
+// ChangePassword 使用者以目前密碼變更自己的密碼
+func (s *service) ChangePassword(userID string, input *model.PasswordChanged) error {
+	user, err := s.Entity.GetByID(&model.Field{ID: &userID})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.CurrentPassword))
+	if err != nil {
+		return err
+	}
+
+	if input.CurrentPassword == input.NewPassword {
+		return ErrPasswordReused
+	}
+
+	return s.setPassword(user, input.NewPassword)
+}
+
+// CreatePasswordReset 建立重設密碼 token,查無信箱時回傳 gorm.ErrRecordNotFound
+func (s *service) CreatePasswordReset(input *model.PasswordForgot) (*model.PasswordResetRequest, error) {
+	user, err := s.Entity.GetByEmail(&model.Field{Email: &input.Email})
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error(err)
+		}
+		return nil, err
+	}
+
+	// 單一登入帳號沒有本地密碼,視同查無信箱
+	if user.AuthProvider != "" && user.AuthProvider != AuthProviderLocal {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	// 同一時間只保留最新的一組重設連結
+	err = s.PasswordResetEntity.InvalidateByUserID(user.ID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	table := &resetModel.Table{
+		PasswordResetID: util.GenerateUUID(),
+		UserID:          user.ID,
+		ExpiresAt:       now.Add(passwordResetTTL()),
+		CreatedAt:       now,
+	}
+
+	err = s.PasswordResetEntity.Create(table)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	token, err := auth.GenerateActionToken(user.ID, table.PasswordResetID, PasswordResetPurpose, passwordResetTTL())
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return &model.PasswordResetRequest{
+		User: &model.Base{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Role:     user.Role,
+		},
+		Token:     token,
+		ExpiresAt: table.ExpiresAt,
+	}, nil
+}
+
+// ResetPassword 驗證重設連結並設定新密碼,連結僅能使用一次
+func (s *service) ResetPassword(input *model.PasswordReset) (*model.Base, error) {
+	claims, err := auth.ValidateActionToken(input.Token, PasswordResetPurpose)
+	if err != nil {
+		return nil, ErrInvalidResetToken
+	}
+
+	reset, err := s.PasswordResetEntity.GetByID(claims.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidResetToken
+		}
+		log.Error(err)
+		return nil, err
+	}
+
+	if reset.UserID != claims.Subject || reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return nil, ErrInvalidResetToken
+	}
+
+	err = s.PasswordResetEntity.MarkUsed(reset)
+	if err != nil {
+		if errors.Is(err, password_reset.ErrAlreadyUsed) {
+			return nil, ErrInvalidResetToken
+		}
+		log.Error(err)
+		return nil, err
+	}
+
+	user, err := s.Entity.GetByID(&model.Field{ID: &reset.UserID})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = s.setPassword(user, input.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Base{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+	}, nil
+}
+
+// setPassword 設定新密碼並清除強制變更旗標
+func (s *service) setPassword(user *model.Table, newPassword string) error {
+	err := password.Validate(newPassword, user.Username)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = s.Entity.UpdateColumns(user.ID, map[string]interface{}{
+		"password":             string(hashedPassword),
+		"must_change_password": false,
+		"password_changed_at":  now,
+		"updated_at":           now,
+	})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// passwordResetTTL 重設連結有效時間,預設 30 分鐘
+func passwordResetTTL() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_TTL_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 30
+	}
+	return time.Duration(minutes) * time.Minute
+}
@@ -1,6 +1,11 @@
 package user
 
 import (
+	"time"
+
+	"esst_sendEmail/internal/v1/entity/login_attempt"
+	"esst_sendEmail/internal/v1/entity/password_reset"
+	"esst_sendEmail/internal/v1/entity/recovery_code"
 	"esst_sendEmail/internal/v1/entity/user"
 	model "esst_sendEmail/internal/v1/structure/users"
 	"gorm.io/gorm"
@@ -9,25 +14,48 @@ import (
 type Service interface {
 	WithTrx(tx *gorm.DB) Service
 	Create(input *model.Created) (*model.Base, error)
-	Authenticate(username, password string) (*model.Base, error)
+	Authenticate(input *model.Login) (*model.Base, error)
+	RecordLoginFailure(userID, ip string) error
+	RecordLoginSuccess(userID, ip string) error
+	Unlock(input *model.Field) error
+	CreateLoginTicket(userID string) (string, time.Time, error)
+	ValidateLoginTicket(ticket string) (string, error)
+	ProvisionOIDCUser(input *model.ExternalIdentity) (*model.Base, error)
 	GetByID(input *model.Field) (*model.Base, error)
 	List(input *model.Fields) (int64, []*model.Base, error)
 	Update(input *model.Updated) error
 	Delete(input *model.Field) error
+	SetupTOTP(userID string) (*model.TOTPSetup, error)
+	ConfirmTOTP(userID string, input *model.TOTPConfirm) (*model.RecoveryCodes, error)
+	DisableTOTP(userID string, input *model.TOTPDisable) error
+	RegenerateRecoveryCodes(userID string, input *model.TOTPConfirm) (*model.RecoveryCodes, error)
+	VerifyTOTP(userID, code string) error
+	ChangePassword(userID string, input *model.PasswordChanged) error
+	CreatePasswordReset(input *model.PasswordForgot) (*model.PasswordResetRequest, error)
+	ResetPassword(input *model.PasswordReset) (*model.Base, error)
 }
 
 type service struct {
-	Entity user.Entity
+	Entity              user.Entity
+	RecoveryCodeEntity  recovery_code.Entity
+	PasswordResetEntity password_reset.Entity
+	LoginAttemptEntity  login_attempt.Entity
 }
 
 func New(db *gorm.DB) Service {
 	return &service{
-		Entity: user.New(db),
+		Entity:              user.New(db),
+		RecoveryCodeEntity:  recovery_code.New(db),
+		PasswordResetEntity: password_reset.New(db),
+		LoginAttemptEntity:  login_attempt.New(db),
 	}
 }
 
 func (s *service) WithTrx(tx *gorm.DB) Service {
 	return &service{
-		Entity: s.Entity.WithTrx(tx),
+		Entity:              s.Entity.WithTrx(tx),
+		RecoveryCodeEntity:  s.RecoveryCodeEntity.WithTrx(tx),
+		PasswordResetEntity: s.PasswordResetEntity.WithTrx(tx),
+		LoginAttemptEntity:  s.LoginAttemptEntity.WithTrx(tx),
 	}
 }
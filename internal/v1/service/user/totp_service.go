@@ -0,0 +1,294 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/encryption"
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/totp"
+	"esst_sendEmail/internal/pkg/util"
+	"esst_sendEmail/internal/v1/entity/recovery_code"
+	recoveryModel "esst_sendEmail/internal/v1/structure/recovery_codes"
+	model "esst_sendEmail/internal/v1/structure/users"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// TwoFactorEmail 以 email 驗證碼作為第二因子
+	TwoFactorEmail = "email"
+	// TwoFactorTOTP 以驗證器 App (TOTP) 作為第二因子
+	TwoFactorTOTP = "totp"
+
+	// 每次產生的復原碼數量
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrTOTPAlreadyEnabled   = errors.New("TOTP 已啟用")
+	ErrTOTPNotSetup         = errors.New("尚未產生 TOTP 金鑰,請先執行綁定")
+	ErrTOTPNotEnabled       = errors.New("尚未啟用 TOTP")
+	ErrInvalidTwoFactorCode = errors.New("驗證碼錯誤或已使用")
+)
+
+// SetupTOTP 產生新的 TOTP 金鑰,確認前不會生效
+func (s *service) SetupTOTP(userID string) (*model.TOTPSetup, error) {
+	user, err := s.Entity.GetByID(&model.Field{ID: &userID})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	if user.TwoFactorMethod == TwoFactorTOTP {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	encrypted, err := encryption.Encrypt(secret)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	err = s.Entity.UpdateColumns(user.ID, map[string]interface{}{
+		"totp_secret":    encrypted,
+		"totp_last_step": 0,
+		"updated_at":     time.Now(),
+	})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return &model.TOTPSetup{
+		Secret:     secret,
+		OTPAuthURL: totp.KeyURI(totpIssuer(), user.Username, secret),
+	}, nil
+}
+
+// ConfirmTOTP 以第一組驗證碼確認綁定,成功後切換為 TOTP 並產生復原碼
+func (s *service) ConfirmTOTP(userID string, input *model.TOTPConfirm) (*model.RecoveryCodes, error) {
+	user, err := s.Entity.GetByID(&model.Field{ID: &userID})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	if user.TwoFactorMethod == TwoFactorTOTP {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTOTPNotSetup
+	}
+
+	if err := s.validateTOTP(user, input.Code); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = s.Entity.UpdateColumns(user.ID, map[string]interface{}{
+		"two_factor_method": TwoFactorTOTP,
+		"totp_enabled_at":   now,
+		"updated_at":        now,
+	})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(user.ID)
+}
+
+// DisableTOTP 停用 TOTP,改回 email 驗證碼
+func (s *service) DisableTOTP(userID string, input *model.TOTPDisable) error {
+	user, err := s.Entity.GetByID(&model.Field{ID: &userID})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if user.TwoFactorMethod != TwoFactorTOTP {
+		return ErrTOTPNotEnabled
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password))
+	if err != nil {
+		return err
+	}
+
+	if err = s.VerifyTOTP(user.ID, input.Code); err != nil {
+		return err
+	}
+
+	err = s.Entity.UpdateColumns(user.ID, map[string]interface{}{
+		"two_factor_method": TwoFactorEmail,
+		"totp_secret":       nil,
+		"totp_enabled_at":   nil,
+		"totp_last_step":    0,
+		"updated_at":        time.Now(),
+	})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return s.RecoveryCodeEntity.DeleteByUserID(user.ID)
+}
+
+// RegenerateRecoveryCodes 重新產生復原碼,舊的復原碼全部失效
+func (s *service) RegenerateRecoveryCodes(userID string, input *model.TOTPConfirm) (*model.RecoveryCodes, error) {
+	user, err := s.Entity.GetByID(&model.Field{ID: &userID})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	if user.TwoFactorMethod != TwoFactorTOTP {
+		return nil, ErrTOTPNotEnabled
+	}
+
+	if err := s.validateTOTP(user, input.Code); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(user.ID)
+}
+
+// VerifyTOTP 驗證 TOTP 驗證碼,若不符合則嘗試以復原碼驗證(復原碼僅能使用一次)
+func (s *service) VerifyTOTP(userID, code string) error {
+	user, err := s.Entity.GetByID(&model.Field{ID: &userID})
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if user.TwoFactorMethod != TwoFactorTOTP {
+		return ErrTOTPNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.validateTOTP(user, code)
+	}
+
+	recoveryCode, err := s.RecoveryCodeEntity.GetUnused(user.ID, hashRecoveryCode(code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidTwoFactorCode
+		}
+		log.Error(err)
+		return err
+	}
+
+	err = s.RecoveryCodeEntity.MarkUsed(recoveryCode)
+	if err != nil {
+		if errors.Is(err, recovery_code.ErrAlreadyUsed) {
+			return ErrInvalidTwoFactorCode
+		}
+		log.Error(err)
+		return err
+	}
+
+	log.Info("Recovery code used by user:", user.ID)
+	return nil
+}
+
+// validateTOTP 驗證 TOTP 並記錄使用的時間區間,拒絕重複使用同一區間(或更早)的驗證碼
+// 以條件更新認領時間區間,同時送出相同驗證碼的請求只有一個會成功
+func (s *service) validateTOTP(user *model.Table, code string) error {
+	secret, err := encryption.Decrypt(user.TOTPSecret)
+	if err != nil {
+		log.Error("Failed to decrypt TOTP secret:", err)
+		return err
+	}
+
+	step, ok := totp.Verify(secret, code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	claimed, err := s.Entity.ClaimTOTPStep(user.ID, step)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if !claimed {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes 刪除舊復原碼並產生新的一組
+func (s *service) replaceRecoveryCodes(userID string) (*model.RecoveryCodes, error) {
+	err := s.RecoveryCodeEntity.DeleteByUserID(userID)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	output := &model.RecoveryCodes{Codes: make([]string, 0, recoveryCodeCount)}
+	tables := make([]*recoveryModel.Table, 0, recoveryCodeCount)
+	now := time.Now()
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			log.Error(err)
+			return nil, err
+		}
+
+		output.Codes = append(output.Codes, code)
+		tables = append(tables, &recoveryModel.Table{
+			RecoveryCodeID: util.GenerateUUID(),
+			UserID:         userID,
+			CodeHash:       hashRecoveryCode(code),
+			CreatedAt:      now,
+		})
+	}
+
+	err = s.RecoveryCodeEntity.CreateBatch(tables)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// generateRecoveryCode 產生格式為 xxxxx-xxxxx 的復原碼
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return raw[:5] + "-" + raw[5:], nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.TrimSpace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func totpIssuer() string {
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "ESST 專案報備系統"
+}
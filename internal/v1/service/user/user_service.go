@@ -2,10 +2,10 @@ package user
 
 import (
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/password"
 	"esst_sendEmail/internal/pkg/util"
 	model "esst_sendEmail/internal/v1/structure/users"
 
@@ -13,6 +13,12 @@ import (
 )
 
 func (s *service) Create(input *model.Created) (*model.Base, error) {
+	// 檢查密碼規則
+	err := password.Validate(input.Password, input.Username)
+	if err != nil {
+		return nil, err
+	}
+
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -33,6 +39,14 @@ func (s *service) Create(input *model.Created) (*model.Base, error) {
 		Password:  string(hashedPassword),
 		Role:      role,
 		CreatedAt: time.Now(),
+
+		TwoFactorMethod: "email",
+		// 管理員建立的帳號首次登入需變更密碼
+		MustChangePassword: true,
+		AuthProvider:       AuthProviderLocal,
+	}
+	if input.Team != "" {
+		table.Team = &input.Team
 	}
 
 	// 建立用戶
@@ -48,34 +62,10 @@ func (s *service) Create(input *model.Created) (*model.Base, error) {
 		Email:     table.Email, // 新增 email
 		Role:      table.Role,
 		CreatedAt: table.CreatedAt,
-	}
-
-	return output, nil
-}
-
-func (s *service) Authenticate(username, password string) (*model.Base, error) {
-	// 查詢用戶
-	field := &model.Field{Username: &username}
-	user, err := s.Entity.GetByUsername(field)
-	if err != nil {
-		return nil, err
-	}
-
-	// 驗證密碼
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
-	if err != nil {
-		fmt.Println("❌ Password compare failed:", err)
-		return nil, err
-	}
 
-	// 轉換為 Base 結構
-	output := &model.Base{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Role:      user.Role,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		TwoFactorMethod:    table.TwoFactorMethod,
+		MustChangePassword: table.MustChangePassword,
+		Team:               table.Team,
 	}
 
 	return output, nil
@@ -87,17 +77,7 @@ func (s *service) GetByID(input *model.Field) (*model.Base, error) {
 		return nil, err
 	}
 
-	// 轉換為 Base 結構
-	output := &model.Base{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Role:      user.Role,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}
-
-	return output, nil
+	return toBase(user), nil
 }
 
 func (s *service) List(input *model.Fields) (int64, []*model.Base, error) {
@@ -139,15 +119,27 @@ func (s *service) Update(input *model.Updated) error {
 		user.Email = input.Email
 	}
 	if input.Password != "" {
+		err = password.Validate(input.Password, user.Username)
+		if err != nil {
+			return err
+		}
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 		if err != nil {
 			return err
 		}
 		user.Password = string(hashedPassword)
+		// 管理員重設的密碼,使用者下次登入需自行變更
+		user.MustChangePassword = true
 	}
 	if input.Role != "" {
 		user.Role = input.Role
 	}
+	if input.AuthProvider != "" {
+		user.AuthProvider = input.AuthProvider
+	}
+	if input.Team != "" {
+		user.Team = &input.Team
+	}
 
 	now := time.Now()
 	user.UpdatedAt = &now
@@ -0,0 +1,121 @@
+package api_keys
+
+import (
+	model "esst_sendEmail/internal/v1/structure"
+	"time"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 金鑰編號
+	APIKeyID string `gorm:"primaryKey;uuid_generate_v4();column:ak_id;type:uuid;" json:"ak_id,omitempty"`
+	// 擁有者
+	UserID string `gorm:"column:user_id;type:uuid;" json:"user_id,omitempty"`
+	// 金鑰名稱
+	Name string `gorm:"column:name;type:TEXT;" json:"name,omitempty"`
+	// 公開前綴
+	Prefix string `gorm:"column:prefix;type:TEXT;unique;" json:"prefix,omitempty"`
+	// 密鑰雜湊
+	SecretHash string `gorm:"column:secret_hash;type:TEXT;" json:"-"`
+	// 授權範圍(以空白分隔)
+	Scopes string `gorm:"column:scopes;type:TEXT;" json:"scopes"`
+	// 到期時間
+	ExpiresAt *time.Time `gorm:"column:expires_at;type:TIMESTAMP;" json:"expires_at,omitempty"`
+	// 最後使用時間
+	LastUsedAt *time.Time `gorm:"column:last_used_at;type:TIMESTAMP;" json:"last_used_at,omitempty"`
+	// 最後使用 IP
+	LastUsedIP string `gorm:"column:last_used_ip;type:TEXT;" json:"last_used_ip,omitempty"`
+	// 撤銷時間
+	RevokedAt *time.Time `gorm:"column:revoked_at;type:TIMESTAMP;" json:"revoked_at,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 金鑰編號
+	APIKeyID string `json:"ak_id,omitempty"`
+	// 擁有者
+	UserID string `json:"user_id,omitempty"`
+	// 金鑰名稱
+	Name string `json:"name,omitempty"`
+	// 公開前綴
+	Prefix string `json:"prefix,omitempty"`
+	// 授權範圍
+	Scopes []string `json:"scopes"`
+	// 到期時間
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// 最後使用時間
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	// 最後使用 IP
+	LastUsedIP string `json:"last_used_ip,omitempty"`
+	// 撤銷時間
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Created 建立金鑰
+type Created struct {
+	// 擁有者(僅管理員可替其他使用者建立,預設為自己)
+	UserID string `json:"user_id,omitempty" binding:"omitempty,uuid4"`
+	// 金鑰名稱
+	Name string `json:"name" binding:"required" validate:"required"`
+	// 授權範圍,例如 projects:read、stocks:write
+	Scopes []string `json:"scopes" binding:"required,min=1" validate:"required"`
+	// 有效天數(0 表示不過期)
+	ExpiresInDays int `json:"expires_in_days,omitempty" binding:"omitempty,gte=0"`
+}
+
+// Issued 建立成功時回傳,Key 僅會顯示這一次
+type Issued struct {
+	Base
+	// 完整金鑰
+	Key string `json:"key"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 金鑰編號
+	APIKeyID string `json:"ak_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 擁有者
+	UserID *string `json:"user_id,omitempty" form:"user_id" binding:"omitempty,uuid4"`
+	// 是否包含已撤銷的金鑰
+	IncludeRevoked bool `json:"include_revoked,omitempty" form:"include_revoked"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	APIKeys []*Base `json:"api_keys"`
+	model.OutPage
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "api_keys"
+}
+
+// Principal 以 API 金鑰驗證成功後的身分
+type Principal struct {
+	APIKeyID string
+	UserID   string
+	Username string
+	Role     string
+	Scopes   []string
+}
+
+// HasScope 是否具備指定的授權範圍
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
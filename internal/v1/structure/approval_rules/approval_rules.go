@@ -0,0 +1,109 @@
+package approval_rules
+
+import (
+	model "esst_sendEmail/internal/v1/structure"
+	"time"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 規則編號
+	RuleID string `gorm:"primaryKey;uuid_generate_v4();column:ar_id;type:uuid;" json:"ar_id,omitempty"`
+	// 規則名稱
+	Name string `gorm:"column:name;type:TEXT;" json:"name,omitempty"`
+	// 審核關卡(由 1 開始)
+	Level int `gorm:"column:level;type:INTEGER;" json:"level"`
+	// 設備總數量門檻(空值表示不限)
+	MinQuantity *int64 `gorm:"column:min_quantity;type:INTEGER;" json:"min_quantity,omitempty"`
+	// 建立者所屬團隊(空值表示不限)
+	Team *string `gorm:"column:team;type:TEXT;" json:"team,omitempty"`
+	// 審核者角色
+	ApproverRole string `gorm:"column:approver_role;type:TEXT;" json:"approver_role,omitempty"`
+	// 是否啟用
+	Enabled bool `gorm:"column:enabled;type:BOOLEAN;default:true;" json:"enabled"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+	// 更新時間
+	UpdatedAt *time.Time `gorm:"column:updated_at;type:TIMESTAMP;" json:"updated_at,omitempty"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 規則編號
+	RuleID string `json:"ar_id,omitempty"`
+	// 規則名稱
+	Name string `json:"name,omitempty"`
+	// 審核關卡
+	Level int `json:"level"`
+	// 設備總數量門檻
+	MinQuantity *int64 `json:"min_quantity,omitempty"`
+	// 建立者所屬團隊
+	Team *string `json:"team,omitempty"`
+	// 審核者角色
+	ApproverRole string `json:"approver_role,omitempty"`
+	// 是否啟用
+	Enabled bool `json:"enabled"`
+	// 建立時間
+	CreatedAt time.Time `json:"created_at"`
+	// 更新時間
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Created 新增審核規則
+type Created struct {
+	// 規則名稱
+	Name string `json:"name" binding:"required" validate:"required"`
+	// 審核關卡
+	Level int `json:"level" binding:"required,gte=1,lte=10" validate:"required"`
+	// 設備總數量門檻
+	MinQuantity *int64 `json:"min_quantity,omitempty" binding:"omitempty,gte=0"`
+	// 建立者所屬團隊
+	Team *string `json:"team,omitempty"`
+	// 審核者角色
+	ApproverRole string `json:"approver_role" binding:"required" validate:"required"`
+	// 是否啟用(預設啟用)
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 規則編號
+	RuleID string `json:"ar_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 是否啟用
+	Enabled *bool `json:"enabled,omitempty" form:"enabled"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Rules []*Base `json:"rules"`
+	model.OutPage
+}
+
+// Updated 更新審核規則
+type Updated struct {
+	// 規則編號
+	RuleID string `json:"ar_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 規則名稱
+	Name *string `json:"name,omitempty"`
+	// 審核關卡
+	Level *int `json:"level,omitempty" binding:"omitempty,gte=1,lte=10"`
+	// 設備總數量門檻
+	MinQuantity *int64 `json:"min_quantity,omitempty" binding:"omitempty,gte=0"`
+	// 建立者所屬團隊
+	Team *string `json:"team,omitempty"`
+	// 審核者角色
+	ApproverRole *string `json:"approver_role,omitempty"`
+	// 是否啟用
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "approval_rules"
+}
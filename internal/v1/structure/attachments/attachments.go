@@ -0,0 +1,110 @@
+package attachments
+
+import (
+	model "esst_sendEmail/internal/v1/structure"
+	"mime/multipart"
+	"time"
+)
+
+// 附件對象類型
+const (
+	TargetProject = "project"
+	TargetStock   = "stock"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 附件編號
+	AttachmentID string `gorm:"primaryKey;uuid_generate_v4();column:a_id;type:uuid;" json:"a_id,omitempty"`
+	// 附件對象類型
+	TargetType string `gorm:"column:target_type;type:TEXT;" json:"target_type,omitempty"`
+	// 附件對象編號
+	TargetID string `gorm:"column:target_id;type:uuid;" json:"target_id,omitempty"`
+	// 原始檔名
+	Filename string `gorm:"column:filename;type:TEXT;" json:"filename,omitempty"`
+	// 檔案大小(bytes)
+	Size int64 `gorm:"column:size;type:BIGINT;" json:"size"`
+	// 檔案類型
+	ContentType string `gorm:"column:content_type;type:TEXT;" json:"content_type,omitempty"`
+	// SHA-256 檢查碼
+	Checksum string `gorm:"column:checksum;type:TEXT;" json:"checksum,omitempty"`
+	// 儲存服務中的物件鍵值
+	StorageKey string `gorm:"column:storage_key;type:TEXT;" json:"-"`
+	// 上傳者
+	UploadedBy *string `gorm:"column:uploaded_by;type:uuid;" json:"uploaded_by,omitempty"`
+	// 上傳時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 附件編號
+	AttachmentID string `json:"a_id,omitempty"`
+	// 附件對象類型
+	TargetType string `json:"target_type,omitempty"`
+	// 附件對象編號
+	TargetID string `json:"target_id,omitempty"`
+	// 原始檔名
+	Filename string `json:"filename,omitempty"`
+	// 檔案大小(bytes)
+	Size int64 `json:"size"`
+	// 檔案類型
+	ContentType string `json:"content_type,omitempty"`
+	// SHA-256 檢查碼
+	Checksum string `json:"checksum,omitempty"`
+	// 上傳者
+	UploadedBy *string `json:"uploaded_by,omitempty"`
+	// 上傳時間
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Created 上傳附件
+type Created struct {
+	// 附件對象類型(由路由決定)
+	TargetType string `form:"-"`
+	// 附件對象編號(由路由決定)
+	TargetID string `form:"-"`
+	// 檔案
+	File *multipart.FileHeader `form:"file" binding:"required" swaggerignore:"true"`
+	// 上傳者(由 JWT 取得)
+	UploadedBy string `form:"-"`
+}
+
+// Download 限時下載網址
+type Download struct {
+	// 下載網址
+	URL string `json:"url"`
+	// 網址到期時間
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 附件編號
+	AttachmentID string `json:"a_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 附件對象類型
+	TargetType string `json:"-"`
+	// 附件對象編號
+	TargetID string `json:"-"`
+	// 操作者(刪除時由 JWT 取得)
+	UserID string `json:"-"`
+	// 操作者角色(刪除時由 JWT 取得)
+	Role string `json:"-"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Attachments []*Base `json:"attachments"`
+	model.OutPage
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "attachments"
+}
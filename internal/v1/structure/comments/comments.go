@@ -0,0 +1,116 @@
+package comments
+
+import (
+	model "esst_sendEmail/internal/v1/structure"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 留言對象類型
+const (
+	TargetProject = "project"
+	TargetStock   = "stock"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 留言編號
+	CommentID string `gorm:"primaryKey;uuid_generate_v4();column:c_id;type:uuid;" json:"c_id,omitempty"`
+	// 留言對象類型
+	TargetType string `gorm:"column:target_type;type:TEXT;" json:"target_type,omitempty"`
+	// 留言對象編號
+	TargetID string `gorm:"column:target_id;type:uuid;" json:"target_id,omitempty"`
+	// 作者(空值表示由備註轉入)
+	AuthorID *string `gorm:"column:author_id;type:uuid;" json:"author_id,omitempty"`
+	// 留言內容
+	Body string `gorm:"column:body;type:TEXT;" json:"body,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+	// 最後編輯時間
+	UpdatedAt *time.Time `gorm:"column:updated_at;type:TIMESTAMP;" json:"updated_at,omitempty"`
+	// 刪除時間
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:TIMESTAMP;index" json:"-"`
+}
+
+// Row 查詢列表時的資料列(留言與作者帳號)
+type Row struct {
+	Table
+	// 作者帳號
+	AuthorName *string `gorm:"column:author_name" json:"author_name,omitempty"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 留言編號
+	CommentID string `json:"c_id,omitempty"`
+	// 留言對象類型
+	TargetType string `json:"target_type,omitempty"`
+	// 留言對象編號
+	TargetID string `json:"target_id,omitempty"`
+	// 作者
+	AuthorID *string `json:"author_id,omitempty"`
+	// 作者帳號
+	AuthorName *string `json:"author_name,omitempty"`
+	// 留言內容
+	Body string `json:"body,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `json:"created_at"`
+	// 最後編輯時間
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Created 新增留言
+type Created struct {
+	// 留言對象類型(由路由決定)
+	TargetType string `json:"-"`
+	// 留言對象編號(由路由決定)
+	TargetID string `json:"-"`
+	// 留言內容,可用 @帳號 提及其他使用者
+	Body string `json:"body" binding:"required,max=5000" validate:"required"`
+	// 作者(由 JWT 取得)
+	AuthorID string `json:"-"`
+}
+
+// Updated 編輯留言
+type Updated struct {
+	// 留言編號
+	CommentID string `json:"c_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 留言對象類型(由路由決定)
+	TargetType string `json:"-"`
+	// 留言對象編號(由路由決定)
+	TargetID string `json:"-"`
+	// 留言內容
+	Body string `json:"body" binding:"required,max=5000" validate:"required"`
+	// 編輯者(由 JWT 取得)
+	AuthorID string `json:"-"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 留言編號
+	CommentID string `json:"c_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 留言對象類型
+	TargetType string `json:"-"`
+	// 留言對象編號
+	TargetID string `json:"-"`
+	// 操作者(刪除時由 JWT 取得)
+	AuthorID string `json:"-"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Comments []*Base `json:"comments"`
+	model.OutPage
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "comments"
+}
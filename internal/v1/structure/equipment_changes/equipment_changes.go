@@ -0,0 +1,108 @@
+package equipment_changes
+
+import (
+	"encoding/json"
+	model "esst_sendEmail/internal/v1/structure"
+	"time"
+)
+
+// 對象類型
+const (
+	// TargetProject 專案設備
+	TargetProject = "project"
+	// TargetStock 現貨設備
+	TargetStock = "stock"
+)
+
+// 異動類型
+const (
+	// ActionAdd 新增
+	ActionAdd = "add"
+	// ActionUpdate 修改
+	ActionUpdate = "update"
+	// ActionDelete 刪除
+	ActionDelete = "delete"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 異動紀錄編號
+	ChangeID string `gorm:"primaryKey;uuid_generate_v4();column:ec_id;type:uuid;" json:"ec_id,omitempty"`
+	// 同一次取代的批次編號
+	BatchID string `gorm:"column:batch_id;type:uuid;" json:"batch_id,omitempty"`
+	// 對象類型
+	TargetType string `gorm:"column:target_type;type:TEXT;" json:"target_type,omitempty"`
+	// 專案或現貨報備編號
+	TargetID string `gorm:"column:target_id;type:uuid;" json:"target_id,omitempty"`
+	// 專案設備或現貨設備編號
+	LineID string `gorm:"column:line_id;type:uuid;" json:"line_id,omitempty"`
+	// 異動類型
+	Action string `gorm:"column:action;type:TEXT;" json:"action,omitempty"`
+	// 料號
+	PartNumber string `gorm:"column:part_number;type:TEXT;" json:"part_number,omitempty"`
+	// 異動前的設備(JSON)
+	Before *string `gorm:"column:before_value;type:TEXT;" json:"before_value,omitempty"`
+	// 異動後的設備(JSON)
+	After *string `gorm:"column:after_value;type:TEXT;" json:"after_value,omitempty"`
+	// 異動者
+	ChangedBy *string `gorm:"column:changed_by;type:uuid;" json:"changed_by,omitempty"`
+	// 異動時間
+	ChangedAt time.Time `gorm:"column:changed_at;type:TIMESTAMP;" json:"changed_at"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 異動紀錄編號
+	ChangeID string `json:"ec_id,omitempty"`
+	// 同一次取代的批次編號
+	BatchID string `json:"batch_id,omitempty"`
+	// 對象類型
+	TargetType string `json:"target_type,omitempty"`
+	// 專案或現貨報備編號
+	TargetID string `json:"target_id,omitempty"`
+	// 專案設備或現貨設備編號
+	LineID string `json:"line_id,omitempty"`
+	// 異動類型
+	Action string `json:"action,omitempty"`
+	// 料號
+	PartNumber string `json:"part_number,omitempty"`
+	// 異動前的設備
+	Before json.RawMessage `json:"before,omitempty"`
+	// 異動後的設備
+	After json.RawMessage `json:"after,omitempty"`
+	// 異動者
+	ChangedBy *string `json:"changed_by,omitempty"`
+	// 異動時間
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 對象類型
+	TargetType string `json:"target_type,omitempty" swaggerignore:"true"`
+	// 專案或現貨報備編號
+	TargetID string `json:"target_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 專案設備或現貨設備編號
+	LineID *string `json:"line_id,omitempty" form:"line_id" binding:"omitempty,uuid4"`
+	// 批次編號
+	BatchID *string `json:"batch_id,omitempty" form:"batch_id" binding:"omitempty,uuid4"`
+	// 異動類型
+	Action *string `json:"action,omitempty" form:"action" binding:"omitempty,oneof=add update delete"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Changes []*Base `json:"changes"`
+	model.OutPage
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "equipment_changes"
+}
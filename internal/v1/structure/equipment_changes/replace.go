@@ -0,0 +1,137 @@
+package equipment_changes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"esst_sendEmail/internal/pkg/log"
+	"esst_sendEmail/internal/pkg/util"
+	model "esst_sendEmail/internal/v1/structure"
+)
+
+var (
+	ErrUnknownLine   = errors.New("設備不屬於此清單或已刪除")
+	ErrDuplicateLine = errors.New("同一設備在清單中重複出現")
+)
+
+// Line 專案設備或現貨設備
+type Line interface {
+	LineID() string
+	LineVersion() int64
+}
+
+// Requested 取代清單中指定的既有設備
+type Requested struct {
+	// 設備編號
+	LineID string
+	// 預期的版本號(可省略)
+	Version *int64
+}
+
+// Entry 單筆設備異動,新增時 Before 為 nil,刪除時 After 為 nil
+type Entry struct {
+	Action     string
+	LineID     string
+	PartNumber string
+	Before     interface{}
+	After      interface{}
+}
+
+// Batch 同一次取代的異動
+type Batch struct {
+	BatchID    string
+	TargetType string
+	TargetID   string
+	ChangedBy  string
+	ChangedAt  time.Time
+	Entries    []Entry
+}
+
+// Checksum 設備清單的 ETag,由各設備的編號與版本號計算,任一設備新增、修改或刪除時都會改變
+func Checksum[T Line](lines []T) string {
+	keys := make([]string, 0, len(lines))
+	for _, line := range lines {
+		keys = append(keys, line.LineID()+":"+strconv.FormatInt(line.LineVersion(), 10))
+	}
+	sort.Strings(keys)
+
+	sum := sha256.Sum256([]byte(strings.Join(keys, ",")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Listed 檢查取代清單:清單的 ETag 需與目前相符,指定的設備需屬於目前清單且不可重複,帶入版本號時需相符
+// 回傳清單中保留的既有設備,目前清單中未被保留的即為要刪除的設備
+func Listed[T Line](current []T, checksum string, requested []Requested) (map[string]T, error) {
+	if Checksum(current) != checksum {
+		return nil, model.ErrVersionConflict
+	}
+
+	existing := make(map[string]T, len(current))
+	for _, line := range current {
+		existing[line.LineID()] = line
+	}
+
+	listed := make(map[string]T, len(requested))
+	for _, item := range requested {
+		line, ok := existing[item.LineID]
+		if !ok {
+			return nil, ErrUnknownLine
+		}
+		if _, ok := listed[item.LineID]; ok {
+			return nil, ErrDuplicateLine
+		}
+		if item.Version != nil && *item.Version != line.LineVersion() {
+			return nil, model.ErrVersionConflict
+		}
+		listed[item.LineID] = line
+	}
+
+	return listed, nil
+}
+
+// Records 將取代清單的異動轉為異動紀錄,異動前後的設備以 JSON 保存
+func Records(batch *Batch) []*Table {
+	var changedBy *string
+	if batch.ChangedBy != "" {
+		changedBy = util.PointerString(batch.ChangedBy)
+	}
+
+	output := make([]*Table, 0, len(batch.Entries))
+	for _, entry := range batch.Entries {
+		output = append(output, &Table{
+			ChangeID:   util.GenerateUUID(),
+			BatchID:    batch.BatchID,
+			TargetType: batch.TargetType,
+			TargetID:   batch.TargetID,
+			LineID:     entry.LineID,
+			Action:     entry.Action,
+			PartNumber: entry.PartNumber,
+			Before:     snapshot(entry.Before),
+			After:      snapshot(entry.After),
+			ChangedBy:  changedBy,
+			ChangedAt:  batch.ChangedAt,
+		})
+	}
+
+	return output
+}
+
+// snapshot 以 JSON 文字保存設備內容,nil 表示沒有內容(新增前或刪除後)
+func snapshot(value interface{}) *string {
+	if value == nil {
+		return nil
+	}
+
+	marshal, err := json.Marshal(value)
+	if err != nil {
+		log.Error(err)
+		return nil
+	}
+	return util.PointerString(string(marshal))
+}
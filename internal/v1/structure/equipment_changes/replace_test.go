@@ -0,0 +1,184 @@
+package equipment_changes
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	model "esst_sendEmail/internal/v1/structure"
+)
+
+type line struct {
+	ID         string `json:"id"`
+	Version    int64  `json:"version"`
+	PartNumber string `json:"part_number"`
+}
+
+func (l *line) LineID() string     { return l.ID }
+func (l *line) LineVersion() int64 { return l.Version }
+
+func version(v int64) *int64 { return &v }
+
+func TestChecksum(t *testing.T) {
+	current := []*line{{ID: "a", Version: 1}, {ID: "b", Version: 2}}
+	checksum := Checksum(current)
+
+	tests := []struct {
+		name  string
+		lines []*line
+		same  bool
+	}{
+		{"same lines in another order", []*line{{ID: "b", Version: 2}, {ID: "a", Version: 1}}, true},
+		{"line updated", []*line{{ID: "a", Version: 2}, {ID: "b", Version: 2}}, false},
+		{"line added", []*line{{ID: "a", Version: 1}, {ID: "b", Version: 2}, {ID: "c", Version: 1}}, false},
+		{"line deleted", []*line{{ID: "a", Version: 1}}, false},
+		{"empty list", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Checksum(tt.lines) == checksum; got != tt.same {
+				t.Errorf("Checksum() equal = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestListed(t *testing.T) {
+	current := []*line{{ID: "a", Version: 1}, {ID: "b", Version: 3}}
+	checksum := Checksum(current)
+
+	tests := []struct {
+		name      string
+		checksum  string
+		requested []Requested
+		want      []string
+		err       error
+	}{
+		{
+			name:      "keep both lines",
+			checksum:  checksum,
+			requested: []Requested{{LineID: "a"}, {LineID: "b", Version: version(3)}},
+			want:      []string{"a", "b"},
+		},
+		{
+			name:     "empty list deletes everything",
+			checksum: checksum,
+			want:     []string{},
+		},
+		{
+			name:      "line added by someone else after the GET",
+			checksum:  Checksum([]*line{{ID: "a", Version: 1}}),
+			requested: []Requested{{LineID: "a"}},
+			err:       model.ErrVersionConflict,
+		},
+		{
+			name:      "missing If-Match value",
+			checksum:  "",
+			requested: []Requested{{LineID: "a"}},
+			err:       model.ErrVersionConflict,
+		},
+		{
+			name:      "line version is stale",
+			checksum:  checksum,
+			requested: []Requested{{LineID: "b", Version: version(2)}},
+			err:       model.ErrVersionConflict,
+		},
+		{
+			name:      "line from another list",
+			checksum:  checksum,
+			requested: []Requested{{LineID: "c"}},
+			err:       ErrUnknownLine,
+		},
+		{
+			name:      "line listed twice",
+			checksum:  checksum,
+			requested: []Requested{{LineID: "a"}, {LineID: "a"}},
+			err:       ErrDuplicateLine,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed, err := Listed(current, tt.checksum, tt.requested)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Listed() error = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				return
+			}
+
+			if len(listed) != len(tt.want) {
+				t.Fatalf("Listed() = %d lines, want %d", len(listed), len(tt.want))
+			}
+			for _, id := range tt.want {
+				if _, ok := listed[id]; !ok {
+					t.Errorf("Listed() is missing line %s", id)
+				}
+			}
+		})
+	}
+}
+
+func TestRecords(t *testing.T) {
+	changedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	before := &line{ID: "b", Version: 1, PartNumber: "P-1"}
+	after := &line{ID: "b", Version: 2, PartNumber: "P-2"}
+
+	batch := &Batch{
+		BatchID:    "batch",
+		TargetType: TargetProject,
+		TargetID:   "project",
+		ChangedAt:  changedAt,
+		Entries: []Entry{
+			{Action: ActionAdd, LineID: "a", PartNumber: "P-0", After: &line{ID: "a", Version: 1, PartNumber: "P-0"}},
+			{Action: ActionUpdate, LineID: "b", PartNumber: "P-2", Before: before, After: after},
+			{Action: ActionDelete, LineID: "c", PartNumber: "P-3", Before: &line{ID: "c", Version: 4, PartNumber: "P-3"}},
+		},
+	}
+
+	records := Records(batch)
+	if len(records) != 3 {
+		t.Fatalf("Records() = %d records, want 3", len(records))
+	}
+
+	tests := []struct {
+		action    string
+		lineID    string
+		hasBefore bool
+		hasAfter  bool
+	}{
+		{ActionAdd, "a", false, true},
+		{ActionUpdate, "b", true, true},
+		{ActionDelete, "c", true, false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			record := records[i]
+			if record.Action != tt.action || record.LineID != tt.lineID {
+				t.Errorf("record = %s %s, want %s %s", record.Action, record.LineID, tt.action, tt.lineID)
+			}
+			if record.BatchID != "batch" || record.TargetType != TargetProject || record.TargetID != "project" || !record.ChangedAt.Equal(changedAt) {
+				t.Errorf("record batch fields = %+v", record)
+			}
+			if record.ChangedBy != nil {
+				t.Errorf("ChangedBy = %v, want nil for an empty changer", *record.ChangedBy)
+			}
+			if (record.Before != nil) != tt.hasBefore || (record.After != nil) != tt.hasAfter {
+				t.Errorf("Before/After present = %v/%v, want %v/%v", record.Before != nil, record.After != nil, tt.hasBefore, tt.hasAfter)
+			}
+		})
+	}
+
+	var got line
+	if err := json.Unmarshal([]byte(*records[1].Before), &got); err != nil || got != *before {
+		t.Errorf("Before snapshot = %+v (%v), want %+v", got, err, *before)
+	}
+
+	batch.ChangedBy = "user"
+	if record := Records(batch)[0]; record.ChangedBy == nil || *record.ChangedBy != "user" {
+		t.Errorf("ChangedBy = %v, want user", record.ChangedBy)
+	}
+}
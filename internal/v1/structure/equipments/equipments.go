@@ -1,9 +1,14 @@
 package equipments
 
 import (
+	"esst_sendEmail/internal/pkg/pricing"
 	model "esst_sendEmail/internal/v1/structure"
+	changes "esst_sendEmail/internal/v1/structure/equipment_changes"
+	"esst_sendEmail/internal/v1/structure/parts"
 	"esst_sendEmail/internal/v1/structure/projects"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Equipment struct is a row record of the companies table in the invoice database
@@ -17,12 +22,31 @@ type Table struct {
 	Project projects.Table `gorm:"foreignKey:ProjectID;references:ProjectID" json:"-"`
 	// 料號
 	PartNumber string `gorm:"column:part_number;type:TEXT;" json:"part_number,omitempty"`
+	// 料號目錄編號(空值表示自由輸入的料號)
+	PartID *string `gorm:"column:part_id;type:uuid;" json:"part_id,omitempty"`
 	// 數量
 	Quantity int64 `gorm:"column:quantity;type:integer;" json:"quantity,omitempty"`
 	// 說明
 	Description string `gorm:"column:description;type:TEXT;" json:"description,omitempty"`
+	// 單價(空值表示尚未報價)
+	UnitPrice *float64 `gorm:"column:unit_price;type:NUMERIC(14,2);" json:"unit_price,omitempty"`
+	// 折扣百分比(0~100)
+	Discount *float64 `gorm:"column:discount;type:NUMERIC(5,2);" json:"discount,omitempty"`
+	// 幣別(空值使用預設幣別)
+	Currency *string `gorm:"column:currency;type:CHAR(3);" json:"currency,omitempty"`
+	// 稅率百分比(空值使用預設稅率)
+	TaxRate *float64 `gorm:"column:tax_rate;type:NUMERIC(5,2);" json:"tax_rate,omitempty"`
 	// 創建時間
 	CreatedTime time.Time `gorm:"column:created_time;type:TIMESTAMP;" json:"created_time"`
+
+	// 版本號(每次更新加 1,用於 ETag / If-Match)
+	Version int64 `gorm:"column:version;type:bigint;default:1" json:"version"`
+
+	// 軟刪除
+	// 刪除時間(NULL 表示未刪除,刪除後保留於資源回收筒)
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:TIMESTAMP;index" json:"deleted_at"`
+	// 刪除者
+	DeletedBy *string `gorm:"column:deleted_by;type:uuid;" json:"deleted_by,omitempty"`
 }
 
 // Base struct is corresponding to table structure file
@@ -33,13 +57,32 @@ type Base struct {
 	ProjectID string `json:"p_id"`
 	// 料號
 	PartNumber string `json:"part_number,omitempty"`
+	// 料號目錄編號
+	PartID *string `json:"part_id,omitempty"`
 	// 數量
 	Quantity int64 `json:"quantity,omitempty"`
 	// 說明
 	Description string `json:"description,omitempty"`
+	// 單價
+	UnitPrice *float64 `json:"unit_price,omitempty"`
+	// 折扣百分比
+	Discount *float64 `json:"discount,omitempty"`
+	// 幣別
+	Currency *string `json:"currency,omitempty"`
+	// 稅率百分比
+	TaxRate *float64 `json:"tax_rate,omitempty"`
+	// 金額(依單價、折扣與稅率計算,尚未報價時省略)
+	Amount *pricing.Amount `json:"amount,omitempty"`
 
 	// 創建時間
 	CreatedTime time.Time `json:"created_time"`
+
+	// 版本號
+	Version int64 `json:"version"`
+	// 刪除時間
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// 刪除者
+	DeletedBy *string `json:"deleted_by,omitempty"`
 }
 
 // Single return structure file
@@ -50,12 +93,27 @@ type Single struct {
 	ProjectID string `json:"p_id"`
 	// 料號
 	PartNumber string `json:"part_number,omitempty"`
+	// 料號目錄編號
+	PartID *string `json:"part_id,omitempty"`
 	// 數量
 	Quantity int64 `json:"quantity,omitempty"`
 	// 說明
 	Description string `json:"description,omitempty"`
+	// 單價
+	UnitPrice *float64 `json:"unit_price,omitempty"`
+	// 折扣百分比
+	Discount *float64 `json:"discount,omitempty"`
+	// 幣別
+	Currency *string `json:"currency,omitempty"`
+	// 稅率百分比
+	TaxRate *float64 `json:"tax_rate,omitempty"`
+	// 金額(依單價、折扣與稅率計算,尚未報價時省略)
+	Amount *pricing.Amount `json:"amount,omitempty"`
 	// 創建時間
 	CreatedTime time.Time `json:"created_time"`
+
+	// 版本號(同 ETag)
+	Version int64 `json:"version"`
 }
 
 // Created struct is used to create
@@ -64,10 +122,20 @@ type Created struct {
 	ProjectID string `json:"p_id" binding:"required,uuid4" form:"p_id"`
 	// 料號
 	PartNumber string `json:"part_number" binding:"required" validate:"required"`
+	// 料號目錄編號(可省略,省略時依料號比對目錄)
+	PartID *string `json:"part_id,omitempty" binding:"omitempty,uuid4"`
 	// 數量
 	Quantity int64 `json:"quantity" binding:"required,gt=0" validate:"required,gt=0"`
 	// 說明
 	Description string `json:"description,omitempty"`
+	// 單價(可省略,省略表示尚未報價)
+	UnitPrice *float64 `json:"unit_price,omitempty" binding:"omitempty,gte=0"`
+	// 折扣百分比(0~100)
+	Discount *float64 `json:"discount,omitempty" binding:"omitempty,gte=0,lte=100"`
+	// 幣別(ISO 4217,省略時使用預設幣別)
+	Currency *string `json:"currency,omitempty" binding:"omitempty,iso4217"`
+	// 稅率百分比(0~100,省略時使用預設稅率)
+	TaxRate *float64 `json:"tax_rate,omitempty" binding:"omitempty,gte=0,lte=100"`
 }
 
 // BatchCreated struct is used to create multiple equipments
@@ -82,10 +150,20 @@ type BatchCreated struct {
 type BatchEquipment struct {
 	// 料號
 	PartNumber string `json:"part_number" binding:"required" validate:"required"`
+	// 料號目錄編號(可省略,省略時依料號比對目錄)
+	PartID *string `json:"part_id,omitempty" binding:"omitempty,uuid4"`
 	// 數量
 	Quantity int64 `json:"quantity" binding:"required,gt=0" validate:"required,gt=0"`
 	// 說明
 	Description string `json:"description,omitempty"`
+	// 單價(可省略,省略表示尚未報價)
+	UnitPrice *float64 `json:"unit_price,omitempty" binding:"omitempty,gte=0"`
+	// 折扣百分比(0~100)
+	Discount *float64 `json:"discount,omitempty" binding:"omitempty,gte=0,lte=100"`
+	// 幣別(ISO 4217,省略時使用預設幣別)
+	Currency *string `json:"currency,omitempty" binding:"omitempty,iso4217"`
+	// 稅率百分比(0~100,省略時使用預設稅率)
+	TaxRate *float64 `json:"tax_rate,omitempty" binding:"omitempty,gte=0,lte=100"`
 }
 
 // Field is structure file for search
@@ -117,12 +195,31 @@ type List struct {
 		ProjectID string `json:"p_id"`
 		// 料號
 		PartNumber string `json:"part_number,omitempty"`
+		// 料號目錄編號
+		PartID *string `json:"part_id,omitempty"`
 		// 數量
 		Quantity int64 `json:"quantity,omitempty"`
 		// 說明
 		Description string `json:"description,omitempty"`
+		// 單價
+		UnitPrice *float64 `json:"unit_price,omitempty"`
+		// 折扣百分比
+		Discount *float64 `json:"discount,omitempty"`
+		// 幣別
+		Currency *string `json:"currency,omitempty"`
+		// 稅率百分比
+		TaxRate *float64 `json:"tax_rate,omitempty"`
+		// 金額(依單價、折扣與稅率計算,尚未報價時省略)
+		Amount *pricing.Amount `json:"amount,omitempty"`
 		// 創建時間
 		CreatedTime time.Time `json:"created_time"`
+
+		// 版本號
+		Version int64 `json:"version"`
+		// 刪除時間
+		DeletedAt *time.Time `json:"deleted_at,omitempty"`
+		// 刪除者
+		DeletedBy *string `json:"deleted_by,omitempty"`
 	} `json:"equipments"`
 	model.OutPage
 }
@@ -135,13 +232,161 @@ type Updated struct {
 	ProjectID string `json:"p_id" binding:"required,uuid4" validate:"required,uuid4"`
 	// 料號
 	PartNumber string `json:"part_number" binding:"required" validate:"required"`
+	// 料號目錄編號(可省略,省略時依料號比對目錄)
+	PartID *string `json:"part_id,omitempty" binding:"omitempty,uuid4"`
 	// 數量
 	Quantity int64 `json:"quantity" binding:"required,gt=0" validate:"required,gt=0"`
 	// 說明
 	Description string `json:"description,omitempty"`
+	// 單價(可省略,省略表示尚未報價)
+	UnitPrice *float64 `json:"unit_price,omitempty" binding:"omitempty,gte=0"`
+	// 折扣百分比(0~100)
+	Discount *float64 `json:"discount,omitempty" binding:"omitempty,gte=0,lte=100"`
+	// 幣別(ISO 4217,省略時使用預設幣別)
+	Currency *string `json:"currency,omitempty" binding:"omitempty,iso4217"`
+	// 稅率百分比(0~100,省略時使用預設稅率)
+	TaxRate *float64 `json:"tax_rate,omitempty" binding:"omitempty,gte=0,lte=100"`
+
+	// 預期的版本號(由 If-Match 取得)
+	Version int64 `json:"-"`
+	// 刪除者(由 JWT 取得)
+	DeletedBy string `json:"-"`
 }
 
 // TableName sets the insert table name for this struct type
 func (a *Table) TableName() string {
 	return "equipments"
 }
+
+// ProjectCreated 同時建立專案與設備(單一交易)
+type ProjectCreated struct {
+	projects.Created
+	// 設備列表
+	Equipments []BatchEquipment `json:"equipments" binding:"required,min=1,dive" validate:"required,min=1,dive"`
+}
+
+// Replaced 以完整清單取代專案設備(依 eq_id 比對新增、修改、刪除)
+type Replaced struct {
+	// 專案編號(由路徑取得)
+	ProjectID string `json:"-"`
+	// 設備列表(未列出的現有設備會被刪除,空列表表示刪除全部)
+	Equipments []ReplacedEquipment `json:"equipments" binding:"required,dive" validate:"required,dive"`
+	// 是否發送設備清單異動 LINE 通知
+	Notify bool `json:"notify,omitempty"`
+	// 設備清單的 ETag(由 If-Match 取得),與目前清單不符時回傳 412
+	Checksum string `json:"-"`
+
+	// 異動者(由 JWT 取得)
+	ChangedBy string `json:"-"`
+	// 異動者名稱(由 JWT 取得)
+	ChangedByName string `json:"-"`
+}
+
+// ReplacedEquipment 取代清單中的設備
+type ReplacedEquipment struct {
+	// 設備編號(省略表示新增)
+	EquipmentID *string `json:"eq_id,omitempty" binding:"omitempty,uuid4"`
+	// 預期的版本號(可省略,提供時與目前版本不符回傳 412)
+	Version *int64 `json:"version,omitempty" binding:"omitempty,gte=1"`
+	BatchEquipment
+}
+
+// Diff 取代清單的異動結果
+type Diff struct {
+	// 異動批次編號(沒有異動時省略)
+	BatchID string `json:"batch_id,omitempty"`
+	// 新增的設備
+	Added []*Base `json:"added"`
+	// 修改的設備
+	Updated []*Change `json:"updated"`
+	// 刪除的設備
+	Deleted []*Base `json:"deleted"`
+	// 未異動的設備數
+	Unchanged int `json:"unchanged"`
+	// 取代後設備清單的 ETag
+	Checksum string `json:"-"`
+}
+
+// Change 單筆設備異動前後的內容
+type Change struct {
+	// 異動前
+	Before *Base `json:"before"`
+	// 異動後
+	After *Base `json:"after"`
+}
+
+// Requested 清單中指定的既有設備
+func (r *Replaced) Requested() []changes.Requested {
+	output := make([]changes.Requested, 0, len(r.Equipments))
+	for _, eq := range r.Equipments {
+		if eq.EquipmentID != nil {
+			output = append(output, changes.Requested{LineID: *eq.EquipmentID, Version: eq.Version})
+		}
+	}
+	return output
+}
+
+// Entries 轉為異動紀錄
+func (d *Diff) Entries() []changes.Entry {
+	output := make([]changes.Entry, 0, len(d.Added)+len(d.Updated)+len(d.Deleted))
+	for _, eq := range d.Added {
+		output = append(output, changes.Entry{Action: changes.ActionAdd, LineID: eq.EquipmentID, PartNumber: eq.PartNumber, After: eq})
+	}
+	for _, change := range d.Updated {
+		output = append(output, changes.Entry{Action: changes.ActionUpdate, LineID: change.After.EquipmentID, PartNumber: change.After.PartNumber, Before: change.Before, After: change.After})
+	}
+	for _, eq := range d.Deleted {
+		output = append(output, changes.Entry{Action: changes.ActionDelete, LineID: eq.EquipmentID, PartNumber: eq.PartNumber, Before: eq})
+	}
+	return output
+}
+
+// Changed 是否有任何異動
+func (d *Diff) Changed() bool {
+	return len(d.Added) > 0 || len(d.Updated) > 0 || len(d.Deleted) > 0
+}
+
+// QuantityDelta 異動後設備總數量的增減
+func (d *Diff) QuantityDelta() int64 {
+	var delta int64
+	for _, eq := range d.Added {
+		delta += eq.Quantity
+	}
+	for _, change := range d.Updated {
+		delta += change.After.Quantity - change.Before.Quantity
+	}
+	for _, eq := range d.Deleted {
+		delta -= eq.Quantity
+	}
+	return delta
+}
+
+// PartLine 料號目錄比對用的目錄編號與料號
+func (b *BatchEquipment) PartLine() *parts.Line {
+	return &parts.Line{PartID: b.PartID, PartNumber: b.PartNumber}
+}
+
+// SetPart 寫回比對後的目錄編號與標準料號
+func (b *BatchEquipment) SetPart(line *parts.Line) {
+	b.PartID, b.PartNumber = line.PartID, line.PartNumber
+}
+
+// LineID 設備編號
+func (a *Table) LineID() string {
+	return a.EquipmentID
+}
+
+// LineVersion 設備版本號
+func (a *Table) LineVersion() int64 {
+	return a.Version
+}
+
+// LineID 設備編號
+func (b *Base) LineID() string {
+	return b.EquipmentID
+}
+
+// LineVersion 設備版本號
+func (b *Base) LineVersion() int64 {
+	return b.Version
+}
@@ -0,0 +1,66 @@
+package idempotency_keys
+
+import "time"
+
+// Table 資料表結構
+type Table struct {
+	// 紀錄編號
+	IdempotencyKeyID string `gorm:"primaryKey;uuid_generate_v4();column:ik_id;type:uuid;" json:"ik_id,omitempty"`
+	// 發送請求的使用者
+	UserID string `gorm:"column:user_id;type:uuid;" json:"user_id,omitempty"`
+	// 用戶端帶入的 Idempotency-Key
+	Key string `gorm:"column:idempotency_key;type:TEXT;" json:"idempotency_key,omitempty"`
+	// 請求方法
+	Method string `gorm:"column:method;type:TEXT;" json:"method,omitempty"`
+	// 請求路徑
+	Path string `gorm:"column:path;type:TEXT;" json:"path,omitempty"`
+	// 請求內容雜湊(SHA-256)
+	RequestHash string `gorm:"column:request_hash;type:TEXT;" json:"request_hash,omitempty"`
+	// 回應狀態碼(NULL 表示處理中)
+	StatusCode *int `gorm:"column:status_code;type:INTEGER;" json:"status_code,omitempty"`
+	// 回應內容
+	ResponseBody *string `gorm:"column:response_body;type:TEXT;" json:"response_body,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+	// 完成時間
+	CompletedAt *time.Time `gorm:"column:completed_at;type:TIMESTAMP;" json:"completed_at,omitempty"`
+	// 到期時間
+	ExpiresAt time.Time `gorm:"column:expires_at;type:TIMESTAMP;" json:"expires_at"`
+}
+
+// Begun 開始處理請求
+type Begun struct {
+	// 發送請求的使用者
+	UserID string
+	// 用戶端帶入的 Idempotency-Key
+	Key string
+	// 請求方法
+	Method string
+	// 請求路徑
+	Path string
+	// 請求內容雜湊
+	RequestHash string
+}
+
+// Completed 請求處理完成後保存的回應
+type Completed struct {
+	// 紀錄編號
+	IdempotencyKeyID string
+	// 回應狀態碼
+	StatusCode int
+	// 回應內容
+	ResponseBody []byte
+}
+
+// Replay 重送時回傳的原始回應
+type Replay struct {
+	// 回應狀態碼
+	StatusCode int
+	// 回應內容
+	ResponseBody []byte
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "idempotency_keys"
+}
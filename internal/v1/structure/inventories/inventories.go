@@ -0,0 +1,79 @@
+package inventories
+
+import (
+	model "esst_sendEmail/internal/v1/structure"
+	"time"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 庫存編號
+	InventoryID string `gorm:"primaryKey;uuid_generate_v4();column:inv_id;type:uuid;" json:"inv_id,omitempty"`
+	// 料號
+	PartNumber string `gorm:"column:part_number;type:TEXT;" json:"part_number,omitempty"`
+	// 正規化料號
+	Normalized string `gorm:"column:normalized;type:TEXT;" json:"-"`
+	// 料號目錄編號
+	PartID *string `gorm:"column:part_id;type:uuid;" json:"part_id,omitempty"`
+	// 在庫數量
+	OnHand int64 `gorm:"column:on_hand;type:BIGINT;" json:"on_hand"`
+	// 已保留數量
+	Reserved int64 `gorm:"column:reserved;type:BIGINT;" json:"reserved"`
+	// 更新時間
+	UpdatedAt time.Time `gorm:"column:updated_at;type:TIMESTAMP;" json:"updated_at"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 庫存編號
+	InventoryID string `json:"inv_id,omitempty"`
+	// 料號
+	PartNumber string `json:"part_number,omitempty"`
+	// 料號目錄編號
+	PartID *string `json:"part_id,omitempty"`
+	// 在庫數量
+	OnHand int64 `json:"on_hand"`
+	// 已保留數量
+	Reserved int64 `json:"reserved"`
+	// 可用數量(在庫 - 已保留)
+	Available int64 `json:"available"`
+	// 更新時間
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 庫存編號
+	InventoryID string `json:"inv_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 料號關鍵字
+	Query string `json:"q,omitempty" form:"q"`
+	// 只列出有可用數量的料號
+	Available *bool `json:"available,omitempty" form:"available"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Inventories []*Base `json:"inventories"`
+	model.OutPage
+}
+
+// Shortage 可用數量不足的料號
+type Shortage struct {
+	// 料號
+	PartNumber string `json:"part_number"`
+	// 需要保留的數量
+	Requested int64 `json:"requested"`
+	// 可用數量
+	Available int64 `json:"available"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "inventories"
+}
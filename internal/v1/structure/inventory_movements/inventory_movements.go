@@ -0,0 +1,121 @@
+package inventory_movements
+
+import (
+	model "esst_sendEmail/internal/v1/structure"
+	"time"
+)
+
+// 異動類型
+const (
+	// TypeReceive 進貨,在庫增加
+	TypeReceive = "receive"
+	// TypeReserve 現貨報備保留,已保留增加
+	TypeReserve = "reserve"
+	// TypeRelease 現貨報備取消或減量,已保留減少
+	TypeRelease = "release"
+	// TypeShip 出貨,在庫減少(指定現貨設備時一併減少已保留)
+	TypeShip = "ship"
+	// TypeAdjust 盤點調整,在庫增減
+	TypeAdjust = "adjust"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 異動編號
+	MovementID string `gorm:"primaryKey;uuid_generate_v4();column:im_id;type:uuid;" json:"im_id,omitempty"`
+	// 庫存編號
+	InventoryID string `gorm:"column:inv_id;type:uuid;" json:"inv_id,omitempty"`
+	// 異動類型
+	Type string `gorm:"column:type;type:TEXT;" json:"type,omitempty"`
+	// 數量
+	Quantity int64 `gorm:"column:quantity;type:BIGINT;" json:"quantity"`
+	// 現貨報備編號
+	StockID *string `gorm:"column:stock_id;type:uuid;" json:"stock_id,omitempty"`
+	// 現貨設備編號
+	StockEquipmentID *string `gorm:"column:seq_id;type:uuid;" json:"seq_id,omitempty"`
+	// 備註
+	Note *string `gorm:"column:note;type:TEXT;" json:"note,omitempty"`
+	// 操作者
+	CreatedBy *string `gorm:"column:created_by;type:uuid;" json:"created_by,omitempty"`
+	// 異動時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 異動編號
+	MovementID string `json:"im_id,omitempty"`
+	// 庫存編號
+	InventoryID string `json:"inv_id,omitempty"`
+	// 異動類型
+	Type string `json:"type,omitempty"`
+	// 數量
+	Quantity int64 `json:"quantity"`
+	// 現貨報備編號
+	StockID *string `json:"stock_id,omitempty"`
+	// 現貨設備編號
+	StockEquipmentID *string `json:"seq_id,omitempty"`
+	// 備註
+	Note *string `json:"note,omitempty"`
+	// 操作者
+	CreatedBy *string `json:"created_by,omitempty"`
+	// 異動時間
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Created 手動登錄庫存異動(進貨、出貨、盤點調整),保留與釋放由現貨報備自動產生
+type Created struct {
+	// 料號(指定現貨設備時可省略)
+	PartNumber string `json:"part_number" binding:"required_without=StockEquipmentID"`
+	// 料號目錄編號
+	PartID *string `json:"part_id,omitempty" binding:"omitempty,uuid4"`
+	// 異動類型
+	Type string `json:"type" binding:"required,oneof=receive ship adjust"`
+	// 數量(進貨、出貨需大於 0,盤點調整可為負數)
+	Quantity int64 `json:"quantity" binding:"required,ne=0"`
+	// 出貨的現貨設備(指定時由該設備的保留數量出貨)
+	StockEquipmentID *string `json:"seq_id,omitempty" binding:"omitempty,uuid4"`
+	// 備註
+	Note *string `json:"note,omitempty"`
+	// 操作者(由 JWT 取得)
+	CreatedBy string `json:"-"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 庫存編號
+	InventoryID string `json:"inv_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 異動類型
+	Type *string `json:"type,omitempty" form:"type" binding:"omitempty,oneof=receive reserve release ship adjust"`
+	// 現貨報備編號
+	StockID *string `json:"stock_id,omitempty" form:"stock_id" binding:"omitempty,uuid4"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Movements []*Base `json:"movements"`
+	model.OutPage
+}
+
+// Outstanding 現貨設備目前的保留與已出貨數量
+type Outstanding struct {
+	// 現貨設備編號
+	StockEquipmentID string `gorm:"column:seq_id"`
+	// 庫存編號
+	InventoryID string `gorm:"column:inv_id"`
+	// 尚未出貨的保留數量
+	Reserved int64 `gorm:"column:reserved"`
+	// 已出貨數量
+	Shipped int64 `gorm:"column:shipped"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "inventory_movements"
+}
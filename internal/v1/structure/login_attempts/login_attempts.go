@@ -0,0 +1,26 @@
+package login_attempts
+
+import (
+	"time"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 紀錄編號
+	LoginAttemptID string `gorm:"primaryKey;uuid_generate_v4();column:la_id;type:uuid;" json:"la_id,omitempty"`
+	// 嘗試登入的帳號
+	Username string `gorm:"column:username;type:TEXT;" json:"username"`
+	// 使用者編號(帳號不存在時為 NULL)
+	UserID *string `gorm:"column:user_id;type:uuid;" json:"user_id,omitempty"`
+	// 來源 IP
+	IP string `gorm:"column:ip;type:TEXT;" json:"ip"`
+	// 是否成功
+	Success bool `gorm:"column:success;type:BOOLEAN;" json:"success"`
+	// 嘗試時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "login_attempts"
+}
@@ -0,0 +1,207 @@
+package parts
+
+import (
+	model "esst_sendEmail/internal/v1/structure"
+	"time"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 料號目錄編號
+	PartID string `gorm:"primaryKey;uuid_generate_v4();column:part_id;type:uuid;" json:"part_id,omitempty"`
+	// 料號(標準寫法)
+	PartNumber string `gorm:"column:part_number;type:TEXT;" json:"part_number,omitempty"`
+	// 正規化料號,用於比對
+	Normalized string `gorm:"column:normalized;type:TEXT;" json:"-"`
+	// 品名
+	Name string `gorm:"column:name;type:TEXT;" json:"name,omitempty"`
+	// 類別
+	Category *string `gorm:"column:category;type:TEXT;" json:"category,omitempty"`
+	// 品牌
+	Brand *string `gorm:"column:brand;type:TEXT;" json:"brand,omitempty"`
+	// 單位
+	Unit *string `gorm:"column:unit;type:TEXT;" json:"unit,omitempty"`
+	// 牌價
+	ListPrice *float64 `gorm:"column:list_price;type:NUMERIC(14,2);" json:"list_price,omitempty"`
+	// 是否啟用
+	Active bool `gorm:"column:active;type:BOOLEAN;default:true;" json:"active"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+	// 更新時間
+	UpdatedAt *time.Time `gorm:"column:updated_at;type:TIMESTAMP;" json:"updated_at,omitempty"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 料號目錄編號
+	PartID string `json:"part_id,omitempty"`
+	// 料號
+	PartNumber string `json:"part_number,omitempty"`
+	// 品名
+	Name string `json:"name,omitempty"`
+	// 類別
+	Category *string `json:"category,omitempty"`
+	// 品牌
+	Brand *string `json:"brand,omitempty"`
+	// 單位
+	Unit *string `json:"unit,omitempty"`
+	// 牌價
+	ListPrice *float64 `json:"list_price,omitempty"`
+	// 是否啟用
+	Active bool `json:"active"`
+	// 建立時間
+	CreatedAt time.Time `json:"created_at"`
+	// 更新時間
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Created 新增料號
+type Created struct {
+	// 料號
+	PartNumber string `json:"part_number" binding:"required" validate:"required"`
+	// 品名
+	Name string `json:"name" binding:"required" validate:"required"`
+	// 類別
+	Category *string `json:"category,omitempty"`
+	// 品牌
+	Brand *string `json:"brand,omitempty"`
+	// 單位
+	Unit *string `json:"unit,omitempty"`
+	// 牌價
+	ListPrice *float64 `json:"list_price,omitempty" binding:"omitempty,gte=0"`
+	// 是否啟用(預設啟用)
+	Active *bool `json:"active,omitempty"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 料號目錄編號
+	PartID string `json:"part_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 關鍵字(料號或品名)
+	Query string `json:"q,omitempty" form:"q"`
+	// 類別
+	Category *string `json:"category,omitempty" form:"category"`
+	// 品牌
+	Brand *string `json:"brand,omitempty" form:"brand"`
+	// 是否啟用
+	Active *bool `json:"active,omitempty" form:"active"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Parts []*Base `json:"parts"`
+	model.OutPage
+}
+
+// Updated 更新料號
+type Updated struct {
+	// 料號目錄編號
+	PartID string `json:"part_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 料號
+	PartNumber *string `json:"part_number,omitempty" binding:"omitempty,min=1"`
+	// 品名
+	Name *string `json:"name,omitempty" binding:"omitempty,min=1"`
+	// 類別
+	Category *string `json:"category,omitempty"`
+	// 品牌
+	Brand *string `json:"brand,omitempty"`
+	// 單位
+	Unit *string `json:"unit,omitempty"`
+	// 牌價
+	ListPrice *float64 `json:"list_price,omitempty" binding:"omitempty,gte=0"`
+	// 是否啟用
+	Active *bool `json:"active,omitempty"`
+}
+
+// Line 待比對的設備料號
+type Line struct {
+	// 指定的料號目錄編號(可省略,省略時依料號比對)
+	PartID *string
+	// 輸入的料號
+	PartNumber string
+}
+
+// Referenced 可比對料號目錄的設備資料
+type Referenced interface {
+	// PartLine 待比對的目錄編號與料號
+	PartLine() *Line
+	// SetPart 寫回比對後的目錄編號與標準料號
+	SetPart(line *Line)
+}
+
+// Unmatched 無法對應到料號目錄的設備
+type Unmatched struct {
+	// 設備在輸入列表中的位置(由 0 開始)
+	Index int `json:"index"`
+	// 輸入的料號
+	PartNumber string `json:"part_number"`
+	// 原因
+	Reason string `json:"reason"`
+	// 建議的料號
+	Suggestions []*Suggestion `json:"suggestions"`
+}
+
+// Suggestion 建議的料號
+type Suggestion struct {
+	// 料號目錄編號(來自歷史設備且未連結目錄時為空)
+	PartID string `json:"part_id,omitempty"`
+	// 料號
+	PartNumber string `json:"part_number"`
+	// 品名或最近一次的設備說明
+	Name string `json:"name,omitempty"`
+	// 相似度(0~1)
+	Score float64 `json:"score"`
+	// 建議來源(catalog / history)
+	Source string `json:"source"`
+}
+
+// Suggest 料號自動完成查詢條件
+type Suggest struct {
+	// 輸入中的料號
+	Query string `json:"q" form:"q" binding:"required"`
+	// 回傳筆數
+	Limit int `json:"limit,omitempty" form:"limit" binding:"omitempty,gte=1,lte=50"`
+}
+
+// Suggested 料號自動完成結果(依歷史設備彙整)
+type Suggested struct {
+	// 料號(最常見的寫法)
+	PartNumber string `json:"part_number"`
+	// 正規化料號
+	Normalized string `json:"-"`
+	// 最近一次的設備說明
+	Description string `json:"description,omitempty"`
+	// 料號目錄編號(曾連結目錄時)
+	PartID *string `json:"part_id,omitempty"`
+	// 使用次數(專案設備與現貨設備)
+	Count int64 `json:"count"`
+	// 排序分數(前綴相符、三連字相似度與使用次數)
+	Score float64 `json:"score"`
+}
+
+// 建議來源
+const (
+	// SourceCatalog 料號目錄
+	SourceCatalog = "catalog"
+	// SourceHistory 歷史設備
+	SourceHistory = "history"
+)
+
+// 無法對應的原因
+const (
+	// ReasonUnknownPartNumber 料號不在目錄中
+	ReasonUnknownPartNumber = "unknown_part_number"
+	// ReasonUnknownPartID 指定的料號目錄編號不存在或已停用
+	ReasonUnknownPartID = "unknown_part_id"
+)
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "parts"
+}
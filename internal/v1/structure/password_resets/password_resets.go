@@ -0,0 +1,24 @@
+package password_resets
+
+import (
+	"time"
+)
+
+// Table 資料表結構
+type Table struct {
+	// Token 編號(對應簽章 token 的 jti)
+	PasswordResetID string `gorm:"primaryKey;uuid_generate_v4();column:prt_id;type:uuid;" json:"prt_id,omitempty"`
+	// 使用者編號
+	UserID string `gorm:"column:user_id;type:uuid;not null" json:"user_id"`
+	// 到期時間
+	ExpiresAt time.Time `gorm:"column:expires_at;type:TIMESTAMP;" json:"expires_at"`
+	// 使用時間
+	UsedAt *time.Time `gorm:"column:used_at;type:TIMESTAMP;" json:"used_at,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "password_reset_tokens"
+}
@@ -0,0 +1,116 @@
+package project_approvals
+
+import (
+	model "esst_sendEmail/internal/v1/structure"
+	"time"
+)
+
+// 審核狀態
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusCancelled = "cancelled"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 紀錄編號
+	ApprovalID string `gorm:"primaryKey;uuid_generate_v4();column:pa_id;type:uuid;" json:"pa_id,omitempty"`
+	// 專案編號
+	ProjectID string `gorm:"column:p_id;type:uuid;" json:"p_id,omitempty"`
+	// 審核關卡
+	Level int `gorm:"column:level;type:INTEGER;" json:"level"`
+	// 可審核的角色(以空白分隔)
+	ApproverRoles string `gorm:"column:approver_roles;type:TEXT;" json:"approver_roles,omitempty"`
+	// 審核狀態
+	Status string `gorm:"column:status;type:TEXT;default:'pending';" json:"status,omitempty"`
+	// 審核者
+	DecidedBy *string `gorm:"column:decided_by;type:uuid;" json:"decided_by,omitempty"`
+	// 審核時間
+	DecidedAt *time.Time `gorm:"column:decided_at;type:TIMESTAMP;" json:"decided_at,omitempty"`
+	// 核准說明或駁回原因
+	Reason *string `gorm:"column:reason;type:TEXT;" json:"reason,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+}
+
+// Row 查詢列表時的資料列(紀錄與專案資訊)
+type Row struct {
+	Table
+	// 專案名稱
+	ProjectName string `gorm:"column:p_name" json:"p_name,omitempty"`
+	// 雙欣負責人
+	Owner string `gorm:"column:owner" json:"owner,omitempty"`
+	// 聯絡人
+	ContactName string `gorm:"column:contact_name" json:"contact_name,omitempty"`
+	// 專案建立時間
+	ProjectCreatedTime *time.Time `gorm:"column:created_time" json:"created_time,omitempty"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 紀錄編號
+	ApprovalID string `json:"pa_id,omitempty"`
+	// 專案編號
+	ProjectID string `json:"p_id,omitempty"`
+	// 專案名稱
+	ProjectName string `json:"p_name,omitempty"`
+	// 雙欣負責人
+	Owner string `json:"owner,omitempty"`
+	// 聯絡人
+	ContactName string `json:"contact_name,omitempty"`
+	// 專案建立時間
+	ProjectCreatedTime *time.Time `json:"created_time,omitempty"`
+	// 審核關卡
+	Level int `json:"level"`
+	// 可審核的角色(以空白分隔)
+	ApproverRoles string `json:"approver_roles,omitempty"`
+	// 審核狀態
+	Status string `json:"status,omitempty"`
+	// 審核者
+	DecidedBy *string `json:"decided_by,omitempty"`
+	// 審核時間
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+	// 核准說明或駁回原因
+	Reason *string `json:"reason,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Decided 核准或駁回
+type Decided struct {
+	// 紀錄編號
+	ApprovalID string `json:"pa_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 核准說明或駁回原因(駁回時必填)
+	Reason *string `json:"reason,omitempty"`
+	// 審核者(由 JWT 取得)
+	DecidedBy string `json:"-"`
+	// 審核者角色(由 JWT 取得)
+	DecidedRole string `json:"-"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 紀錄編號
+	ApprovalID string `json:"pa_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 專案編號
+	ProjectID *string `json:"p_id,omitempty" form:"p_id" binding:"omitempty,uuid4"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Approvals []*Base `json:"approvals"`
+	model.OutPage
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "project_approvals"
+}
@@ -0,0 +1,199 @@
+package project_conflicts
+
+import (
+	model "esst_sendEmail/internal/v1/structure"
+	"time"
+)
+
+// 審核狀態
+const (
+	StatusPending   = "pending"
+	StatusConfirmed = "confirmed"
+	StatusDismissed = "dismissed"
+)
+
+// 判定原因
+const (
+	ReasonName         = "name"
+	ReasonContactEmail = "contact_email"
+	ReasonContactPhone = "contact_phone"
+	ReasonPartNumbers  = "part_numbers"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 紀錄編號
+	ConflictID string `gorm:"primaryKey;uuid_generate_v4();column:pc_id;type:uuid;" json:"pc_id,omitempty"`
+	// 新建立的專案
+	ProjectID string `gorm:"column:p_id;type:uuid;" json:"p_id,omitempty"`
+	// 可能重複的既有專案
+	ConflictProjectID string `gorm:"column:conflict_p_id;type:uuid;" json:"conflict_p_id,omitempty"`
+	// 判定原因(以空白分隔)
+	Reasons string `gorm:"column:reasons;type:TEXT;" json:"reasons"`
+	// 名稱相似度
+	Similarity float64 `gorm:"column:similarity;type:NUMERIC(4,3);" json:"similarity"`
+	// 審核狀態
+	Status string `gorm:"column:status;type:TEXT;default:'pending';" json:"status,omitempty"`
+	// 審核者
+	ReviewedBy *string `gorm:"column:reviewed_by;type:uuid;" json:"reviewed_by,omitempty"`
+	// 審核時間
+	ReviewedAt *time.Time `gorm:"column:reviewed_at;type:TIMESTAMP;" json:"reviewed_at,omitempty"`
+	// 審核說明
+	ReviewNote *string `gorm:"column:review_note;type:TEXT;" json:"review_note,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+}
+
+// Base 基礎結構,包含雙方專案名稱與負責人
+type Base struct {
+	// 紀錄編號
+	ConflictID string `json:"pc_id,omitempty"`
+	// 新建立的專案
+	ProjectID string `json:"p_id,omitempty"`
+	// 新建立的專案名稱
+	ProjectName string `json:"p_name,omitempty"`
+	// 新建立的專案負責人
+	Owner string `json:"owner,omitempty"`
+	// 可能重複的既有專案
+	ConflictProjectID string `json:"conflict_p_id,omitempty"`
+	// 既有專案名稱
+	ConflictProjectName string `json:"conflict_p_name,omitempty"`
+	// 既有專案負責人
+	ConflictOwner string `json:"conflict_owner,omitempty"`
+	// 判定原因
+	Reasons []string `json:"reasons"`
+	// 名稱相似度
+	Similarity float64 `json:"similarity"`
+	// 審核狀態
+	Status string `json:"status,omitempty"`
+	// 審核者
+	ReviewedBy *string `json:"reviewed_by,omitempty"`
+	// 審核時間
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	// 審核說明
+	ReviewNote *string `json:"review_note,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Row 查詢列表時的資料列(紀錄與雙方專案資訊)
+type Row struct {
+	Table
+	// 新建立的專案名稱
+	ProjectName string `gorm:"column:p_name"`
+	// 新建立的專案負責人
+	Owner string `gorm:"column:owner"`
+	// 既有專案名稱
+	ConflictProjectName string `gorm:"column:conflict_p_name"`
+	// 既有專案負責人
+	ConflictOwner string `gorm:"column:conflict_owner"`
+}
+
+// Checked 重複報備檢查條件
+type Checked struct {
+	// 專案名稱
+	ProjectName string
+	// 聯絡信箱
+	ContactEmail string
+	// 聯絡電話
+	ContactPhone string
+	// 料號(分兩次建立時為空)
+	PartNumbers []string
+	// 排除的專案(新增設備時為專案本身)
+	ExcludeProjectID string
+}
+
+// Candidates 候選專案查詢條件,名稱、信箱、電話與料號皆已正規化
+type Candidates struct {
+	// 檢查期間起點
+	Since time.Time
+	// 專案名稱
+	Name string
+	// 名稱預先篩選的三連字相似度門檻
+	NamePrefilter float64
+	// 聯絡信箱
+	Email string
+	// 聯絡電話
+	Phone string
+	// 料號
+	PartNumbers []string
+	// 排除的專案
+	ExcludeProjectID string
+}
+
+// Candidate 名稱、聯絡方式或料號可能相符的候選專案
+type Candidate struct {
+	// 專案編號
+	ProjectID string `gorm:"column:p_id"`
+	// 專案名稱
+	ProjectName string `gorm:"column:p_name"`
+	// 聯絡姓名
+	ContactName string `gorm:"column:contact_name"`
+	// 聯絡電話
+	ContactPhone string `gorm:"column:contact_phone"`
+	// 聯絡信箱
+	ContactEmail string `gorm:"column:contact_email"`
+	// 雙欣負責人
+	Owner string `gorm:"column:owner"`
+	// 建立時間
+	CreatedTime time.Time `gorm:"column:created_time"`
+	// 相同的料號數
+	MatchedParts int `gorm:"column:matched_parts"`
+}
+
+// Conflict 可能重複的既有專案,建立時回傳給使用者
+type Conflict struct {
+	// 專案編號
+	ProjectID string `json:"p_id"`
+	// 專案名稱
+	ProjectName string `json:"p_name"`
+	// 雙欣負責人
+	Owner string `json:"owner"`
+	// 聯絡姓名
+	ContactName string `json:"contact_name"`
+	// 建立時間
+	CreatedTime time.Time `json:"created_time"`
+	// 判定原因
+	Reasons []string `json:"reasons"`
+	// 名稱相似度
+	Similarity float64 `json:"similarity"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 紀錄編號
+	ConflictID string `json:"pc_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 專案編號(新建立或既有專案)
+	ProjectID *string `json:"p_id,omitempty" form:"p_id" binding:"omitempty,uuid4"`
+	// 審核狀態
+	Status *string `json:"status,omitempty" form:"status" binding:"omitempty,oneof=pending confirmed dismissed"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Conflicts []*Base `json:"conflicts"`
+	model.OutPage
+}
+
+// Reviewed 審核
+type Reviewed struct {
+	// 紀錄編號
+	ConflictID string `json:"pc_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 審核結果
+	Status string `json:"status" binding:"required,oneof=confirmed dismissed" validate:"required"`
+	// 審核說明
+	ReviewNote *string `json:"review_note,omitempty"`
+	// 審核者
+	ReviewedBy string `json:"-"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "project_conflicts"
+}
@@ -0,0 +1,120 @@
+package project_extensions
+
+import (
+	model "esst_sendEmail/internal/v1/structure"
+	"time"
+)
+
+// 審核狀態
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 申請編號
+	ExtensionID string `gorm:"primaryKey;uuid_generate_v4();column:pe_id;type:uuid;" json:"pe_id,omitempty"`
+	// 專案編號
+	ProjectID string `gorm:"column:p_id;type:uuid;" json:"p_id,omitempty"`
+	// 申請者
+	RequestedBy string `gorm:"column:requested_by;type:uuid;" json:"requested_by,omitempty"`
+	// 申請延長天數
+	Days int `gorm:"column:days;type:INTEGER;" json:"days"`
+	// 申請原因
+	Reason string `gorm:"column:reason;type:TEXT;" json:"reason,omitempty"`
+	// 審核狀態
+	Status string `gorm:"column:status;type:TEXT;default:'pending';" json:"status,omitempty"`
+	// 審核者
+	ReviewedBy *string `gorm:"column:reviewed_by;type:uuid;" json:"reviewed_by,omitempty"`
+	// 審核時間
+	ReviewedAt *time.Time `gorm:"column:reviewed_at;type:TIMESTAMP;" json:"reviewed_at,omitempty"`
+	// 審核說明
+	ReviewNote *string `gorm:"column:review_note;type:TEXT;" json:"review_note,omitempty"`
+	// 核准前的到期時間
+	PreviousExpiresAt *time.Time `gorm:"column:previous_expires_at;type:TIMESTAMP;" json:"previous_expires_at,omitempty"`
+	// 核准後的到期時間
+	NewExpiresAt *time.Time `gorm:"column:new_expires_at;type:TIMESTAMP;" json:"new_expires_at,omitempty"`
+	// 申請時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 申請編號
+	ExtensionID string `json:"pe_id,omitempty"`
+	// 專案編號
+	ProjectID string `json:"p_id,omitempty"`
+	// 申請者
+	RequestedBy string `json:"requested_by,omitempty"`
+	// 申請延長天數
+	Days int `json:"days"`
+	// 申請原因
+	Reason string `json:"reason,omitempty"`
+	// 審核狀態
+	Status string `json:"status,omitempty"`
+	// 審核者
+	ReviewedBy *string `json:"reviewed_by,omitempty"`
+	// 審核時間
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	// 審核說明
+	ReviewNote *string `json:"review_note,omitempty"`
+	// 核准前的到期時間
+	PreviousExpiresAt *time.Time `json:"previous_expires_at,omitempty"`
+	// 核准後的到期時間
+	NewExpiresAt *time.Time `json:"new_expires_at,omitempty"`
+	// 申請時間
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Created 申請延長保護期
+type Created struct {
+	// 專案編號
+	ProjectID string `json:"p_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 申請延長天數
+	Days int `json:"days" binding:"required,gt=0,lte=180" validate:"required"`
+	// 申請原因
+	Reason string `json:"reason" binding:"required" validate:"required"`
+	// 申請者(由 JWT 取得)
+	RequestedBy string `json:"-"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 申請編號
+	ExtensionID string `json:"pe_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 專案編號
+	ProjectID *string `json:"p_id,omitempty" form:"p_id" binding:"omitempty,uuid4"`
+	// 審核狀態
+	Status *string `json:"status,omitempty" form:"status" binding:"omitempty,oneof=pending approved rejected"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Extensions []*Base `json:"extensions"`
+	model.OutPage
+}
+
+// Reviewed 審核
+type Reviewed struct {
+	// 申請編號
+	ExtensionID string `json:"pe_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 審核結果
+	Status string `json:"status" binding:"required,oneof=approved rejected" validate:"required"`
+	// 審核說明
+	ReviewNote *string `json:"review_note,omitempty"`
+	// 審核者(由 JWT 取得)
+	ReviewedBy string `json:"-"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "project_extensions"
+}
@@ -3,6 +3,8 @@ package projects
 import (
 	model "esst_sendEmail/internal/v1/structure"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Poject struct is a row record of the companies table in the invoice database
@@ -38,13 +40,36 @@ type Table struct {
 	DeliveryAddress string `gorm:"column:delivery_address;type:TEXT;" json:"delivery_address,omitempty"`
 	// 特殊需求
 	SpecialRequirements string `gorm:"column:special_requirements;type:TEXT;" json:"special_requirements,omitempty"`
-	// 專案狀態 (step1: 第一階段, step2: 第二階段, completed: 已完成)
+	// 專案狀態 (step1: 第一階段, step2: 第二階段, completed: 已完成, expired: 保護期已過)
 	Status string `gorm:"column:status;type:TEXT;default:'step1';" json:"status,omitempty"`
 
 	// 創建時間
 	CreatedTime time.Time `gorm:"column:created_time;type:TIMESTAMP;" json:"created_time"`
 	// 更新時間
 	UpdatedTime *time.Time `gorm:"column:updated_time;type:TIMESTAMP;" json:"updated_time,omitempty"`
+
+	// 保護期
+	// 保護期到期時間(第一階段逾期未進入第二階段即轉為 expired)
+	ExpiresAt *time.Time `gorm:"column:expires_at;type:TIMESTAMP;" json:"expires_at,omitempty"`
+	// 到期前提醒發送時間
+	ExpiryWarnedAt *time.Time `gorm:"column:expiry_warned_at;type:TIMESTAMP;" json:"expiry_warned_at,omitempty"`
+	// 建立者
+	CreatedBy *string `gorm:"column:created_by;type:uuid;" json:"created_by,omitempty"`
+
+	// 審核
+	// 審核狀態 (pending: 待審核, approved: 已核准, rejected: 已駁回)
+	ApprovalStatus string `gorm:"column:approval_status;type:TEXT;default:'approved';" json:"approval_status,omitempty"`
+	// 目前待審核的關卡(0 表示無)
+	ApprovalLevel int `gorm:"column:approval_level;type:INTEGER;default:0;" json:"approval_level"`
+
+	// 版本號(每次更新加 1,用於 ETag / If-Match)
+	Version int64 `gorm:"column:version;type:bigint;default:1" json:"version"`
+
+	// 軟刪除
+	// 刪除時間(NULL 表示未刪除,刪除後保留於資源回收筒)
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:TIMESTAMP;index" json:"deleted_at"`
+	// 刪除者
+	DeletedBy *string `gorm:"column:deleted_by;type:uuid;" json:"deleted_by,omitempty"`
 }
 
 // Base struct is corresponding to table structure file
@@ -86,6 +111,22 @@ type Base struct {
 	CreatedTime time.Time `json:"created_time"`
 	// 更新時間
 	UpdatedTime *time.Time `json:"updated_time,omitempty"`
+
+	// 保護期到期時間
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// 建立者
+	CreatedBy *string `json:"created_by,omitempty"`
+	// 審核狀態
+	ApprovalStatus string `json:"approval_status,omitempty"`
+	// 目前待審核的關卡
+	ApprovalLevel int `json:"approval_level"`
+
+	// 版本號
+	Version int64 `json:"version"`
+	// 刪除時間
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// 刪除者
+	DeletedBy *string `json:"deleted_by,omitempty"`
 }
 
 // Single return structure file
@@ -127,6 +168,18 @@ type Single struct {
 	CreatedTime time.Time `json:"created_time"`
 	// 更新時間
 	UpdatedTime *time.Time `json:"updated_time,omitempty"`
+
+	// 保護期到期時間
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// 建立者
+	CreatedBy *string `json:"created_by,omitempty"`
+	// 審核狀態
+	ApprovalStatus string `json:"approval_status,omitempty"`
+	// 目前待審核的關卡
+	ApprovalLevel int `json:"approval_level"`
+
+	// 版本號(同 ETag)
+	Version int64 `json:"version"`
 }
 
 // Created struct is used to create
@@ -141,8 +194,13 @@ type Created struct {
 	ContactEmail string `json:"contact_email" binding:"required,email" validate:"email"`
 	// 雙欣負責人
 	Owner string `json:"owner" binding:"required" validate:"required"`
-	// 備註
+	// 備註(建立後轉為專案的第一則留言)
 	Remark string `json:"remark,omitempty"`
+
+	// 保護期天數(僅管理員可指定,預設為 PROJECT_PROTECTION_DAYS)
+	ProtectionDays int `json:"protection_days,omitempty" binding:"omitempty,gt=0,lte=365"`
+	// 建立者(由 JWT 取得)
+	CreatedBy string `json:"-"`
 }
 
 // Field is structure file for search
@@ -161,6 +219,9 @@ type Field struct {
 	Owner *string `json:"owner,omitempty" form:"owner"`
 	// 專案狀態
 	Status *string `json:"status,omitempty" form:"status"`
+
+	// 審核狀態
+	ApprovalStatus *string `json:"approval_status,omitempty" form:"approval_status" binding:"omitempty,oneof=pending approved rejected"`
 }
 
 // Fields is the searched structure file (including pagination)
@@ -209,6 +270,22 @@ type List struct {
 		CreatedTime time.Time `json:"created_time"`
 		// 更新時間
 		UpdatedTime *time.Time `json:"updated_time,omitempty"`
+
+		// 保護期到期時間
+		ExpiresAt *time.Time `json:"expires_at,omitempty"`
+		// 建立者
+		CreatedBy *string `json:"created_by,omitempty"`
+		// 審核狀態
+		ApprovalStatus string `json:"approval_status,omitempty"`
+		// 目前待審核的關卡
+		ApprovalLevel int `json:"approval_level"`
+
+		// 版本號
+		Version int64 `json:"version"`
+		// 刪除時間
+		DeletedAt *time.Time `json:"deleted_at,omitempty"`
+		// 刪除者
+		DeletedBy *string `json:"deleted_by,omitempty"`
 	} `json:"projects"`
 	model.OutPage
 }
@@ -227,7 +304,7 @@ type Updated struct {
 	ContactEmail string `json:"contact_email,omitempty"`
 	// 雙欣負責人
 	Owner string `json:"owner,omitempty"`
-	// 備註
+	// 備註(新增為一則留言,不覆寫先前的內容)
 	Remark string `json:"remark,omitempty"`
 
 	// 第二階段欄位
@@ -247,9 +324,31 @@ type Updated struct {
 	SpecialRequirements string `json:"special_requirements,omitempty"`
 	// 專案狀態
 	Status string `json:"status,omitempty"`
+
+	// 預期的版本號(由 If-Match 取得)
+	Version int64 `json:"-"`
+	// 異動者(由 JWT 取得)
+	UpdatedBy string `json:"-"`
+	// 刪除者(由 JWT 取得)
+	DeletedBy string `json:"-"`
 }
 
 // TableName sets the insert table name for this struct type
 func (a *Table) TableName() string {
 	return "projects"
 }
+
+// 專案狀態
+const (
+	StatusStep1     = "step1"
+	StatusStep2     = "step2"
+	StatusCompleted = "completed"
+	StatusExpired   = "expired"
+)
+
+// 審核狀態
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
@@ -0,0 +1,199 @@
+package quotations
+
+import (
+	"time"
+
+	"esst_sendEmail/internal/pkg/pricing"
+	model "esst_sendEmail/internal/v1/structure"
+	"esst_sendEmail/internal/v1/structure/equipments"
+)
+
+// 報價單狀態
+const (
+	// StatusDraft 草稿
+	StatusDraft = "draft"
+	// StatusSent 已寄給客戶
+	StatusSent = "sent"
+	// StatusAccepted 客戶已接受
+	StatusAccepted = "accepted"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 報價單編號
+	QuotationID string `gorm:"primaryKey;uuid_generate_v4();column:q_id;type:uuid;" json:"q_id,omitempty"`
+	// 專案編號
+	ProjectID string `gorm:"column:p_id;type:uuid;" json:"p_id,omitempty"`
+	// 報價版本(依專案遞增)
+	Version int64 `gorm:"column:version;type:INTEGER;" json:"version"`
+	// 狀態
+	Status string `gorm:"column:status;type:TEXT;" json:"status,omitempty"`
+	// 幣別
+	Currency string `gorm:"column:currency;type:CHAR(3);" json:"currency,omitempty"`
+	// 未稅小計(折扣前)
+	Subtotal pricing.Money `gorm:"column:subtotal;type:NUMERIC(14,2);" json:"subtotal"`
+	// 折扣金額
+	Discount pricing.Money `gorm:"column:discount;type:NUMERIC(14,2);" json:"discount"`
+	// 稅額
+	Tax pricing.Money `gorm:"column:tax;type:NUMERIC(14,2);" json:"tax"`
+	// 含稅總額
+	Total pricing.Money `gorm:"column:total;type:NUMERIC(14,2);" json:"total"`
+	// 備註
+	Note *string `gorm:"column:note;type:TEXT;" json:"note,omitempty"`
+	// 報價有效期限
+	ValidUntil *time.Time `gorm:"column:valid_until;type:TIMESTAMP;" json:"valid_until,omitempty"`
+	// 建立者
+	CreatedBy *string `gorm:"column:created_by;type:uuid;" json:"created_by,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+	// 寄出時間
+	SentAt *time.Time `gorm:"column:sent_at;type:TIMESTAMP;" json:"sent_at,omitempty"`
+	// 寄送信箱
+	SentTo *string `gorm:"column:sent_to;type:TEXT;" json:"sent_to,omitempty"`
+	// 寄出者
+	SentBy *string `gorm:"column:sent_by;type:uuid;" json:"sent_by,omitempty"`
+	// 接受時間
+	AcceptedAt *time.Time `gorm:"column:accepted_at;type:TIMESTAMP;" json:"accepted_at,omitempty"`
+	// 更新時間
+	UpdatedAt *time.Time `gorm:"column:updated_at;type:TIMESTAMP;" json:"updated_at,omitempty"`
+}
+
+// LineTable 報價單明細資料表結構
+type LineTable struct {
+	// 明細編號
+	LineID string `gorm:"primaryKey;uuid_generate_v4();column:ql_id;type:uuid;" json:"ql_id,omitempty"`
+	// 報價單編號
+	QuotationID string `gorm:"column:q_id;type:uuid;" json:"q_id,omitempty"`
+	// 專案設備編號(設備刪除後為空)
+	EquipmentID *string `gorm:"column:eq_id;type:uuid;" json:"eq_id,omitempty"`
+	// 排列順序
+	Position int `gorm:"column:position;type:INTEGER;" json:"position"`
+	// 料號
+	PartNumber string `gorm:"column:part_number;type:TEXT;" json:"part_number"`
+	// 說明
+	Description string `gorm:"column:description;type:TEXT;" json:"description,omitempty"`
+	// 數量
+	Quantity int64 `gorm:"column:quantity;type:INTEGER;" json:"quantity"`
+	// 單價
+	UnitPrice pricing.Money `gorm:"column:unit_price;type:NUMERIC(14,2);" json:"unit_price"`
+	// 折扣百分比
+	DiscountRate float64 `gorm:"column:discount_rate;type:NUMERIC(5,2);" json:"discount_rate"`
+	// 稅率百分比
+	TaxRate float64 `gorm:"column:tax_rate;type:NUMERIC(5,2);" json:"tax_rate"`
+	// 未稅小計(折扣前)
+	Subtotal pricing.Money `gorm:"column:subtotal;type:NUMERIC(14,2);" json:"subtotal"`
+	// 折扣金額
+	Discount pricing.Money `gorm:"column:discount;type:NUMERIC(14,2);" json:"discount"`
+	// 稅額
+	Tax pricing.Money `gorm:"column:tax;type:NUMERIC(14,2);" json:"tax"`
+	// 含稅金額
+	Total pricing.Money `gorm:"column:total;type:NUMERIC(14,2);" json:"total"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 報價單編號
+	QuotationID string `json:"q_id,omitempty"`
+	// 專案編號
+	ProjectID string `json:"p_id,omitempty"`
+	// 報價版本
+	Version int64 `json:"version"`
+	// 狀態
+	Status string `json:"status,omitempty"`
+	// 幣別
+	Currency string `json:"currency,omitempty"`
+	// 未稅小計(折扣前)
+	Subtotal pricing.Money `json:"subtotal"`
+	// 折扣金額
+	Discount pricing.Money `json:"discount"`
+	// 稅額
+	Tax pricing.Money `json:"tax"`
+	// 含稅總額
+	Total pricing.Money `json:"total"`
+	// 備註
+	Note *string `json:"note,omitempty"`
+	// 報價有效期限
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	// 建立者
+	CreatedBy *string `json:"created_by,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `json:"created_at"`
+	// 寄出時間
+	SentAt *time.Time `json:"sent_at,omitempty"`
+	// 寄送信箱
+	SentTo *string `json:"sent_to,omitempty"`
+	// 寄出者
+	SentBy *string `json:"sent_by,omitempty"`
+	// 接受時間
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	// 更新時間
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// 明細(列表查詢時省略)
+	Lines []*LineTable `json:"lines,omitempty"`
+}
+
+// Created 以專案目前的設備建立新版本報價單
+type Created struct {
+	// 專案編號(由路徑取得)
+	ProjectID string `json:"-"`
+	// 備註
+	Note *string `json:"note,omitempty"`
+	// 有效天數(省略時使用 QUOTATION_VALID_DAYS,預設 30 天)
+	ValidDays int `json:"valid_days,omitempty" binding:"omitempty,gte=1,lte=365"`
+	// 建立者(由 JWT 取得)
+	CreatedBy string `json:"-"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 報價單編號
+	QuotationID string `json:"q_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 專案編號
+	ProjectID string `json:"p_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 狀態
+	Status *string `json:"status,omitempty" form:"status" binding:"omitempty,oneof=draft sent accepted"`
+}
+
+// Fields 多筆查詢
+type Fields struct {
+	Field
+	model.InPage
+}
+
+// List 多筆回傳
+type List struct {
+	Quotations []*Base `json:"quotations"`
+	model.OutPage
+}
+
+// Action 寄出或接受報價單
+type Action struct {
+	// 報價單編號
+	QuotationID string `json:"-"`
+	// 專案編號
+	ProjectID string `json:"-"`
+	// 操作者(由 JWT 取得)
+	By string `json:"-"`
+}
+
+// Pricing 專案設備的金額與總計
+type Pricing struct {
+	// 專案編號
+	ProjectID string `json:"p_id"`
+	// 設備(含各筆金額)
+	Equipments []*equipments.Base `json:"equipments"`
+	// 依幣別分別加總
+	Totals []*pricing.Amount `json:"totals"`
+	// 尚未填寫單價的設備數
+	Unpriced int `json:"unpriced"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "quotations"
+}
+
+// TableName 設定資料表名稱
+func (t *LineTable) TableName() string {
+	return "quotation_lines"
+}
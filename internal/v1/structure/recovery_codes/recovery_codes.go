@@ -0,0 +1,24 @@
+package recovery_codes
+
+import (
+	"time"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 復原碼編號
+	RecoveryCodeID string `gorm:"primaryKey;uuid_generate_v4();column:rc_id;type:uuid;" json:"rc_id,omitempty"`
+	// 使用者編號
+	UserID string `gorm:"column:user_id;type:uuid;not null" json:"user_id"`
+	// 復原碼雜湊(SHA-256)
+	CodeHash string `gorm:"column:code_hash;type:TEXT;" json:"-"`
+	// 使用時間
+	UsedAt *time.Time `gorm:"column:used_at;type:TIMESTAMP;" json:"used_at,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "user_recovery_codes"
+}
@@ -0,0 +1,48 @@
+package role_policies
+
+import (
+	"time"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 角色 (admin/user)
+	Role string `gorm:"primaryKey;column:role;type:TEXT;" json:"role"`
+	// 是否強制兩步驟驗證
+	RequireTwoFactor bool `gorm:"column:require_two_factor;type:BOOLEAN;" json:"require_two_factor"`
+	// 登入方式 (password/email/totp),空值時依系統預設
+	LoginPolicy string `gorm:"column:login_policy;type:TEXT;" json:"login_policy,omitempty"`
+	// 更新時間
+	UpdatedAt *time.Time `gorm:"column:updated_at;type:TIMESTAMP;" json:"updated_at,omitempty"`
+}
+
+// Base 基礎結構
+type Base struct {
+	Role             string     `json:"role"`
+	RequireTwoFactor bool       `json:"require_two_factor"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+
+	// 角色設定的登入方式,空值時依系統預設
+	LoginPolicy string `json:"login_policy,omitempty"`
+	// 實際套用的登入方式
+	EffectiveLoginPolicy string `json:"effective_login_policy"`
+}
+
+// Field 查詢條件
+type Field struct {
+	Role string `json:"role,omitempty"`
+}
+
+// Updated 更新角色政策
+type Updated struct {
+	Role             string `json:"role,omitempty" swaggerignore:"true"`
+	RequireTwoFactor *bool  `json:"require_two_factor" binding:"required"`
+
+	// 登入方式 (password/email/totp),留空則依系統預設
+	LoginPolicy string `json:"login_policy,omitempty" binding:"omitempty,oneof=password email totp"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "role_policies"
+}
@@ -0,0 +1,121 @@
+package stock_allocations
+
+import (
+	"time"
+)
+
+// Table 資料表結構
+type Table struct {
+	// 分配編號
+	AllocationID string `gorm:"primaryKey;uuid_generate_v4();column:sa_id;type:uuid;" json:"sa_id,omitempty"`
+	// 專案編號
+	ProjectID string `gorm:"column:p_id;type:uuid;" json:"p_id,omitempty"`
+	// 專案設備編號
+	EquipmentID string `gorm:"column:eq_id;type:uuid;" json:"eq_id,omitempty"`
+	// 現貨設備編號
+	StockEquipmentID string `gorm:"column:seq_id;type:uuid;" json:"seq_id,omitempty"`
+	// 分配數量
+	Quantity int64 `gorm:"column:quantity;type:INTEGER;" json:"quantity"`
+	// 分配者
+	AllocatedBy *string `gorm:"column:allocated_by;type:uuid;" json:"allocated_by,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
+	// 更新時間
+	UpdatedAt *time.Time `gorm:"column:updated_at;type:TIMESTAMP;" json:"updated_at,omitempty"`
+}
+
+// Row 分配紀錄(含現貨資訊)
+type Row struct {
+	Table
+	// 現貨報備編號
+	StockID string `gorm:"column:stock_id" json:"stock_id,omitempty"`
+	// 現貨項目名稱
+	StockName string `gorm:"column:stock_name" json:"stock_name,omitempty"`
+	// 現貨設備料號
+	PartNumber string `gorm:"column:part_number" json:"part_number,omitempty"`
+}
+
+// Base 基礎結構
+type Base struct {
+	// 分配編號
+	AllocationID string `json:"sa_id,omitempty"`
+	// 專案編號
+	ProjectID string `json:"p_id,omitempty"`
+	// 專案設備編號
+	EquipmentID string `json:"eq_id,omitempty"`
+	// 現貨設備編號
+	StockEquipmentID string `json:"seq_id,omitempty"`
+	// 現貨報備編號
+	StockID string `json:"stock_id,omitempty"`
+	// 現貨項目名稱
+	StockName string `json:"stock_name,omitempty"`
+	// 現貨設備料號
+	PartNumber string `json:"part_number,omitempty"`
+	// 分配數量
+	Quantity int64 `json:"quantity"`
+	// 分配者
+	AllocatedBy *string `json:"allocated_by,omitempty"`
+	// 建立時間
+	CreatedAt time.Time `json:"created_at"`
+	// 更新時間
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// Created 將現貨設備分配給專案設備(同一組設備重複分配時累加數量)
+type Created struct {
+	// 專案編號(由路徑取得)
+	ProjectID string `json:"-"`
+	// 專案設備編號
+	EquipmentID string `json:"eq_id" binding:"required,uuid4" validate:"required,uuid4"`
+	// 現貨設備編號
+	StockEquipmentID string `json:"seq_id" binding:"required,uuid4" validate:"required,uuid4"`
+	// 分配數量
+	Quantity int64 `json:"quantity" binding:"required,gt=0" validate:"required,gt=0"`
+	// 分配者(由 JWT 取得)
+	AllocatedBy string `json:"-"`
+}
+
+// Field 查詢條件
+type Field struct {
+	// 分配編號
+	AllocationID string `json:"sa_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+	// 專案編號
+	ProjectID string `json:"p_id,omitempty" binding:"omitempty,uuid4" swaggerignore:"true"`
+}
+
+// Coverage 專案設備的現貨支應情況
+type Coverage struct {
+	// 專案設備編號
+	EquipmentID string `json:"eq_id"`
+	// 料號
+	PartNumber string `json:"part_number"`
+	// 說明
+	Description string `json:"description,omitempty"`
+	// 需求數量
+	Quantity int64 `json:"quantity"`
+	// 由現貨支應的數量
+	FromStock int64 `json:"from_stock"`
+	// 需另行採購的數量
+	ToOrder int64 `json:"to_order"`
+	// 分配紀錄
+	Allocations []*Base `json:"allocations"`
+}
+
+// Summary 專案的現貨支應摘要
+type Summary struct {
+	// 專案編號
+	ProjectID string `json:"p_id"`
+	// 需求總數量
+	Quantity int64 `json:"quantity"`
+	// 由現貨支應的總數量
+	FromStock int64 `json:"from_stock"`
+	// 需另行採購的總數量
+	ToOrder int64 `json:"to_order"`
+	// 各設備的支應情況
+	Equipments []*Coverage `json:"equipments"`
+}
+
+// TableName 設定資料表名稱
+func (t *Table) TableName() string {
+	return "stock_allocations"
+}
@@ -3,6 +3,8 @@ package stocks
 import (
 	model "esst_sendEmail/internal/v1/structure"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Stock struct is a row record of the stocks table
@@ -33,6 +35,15 @@ type Table struct {
 	// 時間戳記
 	CreatedTime time.Time  `gorm:"column:created_time;type:TIMESTAMP;" json:"created_time"`
 	UpdatedTime *time.Time `gorm:"column:updated_time;type:TIMESTAMP;" json:"updated_time,omitempty"`
+
+	// 版本號(每次更新加 1,用於 ETag / If-Match)
+	Version int64 `gorm:"column:version;type:bigint;default:1" json:"version"`
+
+	// 軟刪除
+	// 刪除時間(NULL 表示未刪除,刪除後保留於資源回收筒)
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:TIMESTAMP;index" json:"deleted_at"`
+	// 刪除者
+	DeletedBy *string `gorm:"column:deleted_by;type:uuid;" json:"deleted_by,omitempty"`
 }
 
 // Base struct is corresponding to table structure file
@@ -63,6 +74,13 @@ type Base struct {
 	// 時間戳記
 	CreatedTime time.Time  `json:"created_time"`
 	UpdatedTime *time.Time `json:"updated_time,omitempty"`
+
+	// 版本號
+	Version int64 `json:"version"`
+	// 刪除時間
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// 刪除者
+	DeletedBy *string `json:"deleted_by,omitempty"`
 }
 
 // Single return structure file
@@ -93,6 +111,9 @@ type Single struct {
 	// 時間戳記
 	CreatedTime time.Time  `json:"created_time"`
 	UpdatedTime *time.Time `json:"updated_time,omitempty"`
+
+	// 版本號(同 ETag)
+	Version int64 `json:"version"`
 }
 
 // Created struct is used to create
@@ -114,8 +135,11 @@ type Created struct {
 	SpecialRequirements    string `json:"special_requirements,omitempty"`
 	
 	
-	// 備註
+	// 備註(建立後轉為現貨報備的第一則留言)
 	Remark string `json:"remark,omitempty"`
+
+	// 建立者(由 JWT 取得)
+	CreatedBy string `json:"-"`
 }
 
 // Field is structure file for search
@@ -161,6 +185,13 @@ type List struct {
 		// 時間戳記
 		CreatedTime time.Time  `json:"created_time"`
 		UpdatedTime *time.Time `json:"updated_time,omitempty"`
+
+		// 版本號
+		Version int64 `json:"version"`
+		// 刪除時間
+		DeletedAt *time.Time `json:"deleted_at,omitempty"`
+		// 刪除者
+		DeletedBy *string `json:"deleted_by,omitempty"`
 	} `json:"stocks"`
 	model.OutPage
 }
@@ -187,8 +218,15 @@ type Updated struct {
 	SpecialRequirements    string `json:"special_requirements,omitempty"`
 	
 	
-	// 備註
+	// 備註(新增為一則留言,不覆寫先前的內容)
 	Remark string `json:"remark,omitempty"`
+
+	// 預期的版本號(由 If-Match 取得)
+	Version int64 `json:"-"`
+	// 異動者(由 JWT 取得)
+	UpdatedBy string `json:"-"`
+	// 刪除者(由 JWT 取得)
+	DeletedBy string `json:"-"`
 }
 
 // TableName sets the insert table name for this struct type
@@ -1,5 +1,7 @@
 package structure
 
+import "errors"
+
 type InPage struct {
 	//頁數(請從1開始帶入)
 	Page int64 `json:"page" binding:"required,gt=0" validate:"required" form:"page"`
@@ -13,3 +15,6 @@ type OutPage struct {
 	Pages int64 `json:"pages"`
 	Total int64 `json:"total"`
 }
+
+// ErrVersionConflict 資料已被其他人修改(If-Match 的版本號與目前版本不符)
+var ErrVersionConflict = errors.New("資料已被其他人修改，請重新取得最新資料後再更新")
@@ -15,6 +15,28 @@ type Table struct {
 	Role      string     `gorm:"column:role;type:TEXT;default:'user';" json:"role,omitempty"`
 	CreatedAt time.Time  `gorm:"column:created_at;type:TIMESTAMP;" json:"created_at"`
 	UpdatedAt *time.Time `gorm:"column:updated_at;type:TIMESTAMP;" json:"updated_at,omitempty"`
+
+	// 兩步驟驗證
+	TwoFactorMethod string     `gorm:"column:two_factor_method;type:TEXT;default:'email';" json:"two_factor_method,omitempty"` // email/totp
+	TOTPSecret      string     `gorm:"column:totp_secret;type:TEXT;" json:"-"`                                                 // 已加密
+	TOTPEnabledAt   *time.Time `gorm:"column:totp_enabled_at;type:TIMESTAMP;" json:"totp_enabled_at,omitempty"`
+	TOTPLastStep    int64      `gorm:"column:totp_last_step;type:BIGINT;" json:"-"` // 防止驗證碼重複使用
+
+	// 密碼管理
+	MustChangePassword bool       `gorm:"column:must_change_password;type:BOOLEAN;" json:"must_change_password,omitempty"` // 下次登入需變更密碼
+	PasswordChangedAt  *time.Time `gorm:"column:password_changed_at;type:TIMESTAMP;" json:"password_changed_at,omitempty"`
+
+	// 帳號鎖定
+	FailedLoginCount int        `gorm:"column:failed_login_count;type:INTEGER;default:0;" json:"failed_login_count"` // 連續登入失敗次數
+	LockedUntil      *time.Time `gorm:"column:locked_until;type:TIMESTAMP;" json:"locked_until,omitempty"`
+	LastLoginAt      *time.Time `gorm:"column:last_login_at;type:TIMESTAMP;" json:"last_login_at,omitempty"`
+
+	// 外部登入
+	AuthProvider string  `gorm:"column:auth_provider;type:TEXT;default:'local';" json:"auth_provider,omitempty"` // local/oidc/ldap
+	OIDCSubject  *string `gorm:"column:oidc_subject;type:TEXT;" json:"-"`
+
+	// 所屬團隊(審核規則可依團隊套用)
+	Team *string `gorm:"column:team;type:TEXT;" json:"team,omitempty"`
 }
 
 // Base 基礎結構
@@ -26,6 +48,21 @@ type Base struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at,omitempty"`
 	Token     string     `json:"token,omitempty"` // 登入時返回 token
+	// 登入時完成驗證器設定才回傳的復原碼(僅顯示一次)
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+
+	// 兩步驟驗證方式 (email/totp)
+	TwoFactorMethod string `json:"two_factor_method,omitempty"`
+	// 需先變更密碼才能使用其他功能
+	MustChangePassword bool `json:"must_change_password,omitempty"`
+	// 帳號鎖定至(未鎖定時為空)
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	// 最後登入時間
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	// 登入來源 (local/oidc/ldap)
+	AuthProvider string `json:"auth_provider,omitempty"`
+	// 所屬團隊
+	Team *string `json:"team,omitempty"`
 }
 
 // Created 建立用戶
@@ -34,12 +71,56 @@ type Created struct {
 	Email    string `json:"email" binding:"required,email" validate:"required,email"` // 新增 email 欄位
 	Password string `json:"password" binding:"required" validate:"required"`
 	Role     string `json:"role,omitempty"` // 預設為 user
+
+	// 所屬團隊
+	Team string `json:"team,omitempty"`
 }
 
 // Login 登入
 type Login struct {
 	Username string `json:"username" binding:"required" validate:"required"`
 	Password string `json:"password" binding:"required" validate:"required"`
+
+	// 來源 IP,由 presenter 填入
+	IP string `json:"-"`
+}
+
+// LoginChallenge 需要第二因子驗證時回傳的登入票證
+type LoginChallenge struct {
+	// 第二因子驗證方式 (email/totp)
+	Method string `json:"method"`
+	// 短期登入票證,驗證時連同驗證碼送出
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// 驗證碼寄送的信箱(已遮罩)
+	Email   string `json:"email,omitempty"`
+	Message string `json:"message"`
+	// 登入政策要求驗證器 App 但尚未設定,需以 email 驗證碼與新驗證器的驗證碼一併完成設定才能登入
+	TOTPSetupRequired bool `json:"totp_setup_required,omitempty"`
+	// 需完成設定時附上的 TOTP 金鑰
+	TOTPSetup *TOTPSetup `json:"totp_setup,omitempty"`
+}
+
+// LoginVerified 以登入票證與驗證碼完成登入
+type LoginVerified struct {
+	Ticket string `json:"ticket" binding:"required"`
+	Code   string `json:"code" binding:"required"` // email 驗證碼、TOTP 驗證碼或復原碼
+	// 強制設定驗證器時,新驗證器 App 顯示的驗證碼
+	TOTPCode string `json:"totp_code,omitempty" binding:"omitempty,len=6"`
+
+	// 來源 IP,由 presenter 填入
+	IP string `json:"-"`
+}
+
+// ExternalIdentity 外部身分提供者驗證後的使用者資訊
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	// 由群組對應出的角色
+	Role string
 }
 
 // Field 查詢條件
@@ -64,6 +145,17 @@ type List struct {
 		Role      string     `json:"role,omitempty"`
 		CreatedAt time.Time  `json:"created_at"`
 		UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+		// 兩步驟驗證方式 (email/totp)
+		TwoFactorMethod string `json:"two_factor_method,omitempty"`
+		// 帳號鎖定至(未鎖定時為空)
+		LockedUntil *time.Time `json:"locked_until,omitempty"`
+		// 最後登入時間
+		LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+		// 登入來源 (local/oidc/ldap)
+		AuthProvider string `json:"auth_provider,omitempty"`
+		// 所屬團隊
+		Team *string `json:"team,omitempty"`
 	} `json:"users"`
 	model.OutPage
 }
@@ -75,6 +167,64 @@ type Updated struct {
 	Email    string `json:"email,omitempty"` // 新增 email 欄位
 	Password string `json:"password,omitempty"`
 	Role     string `json:"role,omitempty"`
+
+	// 登入來源,設為 local 可讓帳號改用本地密碼(例如緊急管理員帳號)
+	AuthProvider string `json:"auth_provider,omitempty" binding:"omitempty,oneof=local ldap"`
+
+	// 所屬團隊
+	Team string `json:"team,omitempty"`
+}
+
+// PasswordChanged 變更自己的密碼
+type PasswordChanged struct {
+	CurrentPassword string `json:"current_password" binding:"required" validate:"required"`
+	NewPassword     string `json:"new_password" binding:"required" validate:"required"`
+}
+
+// PasswordForgot 忘記密碼
+type PasswordForgot struct {
+	Email string `json:"email" binding:"required,email" validate:"required,email"`
+}
+
+// PasswordReset 以重設連結中的 token 設定新密碼
+type PasswordReset struct {
+	Token       string `json:"token" binding:"required" validate:"required"`
+	NewPassword string `json:"new_password" binding:"required" validate:"required"`
+}
+
+// PasswordResetRequest 建立重設密碼 token 後的資料(供寄送郵件)
+type PasswordResetRequest struct {
+	User      *Base
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TOTPSetup 啟用 TOTP 時回傳的金鑰資訊
+type TOTPSetup struct {
+	Secret     string `json:"secret"`      // 供無法掃描 QR Code 時手動輸入
+	OTPAuthURL string `json:"otpauth_url"` // 前端轉成 QR Code
+}
+
+// TOTPConfirm 確認 TOTP 綁定
+type TOTPConfirm struct {
+	Code string `json:"code" binding:"required,len=6" validate:"required,len=6"`
+}
+
+// TOTPDisable 停用 TOTP
+type TOTPDisable struct {
+	Password string `json:"password" binding:"required" validate:"required"`
+	Code     string `json:"code" binding:"required" validate:"required"` // 驗證器驗證碼或復原碼
+}
+
+// RecoveryCodes 復原碼(僅於產生時顯示一次)
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// FailedLogin 累計登入失敗後的次數與鎖定時間
+type FailedLogin struct {
+	FailedLoginCount int        `gorm:"column:failed_login_count"`
+	LockedUntil      *time.Time `gorm:"column:locked_until"`
 }
 
 // TableName 設定資料表名稱
@@ -5,12 +5,28 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"esst_sendEmail/internal/pkg/auth"
+	"esst_sendEmail/internal/pkg/storage"
+	"esst_sendEmail/internal/v1/job"
 	"esst_sendEmail/internal/v1/middleware"
+	"esst_sendEmail/internal/v1/router/api_key"
+	"esst_sendEmail/internal/v1/router/approval_rule"
+	"esst_sendEmail/internal/v1/router/attachment"
+	"esst_sendEmail/internal/v1/router/comment"
 	"esst_sendEmail/internal/v1/router/equipment"
+	"esst_sendEmail/internal/v1/router/inventory"
+	"esst_sendEmail/internal/v1/router/part"
 	"esst_sendEmail/internal/v1/router/project"
+	"esst_sendEmail/internal/v1/router/project_approval"
+	"esst_sendEmail/internal/v1/router/project_conflict"
+	"esst_sendEmail/internal/v1/router/project_extension"
+	"esst_sendEmail/internal/v1/router/quotation"
+	"esst_sendEmail/internal/v1/router/role_policy"
 	"esst_sendEmail/internal/v1/router/stock"
+	"esst_sendEmail/internal/v1/router/stock_allocation"
 	"esst_sendEmail/internal/v1/router/stock_equipment"
 	"esst_sendEmail/internal/v1/router/user"
 	userModel "esst_sendEmail/internal/v1/structure/users"
@@ -30,7 +46,16 @@ func main() {
 	}
 
 	// 驗證必要的環境變數
-	requiredEnvs := []string{"DB_HOST", "DB_USER", "DB_PASSWORD", "DB_NAME", "JWT_SECRET"}
+	requiredEnvs := []string{"DB_HOST", "DB_USER", "DB_PASSWORD", "DB_NAME"}
+	if auth.Algorithm() == auth.AlgorithmHS256 {
+		requiredEnvs = append(requiredEnvs, "JWT_SECRET")
+	} else {
+		requiredEnvs = append(requiredEnvs, "JWT_KEYSET_FILE")
+	}
+	// 本機儲存的下載網址不需 JWT,以專用金鑰簽章
+	if storage.Driver() == storage.DriverLocal {
+		requiredEnvs = append(requiredEnvs, "STORAGE_SIGNING_KEY")
+	}
 	for _, env := range requiredEnvs {
 		if os.Getenv(env) == "" {
 			log.Fatalf("❌ Required environment variable %s is not set", env)
@@ -59,6 +84,12 @@ func main() {
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
+	// 設定信任的反向代理,只有來自這些位址的 X-Forwarded-For 才會採用為用戶端 IP
+	// 未設定時不信任任何代理,避免偽造標頭繞過 IP 頻率限制與登入失敗紀錄
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+
 	// 設定 CORS
 	router.Use(middleware.CORSMiddleware())
 
@@ -78,6 +109,45 @@ func main() {
 	// 5. 現貨設備管理路由(需要 JWT 驗證)
 	router = stock_equipment.GetRoute(router, db)
 
+	// 6. 角色安全政策路由(需要管理員權限)
+	router = role_policy.GetRoute(router, db)
+
+	// 7. API 金鑰路由(需要 JWT 驗證)
+	router = api_key.GetRoute(router, db)
+
+	// 8. 重複報備審核路由(需要管理員權限)
+	router = project_conflict.GetRoute(router, db)
+
+	// 9. 專案保護期延長申請路由(申請需要 JWT 驗證,審核需要管理員權限)
+	router = project_extension.GetRoute(router, db)
+
+	// 10. 專案報備審核路由(需要 JWT 驗證,角色權限於審核時檢查)
+	router = project_approval.GetRoute(router, db)
+
+	// 11. 審核規則路由(需要管理員權限)
+	router = approval_rule.GetRoute(router, db)
+
+	// 12. 專案與現貨留言路由(需要 JWT 驗證)
+	router = comment.GetRoute(router, db)
+
+	// 13. 專案與現貨附件路由(需要 JWT 驗證,本機儲存的下載網址以簽章驗證)
+	router = attachment.GetRoute(router, db)
+
+	// 14. 料號目錄路由(需要 JWT 驗證,維護需要管理員權限)
+	router = part.GetRoute(router, db)
+
+	// 15. 現貨庫存路由(需要 JWT 驗證,登錄異動需要管理員權限)
+	router = inventory.GetRoute(router, db)
+
+	// 16. 專案現貨分配路由(需要 API 金鑰 / JWT 驗證)
+	router = stock_allocation.GetRoute(router, db)
+
+	// 17. 專案報價路由(需要 API 金鑰 / JWT 驗證,金額只通知主管群組)
+	router = quotation.GetRoute(router, db)
+
+	// 啟動背景排程(資源回收筒清除等)
+	job.Start(db)
+
 	// 啟動服務器
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -88,6 +158,17 @@ func main() {
 	log.Fatal(http.ListenAndServe(":"+port, router))
 }
 
+// trustedProxies 由 TRUSTED_PROXIES 讀取信任的代理 IP 或 CIDR,以逗號分隔
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 // initDefaultAdmin 檢查並建立預設管理員帳號
 func initDefaultAdmin(db *gorm.DB) {
 	var count int64
@@ -108,6 +189,10 @@ func initDefaultAdmin(db *gorm.DB) {
 			Password:  string(hashedPassword),
 			Role:      "admin",
 			CreatedAt: time.Now(),
+
+			TwoFactorMethod: "email",
+			// 首次登入需變更預設密碼
+			MustChangePassword: true,
 		}
 
 		if err := db.Create(adminUser).Error; err != nil {
@@ -118,6 +203,6 @@ func initDefaultAdmin(db *gorm.DB) {
 		log.Println("⚠️  帳號: admin")
 		log.Println("⚠️  密碼: admin123")
 		log.Println("⚠️  信箱: skes1114@gmail.com")
-		log.Println("⚠️  請立即登入並修改密碼與信箱!")
+		log.Println("⚠️  首次登入後須先修改密碼,並請更新信箱!")
 	}
 }
@@ -0,0 +1,16 @@
+-- 回滾 migration 檔案
+-- 移除兩步驟驗證相關欄位與資料表
+
+-- 刪除索引
+DROP INDEX IF EXISTS idx_user_recovery_codes_user_id;
+
+-- 刪除資料表
+DROP TABLE IF EXISTS role_policies;
+DROP TABLE IF EXISTS user_recovery_codes;
+
+-- 移除欄位
+ALTER TABLE users
+DROP COLUMN IF EXISTS totp_last_step,
+DROP COLUMN IF EXISTS totp_enabled_at,
+DROP COLUMN IF EXISTS totp_secret,
+DROP COLUMN IF EXISTS two_factor_method;
@@ -0,0 +1,47 @@
+-- 使用者兩步驟驗證設定
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS two_factor_method TEXT NOT NULL DEFAULT 'email',
+ADD COLUMN IF NOT EXISTS totp_secret TEXT,
+ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+-- 復原碼表
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    rc_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,                     -- 使用者編號(必填,外鍵)
+    code_hash TEXT NOT NULL,                   -- 復原碼雜湊(SHA-256)
+    used_at TIMESTAMP,                         -- 使用時間(未使用為 NULL)
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT fk_recovery_code_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- 角色安全政策表
+CREATE TABLE IF NOT EXISTS role_policies (
+    role TEXT PRIMARY KEY,
+    require_two_factor BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMP
+);
+
+-- 建立索引
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);
+
+-- 新增註解
+COMMENT ON COLUMN users.two_factor_method IS '兩步驟驗證方式 (email/totp)';
+COMMENT ON COLUMN users.totp_secret IS 'TOTP 金鑰(已加密)';
+COMMENT ON COLUMN users.totp_enabled_at IS 'TOTP 啟用時間';
+COMMENT ON COLUMN users.totp_last_step IS '最後一次使用的 TOTP 時間區間(防止重複使用)';
+
+COMMENT ON TABLE user_recovery_codes IS '兩步驟驗證復原碼表';
+COMMENT ON COLUMN user_recovery_codes.rc_id IS '復原碼編號(UUID)';
+COMMENT ON COLUMN user_recovery_codes.user_id IS '使用者編號(外鍵)';
+COMMENT ON COLUMN user_recovery_codes.code_hash IS '復原碼雜湊';
+COMMENT ON COLUMN user_recovery_codes.used_at IS '使用時間';
+COMMENT ON COLUMN user_recovery_codes.created_at IS '建立時間';
+
+COMMENT ON TABLE role_policies IS '角色安全政策表';
+COMMENT ON COLUMN role_policies.role IS '角色 (admin/user)';
+COMMENT ON COLUMN role_policies.require_two_factor IS '是否強制兩步驟驗證';
+COMMENT ON COLUMN role_policies.updated_at IS '更新時間';
@@ -0,0 +1,13 @@
+-- 回滾 migration 檔案
+-- 移除密碼管理相關欄位與資料表
+
+-- 刪除索引
+DROP INDEX IF EXISTS idx_password_reset_tokens_user_id;
+
+-- 刪除資料表
+DROP TABLE IF EXISTS password_reset_tokens;
+
+-- 移除欄位
+ALTER TABLE users
+DROP COLUMN IF EXISTS password_changed_at,
+DROP COLUMN IF EXISTS must_change_password;
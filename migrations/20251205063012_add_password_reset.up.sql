@@ -0,0 +1,36 @@
+-- 使用者密碼管理欄位
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP;
+
+-- 預設管理員帳號首次登入需變更密碼
+UPDATE users
+SET must_change_password = true
+WHERE username = 'admin' AND password_changed_at IS NULL;
+
+-- 重設密碼 token 表
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    prt_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,                     -- 使用者編號(必填,外鍵)
+    expires_at TIMESTAMP NOT NULL,             -- 到期時間
+    used_at TIMESTAMP,                         -- 使用時間(未使用為 NULL)
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT fk_password_reset_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- 建立索引
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+
+-- 新增註解
+COMMENT ON COLUMN users.must_change_password IS '下次登入是否需變更密碼';
+COMMENT ON COLUMN users.password_changed_at IS '密碼最後變更時間';
+
+COMMENT ON TABLE password_reset_tokens IS '重設密碼 token 表';
+COMMENT ON COLUMN password_reset_tokens.prt_id IS 'Token 編號(UUID,對應簽章 token 的 jti)';
+COMMENT ON COLUMN password_reset_tokens.user_id IS '使用者編號(外鍵)';
+COMMENT ON COLUMN password_reset_tokens.expires_at IS '到期時間';
+COMMENT ON COLUMN password_reset_tokens.used_at IS '使用時間';
+COMMENT ON COLUMN password_reset_tokens.created_at IS '建立時間';
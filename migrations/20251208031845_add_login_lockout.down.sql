@@ -0,0 +1,15 @@
+-- 回滾 migration 檔案
+-- 移除帳號鎖定相關欄位與資料表
+
+-- 刪除索引
+DROP INDEX IF EXISTS idx_login_attempts_username_created_at;
+DROP INDEX IF EXISTS idx_login_attempts_ip_created_at;
+
+-- 刪除資料表
+DROP TABLE IF EXISTS login_attempts;
+
+-- 移除欄位
+ALTER TABLE users
+DROP COLUMN IF EXISTS last_login_at,
+DROP COLUMN IF EXISTS locked_until,
+DROP COLUMN IF EXISTS failed_login_count;
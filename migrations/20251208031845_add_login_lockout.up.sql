@@ -0,0 +1,32 @@
+-- 帳號鎖定欄位
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS failed_login_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP,
+ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP;
+
+-- 登入嘗試紀錄表
+CREATE TABLE IF NOT EXISTS login_attempts (
+    la_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    username TEXT NOT NULL,                    -- 嘗試登入的帳號
+    user_id UUID,                              -- 使用者編號(帳號不存在時為 NULL)
+    ip TEXT NOT NULL,                          -- 來源 IP
+    success BOOLEAN NOT NULL DEFAULT false,    -- 是否成功
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip_created_at ON login_attempts(ip, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_username_created_at ON login_attempts(username, created_at DESC);
+
+-- 新增註解
+COMMENT ON COLUMN users.failed_login_count IS '連續登入失敗次數';
+COMMENT ON COLUMN users.locked_until IS '帳號鎖定至';
+COMMENT ON COLUMN users.last_login_at IS '最後登入時間';
+
+COMMENT ON TABLE login_attempts IS '登入嘗試紀錄表';
+COMMENT ON COLUMN login_attempts.la_id IS '紀錄編號(UUID)';
+COMMENT ON COLUMN login_attempts.username IS '嘗試登入的帳號';
+COMMENT ON COLUMN login_attempts.user_id IS '使用者編號';
+COMMENT ON COLUMN login_attempts.ip IS '來源 IP';
+COMMENT ON COLUMN login_attempts.success IS '是否成功';
+COMMENT ON COLUMN login_attempts.created_at IS '嘗試時間';
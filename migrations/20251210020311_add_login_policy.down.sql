@@ -0,0 +1,5 @@
+-- 回滾 migration 檔案
+-- 移除角色登入方式
+
+ALTER TABLE role_policies
+DROP COLUMN IF EXISTS login_policy;
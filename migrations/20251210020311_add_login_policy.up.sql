@@ -0,0 +1,6 @@
+-- 角色登入方式
+ALTER TABLE role_policies
+ADD COLUMN IF NOT EXISTS login_policy TEXT;
+
+-- 新增註解
+COMMENT ON COLUMN role_policies.login_policy IS '登入方式 (password/email/totp),空值表示依系統預設';
@@ -0,0 +1,8 @@
+-- 回滾 migration 檔案
+-- 移除外部登入欄位
+
+DROP INDEX IF EXISTS idx_users_oidc_subject;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS oidc_subject,
+DROP COLUMN IF EXISTS auth_provider;
@@ -0,0 +1,11 @@
+-- 外部登入(OIDC 單一登入)欄位
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS auth_provider TEXT NOT NULL DEFAULT 'local',
+ADD COLUMN IF NOT EXISTS oidc_subject TEXT;
+
+-- 同一身分提供者的 subject 只能對應一個使用者
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_oidc_subject ON users(oidc_subject) WHERE oidc_subject IS NOT NULL;
+
+-- 新增註解
+COMMENT ON COLUMN users.auth_provider IS '登入來源 (local/oidc)';
+COMMENT ON COLUMN users.oidc_subject IS 'OIDC 身分提供者的使用者識別碼 (sub)';
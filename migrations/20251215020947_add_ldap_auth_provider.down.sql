@@ -0,0 +1,6 @@
+-- 回滾 migration 檔案
+-- LDAP 帳號改回本地帳號(需由管理員重設密碼)
+
+UPDATE users SET auth_provider = 'local' WHERE auth_provider = 'ldap';
+
+COMMENT ON COLUMN users.auth_provider IS '登入來源 (local/oidc)';
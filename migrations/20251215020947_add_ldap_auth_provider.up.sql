@@ -0,0 +1,4 @@
+-- LDAP / Active Directory 登入來源
+-- auth_provider 新增 ldap 選項,既有帳號維持 local
+
+COMMENT ON COLUMN users.auth_provider IS '登入來源 (local/oidc/ldap)';
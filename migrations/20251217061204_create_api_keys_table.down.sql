@@ -0,0 +1,6 @@
+-- 回滾 migration 檔案
+-- 刪除 API 金鑰資料表
+
+DROP INDEX IF EXISTS idx_api_keys_user_id;
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,35 @@
+-- 建立 API 金鑰資料表
+CREATE TABLE IF NOT EXISTS api_keys (
+    ak_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,                     -- 擁有者
+    name TEXT NOT NULL,                        -- 金鑰名稱(用途說明)
+    prefix TEXT NOT NULL UNIQUE,               -- 公開前綴,用於查詢金鑰
+    secret_hash TEXT NOT NULL,                 -- 密鑰雜湊(SHA-256)
+    scopes TEXT NOT NULL DEFAULT '',           -- 授權範圍(以空白分隔)
+    expires_at TIMESTAMP,                      -- 到期時間(NULL 表示不過期)
+    last_used_at TIMESTAMP,                    -- 最後使用時間
+    last_used_ip TEXT,                         -- 最後使用 IP
+    revoked_at TIMESTAMP,                      -- 撤銷時間
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT fk_api_key_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+
+-- 新增註解
+COMMENT ON TABLE api_keys IS 'API 金鑰表';
+COMMENT ON COLUMN api_keys.ak_id IS '金鑰編號(UUID)';
+COMMENT ON COLUMN api_keys.user_id IS '擁有者使用者編號';
+COMMENT ON COLUMN api_keys.name IS '金鑰名稱';
+COMMENT ON COLUMN api_keys.prefix IS '公開前綴';
+COMMENT ON COLUMN api_keys.secret_hash IS '密鑰雜湊(SHA-256)';
+COMMENT ON COLUMN api_keys.scopes IS '授權範圍(以空白分隔)';
+COMMENT ON COLUMN api_keys.expires_at IS '到期時間';
+COMMENT ON COLUMN api_keys.last_used_at IS '最後使用時間';
+COMMENT ON COLUMN api_keys.last_used_ip IS '最後使用 IP';
+COMMENT ON COLUMN api_keys.revoked_at IS '撤銷時間';
+COMMENT ON COLUMN api_keys.created_at IS '建立時間';
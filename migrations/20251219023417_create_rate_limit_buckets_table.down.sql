@@ -0,0 +1,6 @@
+-- 回滾 migration 檔案
+-- 刪除請求頻率限制資料表
+
+DROP INDEX IF EXISTS idx_rate_limit_buckets_full_at;
+
+DROP TABLE IF EXISTS rate_limit_buckets;
@@ -0,0 +1,17 @@
+-- 建立請求頻率限制資料表(RATE_LIMIT_STORE=postgres 時使用)
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    bucket_key TEXT PRIMARY KEY,               -- 限制規則 + IP / 使用者 / 路由
+    tokens DOUBLE PRECISION NOT NULL,          -- 剩餘可用次數
+    updated_at TIMESTAMP NOT NULL,             -- 最後更新時間
+    full_at TIMESTAMP NOT NULL                 -- 補滿的時間,之後可清除
+);
+
+-- 建立索引以提升清除效能
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_full_at ON rate_limit_buckets(full_at);
+
+-- 新增註解
+COMMENT ON TABLE rate_limit_buckets IS '請求頻率限制表(token bucket)';
+COMMENT ON COLUMN rate_limit_buckets.bucket_key IS '限制鍵值';
+COMMENT ON COLUMN rate_limit_buckets.tokens IS '剩餘可用次數';
+COMMENT ON COLUMN rate_limit_buckets.updated_at IS '最後更新時間';
+COMMENT ON COLUMN rate_limit_buckets.full_at IS '補滿時間';
@@ -0,0 +1,28 @@
+-- 回滾 migration 檔案
+-- 移除軟刪除欄位(已在資源回收筒中的資料會一併刪除)
+
+DELETE FROM equipments WHERE deleted_at IS NOT NULL;
+DELETE FROM projects WHERE deleted_at IS NOT NULL;
+DELETE FROM stock_equipments WHERE deleted_at IS NOT NULL;
+DELETE FROM stocks WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_projects_deleted_at;
+DROP INDEX IF EXISTS idx_equipments_deleted_at;
+DROP INDEX IF EXISTS idx_stocks_deleted_at;
+DROP INDEX IF EXISTS idx_stock_equipments_deleted_at;
+
+ALTER TABLE projects
+DROP COLUMN IF EXISTS deleted_at,
+DROP COLUMN IF EXISTS deleted_by;
+
+ALTER TABLE equipments
+DROP COLUMN IF EXISTS deleted_at,
+DROP COLUMN IF EXISTS deleted_by;
+
+ALTER TABLE stocks
+DROP COLUMN IF EXISTS deleted_at,
+DROP COLUMN IF EXISTS deleted_by;
+
+ALTER TABLE stock_equipments
+DROP COLUMN IF EXISTS deleted_at,
+DROP COLUMN IF EXISTS deleted_by;
@@ -0,0 +1,33 @@
+-- 軟刪除欄位
+-- 刪除後資料保留於資源回收筒,超過保留期限才由排程永久刪除
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS deleted_by UUID;
+
+ALTER TABLE equipments
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS deleted_by UUID;
+
+ALTER TABLE stocks
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS deleted_by UUID;
+
+ALTER TABLE stock_equipments
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS deleted_by UUID;
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_equipments_deleted_at ON equipments(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_stocks_deleted_at ON stocks(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_stock_equipments_deleted_at ON stock_equipments(deleted_at);
+
+-- 新增註解
+COMMENT ON COLUMN projects.deleted_at IS '刪除時間(NULL 表示未刪除)';
+COMMENT ON COLUMN projects.deleted_by IS '刪除者使用者編號';
+COMMENT ON COLUMN equipments.deleted_at IS '刪除時間(NULL 表示未刪除)';
+COMMENT ON COLUMN equipments.deleted_by IS '刪除者使用者編號';
+COMMENT ON COLUMN stocks.deleted_at IS '刪除時間(NULL 表示未刪除)';
+COMMENT ON COLUMN stocks.deleted_by IS '刪除者使用者編號';
+COMMENT ON COLUMN stock_equipments.deleted_at IS '刪除時間(NULL 表示未刪除)';
+COMMENT ON COLUMN stock_equipments.deleted_by IS '刪除者使用者編號';
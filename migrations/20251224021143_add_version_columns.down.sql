@@ -0,0 +1,7 @@
+-- 回滾 migration 檔案
+-- 移除版本號欄位
+
+ALTER TABLE projects DROP COLUMN IF EXISTS version;
+ALTER TABLE equipments DROP COLUMN IF EXISTS version;
+ALTER TABLE stocks DROP COLUMN IF EXISTS version;
+ALTER TABLE stock_equipments DROP COLUMN IF EXISTS version;
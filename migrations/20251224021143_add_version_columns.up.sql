@@ -0,0 +1,19 @@
+-- 版本號欄位(樂觀鎖)
+-- 每次更新、刪除或還原時加 1,GetByID 以 ETag 回傳,PATCH / DELETE 需以 If-Match 帶入
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE equipments
+ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE stocks
+ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE stock_equipments
+ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+-- 新增註解
+COMMENT ON COLUMN projects.version IS '版本號(樂觀鎖)';
+COMMENT ON COLUMN equipments.version IS '版本號(樂觀鎖)';
+COMMENT ON COLUMN stocks.version IS '版本號(樂觀鎖)';
+COMMENT ON COLUMN stock_equipments.version IS '版本號(樂觀鎖)';
@@ -0,0 +1,6 @@
+-- 回滾 migration 檔案
+-- 刪除 Idempotency-Key 紀錄表
+
+DROP INDEX IF EXISTS idx_idempotency_keys_expires_at;
+
+DROP TABLE IF EXISTS idempotency_keys;
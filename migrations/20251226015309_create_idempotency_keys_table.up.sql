@@ -0,0 +1,37 @@
+-- 建立 Idempotency-Key 紀錄表
+-- 保存建立類請求的內容雜湊與回應,用戶端重送時直接回傳原始回應,避免重複建立資料與發送通知
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    ik_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,                     -- 發送請求的使用者
+    idempotency_key TEXT NOT NULL,             -- 用戶端帶入的 Idempotency-Key
+    method TEXT NOT NULL,                      -- 請求方法
+    path TEXT NOT NULL,                        -- 請求路徑
+    request_hash TEXT NOT NULL,                -- 請求內容雜湊(SHA-256)
+    status_code INTEGER,                       -- 回應狀態碼(NULL 表示處理中)
+    response_body TEXT,                        -- 回應內容
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    completed_at TIMESTAMP,                    -- 完成時間
+    expires_at TIMESTAMP NOT NULL,             -- 到期時間
+    CONSTRAINT uq_idempotency_keys_user_key UNIQUE (user_id, idempotency_key),
+    CONSTRAINT fk_idempotency_key_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- 建立索引以提升清除效能
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+-- 新增註解
+COMMENT ON TABLE idempotency_keys IS 'Idempotency-Key 紀錄表';
+COMMENT ON COLUMN idempotency_keys.ik_id IS '紀錄編號(UUID)';
+COMMENT ON COLUMN idempotency_keys.user_id IS '發送請求的使用者編號';
+COMMENT ON COLUMN idempotency_keys.idempotency_key IS '用戶端帶入的 Idempotency-Key';
+COMMENT ON COLUMN idempotency_keys.method IS '請求方法';
+COMMENT ON COLUMN idempotency_keys.path IS '請求路徑';
+COMMENT ON COLUMN idempotency_keys.request_hash IS '請求內容雜湊(SHA-256)';
+COMMENT ON COLUMN idempotency_keys.status_code IS '回應狀態碼(NULL 表示處理中)';
+COMMENT ON COLUMN idempotency_keys.response_body IS '回應內容';
+COMMENT ON COLUMN idempotency_keys.created_at IS '建立時間';
+COMMENT ON COLUMN idempotency_keys.completed_at IS '完成時間';
+COMMENT ON COLUMN idempotency_keys.expires_at IS '到期時間';
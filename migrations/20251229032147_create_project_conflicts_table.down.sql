@@ -0,0 +1,7 @@
+-- 回滾 migration 檔案
+-- 刪除重複報備紀錄表
+
+DROP INDEX IF EXISTS idx_project_conflicts_conflict_p_id;
+DROP INDEX IF EXISTS idx_project_conflicts_status;
+
+DROP TABLE IF EXISTS project_conflicts;
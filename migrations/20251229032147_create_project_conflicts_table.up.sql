@@ -0,0 +1,40 @@
+-- 建立重複報備紀錄表
+-- 建立專案時若與既有專案的名稱相似、聯絡人相同或料號重疊,記錄於此供管理員審核
+CREATE TABLE IF NOT EXISTS project_conflicts (
+    pc_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    p_id UUID NOT NULL,                        -- 新建立的專案
+    conflict_p_id UUID NOT NULL,               -- 可能重複的既有專案
+    reasons TEXT NOT NULL DEFAULT '',          -- 判定原因(以空白分隔)
+    similarity NUMERIC(4, 3) NOT NULL DEFAULT 0, -- 名稱相似度
+    status TEXT NOT NULL DEFAULT 'pending',    -- 審核狀態(pending / confirmed / dismissed)
+    reviewed_by UUID,                          -- 審核者
+    reviewed_at TIMESTAMP,                     -- 審核時間
+    review_note TEXT,                          -- 審核說明
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT uq_project_conflicts_pair UNIQUE (p_id, conflict_p_id),
+    CONSTRAINT fk_project_conflict_project
+        FOREIGN KEY (p_id)
+        REFERENCES projects(p_id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_project_conflict_conflict_project
+        FOREIGN KEY (conflict_p_id)
+        REFERENCES projects(p_id)
+        ON DELETE CASCADE
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_project_conflicts_status ON project_conflicts(status);
+CREATE INDEX IF NOT EXISTS idx_project_conflicts_conflict_p_id ON project_conflicts(conflict_p_id);
+
+-- 新增註解
+COMMENT ON TABLE project_conflicts IS '重複報備紀錄表';
+COMMENT ON COLUMN project_conflicts.pc_id IS '紀錄編號(UUID)';
+COMMENT ON COLUMN project_conflicts.p_id IS '新建立的專案編號';
+COMMENT ON COLUMN project_conflicts.conflict_p_id IS '可能重複的既有專案編號';
+COMMENT ON COLUMN project_conflicts.reasons IS '判定原因(name / contact_email / contact_phone / part_numbers,以空白分隔)';
+COMMENT ON COLUMN project_conflicts.similarity IS '名稱相似度(0~1)';
+COMMENT ON COLUMN project_conflicts.status IS '審核狀態(pending: 待審核, confirmed: 確認重複, dismissed: 非重複)';
+COMMENT ON COLUMN project_conflicts.reviewed_by IS '審核者使用者編號';
+COMMENT ON COLUMN project_conflicts.reviewed_at IS '審核時間';
+COMMENT ON COLUMN project_conflicts.review_note IS '審核說明';
+COMMENT ON COLUMN project_conflicts.created_at IS '建立時間';
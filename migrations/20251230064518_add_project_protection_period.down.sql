@@ -0,0 +1,19 @@
+-- 回滾 migration 檔案
+-- 刪除保護期延長申請資料表與保護期欄位
+
+DROP INDEX IF EXISTS idx_project_extensions_status;
+DROP INDEX IF EXISTS uq_project_extensions_pending;
+
+DROP TABLE IF EXISTS project_extensions;
+
+-- 已過期的專案回復為第一階段
+UPDATE projects SET status = 'step1' WHERE status = 'expired';
+
+DROP INDEX IF EXISTS idx_projects_status_expires_at;
+
+ALTER TABLE projects
+DROP COLUMN IF EXISTS created_by,
+DROP COLUMN IF EXISTS expiry_warned_at,
+DROP COLUMN IF EXISTS expires_at;
+
+COMMENT ON COLUMN projects.status IS '專案狀態 (step1: 第一階段, step2: 第二階段, completed: 已完成)';
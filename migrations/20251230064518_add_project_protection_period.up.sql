@@ -0,0 +1,59 @@
+-- 專案保護期
+-- 第一階段報備在保護期內受保護,逾期未進入第二階段由排程轉為 expired,可申請延長並由管理員核准
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS expiry_warned_at TIMESTAMP,
+ADD COLUMN IF NOT EXISTS created_by UUID;
+
+-- 既有的第一階段專案以建立時間起算 90 天,但至少從上線時起算 90 天
+-- 避免上線後第一次排程就將所有舊專案轉為 expired 並逐筆發送到期通知
+UPDATE projects
+SET expires_at = GREATEST(created_time, now() AT TIME ZONE 'UTC') + INTERVAL '90 days'
+WHERE expires_at IS NULL AND status = 'step1';
+
+-- 建立索引以提升排程查詢效能
+CREATE INDEX IF NOT EXISTS idx_projects_status_expires_at ON projects(status, expires_at);
+
+-- 建立保護期延長申請資料表
+CREATE TABLE IF NOT EXISTS project_extensions (
+    pe_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    p_id UUID NOT NULL,                        -- 專案編號
+    requested_by UUID NOT NULL,                -- 申請者
+    days INTEGER NOT NULL,                     -- 申請延長天數
+    reason TEXT NOT NULL,                      -- 申請原因
+    status TEXT NOT NULL DEFAULT 'pending',    -- 審核狀態(pending / approved / rejected)
+    reviewed_by UUID,                          -- 審核者
+    reviewed_at TIMESTAMP,                     -- 審核時間
+    review_note TEXT,                          -- 審核說明
+    previous_expires_at TIMESTAMP,             -- 核准前的到期時間
+    new_expires_at TIMESTAMP,                  -- 核准後的到期時間
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT fk_project_extension_project
+        FOREIGN KEY (p_id)
+        REFERENCES projects(p_id)
+        ON DELETE CASCADE
+);
+
+-- 每個專案同時只能有一筆待審核的申請
+CREATE UNIQUE INDEX IF NOT EXISTS uq_project_extensions_pending ON project_extensions(p_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_project_extensions_status ON project_extensions(status);
+
+-- 新增註解
+COMMENT ON COLUMN projects.status IS '專案狀態 (step1: 第一階段, step2: 第二階段, completed: 已完成, expired: 保護期已過)';
+COMMENT ON COLUMN projects.expires_at IS '保護期到期時間';
+COMMENT ON COLUMN projects.expiry_warned_at IS '到期前提醒發送時間';
+COMMENT ON COLUMN projects.created_by IS '建立者使用者編號';
+
+COMMENT ON TABLE project_extensions IS '專案保護期延長申請表';
+COMMENT ON COLUMN project_extensions.pe_id IS '申請編號(UUID)';
+COMMENT ON COLUMN project_extensions.p_id IS '專案編號';
+COMMENT ON COLUMN project_extensions.requested_by IS '申請者使用者編號';
+COMMENT ON COLUMN project_extensions.days IS '申請延長天數';
+COMMENT ON COLUMN project_extensions.reason IS '申請原因';
+COMMENT ON COLUMN project_extensions.status IS '審核狀態(pending: 待審核, approved: 已核准, rejected: 已駁回)';
+COMMENT ON COLUMN project_extensions.reviewed_by IS '審核者使用者編號';
+COMMENT ON COLUMN project_extensions.reviewed_at IS '審核時間';
+COMMENT ON COLUMN project_extensions.review_note IS '審核說明';
+COMMENT ON COLUMN project_extensions.previous_expires_at IS '核准前的到期時間';
+COMMENT ON COLUMN project_extensions.new_expires_at IS '核准後的到期時間';
+COMMENT ON COLUMN project_extensions.created_at IS '申請時間';
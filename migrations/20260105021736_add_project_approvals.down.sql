@@ -0,0 +1,17 @@
+-- 回滾 migration 檔案
+-- 刪除審核紀錄、審核規則與相關欄位
+
+DROP INDEX IF EXISTS idx_project_approvals_status;
+
+DROP TABLE IF EXISTS project_approvals;
+
+DROP TABLE IF EXISTS approval_rules;
+
+DROP INDEX IF EXISTS idx_projects_approval_status;
+
+ALTER TABLE projects
+DROP COLUMN IF EXISTS approval_level,
+DROP COLUMN IF EXISTS approval_status;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS team;
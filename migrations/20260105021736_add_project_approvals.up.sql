@@ -0,0 +1,78 @@
+-- 專案報備審核流程
+-- 每筆報備依審核規則產生一至多關審核,依關卡順序由對應角色的審核者核准或駁回
+
+-- 使用者所屬團隊(審核規則可依團隊套用)
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS team TEXT;
+
+-- 專案審核狀態,既有專案視為已核准
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS approval_status TEXT NOT NULL DEFAULT 'approved',
+ADD COLUMN IF NOT EXISTS approval_level INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_projects_approval_status ON projects(approval_status);
+
+-- 建立審核規則資料表
+CREATE TABLE IF NOT EXISTS approval_rules (
+    ar_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL,                        -- 規則名稱
+    level INTEGER NOT NULL,                    -- 審核關卡(由 1 開始,依序審核)
+    min_quantity INTEGER,                      -- 設備總數量達此數量時套用(NULL 表示不限)
+    team TEXT,                                 -- 建立者所屬團隊相符時套用(NULL 表示不限)
+    approver_role TEXT NOT NULL,               -- 審核者角色
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,     -- 是否啟用
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP
+);
+
+-- 預設規則:所有報備需由 manager 審核
+INSERT INTO approval_rules (name, level, approver_role)
+VALUES ('主管審核', 1, 'manager');
+
+-- 建立專案審核紀錄資料表
+CREATE TABLE IF NOT EXISTS project_approvals (
+    pa_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    p_id UUID NOT NULL,                        -- 專案編號
+    level INTEGER NOT NULL,                    -- 審核關卡
+    approver_roles TEXT NOT NULL,              -- 可審核的角色(以空白分隔)
+    status TEXT NOT NULL DEFAULT 'pending',    -- 審核狀態(pending / approved / rejected / cancelled)
+    decided_by UUID,                           -- 審核者
+    decided_at TIMESTAMP,                      -- 審核時間
+    reason TEXT,                               -- 核准說明或駁回原因
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT uq_project_approvals_level UNIQUE (p_id, level),
+    CONSTRAINT fk_project_approval_project
+        FOREIGN KEY (p_id)
+        REFERENCES projects(p_id)
+        ON DELETE CASCADE
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_project_approvals_status ON project_approvals(status);
+
+-- 新增註解
+COMMENT ON COLUMN users.team IS '所屬團隊';
+COMMENT ON COLUMN projects.approval_status IS '審核狀態 (pending: 待審核, approved: 已核准, rejected: 已駁回)';
+COMMENT ON COLUMN projects.approval_level IS '目前待審核的關卡(0 表示無)';
+
+COMMENT ON TABLE approval_rules IS '審核規則表';
+COMMENT ON COLUMN approval_rules.ar_id IS '規則編號(UUID)';
+COMMENT ON COLUMN approval_rules.name IS '規則名稱';
+COMMENT ON COLUMN approval_rules.level IS '審核關卡(由 1 開始)';
+COMMENT ON COLUMN approval_rules.min_quantity IS '設備總數量門檻';
+COMMENT ON COLUMN approval_rules.team IS '建立者所屬團隊';
+COMMENT ON COLUMN approval_rules.approver_role IS '審核者角色';
+COMMENT ON COLUMN approval_rules.enabled IS '是否啟用';
+COMMENT ON COLUMN approval_rules.created_at IS '建立時間';
+COMMENT ON COLUMN approval_rules.updated_at IS '更新時間';
+
+COMMENT ON TABLE project_approvals IS '專案審核紀錄表';
+COMMENT ON COLUMN project_approvals.pa_id IS '紀錄編號(UUID)';
+COMMENT ON COLUMN project_approvals.p_id IS '專案編號';
+COMMENT ON COLUMN project_approvals.level IS '審核關卡';
+COMMENT ON COLUMN project_approvals.approver_roles IS '可審核的角色(以空白分隔)';
+COMMENT ON COLUMN project_approvals.status IS '審核狀態 (pending: 待審核, approved: 已核准, rejected: 已駁回, cancelled: 因前一關駁回而取消)';
+COMMENT ON COLUMN project_approvals.decided_by IS '審核者使用者編號';
+COMMENT ON COLUMN project_approvals.decided_at IS '審核時間';
+COMMENT ON COLUMN project_approvals.reason IS '核准說明或駁回原因';
+COMMENT ON COLUMN project_approvals.created_at IS '建立時間';
@@ -0,0 +1,8 @@
+-- 回滾 migration 檔案
+-- 刪除留言資料表(原備註欄位未變動,不需還原)
+
+DROP INDEX IF EXISTS idx_comments_deleted_at;
+
+DROP INDEX IF EXISTS idx_comments_target;
+
+DROP TABLE IF EXISTS comments;
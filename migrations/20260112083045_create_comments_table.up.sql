@@ -0,0 +1,40 @@
+-- 專案與現貨的留言串
+-- 取代只能整段覆寫的備註欄位,每則留言保留作者與時間,並可 @ 提及其他使用者
+
+CREATE TABLE IF NOT EXISTS comments (
+    c_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    target_type TEXT NOT NULL,                 -- 留言對象類型(project / stock)
+    target_id UUID NOT NULL,                   -- 留言對象編號(專案或現貨編號)
+    author_id UUID,                            -- 作者(NULL 表示由系統轉入)
+    body TEXT NOT NULL,                        -- 留言內容
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP,
+    CONSTRAINT chk_comments_target_type CHECK (target_type IN ('project', 'stock'))
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_comments_target ON comments(target_type, target_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_deleted_at ON comments(deleted_at);
+
+-- 將既有備註轉為第一則留言
+INSERT INTO comments (target_type, target_id, author_id, body, created_at)
+SELECT 'project', p_id, created_by, remark, created_time
+FROM projects
+WHERE remark IS NOT NULL AND btrim(remark) <> '';
+
+INSERT INTO comments (target_type, target_id, body, created_at)
+SELECT 'stock', stock_id, remark, created_time
+FROM stocks
+WHERE remark IS NOT NULL AND btrim(remark) <> '';
+
+-- 新增註解
+COMMENT ON TABLE comments IS '專案與現貨留言表';
+COMMENT ON COLUMN comments.c_id IS '留言編號(UUID)';
+COMMENT ON COLUMN comments.target_type IS '留言對象類型 (project: 專案, stock: 現貨)';
+COMMENT ON COLUMN comments.target_id IS '留言對象編號';
+COMMENT ON COLUMN comments.author_id IS '作者使用者編號(NULL 表示由備註轉入)';
+COMMENT ON COLUMN comments.body IS '留言內容';
+COMMENT ON COLUMN comments.created_at IS '建立時間';
+COMMENT ON COLUMN comments.updated_at IS '最後編輯時間';
+COMMENT ON COLUMN comments.deleted_at IS '刪除時間';
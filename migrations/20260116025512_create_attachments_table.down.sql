@@ -0,0 +1,6 @@
+-- 回滾 migration 檔案
+-- 刪除附件資料表(儲存服務中的檔案需另行清除)
+
+DROP INDEX IF EXISTS idx_attachments_target;
+
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,32 @@
+-- 專案與現貨附件(報價單、採購單、規格書等)
+-- 檔案本身存放於儲存服務(本機或 S3 / MinIO),此表只記錄中繼資料
+
+CREATE TABLE IF NOT EXISTS attachments (
+    a_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    target_type TEXT NOT NULL,                 -- 附件對象類型(project / stock)
+    target_id UUID NOT NULL,                   -- 附件對象編號(專案或現貨編號)
+    filename TEXT NOT NULL,                    -- 原始檔名
+    size BIGINT NOT NULL,                      -- 檔案大小(bytes)
+    content_type TEXT NOT NULL,                -- 檔案類型
+    checksum TEXT NOT NULL,                    -- SHA-256 檢查碼
+    storage_key TEXT NOT NULL UNIQUE,          -- 儲存服務中的物件鍵值
+    uploaded_by UUID,                          -- 上傳者
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT chk_attachments_target_type CHECK (target_type IN ('project', 'stock'))
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_attachments_target ON attachments(target_type, target_id, created_at);
+
+-- 新增註解
+COMMENT ON TABLE attachments IS '專案與現貨附件表';
+COMMENT ON COLUMN attachments.a_id IS '附件編號(UUID)';
+COMMENT ON COLUMN attachments.target_type IS '附件對象類型 (project: 專案, stock: 現貨)';
+COMMENT ON COLUMN attachments.target_id IS '附件對象編號';
+COMMENT ON COLUMN attachments.filename IS '原始檔名';
+COMMENT ON COLUMN attachments.size IS '檔案大小(bytes)';
+COMMENT ON COLUMN attachments.content_type IS '檔案類型';
+COMMENT ON COLUMN attachments.checksum IS 'SHA-256 檢查碼';
+COMMENT ON COLUMN attachments.storage_key IS '儲存服務中的物件鍵值';
+COMMENT ON COLUMN attachments.uploaded_by IS '上傳者使用者編號';
+COMMENT ON COLUMN attachments.created_at IS '上傳時間';
@@ -0,0 +1,14 @@
+-- 回滾 migration 檔案
+-- 移除設備的料號目錄參照並刪除料號目錄資料表
+
+DROP INDEX IF EXISTS idx_stock_equipments_part_id;
+DROP INDEX IF EXISTS idx_equipments_part_id;
+
+ALTER TABLE stock_equipments DROP COLUMN IF EXISTS part_id;
+ALTER TABLE equipments DROP COLUMN IF EXISTS part_id;
+
+DROP INDEX IF EXISTS idx_parts_normalized_prefix;
+DROP INDEX IF EXISTS idx_parts_brand;
+DROP INDEX IF EXISTS idx_parts_category;
+
+DROP TABLE IF EXISTS parts;
@@ -0,0 +1,44 @@
+-- 料號目錄(設備料號主檔)
+-- equipments / stock_equipments 的料號原為自由輸入,改為可參照目錄,但保留自由輸入作為備援
+
+CREATE TABLE IF NOT EXISTS parts (
+    part_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    part_number TEXT NOT NULL,                 -- 料號(標準寫法)
+    normalized TEXT NOT NULL UNIQUE,           -- 正規化料號(轉大寫並移除空白),用於比對
+    name TEXT NOT NULL,                        -- 品名
+    category TEXT,                             -- 類別
+    brand TEXT,                                -- 品牌
+    unit TEXT,                                 -- 單位
+    list_price NUMERIC(14, 2),                 -- 牌價
+    active BOOLEAN NOT NULL DEFAULT true,      -- 是否啟用
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_parts_category ON parts(category);
+CREATE INDEX IF NOT EXISTS idx_parts_brand ON parts(brand);
+CREATE INDEX IF NOT EXISTS idx_parts_normalized_prefix ON parts(normalized text_pattern_ops);
+
+-- 設備與現貨設備參照料號目錄(空值表示自由輸入的料號)
+ALTER TABLE equipments ADD COLUMN IF NOT EXISTS part_id UUID REFERENCES parts(part_id) ON DELETE SET NULL;
+ALTER TABLE stock_equipments ADD COLUMN IF NOT EXISTS part_id UUID REFERENCES parts(part_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_equipments_part_id ON equipments(part_id);
+CREATE INDEX IF NOT EXISTS idx_stock_equipments_part_id ON stock_equipments(part_id);
+
+-- 新增註解
+COMMENT ON TABLE parts IS '料號目錄';
+COMMENT ON COLUMN parts.part_id IS '料號目錄編號(UUID)';
+COMMENT ON COLUMN parts.part_number IS '料號(標準寫法)';
+COMMENT ON COLUMN parts.normalized IS '正規化料號(轉大寫並移除空白)';
+COMMENT ON COLUMN parts.name IS '品名';
+COMMENT ON COLUMN parts.category IS '類別';
+COMMENT ON COLUMN parts.brand IS '品牌';
+COMMENT ON COLUMN parts.unit IS '單位';
+COMMENT ON COLUMN parts.list_price IS '牌價';
+COMMENT ON COLUMN parts.active IS '是否啟用 (停用的料號不再用於比對)';
+COMMENT ON COLUMN parts.created_at IS '建立時間';
+COMMENT ON COLUMN parts.updated_at IS '更新時間';
+COMMENT ON COLUMN equipments.part_id IS '料號目錄編號(空值表示自由輸入)';
+COMMENT ON COLUMN stock_equipments.part_id IS '料號目錄編號(空值表示自由輸入)';
@@ -0,0 +1,5 @@
+-- 回滾 migration 檔案
+-- 刪除料號三連字索引(pg_trgm 擴充套件可能被其他功能使用,保留不移除)
+
+DROP INDEX IF EXISTS idx_stock_equipments_part_number_trgm;
+DROP INDEX IF EXISTS idx_equipments_part_number_trgm;
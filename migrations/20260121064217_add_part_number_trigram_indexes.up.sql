@@ -0,0 +1,8 @@
+-- 料號自動完成與拼字建議
+-- 以 pg_trgm 三連字索引加速歷史設備料號的前綴與相似度查詢
+-- 索引運算式與查詢相同:去除空白後轉大寫
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_equipments_part_number_trgm ON equipments USING gin (upper(regexp_replace(part_number, '\s', '', 'g')) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_stock_equipments_part_number_trgm ON stock_equipments USING gin (upper(regexp_replace(part_number, '\s', '', 'g')) gin_trgm_ops);
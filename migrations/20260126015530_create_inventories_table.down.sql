@@ -0,0 +1,9 @@
+-- 回滾 migration 檔案
+-- 刪除庫存異動紀錄與庫存資料表
+
+DROP INDEX IF EXISTS idx_inventory_movements_stock_id;
+DROP INDEX IF EXISTS idx_inventory_movements_inv_id;
+DROP INDEX IF EXISTS idx_inventories_part_id;
+
+DROP TABLE IF EXISTS inventory_movements;
+DROP TABLE IF EXISTS inventories;
@@ -0,0 +1,52 @@
+-- 現貨庫存帳
+-- 每個料號一筆庫存(在庫、已保留),異動紀錄保存進貨、保留、釋放、出貨與盤點調整
+
+CREATE TABLE IF NOT EXISTS inventories (
+    inv_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    part_number TEXT NOT NULL,                 -- 料號
+    normalized TEXT NOT NULL UNIQUE,           -- 正規化料號(轉大寫並移除空白)
+    part_id UUID REFERENCES parts(part_id) ON DELETE SET NULL, -- 料號目錄編號
+    on_hand BIGINT NOT NULL DEFAULT 0,         -- 在庫數量
+    reserved BIGINT NOT NULL DEFAULT 0,        -- 已保留數量
+    updated_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT chk_inventories_on_hand CHECK (on_hand >= 0),
+    CONSTRAINT chk_inventories_reserved CHECK (reserved >= 0 AND reserved <= on_hand)
+);
+
+CREATE TABLE IF NOT EXISTS inventory_movements (
+    im_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    inv_id UUID NOT NULL REFERENCES inventories(inv_id) ON DELETE CASCADE,
+    type TEXT NOT NULL,                        -- 異動類型
+    quantity BIGINT NOT NULL,                  -- 數量(盤點調整可為負數)
+    stock_id UUID,                             -- 相關的現貨報備
+    seq_id UUID,                               -- 相關的現貨設備
+    note TEXT,                                 -- 備註
+    created_by UUID,                           -- 操作者(系統自動保留 / 釋放時為空)
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT chk_inventory_movements_type CHECK (type IN ('receive', 'reserve', 'release', 'ship', 'adjust'))
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_inventories_part_id ON inventories(part_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_inv_id ON inventory_movements(inv_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_stock_id ON inventory_movements(stock_id);
+
+-- 新增註解
+COMMENT ON TABLE inventories IS '現貨庫存表';
+COMMENT ON COLUMN inventories.inv_id IS '庫存編號(UUID)';
+COMMENT ON COLUMN inventories.part_number IS '料號';
+COMMENT ON COLUMN inventories.normalized IS '正規化料號(轉大寫並移除空白)';
+COMMENT ON COLUMN inventories.part_id IS '料號目錄編號';
+COMMENT ON COLUMN inventories.on_hand IS '在庫數量';
+COMMENT ON COLUMN inventories.reserved IS '已保留數量(可用數量 = 在庫 - 已保留)';
+COMMENT ON COLUMN inventories.updated_at IS '更新時間';
+COMMENT ON TABLE inventory_movements IS '庫存異動紀錄表';
+COMMENT ON COLUMN inventory_movements.im_id IS '異動編號(UUID)';
+COMMENT ON COLUMN inventory_movements.inv_id IS '庫存編號';
+COMMENT ON COLUMN inventory_movements.type IS '異動類型 (receive: 進貨, reserve: 保留, release: 釋放, ship: 出貨, adjust: 盤點調整)';
+COMMENT ON COLUMN inventory_movements.quantity IS '數量(盤點調整可為負數)';
+COMMENT ON COLUMN inventory_movements.stock_id IS '相關的現貨報備編號';
+COMMENT ON COLUMN inventory_movements.seq_id IS '相關的現貨設備編號';
+COMMENT ON COLUMN inventory_movements.note IS '備註';
+COMMENT ON COLUMN inventory_movements.created_by IS '操作者使用者編號(系統自動異動時為空)';
+COMMENT ON COLUMN inventory_movements.created_at IS '異動時間';
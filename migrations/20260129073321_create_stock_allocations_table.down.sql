@@ -0,0 +1,7 @@
+-- 回滾 migration 檔案
+-- 刪除現貨支應專案設備資料表
+
+DROP INDEX IF EXISTS idx_stock_allocations_seq_id;
+DROP INDEX IF EXISTS idx_stock_allocations_p_id;
+
+DROP TABLE IF EXISTS stock_allocations;
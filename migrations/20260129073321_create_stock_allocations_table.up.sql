@@ -0,0 +1,30 @@
+-- 現貨支應專案設備
+-- 將現貨設備(stock_equipments)分配給專案設備(equipments),記錄由現貨支應的數量
+
+CREATE TABLE IF NOT EXISTS stock_allocations (
+    sa_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    p_id UUID NOT NULL REFERENCES projects(p_id) ON DELETE CASCADE,          -- 專案編號
+    eq_id UUID NOT NULL REFERENCES equipments(eq_id) ON DELETE CASCADE,      -- 專案設備編號
+    seq_id UUID NOT NULL REFERENCES stock_equipments(seq_id) ON DELETE CASCADE, -- 現貨設備編號
+    quantity INTEGER NOT NULL,                 -- 分配數量
+    allocated_by UUID,                         -- 分配者
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP,
+    CONSTRAINT chk_stock_allocations_quantity CHECK (quantity > 0),
+    CONSTRAINT uq_stock_allocations_line UNIQUE (eq_id, seq_id)
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_stock_allocations_p_id ON stock_allocations(p_id);
+CREATE INDEX IF NOT EXISTS idx_stock_allocations_seq_id ON stock_allocations(seq_id);
+
+-- 新增註解
+COMMENT ON TABLE stock_allocations IS '現貨支應專案設備表';
+COMMENT ON COLUMN stock_allocations.sa_id IS '分配編號(UUID)';
+COMMENT ON COLUMN stock_allocations.p_id IS '專案編號';
+COMMENT ON COLUMN stock_allocations.eq_id IS '專案設備編號';
+COMMENT ON COLUMN stock_allocations.seq_id IS '現貨設備編號';
+COMMENT ON COLUMN stock_allocations.quantity IS '分配數量';
+COMMENT ON COLUMN stock_allocations.allocated_by IS '分配者使用者編號';
+COMMENT ON COLUMN stock_allocations.created_at IS '建立時間';
+COMMENT ON COLUMN stock_allocations.updated_at IS '更新時間';
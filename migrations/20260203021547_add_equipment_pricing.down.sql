@@ -0,0 +1,11 @@
+-- 回滾 migration 檔案
+-- 移除專案設備的報價欄位
+
+ALTER TABLE equipments DROP CONSTRAINT IF EXISTS chk_equipments_tax_rate;
+ALTER TABLE equipments DROP CONSTRAINT IF EXISTS chk_equipments_discount;
+ALTER TABLE equipments DROP CONSTRAINT IF EXISTS chk_equipments_unit_price;
+
+ALTER TABLE equipments DROP COLUMN IF EXISTS tax_rate;
+ALTER TABLE equipments DROP COLUMN IF EXISTS currency;
+ALTER TABLE equipments DROP COLUMN IF EXISTS discount;
+ALTER TABLE equipments DROP COLUMN IF EXISTS unit_price;
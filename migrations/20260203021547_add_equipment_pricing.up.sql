@@ -0,0 +1,17 @@
+-- 專案設備報價欄位
+-- 單價、折扣、幣別與稅率,小計與總額由程式依這些欄位計算
+
+ALTER TABLE equipments ADD COLUMN IF NOT EXISTS unit_price NUMERIC(14,2);
+ALTER TABLE equipments ADD COLUMN IF NOT EXISTS discount NUMERIC(5,2);
+ALTER TABLE equipments ADD COLUMN IF NOT EXISTS currency CHAR(3);
+ALTER TABLE equipments ADD COLUMN IF NOT EXISTS tax_rate NUMERIC(5,2);
+
+ALTER TABLE equipments ADD CONSTRAINT chk_equipments_unit_price CHECK (unit_price IS NULL OR unit_price >= 0);
+ALTER TABLE equipments ADD CONSTRAINT chk_equipments_discount CHECK (discount IS NULL OR (discount >= 0 AND discount <= 100));
+ALTER TABLE equipments ADD CONSTRAINT chk_equipments_tax_rate CHECK (tax_rate IS NULL OR (tax_rate >= 0 AND tax_rate <= 100));
+
+-- 新增註解
+COMMENT ON COLUMN equipments.unit_price IS '單價(NULL 表示尚未報價)';
+COMMENT ON COLUMN equipments.discount IS '折扣百分比(0~100,NULL 表示不打折)';
+COMMENT ON COLUMN equipments.currency IS '幣別(ISO 4217,NULL 使用預設幣別)';
+COMMENT ON COLUMN equipments.tax_rate IS '稅率百分比(0~100,NULL 使用預設稅率)';
@@ -0,0 +1,9 @@
+-- 回滾 migration 檔案
+-- 刪除報價單與明細資料表
+
+DROP INDEX IF EXISTS idx_quotation_lines_q_id;
+DROP INDEX IF EXISTS idx_quotations_p_id;
+DROP INDEX IF EXISTS uq_quotations_accepted;
+
+DROP TABLE IF EXISTS quotation_lines;
+DROP TABLE IF EXISTS quotations;
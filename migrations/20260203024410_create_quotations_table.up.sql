@@ -0,0 +1,86 @@
+-- 專案報價單
+-- 每次建立報價單都是專案設備當下的快照,版本號依專案遞增
+-- 狀態:draft(草稿)→ sent(已寄出)→ accepted(客戶接受),每個專案只能有一張已接受的報價單
+
+CREATE TABLE IF NOT EXISTS quotations (
+    q_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    p_id UUID NOT NULL REFERENCES projects(p_id) ON DELETE CASCADE, -- 專案編號
+    version INTEGER NOT NULL,                  -- 報價版本
+    status TEXT NOT NULL DEFAULT 'draft',      -- 狀態
+    currency CHAR(3) NOT NULL,                 -- 幣別
+    subtotal NUMERIC(14,2) NOT NULL DEFAULT 0, -- 未稅小計(折扣前)
+    discount NUMERIC(14,2) NOT NULL DEFAULT 0, -- 折扣金額
+    tax NUMERIC(14,2) NOT NULL DEFAULT 0,      -- 稅額
+    total NUMERIC(14,2) NOT NULL DEFAULT 0,    -- 含稅總額
+    note TEXT,                                 -- 備註
+    valid_until TIMESTAMP,                     -- 報價有效期限
+    created_by UUID,                           -- 建立者
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    sent_at TIMESTAMP,                         -- 寄出時間
+    sent_to TEXT,                              -- 寄送信箱
+    sent_by UUID,                              -- 寄出者
+    accepted_at TIMESTAMP,                     -- 接受時間
+    updated_at TIMESTAMP,
+    CONSTRAINT chk_quotations_status CHECK (status IN ('draft', 'sent', 'accepted')),
+    CONSTRAINT uq_quotations_version UNIQUE (p_id, version)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_quotations_accepted ON quotations(p_id) WHERE status = 'accepted';
+
+-- 報價單明細(建立報價單時的設備快照)
+CREATE TABLE IF NOT EXISTS quotation_lines (
+    ql_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    q_id UUID NOT NULL REFERENCES quotations(q_id) ON DELETE CASCADE,  -- 報價單編號
+    eq_id UUID REFERENCES equipments(eq_id) ON DELETE SET NULL,        -- 專案設備編號
+    position INTEGER NOT NULL,                     -- 排列順序
+    part_number TEXT NOT NULL,                     -- 料號
+    description TEXT,                              -- 說明
+    quantity INTEGER NOT NULL,                     -- 數量
+    unit_price NUMERIC(14,2) NOT NULL,             -- 單價
+    discount_rate NUMERIC(5,2) NOT NULL DEFAULT 0, -- 折扣百分比
+    tax_rate NUMERIC(5,2) NOT NULL DEFAULT 0,      -- 稅率百分比
+    subtotal NUMERIC(14,2) NOT NULL,               -- 未稅小計(折扣前)
+    discount NUMERIC(14,2) NOT NULL,               -- 折扣金額
+    tax NUMERIC(14,2) NOT NULL,                    -- 稅額
+    total NUMERIC(14,2) NOT NULL                   -- 含稅金額
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_quotations_p_id ON quotations(p_id);
+CREATE INDEX IF NOT EXISTS idx_quotation_lines_q_id ON quotation_lines(q_id);
+
+-- 新增註解
+COMMENT ON TABLE quotations IS '專案報價單表';
+COMMENT ON COLUMN quotations.q_id IS '報價單編號(UUID)';
+COMMENT ON COLUMN quotations.p_id IS '專案編號';
+COMMENT ON COLUMN quotations.version IS '報價版本(依專案遞增)';
+COMMENT ON COLUMN quotations.status IS '狀態:draft / sent / accepted';
+COMMENT ON COLUMN quotations.currency IS '幣別(ISO 4217)';
+COMMENT ON COLUMN quotations.subtotal IS '未稅小計(折扣前)';
+COMMENT ON COLUMN quotations.discount IS '折扣金額';
+COMMENT ON COLUMN quotations.tax IS '稅額';
+COMMENT ON COLUMN quotations.total IS '含稅總額';
+COMMENT ON COLUMN quotations.note IS '備註';
+COMMENT ON COLUMN quotations.valid_until IS '報價有效期限';
+COMMENT ON COLUMN quotations.created_by IS '建立者使用者編號';
+COMMENT ON COLUMN quotations.created_at IS '建立時間';
+COMMENT ON COLUMN quotations.sent_at IS '寄出時間';
+COMMENT ON COLUMN quotations.sent_to IS '寄送信箱(專案聯絡人信箱)';
+COMMENT ON COLUMN quotations.sent_by IS '寄出者使用者編號';
+COMMENT ON COLUMN quotations.accepted_at IS '客戶接受時間';
+COMMENT ON COLUMN quotations.updated_at IS '更新時間';
+COMMENT ON TABLE quotation_lines IS '報價單明細表';
+COMMENT ON COLUMN quotation_lines.ql_id IS '明細編號(UUID)';
+COMMENT ON COLUMN quotation_lines.q_id IS '報價單編號';
+COMMENT ON COLUMN quotation_lines.eq_id IS '專案設備編號(設備刪除後為 NULL)';
+COMMENT ON COLUMN quotation_lines.position IS '排列順序';
+COMMENT ON COLUMN quotation_lines.part_number IS '料號';
+COMMENT ON COLUMN quotation_lines.description IS '說明';
+COMMENT ON COLUMN quotation_lines.quantity IS '數量';
+COMMENT ON COLUMN quotation_lines.unit_price IS '單價';
+COMMENT ON COLUMN quotation_lines.discount_rate IS '折扣百分比';
+COMMENT ON COLUMN quotation_lines.tax_rate IS '稅率百分比';
+COMMENT ON COLUMN quotation_lines.subtotal IS '未稅小計(折扣前)';
+COMMENT ON COLUMN quotation_lines.discount IS '折扣金額';
+COMMENT ON COLUMN quotation_lines.tax IS '稅額';
+COMMENT ON COLUMN quotation_lines.total IS '含稅金額';
@@ -0,0 +1,7 @@
+-- 回滾 migration 檔案
+-- 刪除設備清單異動紀錄資料表
+
+DROP INDEX IF EXISTS idx_equipment_changes_batch_id;
+DROP INDEX IF EXISTS idx_equipment_changes_target;
+
+DROP TABLE IF EXISTS equipment_changes;
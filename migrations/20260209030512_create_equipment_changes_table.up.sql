@@ -0,0 +1,36 @@
+-- 設備清單異動紀錄
+-- 以完整清單取代專案設備或現貨設備時,每筆新增、更新、刪除的設備各記錄一筆,同一次取代共用 batch_id
+
+CREATE TABLE IF NOT EXISTS equipment_changes (
+    ec_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL,                    -- 同一次取代的批次編號
+    target_type TEXT NOT NULL,                 -- 對象類型(project / stock)
+    target_id UUID NOT NULL,                   -- 專案或現貨報備編號
+    line_id UUID NOT NULL,                     -- 設備編號(eq_id / seq_id)
+    action TEXT NOT NULL,                      -- 異動類型
+    part_number TEXT NOT NULL,                 -- 料號(刪除時為原料號)
+    before_value TEXT,                         -- 異動前的設備(JSON)
+    after_value TEXT,                          -- 異動後的設備(JSON)
+    changed_by UUID,                           -- 異動者
+    changed_at TIMESTAMP NOT NULL DEFAULT now(),
+    CONSTRAINT chk_equipment_changes_target_type CHECK (target_type IN ('project', 'stock')),
+    CONSTRAINT chk_equipment_changes_action CHECK (action IN ('add', 'update', 'delete'))
+);
+
+-- 建立索引以提升查詢效能
+CREATE INDEX IF NOT EXISTS idx_equipment_changes_target ON equipment_changes(target_type, target_id, changed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_equipment_changes_batch_id ON equipment_changes(batch_id);
+
+-- 新增註解
+COMMENT ON TABLE equipment_changes IS '設備清單異動紀錄表';
+COMMENT ON COLUMN equipment_changes.ec_id IS '異動紀錄編號(UUID)';
+COMMENT ON COLUMN equipment_changes.batch_id IS '同一次取代的批次編號';
+COMMENT ON COLUMN equipment_changes.target_type IS '對象類型:project / stock';
+COMMENT ON COLUMN equipment_changes.target_id IS '專案或現貨報備編號';
+COMMENT ON COLUMN equipment_changes.line_id IS '專案設備或現貨設備編號';
+COMMENT ON COLUMN equipment_changes.action IS '異動類型:add / update / delete';
+COMMENT ON COLUMN equipment_changes.part_number IS '料號';
+COMMENT ON COLUMN equipment_changes.before_value IS '異動前的設備內容(JSON,新增時為 NULL)';
+COMMENT ON COLUMN equipment_changes.after_value IS '異動後的設備內容(JSON,刪除時為 NULL)';
+COMMENT ON COLUMN equipment_changes.changed_by IS '異動者使用者編號';
+COMMENT ON COLUMN equipment_changes.changed_at IS '異動時間';